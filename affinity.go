@@ -0,0 +1,470 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// Affinity rule types
+const (
+	affinityNode    = "node-affinity"
+	affinityPod     = "pod-affinity"
+	affinityPodAnti = "pod-anti-affinity"
+)
+
+// AffinityRule is one affinity rule of a pod and the nodes satisfying it
+type AffinityRule struct {
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	// Weight is set for preferred rules
+	Weight int32 `json:"weight,omitempty"`
+	// Match holds the node selector term of node affinity rules, and the
+	// label selector of the pods of pod (anti-)affinity rules
+	Match string `json:"match"`
+	// TopologyKey and Namespaces are set for pod (anti-)affinity rules
+	TopologyKey string   `json:"topologyKey,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty"`
+	Nodes       []string `json:"nodes"`
+}
+
+// AffinityInfo holds the affinity rules of a pod or of the pod template of
+// a deployment
+type AffinityInfo struct {
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Rules     []AffinityRule `json:"rules"`
+}
+
+// EvaluateNodeAffinity returns the names of the nodes satisfying the
+// required terms of affinity: a node has to match one of the terms. Without
+// required terms every node does.
+func EvaluateNodeAffinity(affinity *v1.NodeAffinity, nodes []v1.Node) []string {
+	names := []string{}
+	for i := range nodes {
+		if affinity == nil || affinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+			slices.ContainsFunc(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, func(term v1.NodeSelectorTerm) bool {
+				return nodeSelectorTermMatches(term, &nodes[i])
+			}) {
+			names = append(names, nodes[i].Name)
+		}
+	}
+	return names
+}
+
+// nodesMatchingTerm returns the names of the nodes matching term
+func nodesMatchingTerm(term v1.NodeSelectorTerm, nodes []v1.Node) []string {
+	names := []string{}
+	for i := range nodes {
+		if nodeSelectorTermMatches(term, &nodes[i]) {
+			names = append(names, nodes[i].Name)
+		}
+	}
+	return names
+}
+
+// nodeSelectorTermMatches reports whether node meets every requirement of
+// term. As in the scheduler, a term without requirements matches no node,
+// and metadata.name is the only field that can be matched.
+func nodeSelectorTermMatches(term v1.NodeSelectorTerm, node *v1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		value, present := node.Labels[req.Key]
+		if !nodeRequirementMatches(req, value, present) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if req.Key != metav1.ObjectNameField || !nodeRequirementMatches(req, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeRequirementMatches reports whether a label or field, with value when
+// present, meets req. Gt and Lt compare integers; a value that is not one
+// matches neither.
+func nodeRequirementMatches(req v1.NodeSelectorRequirement, value string, present bool) bool {
+	switch req.Operator {
+	case v1.NodeSelectorOpIn:
+		return present && slices.Contains(req.Values, value)
+	case v1.NodeSelectorOpNotIn:
+		return !present || !slices.Contains(req.Values, value)
+	case v1.NodeSelectorOpExists:
+		return present
+	case v1.NodeSelectorOpDoesNotExist:
+		return !present
+	case v1.NodeSelectorOpGt, v1.NodeSelectorOpLt:
+		if !present || len(req.Values) != 1 {
+			return false
+		}
+		actual, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		bound, err := strconv.ParseInt(req.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if req.Operator == v1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+	return false
+}
+
+// podAffinityNamespaces returns the namespaces whose pods term selects:
+// those it lists and those its namespace selector matches, or podNamespace
+// when it has neither. known holds the labels of the cluster's namespaces.
+func podAffinityNamespaces(term v1.PodAffinityTerm, podNamespace string, known map[string]map[string]string) []string {
+	if len(term.Namespaces) == 0 && term.NamespaceSelector == nil {
+		return []string{podNamespace}
+	}
+	namespaces := slices.Clone(term.Namespaces)
+	if term.NamespaceSelector != nil {
+		for namespace := range known {
+			if selectorMatches(term.NamespaceSelector, namespaceLabels(known, namespace)) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// nodesForPodAffinity returns the names of the nodes satisfying term: for
+// affinity, the nodes sharing a topology domain (the value of the
+// topology key label) with a scheduled pod the term selects; for
+// anti-affinity, the others, including nodes without the label. The pod
+// with UID self is not counted.
+func nodesForPodAffinity(term v1.PodAffinityTerm, namespaces []string, anti bool, self types.UID, nodes []v1.Node, pods []v1.Pod) []string {
+	nodeLabels := make(map[string]map[string]string, len(nodes))
+	for _, node := range nodes {
+		nodeLabels[node.Name] = node.Labels
+	}
+	domains := map[string]bool{}
+	for _, pod := range pods {
+		// A nil label selector selects no pod
+		if self != "" && pod.UID == self || pod.Spec.NodeName == "" || term.LabelSelector == nil ||
+			!slices.Contains(namespaces, pod.Namespace) || !selectorMatches(term.LabelSelector, pod.Labels) {
+			continue
+		}
+		if domain, ok := nodeLabels[pod.Spec.NodeName][term.TopologyKey]; ok {
+			domains[domain] = true
+		}
+	}
+
+	names := []string{}
+	for _, node := range nodes {
+		domain, ok := node.Labels[term.TopologyKey]
+		shared := ok && domains[domain]
+		if shared != anti {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
+
+// explainAffinity lists the rules of affinity with the nodes satisfying
+// each: node affinity, then pod affinity, then pod anti-affinity, the
+// required rules of each before the preferred ones. namespace is the pod's
+// and self its UID, which is empty for a pod template.
+func explainAffinity(affinity *v1.Affinity, namespace string, self types.UID, nodes []v1.Node, pods []v1.Pod, known map[string]map[string]string) []AffinityRule {
+	rules := []AffinityRule{}
+	if affinity == nil {
+		return rules
+	}
+	if na := affinity.NodeAffinity; na != nil {
+		if required := na.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for _, term := range required.NodeSelectorTerms {
+				rules = append(rules, AffinityRule{
+					Type:     affinityNode,
+					Required: true,
+					Match:    podinfo.FormatNodeSelectorTerm(term),
+					Nodes:    nodesMatchingTerm(term, nodes),
+				})
+			}
+		}
+		for _, preferred := range na.PreferredDuringSchedulingIgnoredDuringExecution {
+			rules = append(rules, AffinityRule{
+				Type:   affinityNode,
+				Weight: preferred.Weight,
+				Match:  podinfo.FormatNodeSelectorTerm(preferred.Preference),
+				Nodes:  nodesMatchingTerm(preferred.Preference, nodes),
+			})
+		}
+	}
+
+	podRules := func(ruleType string, required []v1.PodAffinityTerm, preferred []v1.WeightedPodAffinityTerm) {
+		rule := func(term v1.PodAffinityTerm, weight int32) AffinityRule {
+			namespaces := podAffinityNamespaces(term, namespace, known)
+			return AffinityRule{
+				Type:        ruleType,
+				Required:    weight == 0,
+				Weight:      weight,
+				Match:       podTermSelector(term),
+				TopologyKey: term.TopologyKey,
+				Namespaces:  namespaces,
+				Nodes:       nodesForPodAffinity(term, namespaces, ruleType == affinityPodAnti, self, nodes, pods),
+			}
+		}
+		for _, term := range required {
+			rules = append(rules, rule(term, 0))
+		}
+		for _, weighted := range preferred {
+			rules = append(rules, rule(weighted.PodAffinityTerm, weighted.Weight))
+		}
+	}
+	if pa := affinity.PodAffinity; pa != nil {
+		podRules(affinityPod, pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	if paa := affinity.PodAntiAffinity; paa != nil {
+		podRules(affinityPodAnti, paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	return rules
+}
+
+// podTermSelector formats the label selector of a pod affinity term
+func podTermSelector(term v1.PodAffinityTerm) string {
+	if term.LabelSelector == nil {
+		return "no pods"
+	}
+	return selectorString(term.LabelSelector, "all pods")
+}
+
+// parseAffinityTarget splits the argument of the affinity subcommand,
+// pod/NAME, deployment/NAME or a pod name, into a kind and a name
+func parseAffinityTarget(ref string) (string, string, error) {
+	kind, name, found := strings.Cut(ref, "/")
+	if !found {
+		kind, name = "pod", ref
+	}
+	switch kind {
+	case "pod", "pods", "po":
+		kind = "pod"
+	case "deployment", "deployments", "deploy":
+		kind = "deployment"
+	default:
+		return "", "", fmt.Errorf("unsupported kind %q (want pod or deployment)", kind)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid target %q (want pod/NAME, deployment/NAME or NAME)", ref)
+	}
+	return kind, name, nil
+}
+
+// hasPodAffinity reports whether affinity has pod (anti-)affinity rules,
+// which need the cluster's pods to evaluate
+func hasPodAffinity(affinity *v1.Affinity) bool {
+	return affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil)
+}
+
+// podAffinityTerms returns every pod (anti-)affinity term of affinity
+func podAffinityTerms(affinity *v1.Affinity) []v1.PodAffinityTerm {
+	var terms []v1.PodAffinityTerm
+	add := func(required []v1.PodAffinityTerm, preferred []v1.WeightedPodAffinityTerm) {
+		terms = append(terms, required...)
+		for _, weighted := range preferred {
+			terms = append(terms, weighted.PodAffinityTerm)
+		}
+	}
+	if pa := affinity.PodAffinity; pa != nil {
+		add(pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	if paa := affinity.PodAntiAffinity; paa != nil {
+		add(paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution)
+	}
+	return terms
+}
+
+// gatherAffinityInfo fetches the pod or deployment, the nodes and, for pod
+// (anti-)affinity rules, the namespaces and pods those select, and explains
+// its affinity. When the namespaces cannot be listed, namespace selectors
+// only match namespaces by name, with a warning.
+func gatherAffinityInfo(ctx context.Context, client kubernetes.Interface, kind, namespace, name string) (AffinityInfo, []error, error) {
+	info := AffinityInfo{Kind: kind, Namespace: namespace, Name: name}
+	var affinity *v1.Affinity
+	var self types.UID
+	switch kind {
+	case "pod":
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return info, nil, handleAPIError(err, "pods", namespace)
+		}
+		affinity, self = pod.Spec.Affinity, pod.UID
+	default:
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return info, nil, handleAPIError(err, "deployments", namespace)
+		}
+		affinity = deployment.Spec.Template.Spec.Affinity
+	}
+	if affinity == nil {
+		info.Rules = []AffinityRule{}
+		return info, nil, nil
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return info, nil, handleAPIError(err, "nodes", "")
+	}
+
+	var warnings []error
+	var pods []v1.Pod
+	known := map[string]map[string]string{}
+	if hasPodAffinity(affinity) {
+		terms := podAffinityTerms(affinity)
+		if slices.ContainsFunc(terms, func(term v1.PodAffinityTerm) bool { return term.NamespaceSelector != nil }) {
+			list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				warnings = append(warnings, fmt.Errorf("namespace selectors only match namespaces by name: %w", handleAPIError(err, "namespaces", "")))
+			} else {
+				for _, ns := range list.Items {
+					known[ns.Name] = namespaceLabels(nil, ns.Name)
+					for k, v := range ns.Labels {
+						known[ns.Name][k] = v
+					}
+				}
+			}
+		}
+		var namespaces []string
+		for _, term := range terms {
+			namespaces = append(namespaces, podAffinityNamespaces(term, namespace, known)...)
+		}
+		slices.Sort(namespaces)
+		for _, ns := range slices.Compact(namespaces) {
+			list, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return info, nil, handleAPIError(err, "pods", ns)
+			}
+			pods = append(pods, list.Items...)
+		}
+	}
+	info.Rules = explainAffinity(affinity, namespace, self, nodes.Items, pods, known)
+	return info, warnings, nil
+}
+
+// affinityRuleHeading names the type of rule, e.g. "Pod anti-affinity, preferred"
+func affinityRuleHeading(rule AffinityRule) string {
+	heading := map[string]string{
+		affinityNode:    "Node affinity",
+		affinityPod:     "Pod affinity",
+		affinityPodAnti: "Pod anti-affinity",
+	}[rule.Type]
+	switch {
+	case !rule.Required:
+		return heading + ", preferred"
+	case rule.Type == affinityNode:
+		return heading + ", required (a node must match one of the terms)"
+	default:
+		return heading + ", required"
+	}
+}
+
+// printAffinityInfo prints the rules under a heading per type of rule, each
+// followed by the nodes satisfying it
+func printAffinityInfo(out io.Writer, info AffinityInfo) {
+	target := fmt.Sprintf("%s %s/%s", info.Kind, info.Namespace, info.Name)
+	if len(info.Rules) == 0 {
+		fmt.Fprintf(out, "%s has no affinity rules\n", target)
+		return
+	}
+	fmt.Fprintf(out, "Affinity of %s:\n", target)
+	heading := ""
+	for _, rule := range info.Rules {
+		if h := affinityRuleHeading(rule); h != heading {
+			heading = h
+			fmt.Fprintf(out, "%s:\n", heading)
+		}
+		line := rule.Match
+		if rule.Type != affinityNode {
+			line = fmt.Sprintf("pods matching %s in %s, topology key %s", rule.Match, strings.Join(rule.Namespaces, ","), orNone(rule.TopologyKey))
+		}
+		if !rule.Required {
+			line = fmt.Sprintf("weight %d: %s", rule.Weight, line)
+		}
+		fmt.Fprintf(out, "  - %s\n", line)
+		fmt.Fprintf(out, "    nodes: %s\n", orNoneList(rule.Nodes))
+	}
+}
+
+// runAffinity implements the affinity subcommand
+func runAffinity(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("affinity", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s affinity [flags] pod/NAME|deployment/NAME\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", metav1.NamespaceDefault, "namespace of the pod or deployment")
+	output := fs.String("output", outputText, "output format: text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Allow flags after the target, as kubectl does
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("a pod or deployment is required")
+	}
+	target := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments after the target: %s", strings.Join(fs.Args(), " "))
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputText, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want text, json or yaml)", *output)
+		}
+		kind, name, err := parseAffinityTarget(target)
+		if err != nil {
+			return err
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		info, warnings, err := gatherAffinityInfo(ctx, client, kind, *namespace, name)
+		if err != nil {
+			return fmt.Errorf("error explaining affinity: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		logWarnings(ctx, warnings)
+		if *output != outputText {
+			return printStructured(out, info, *output)
+		}
+		printAffinityInfo(out, info)
+		return nil
+	})
+}
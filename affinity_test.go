@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newAffinityNode returns a node with labels
+func newAffinityNode(name string, labels map[string]string) v1.Node {
+	return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+// requiredNodeAffinity returns node affinity requiring one of terms, each
+// term holding a single expression
+func requiredNodeAffinity(reqs ...v1.NodeSelectorRequirement) *v1.NodeAffinity {
+	selector := &v1.NodeSelector{}
+	for _, req := range reqs {
+		selector.NodeSelectorTerms = append(selector.NodeSelectorTerms, v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{req}})
+	}
+	return &v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: selector}
+}
+
+func TestEvaluateNodeAffinity(t *testing.T) {
+	nodes := []v1.Node{
+		newAffinityNode("ssd-a", map[string]string{"disktype": "ssd", "zone": "a", "cores": "8"}),
+		newAffinityNode("hdd-b", map[string]string{"disktype": "hdd", "zone": "b", "cores": "32"}),
+		newAffinityNode("gpu-c", map[string]string{"zone": "c", "gpu": "", "cores": "many"}),
+	}
+	req := func(key string, op v1.NodeSelectorOperator, values ...string) v1.NodeSelectorRequirement {
+		return v1.NodeSelectorRequirement{Key: key, Operator: op, Values: values}
+	}
+	tests := []struct {
+		name     string
+		affinity *v1.NodeAffinity
+		want     []string
+	}{
+		{"no affinity", nil, []string{"ssd-a", "hdd-b", "gpu-c"}},
+		{"no required terms", &v1.NodeAffinity{}, []string{"ssd-a", "hdd-b", "gpu-c"}},
+		{"In", requiredNodeAffinity(req("disktype", v1.NodeSelectorOpIn, "ssd", "nvme")), []string{"ssd-a"}},
+		{"In a missing label", requiredNodeAffinity(req("rack", v1.NodeSelectorOpIn, "r1")), []string{}},
+		{"NotIn", requiredNodeAffinity(req("disktype", v1.NodeSelectorOpNotIn, "hdd")), []string{"ssd-a", "gpu-c"}},
+		{"Exists", requiredNodeAffinity(req("gpu", v1.NodeSelectorOpExists)), []string{"gpu-c"}},
+		{"DoesNotExist", requiredNodeAffinity(req("gpu", v1.NodeSelectorOpDoesNotExist)), []string{"ssd-a", "hdd-b"}},
+		{"Gt", requiredNodeAffinity(req("cores", v1.NodeSelectorOpGt, "16")), []string{"hdd-b"}},
+		{"Lt", requiredNodeAffinity(req("cores", v1.NodeSelectorOpLt, "16")), []string{"ssd-a"}},
+		{"Gt without an integer bound", requiredNodeAffinity(req("cores", v1.NodeSelectorOpGt, "x")), []string{}},
+		{"any of the terms", requiredNodeAffinity(req("zone", v1.NodeSelectorOpIn, "a"), req("zone", v1.NodeSelectorOpIn, "c")), []string{"ssd-a", "gpu-c"}},
+		{
+			"all expressions of a term",
+			&v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: []v1.NodeSelectorRequirement{req("zone", v1.NodeSelectorOpIn, "a", "b"), req("disktype", v1.NodeSelectorOpNotIn, "ssd")},
+			}}}},
+			[]string{"hdd-b"},
+		},
+		{
+			"a field",
+			&v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchFields: []v1.NodeSelectorRequirement{req(metav1.ObjectNameField, v1.NodeSelectorOpIn, "gpu-c")},
+			}}}},
+			[]string{"gpu-c"},
+		},
+		{
+			"an empty term",
+			&v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{}}}},
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		if got := EvaluateNodeAffinity(tt.affinity, nodes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: EvaluateNodeAffinity() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNodesForPodAffinity(t *testing.T) {
+	nodes := []v1.Node{
+		newAffinityNode("node-a1", map[string]string{"zone": "a"}),
+		newAffinityNode("node-a2", map[string]string{"zone": "a"}),
+		newAffinityNode("node-b1", map[string]string{"zone": "b"}),
+		newAffinityNode("node-x", nil),
+	}
+	pod := func(namespace, name, node string, labels map[string]string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID("uid-" + name), Labels: labels},
+			Spec:       v1.PodSpec{NodeName: node},
+		}
+	}
+	pods := []v1.Pod{
+		pod("shop", "cache-1", "node-a1", map[string]string{"app": "cache", "tier": "backend"}),
+		pod("shop", "web-1", "node-b1", map[string]string{"app": "web"}),
+		pod("shop", "api-1", "node-b1", map[string]string{"app": "api"}),
+		pod("shop", "debug", "node-x", map[string]string{"app": "debug"}),
+		pod("shop", "unscheduled", "", map[string]string{"app": "queue"}),
+		pod("other", "cache-2", "node-b1", map[string]string{"app": "cache"}),
+	}
+	selector := func(reqs ...metav1.LabelSelectorRequirement) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchExpressions: reqs}
+	}
+	req := func(key string, op metav1.LabelSelectorOperator, values ...string) metav1.LabelSelectorRequirement {
+		return metav1.LabelSelectorRequirement{Key: key, Operator: op, Values: values}
+	}
+	tests := []struct {
+		name       string
+		selector   *metav1.LabelSelector
+		namespaces []string
+		anti       bool
+		want       []string
+	}{
+		{"In", selector(req("app", metav1.LabelSelectorOpIn, "cache")), []string{"shop"}, false, []string{"node-a1", "node-a2"}},
+		{"In, anti-affinity", selector(req("app", metav1.LabelSelectorOpIn, "cache")), []string{"shop"}, true, []string{"node-b1", "node-x"}},
+		{"In, several namespaces", selector(req("app", metav1.LabelSelectorOpIn, "cache")), []string{"other", "shop"}, false, []string{"node-a1", "node-a2", "node-b1"}},
+		{"NotIn", selector(req("app", metav1.LabelSelectorOpNotIn, "cache", "debug")), []string{"shop"}, false, []string{"node-b1"}},
+		{"Exists", selector(req("tier", metav1.LabelSelectorOpExists)), []string{"shop"}, false, []string{"node-a1", "node-a2"}},
+		{"DoesNotExist", selector(req("tier", metav1.LabelSelectorOpDoesNotExist)), []string{"shop"}, false, []string{"node-b1"}},
+		{"unscheduled pods", selector(req("app", metav1.LabelSelectorOpIn, "queue")), []string{"shop"}, false, []string{}},
+		{"the pod itself", selector(req("app", metav1.LabelSelectorOpIn, "web")), []string{"shop"}, false, []string{}},
+		{"a nil selector", nil, []string{"shop"}, true, []string{"node-a1", "node-a2", "node-b1", "node-x"}},
+	}
+	for _, tt := range tests {
+		term := v1.PodAffinityTerm{LabelSelector: tt.selector, TopologyKey: "zone"}
+		if got := nodesForPodAffinity(term, tt.namespaces, tt.anti, "uid-web-1", nodes, pods); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: nodesForPodAffinity() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPodAffinityNamespaces(t *testing.T) {
+	known := map[string]map[string]string{
+		"shop":    {v1.LabelMetadataName: "shop", "team": "payments"},
+		"billing": {v1.LabelMetadataName: "billing", "team": "payments"},
+		"infra":   {v1.LabelMetadataName: "infra"},
+	}
+	tests := []struct {
+		term v1.PodAffinityTerm
+		want []string
+	}{
+		{v1.PodAffinityTerm{}, []string{"web"}},
+		{v1.PodAffinityTerm{Namespaces: []string{"infra"}}, []string{"infra"}},
+		{v1.PodAffinityTerm{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}}, []string{"billing", "shop"}},
+		{v1.PodAffinityTerm{Namespaces: []string{"shop"}, NamespaceSelector: &metav1.LabelSelector{}}, []string{"billing", "infra", "shop"}},
+	}
+	for _, tt := range tests {
+		if got := podAffinityNamespaces(tt.term, "web", known); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("podAffinityNamespaces(%+v) = %v, want %v", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestGatherAffinityInfo(t *testing.T) {
+	nodeA := newAffinityNode("node-a", map[string]string{"disktype": "ssd", v1.LabelHostname: "node-a"})
+	nodeB := newAffinityNode("node-b", map[string]string{"disktype": "hdd", v1.LabelHostname: "node-b"})
+	web := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web-1", UID: "web-1", Labels: map[string]string{"app": "web"}},
+		Spec:       v1.PodSpec{NodeName: "node-a"},
+	}
+	affinity := &v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+				MatchExpressions: []v1.NodeSelectorRequirement{{Key: "disktype", Operator: v1.NodeSelectorOpExists}},
+			}}},
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{
+				Weight:     50,
+				Preference: v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "disktype", Operator: v1.NodeSelectorOpIn, Values: []string{"ssd"}}}},
+			}},
+		},
+		PodAntiAffinity: &v1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				TopologyKey:   v1.LabelHostname,
+			}},
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+		Spec:       appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Affinity: affinity}}},
+	}
+	client := fake.NewSimpleClientset(&nodeA, &nodeB, web, deployment)
+
+	info, warnings, err := gatherAffinityInfo(context.Background(), client, "deployment", "shop", "web")
+	if err != nil || len(warnings) > 0 {
+		t.Fatalf("gatherAffinityInfo() error = %v, warnings = %v", err, warnings)
+	}
+	var out bytes.Buffer
+	printAffinityInfo(&out, info)
+	want := `Affinity of deployment shop/web:
+Node affinity, required (a node must match one of the terms):
+  - disktype Exists
+    nodes: node-a, node-b
+Node affinity, preferred:
+  - weight 50: disktype In (ssd)
+    nodes: node-a
+Pod anti-affinity, required:
+  - pods matching app=web in shop, topology key kubernetes.io/hostname
+    nodes: node-b
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if _, _, err := gatherAffinityInfo(context.Background(), client, "pod", "shop", "missing"); err == nil {
+		t.Error("gatherAffinityInfo() of a missing pod succeeded, want an error")
+	}
+	info, _, err = gatherAffinityInfo(context.Background(), client, "pod", "shop", "web-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	printAffinityInfo(&out, info)
+	if want := "pod shop/web-1 has no affinity rules\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestParseAffinityTarget(t *testing.T) {
+	tests := []struct {
+		ref, kind, name string
+		wantErr         bool
+	}{
+		{ref: "web-1", kind: "pod", name: "web-1"},
+		{ref: "pod/web-1", kind: "pod", name: "web-1"},
+		{ref: "deploy/web", kind: "deployment", name: "web"},
+		{ref: "deployment/web", kind: "deployment", name: "web"},
+		{ref: "statefulset/db", wantErr: true},
+		{ref: "pod/", wantErr: true},
+		{ref: "pod/a/b", wantErr: true},
+	}
+	for _, tt := range tests {
+		kind, name, err := parseAffinityTarget(tt.ref)
+		if (err != nil) != tt.wantErr || kind != tt.kind || name != tt.name {
+			t.Errorf("parseAffinityTarget(%q) = %q, %q, %v", tt.ref, kind, name, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Formats accepted by --age-format
+const (
+	ageCompact  = "compact"
+	ageFull     = "full"
+	ageAbsolute = "absolute"
+)
+
+// validateAgeFormat rejects unknown --age-format values
+func validateAgeFormat(format string) error {
+	switch format {
+	case ageCompact, ageFull, ageAbsolute:
+		return nil
+	}
+	return fmt.Errorf("unknown --age-format %q (want compact, full or absolute)", format)
+}
+
+// formatAge renders an age for text and table output: compact like kubectl
+// ("45s", "12m", "3h", "5d", "2y14d"), the full duration ("26h3m4s"), or the
+// creation timestamp
+func formatAge(format string, age time.Duration, createdAt time.Time) string {
+	switch format {
+	case ageFull:
+		return age.String()
+	case ageAbsolute:
+		return createdAt.Format(time.RFC3339)
+	}
+	return duration.HumanDuration(age)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAgeCompact(t *testing.T) {
+	day := 24 * time.Hour
+	year := 365 * day
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{age: -5 * time.Second, want: "<invalid>"},
+		{age: -500 * time.Millisecond, want: "0s"},
+		{age: 0, want: "0s"},
+		{age: 45 * time.Second, want: "45s"},
+		{age: 119 * time.Second, want: "119s"},
+		{age: 2 * time.Minute, want: "2m"},
+		{age: 5*time.Minute + 30*time.Second, want: "5m30s"},
+		{age: 10 * time.Minute, want: "10m"},
+		{age: 179 * time.Minute, want: "179m"},
+		{age: 3 * time.Hour, want: "3h"},
+		{age: 7*time.Hour + 59*time.Minute, want: "7h59m"},
+		{age: 8 * time.Hour, want: "8h"},
+		{age: 47 * time.Hour, want: "47h"},
+		{age: 2 * day, want: "2d"},
+		{age: 7*day + 23*time.Hour, want: "7d23h"},
+		{age: 8 * day, want: "8d"},
+		{age: 2*year - day, want: "729d"},
+		{age: 2 * year, want: "2y"},
+		{age: 2*year + 14*day, want: "2y14d"},
+		{age: 8*year + 100*day, want: "8y"},
+		{age: 2562047 * time.Hour, want: "292y"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(ageCompact, tt.age, time.Time{}); got != tt.want {
+			t.Errorf("formatAge(%s) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAgeFullAndAbsolute(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	age := 26*time.Hour + 3*time.Minute + 4*time.Second
+
+	if got := formatAge(ageFull, age, created); got != "26h3m4s" {
+		t.Errorf("full = %q, want 26h3m4s", got)
+	}
+	if got := formatAge(ageAbsolute, age, created); got != "2024-03-01T12:00:00Z" {
+		t.Errorf("absolute = %q, want 2024-03-01T12:00:00Z", got)
+	}
+}
+
+func TestValidateAgeFormat(t *testing.T) {
+	for _, format := range []string{ageCompact, ageFull, ageAbsolute} {
+		if err := validateAgeFormat(format); err != nil {
+			t.Errorf("validateAgeFormat(%q) = %v", format, err)
+		}
+	}
+	if err := validateAgeFormat("relative"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
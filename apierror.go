@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// forbiddenPattern extracts the user and verb from the API server's
+// Forbidden message: `User "alice" cannot list resource "pods" ...`
+var forbiddenPattern = regexp.MustCompile(`User "([^"]*)" cannot (\S+) resource`)
+
+// apiError is an API failure explained in terms of what the user can do
+// about it. It wraps the original error, so apierrors.IsForbidden and
+// friends still recognize it.
+type apiError struct {
+	msg string
+	err error
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func (e *apiError) Unwrap() error {
+	return e.err
+}
+
+// handleAPIError explains Forbidden, Unauthorized and namespace NotFound
+// errors from an API call on resourceKind (e.g. "pods") in namespace (empty
+// for all namespaces or cluster-scoped kinds). Other errors are returned
+// unchanged.
+func handleAPIError(err error, resourceKind, namespace string) error {
+	var status apierrors.APIStatus
+	if err == nil || !errors.As(err, &status) {
+		return err
+	}
+	switch {
+	case apierrors.IsForbidden(err):
+		// The verb is only known from the server's message; listing is what
+		// the subcommands do most
+		verb, subject := "list", ""
+		if m := forbiddenPattern.FindStringSubmatch(status.Status().Message); m != nil {
+			subject, verb = m[1], m[2]
+		}
+		scope := "cluster-wide"
+		if namespace != "" {
+			scope = fmt.Sprintf("in namespace '%s'", namespace)
+		}
+		msg := fmt.Sprintf("forbidden: requires '%s %s' %s", verb, resourceKind, scope)
+		if subject != "" {
+			msg += fmt.Sprintf(", which user %q does not have", subject)
+		}
+		return &apiError{msg: msg, err: err}
+	case apierrors.IsUnauthorized(err):
+		return &apiError{
+			msg: "unauthorized: the API server rejected the credentials, check that the kubeconfig token or certificate has not expired",
+			err: err,
+		}
+	case apierrors.IsNotFound(err):
+		if details := status.Status().Details; details != nil && details.Kind == "namespaces" {
+			return &apiError{msg: fmt.Sprintf("namespace '%s' not found", details.Name), err: err}
+		}
+	}
+	return err
+}
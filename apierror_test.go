@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// serverForbidden returns a Forbidden error with the message format of a
+// real API server
+func serverForbidden(user, verb string) error {
+	return apierrors.NewForbidden(v1.Resource("pods"), "",
+		errors.New(`User "`+user+`" cannot `+verb+` resource "pods" in API group "" in the namespace "foo"`))
+}
+
+func TestHandleAPIError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		kind      string
+		namespace string
+		want      string
+		check     func(error) bool
+	}{
+		{
+			name:      "403 with the server message",
+			err:       serverForbidden("alice", "get"),
+			kind:      "pods",
+			namespace: "foo",
+			want:      `forbidden: requires 'get pods' in namespace 'foo', which user "alice" does not have`,
+			check:     apierrors.IsForbidden,
+		},
+		{
+			name:  "403 cluster-wide without a parsable message",
+			err:   apierrors.NewForbidden(v1.Resource("nodes"), "", errors.New("denied")),
+			kind:  "nodes",
+			want:  "forbidden: requires 'list nodes' cluster-wide",
+			check: apierrors.IsForbidden,
+		},
+		{
+			name:  "401",
+			err:   apierrors.NewUnauthorized("Unauthorized"),
+			kind:  "pods",
+			want:  "unauthorized: the API server rejected the credentials, check that the kubeconfig token or certificate has not expired",
+			check: apierrors.IsUnauthorized,
+		},
+		{
+			name:      "404 namespace",
+			err:       apierrors.NewNotFound(v1.Resource("namespaces"), "foo"),
+			kind:      "pods",
+			namespace: "foo",
+			want:      "namespace 'foo' not found",
+			check:     apierrors.IsNotFound,
+		},
+		{
+			name:      "404 of the resource is unchanged",
+			err:       apierrors.NewNotFound(v1.Resource("pods"), "web"),
+			kind:      "pods",
+			namespace: "foo",
+			want:      `pods "web" not found`,
+			check:     apierrors.IsNotFound,
+		},
+		{
+			name:  "500 is unchanged",
+			err:   apierrors.NewInternalError(errors.New("etcd is down")),
+			kind:  "pods",
+			want:  "Internal error occurred: etcd is down",
+			check: apierrors.IsInternalError,
+		},
+		{
+			name:  "non-API errors are unchanged",
+			err:   context.DeadlineExceeded,
+			kind:  "pods",
+			want:  "context deadline exceeded",
+			check: func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := handleAPIError(tt.err, tt.kind, tt.namespace)
+			if got.Error() != tt.want {
+				t.Errorf("handleAPIError() = %q, want %q", got, tt.want)
+			}
+			if !tt.check(got) {
+				t.Errorf("handleAPIError() = %v no longer matches the original status", got)
+			}
+		})
+	}
+
+	if err := handleAPIError(nil, "pods", ""); err != nil {
+		t.Errorf("handleAPIError(nil) = %v, want nil", err)
+	}
+}
+
+func TestListPodsForbiddenMessage(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, serverForbidden("system:serviceaccount:ci:deployer", "list")
+	})
+
+	_, _, err := listPods(context.Background(), client, nil, metav1.ListOptions{}, podinfo.DefaultConcurrency)
+	want := `forbidden: requires 'list pods' cluster-wide, which user "system:serviceaccount:ci:deployer" does not have`
+	if err == nil || err.Error() != want {
+		t.Errorf("listPods() error = %v, want %q", err, want)
+	}
+}
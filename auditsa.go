@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// Permission is what a service account may do with one resource of an API
+// group, merged across every rule granting it
+type Permission struct {
+	APIGroup string   `json:"apiGroup"`
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+}
+
+// bindsServiceAccount reports whether subjects include the service account
+// saName of namespace. A subject without a namespace is in bindingNamespace,
+// the namespace of a RoleBinding.
+func bindsServiceAccount(subjects []rbacv1.Subject, bindingNamespace, namespace, saName string) bool {
+	for _, subject := range subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind || subject.Name != saName {
+			continue
+		}
+		subjectNamespace := subject.Namespace
+		if subjectNamespace == "" {
+			subjectNamespace = bindingNamespace
+		}
+		if subjectNamespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchGrantedPermissions returns the permissions the service account
+// saName of namespace holds in that namespace: those of the Roles and
+// ClusterRoles its RoleBindings there reference, and those of the
+// ClusterRoles its ClusterRoleBindings reference. Entries are merged per API
+// group and resource and sorted; non-resource URLs are left out. A binding
+// whose role doesn't exist grants nothing and is skipped.
+func FetchGrantedPermissions(ctx context.Context, client kubernetes.Interface, namespace, saName string) ([]Permission, error) {
+	var rules []rbacv1.PolicyRule
+	roleBindings, err := client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "rolebindings", namespace)
+	}
+	for _, binding := range roleBindings.Items {
+		if !bindsServiceAccount(binding.Subjects, binding.Namespace, namespace, saName) {
+			continue
+		}
+		bound, err := roleRules(ctx, client, binding.Namespace, binding.RoleRef)
+		if err != nil {
+			return nil, fmt.Errorf("rolebinding %s/%s: %w", binding.Namespace, binding.Name, err)
+		}
+		rules = append(rules, bound...)
+	}
+
+	clusterRoleBindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "clusterrolebindings", "")
+	}
+	for _, binding := range clusterRoleBindings.Items {
+		if !bindsServiceAccount(binding.Subjects, "", namespace, saName) {
+			continue
+		}
+		bound, err := roleRules(ctx, client, "", binding.RoleRef)
+		if err != nil {
+			return nil, fmt.Errorf("clusterrolebinding %s: %w", binding.Name, err)
+		}
+		rules = append(rules, bound...)
+	}
+	return mergePermissions(rules), nil
+}
+
+// roleRules fetches the rules of the Role or ClusterRole ref points to. A
+// Role is looked up in namespace.
+func roleRules(ctx context.Context, client kubernetes.Interface, namespace string, ref rbacv1.RoleRef) ([]rbacv1.PolicyRule, error) {
+	var rules []rbacv1.PolicyRule
+	var err error
+	switch ref.Kind {
+	case "Role":
+		var role *rbacv1.Role
+		if role, err = client.RbacV1().Roles(namespace).Get(ctx, ref.Name, metav1.GetOptions{}); err == nil {
+			rules = role.Rules
+		}
+	case "ClusterRole":
+		var role *rbacv1.ClusterRole
+		if role, err = client.RbacV1().ClusterRoles().Get(ctx, ref.Name, metav1.GetOptions{}); err == nil {
+			rules = role.Rules
+		}
+	default:
+		return nil, fmt.Errorf("unsupported roleRef kind %q", ref.Kind)
+	}
+	if apierrors.IsNotFound(err) {
+		logging.FromContext(ctx).Debug("skipping a binding to a missing role", "kind", ref.Kind, "name", ref.Name, "namespace", namespace)
+		return nil, nil
+	}
+	return rules, err
+}
+
+// mergePermissions expands rules into one Permission per API group and
+// resource, with the union of their verbs, sorted by API group, resource
+// and verb
+func mergePermissions(rules []rbacv1.PolicyRule) []Permission {
+	type key struct{ group, resource string }
+	verbs := make(map[key]map[string]bool)
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				k := key{group, resource}
+				if verbs[k] == nil {
+					verbs[k] = make(map[string]bool)
+				}
+				for _, verb := range rule.Verbs {
+					verbs[k][verb] = true
+				}
+			}
+		}
+	}
+
+	permissions := []Permission{}
+	for k, set := range verbs {
+		p := Permission{APIGroup: k.group, Resource: k.resource}
+		for verb := range set {
+			p.Verbs = append(p.Verbs, verb)
+		}
+		sort.Strings(p.Verbs)
+		permissions = append(permissions, p)
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		if permissions[i].APIGroup != permissions[j].APIGroup {
+			return permissions[i].APIGroup < permissions[j].APIGroup
+		}
+		return permissions[i].Resource < permissions[j].Resource
+	})
+	return permissions
+}
+
+// filterPermissions keeps the permissions covering resource: the resource
+// itself, its subresources such as pods/log, and the * wildcard. The match
+// is case-insensitive.
+func filterPermissions(permissions []Permission, resource string) []Permission {
+	resource = strings.ToLower(resource)
+	return slices.DeleteFunc(slices.Clone(permissions), func(p Permission) bool {
+		r := strings.ToLower(p.Resource)
+		return r != rbacv1.ResourceAll && r != resource && !strings.HasPrefix(r, resource+"/")
+	})
+}
+
+// printPermissionTable prints one row per permission. The core API group
+// is shown as "core".
+func printPermissionTable(out io.Writer, permissions []Permission) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "APIGROUP\tRESOURCE\tVERBS")
+	for _, p := range permissions {
+		group := p.APIGroup
+		if group == "" {
+			group = "core"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", group, p.Resource, strings.Join(p.Verbs, ","))
+	}
+	return w.Flush()
+}
+
+// runAuditSA implements the audit-sa subcommand
+func runAuditSA(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("audit-sa", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "default", "namespace of the service account")
+	saName := fs.String("service-account", "", "name of the service account to audit (required)")
+	resourceFilter := fs.String("resource-filter", "", "only show the permissions covering this resource, e.g. pods or deployments")
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	if *saName == "" {
+		return errors.New("--service-account is required")
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(logging.NewContext(context.Background(), clientOpts.logger()), clientOpts.Timeout)
+		defer cancel()
+
+		permissions, err := FetchGrantedPermissions(ctx, client, *namespace, *saName)
+		if err != nil {
+			return fmt.Errorf("error fetching the permissions of service account %s/%s: %w", *namespace, *saName, timeoutError(err, clientOpts.Timeout))
+		}
+		if *resourceFilter != "" {
+			permissions = filterPermissions(permissions, *resourceFilter)
+		}
+		if *output != outputTable {
+			return printStructured(out, permissions, *output)
+		}
+		if len(permissions) == 0 {
+			fmt.Fprintf(out, "No permissions found for service account %s/%s\n", *namespace, *saName)
+			return nil
+		}
+		return printPermissionTable(out, permissions)
+	})
+}
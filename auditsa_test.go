@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newRBACClient returns a fake client where the service account ci/deployer
+// is bound to:
+//   - the Role ci/deployer (deployments, pods) through a RoleBinding,
+//   - the ClusterRole pod-reader (pods, pods/log) through a RoleBinding in ci,
+//   - the ClusterRole pod-reader and node-viewer through ClusterRoleBindings.
+//
+// Bindings of other subjects and a RoleBinding to a missing Role must grant
+// it nothing.
+func newRBACClient() *fake.Clientset {
+	deployer := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "ci"}
+	other := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "prod"}
+	roleRef := func(kind, name string) rbacv1.RoleRef {
+		return rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: kind, Name: name}
+	}
+	return fake.NewSimpleClientset(
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "deployer"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "update", "patch"}},
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"delete"}},
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-reader"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "list", "watch"}},
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-viewer"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}},
+				{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "deployer"},
+			Subjects:   []rbacv1.Subject{deployer},
+			RoleRef:    roleRef("Role", "deployer"),
+		},
+		&rbacv1.RoleBinding{
+			// A service account subject without a namespace is in the
+			// namespace of the binding
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "read-pods"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "deployer"}},
+			RoleRef:    roleRef("ClusterRole", "pod-reader"),
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "dangling"},
+			Subjects:   []rbacv1.Subject{deployer},
+			RoleRef:    roleRef("Role", "deleted"),
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "read-pods"},
+			Subjects:   []rbacv1.Subject{deployer},
+			RoleRef:    roleRef("ClusterRole", "pod-reader"),
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "view-nodes"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}, deployer},
+			RoleRef:    roleRef("ClusterRole", "node-viewer"),
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "prod-admin"},
+			Subjects:   []rbacv1.Subject{other},
+			RoleRef:    roleRef("ClusterRole", "admin"),
+		},
+	)
+}
+
+func TestFetchGrantedPermissions(t *testing.T) {
+	permissions, err := FetchGrantedPermissions(context.Background(), newRBACClient(), "ci", "deployer")
+	if err != nil {
+		t.Fatalf("FetchGrantedPermissions() error: %v", err)
+	}
+	want := []Permission{
+		{APIGroup: "", Resource: "nodes", Verbs: []string{"get", "list"}},
+		{APIGroup: "", Resource: "pods", Verbs: []string{"delete", "get", "list", "watch"}},
+		{APIGroup: "", Resource: "pods/log", Verbs: []string{"get", "list", "watch"}},
+		{APIGroup: "apps", Resource: "deployments", Verbs: []string{"get", "patch", "update"}},
+	}
+	if !reflect.DeepEqual(permissions, want) {
+		t.Errorf("FetchGrantedPermissions() = %+v, want %+v", permissions, want)
+	}
+}
+
+func TestFetchGrantedPermissionsUnbound(t *testing.T) {
+	permissions, err := FetchGrantedPermissions(context.Background(), newRBACClient(), "ci", "nobody")
+	if err != nil {
+		t.Fatalf("FetchGrantedPermissions() error: %v", err)
+	}
+	if len(permissions) != 0 {
+		t.Errorf("FetchGrantedPermissions(nobody) = %+v, want none", permissions)
+	}
+}
+
+func TestFilterPermissions(t *testing.T) {
+	permissions := []Permission{
+		{APIGroup: "", Resource: "pods", Verbs: []string{"get"}},
+		{APIGroup: "", Resource: "pods/log", Verbs: []string{"get"}},
+		{APIGroup: "", Resource: "podtemplates", Verbs: []string{"get"}},
+		{APIGroup: "*", Resource: "*", Verbs: []string{"*"}},
+		{APIGroup: "apps", Resource: "deployments", Verbs: []string{"get"}},
+	}
+	var got []string
+	for _, p := range filterPermissions(permissions, "Pods") {
+		got = append(got, p.Resource)
+	}
+	if want := []string{"pods", "pods/log", "*"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("filterPermissions(Pods) = %q, want %q", got, want)
+	}
+	if len(permissions) != 5 {
+		t.Errorf("filterPermissions modified its input: %+v", permissions)
+	}
+}
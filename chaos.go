@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// PodChaosSimulator deletes a random pod matching a selector, at most one
+// per Step, to check that workloads survive losing pods
+type PodChaosSimulator struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Selector  string
+	// Probability is the chance, from 0 to 1, that a Step deletes the pod
+	// it picked
+	Probability float64
+	// GracePeriod is the termination grace period in seconds, negative to
+	// keep the pod's own
+	GracePeriod int64
+	// DryRun only prints the pods that would be deleted
+	DryRun bool
+	// Rand picks the pods and rolls the dice
+	Rand *rand.Rand
+	Out  io.Writer
+}
+
+// newChaosRand returns a *rand.Rand whose ChaCha8 source is seeded from
+// crypto/rand, so runs can't be predicted from the time they started
+func newChaosRand() (*rand.Rand, error) {
+	var seed [32]byte
+	if _, err := cryptorand.Read(seed[:]); err != nil {
+		return nil, fmt.Errorf("seeding the random source: %w", err)
+	}
+	return rand.New(rand.NewChaCha8(seed)), nil
+}
+
+// SelectRandomPod returns a pod of pods chosen uniformly with rng, or nil
+// when pods is empty
+func SelectRandomPod(rng *rand.Rand, pods []v1.Pod) *v1.Pod {
+	if len(pods) == 0 {
+		return nil
+	}
+	return &pods[rng.IntN(len(pods))]
+}
+
+// DeletePodGracefully deletes pod with gracePeriod seconds to terminate, or
+// with its own period when gracePeriod is negative. The deletion is
+// conditional on the pod's UID so a replacement created under the same name
+// in the meantime is left alone.
+func DeletePodGracefully(ctx context.Context, client kubernetes.Interface, pod *v1.Pod, gracePeriod int64) error {
+	opts := metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(pod.UID))}
+	if gracePeriod >= 0 {
+		opts.GracePeriodSeconds = &gracePeriod
+	}
+	if err := client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, opts); err != nil {
+		return handleAPIError(err, "pods", pod.Namespace)
+	}
+	return nil
+}
+
+// chaosCandidates returns the running pods that are not already being
+// deleted
+func chaosCandidates(pods []v1.Pod) []v1.Pod {
+	var candidates []v1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning && pod.DeletionTimestamp == nil {
+			candidates = append(candidates, pod)
+		}
+	}
+	return candidates
+}
+
+// Step picks a random running pod and deletes it with Probability. It
+// returns the deleted pod, or nil when none was.
+func (s *PodChaosSimulator) Step(ctx context.Context) (*v1.Pod, error) {
+	logger := logging.FromContext(ctx)
+	list, err := s.Client.CoreV1().Pods(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.Selector})
+	if err != nil {
+		return nil, handleAPIError(err, "pods", s.Namespace)
+	}
+	pod := SelectRandomPod(s.Rand, chaosCandidates(list.Items))
+	if pod == nil {
+		logger.Debug("no running pod matches the selector", "namespace", s.Namespace, "selector", s.Selector)
+		return nil, nil
+	}
+
+	roll := s.Rand.Float64()
+	triggered := roll < s.Probability
+	logger.Debug("chaos decision", "pod", pod.Namespace+"/"+pod.Name, "roll", roll, "probability", s.Probability, "delete", triggered)
+	if !triggered {
+		return nil, nil
+	}
+	if s.DryRun {
+		fmt.Fprintf(s.Out, "%s pod %s/%s would be deleted (dry run)\n", time.Now().Format(time.TimeOnly), pod.Namespace, pod.Name)
+		return pod, nil
+	}
+	if err := DeletePodGracefully(ctx, s.Client, pod, s.GracePeriod); err != nil {
+		return nil, fmt.Errorf("deleting pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	fmt.Fprintf(s.Out, "%s pod %s/%s deleted\n", time.Now().Format(time.TimeOnly), pod.Namespace, pod.Name)
+	return pod, nil
+}
+
+// Run calls Step right away and then every interval until ctx is
+// cancelled. A failed Step is logged as a warning and the next one still
+// runs; timeout bounds the API calls of each Step.
+func (s *PodChaosSimulator) Run(ctx context.Context, interval, timeout time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		stepCtx, cancel := requestContext(ctx, timeout)
+		_, err := s.Step(stepCtx)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			logWarnings(ctx, []error{timeoutError(err, timeout)})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// runChaos implements the chaos subcommand
+func runChaos(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("chaos", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "default", "namespace to delete pods in")
+	selector := fs.String("selector", "", "label selector of the pods that may be deleted, e.g. app=web (required)")
+	interval := fs.Duration("interval", time.Minute, "how often to pick a pod and roll the dice")
+	probability := fs.Float64("probability", 0.5, "chance from 0.0 to 1.0 that the picked pod is deleted")
+	gracePeriod := fs.Int64("grace-period", -1, "seconds each pod gets to terminate gracefully; negative keeps the pod's own period")
+	dryRun := fs.Bool("dry-run", false, "only print the pods that would be deleted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(outputText); err != nil {
+		return err
+	}
+	switch {
+	case *selector == "":
+		return errors.New("--selector is required, so chaos never targets every pod of the namespace")
+	case *interval <= 0:
+		return fmt.Errorf("--interval must be positive, got %s", *interval)
+	case *probability < 0 || *probability > 1:
+		return fmt.Errorf("--probability must be between 0.0 and 1.0, got %g", *probability)
+	}
+	if _, err := labels.Parse(*selector); err != nil {
+		return fmt.Errorf("invalid --selector: %w", err)
+	}
+
+	client, _, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+	rng, err := newChaosRand()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(logging.NewContext(context.Background(), clientOpts.logger()), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	simulator := &PodChaosSimulator{
+		Client:      client,
+		Namespace:   *namespace,
+		Selector:    *selector,
+		Probability: *probability,
+		GracePeriod: *gracePeriod,
+		DryRun:      *dryRun,
+		Rand:        rng,
+		Out:         os.Stdout,
+	}
+	return simulator.Run(ctx, *interval, clientOpts.Timeout)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// chaosPod returns a pod of the web app in default
+func chaosPod(name string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name + "-uid"), Labels: map[string]string{"app": "web"}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestSelectRandomPod(t *testing.T) {
+	if SelectRandomPod(rand.New(rand.NewPCG(1, 2)), nil) != nil {
+		t.Error("SelectRandomPod(nil) should return nil")
+	}
+
+	pods := []v1.Pod{*chaosPod("a", v1.PodRunning), *chaosPod("b", v1.PodRunning), *chaosPod("c", v1.PodRunning)}
+	pick := func(seed uint64) []string {
+		rng := rand.New(rand.NewPCG(seed, 0))
+		var names []string
+		for range 10 {
+			names = append(names, SelectRandomPod(rng, pods).Name)
+		}
+		return names
+	}
+	first := strings.Join(pick(7), ",")
+	if again := strings.Join(pick(7), ","); again != first {
+		t.Errorf("the same seed picked %s, then %s", first, again)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if !strings.Contains(first, name) {
+			t.Errorf("10 picks %s never chose %s", first, name)
+		}
+	}
+}
+
+func TestDeletePodGracefully(t *testing.T) {
+	pod := chaosPod("web-1", v1.PodRunning)
+	client := fake.NewSimpleClientset(pod)
+	var opts []metav1.DeleteOptions
+	client.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts = append(opts, action.(k8stesting.DeleteActionImpl).DeleteOptions)
+		return false, nil, nil
+	})
+
+	if err := DeletePodGracefully(context.Background(), client, pod, 5); err != nil {
+		t.Fatalf("DeletePodGracefully() error = %v", err)
+	}
+	if len(opts) != 1 || opts[0].GracePeriodSeconds == nil || *opts[0].GracePeriodSeconds != 5 {
+		t.Fatalf("delete options = %+v, want a 5 second grace period", opts)
+	}
+	if opts[0].Preconditions == nil || *opts[0].Preconditions.UID != "web-1-uid" {
+		t.Errorf("preconditions = %+v, want the pod's UID", opts[0].Preconditions)
+	}
+
+	// A negative grace period keeps the pod's own
+	other := chaosPod("web-2", v1.PodRunning)
+	client.Tracker().Add(other)
+	if err := DeletePodGracefully(context.Background(), client, other, -1); err != nil {
+		t.Fatal(err)
+	}
+	if opts[1].GracePeriodSeconds != nil {
+		t.Errorf("grace period = %d, want the pod's own", *opts[1].GracePeriodSeconds)
+	}
+}
+
+func TestPodChaosSimulatorStep(t *testing.T) {
+	newSimulator := func(probability float64, dryRun bool) (*PodChaosSimulator, *fake.Clientset, *bytes.Buffer) {
+		terminating := chaosPod("web-terminating", v1.PodRunning)
+		terminating.DeletionTimestamp = &metav1.Time{}
+		client := fake.NewSimpleClientset(
+			chaosPod("web-1", v1.PodRunning),
+			chaosPod("web-pending", v1.PodPending),
+			terminating,
+			&v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db", Labels: map[string]string{"app": "db"}}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		)
+		var out bytes.Buffer
+		return &PodChaosSimulator{
+			Client:      client,
+			Namespace:   "default",
+			Selector:    "app=web",
+			Probability: probability,
+			GracePeriod: -1,
+			DryRun:      dryRun,
+			Rand:        rand.New(rand.NewPCG(1, 2)),
+			Out:         &out,
+		}, client, &out
+	}
+	remaining := func(client *fake.Clientset) int {
+		pods, _ := client.CoreV1().Pods("default").List(context.Background(), metav1.ListOptions{})
+		return len(pods.Items)
+	}
+
+	// Only web-1 is running, matching and not already terminating
+	simulator, client, out := newSimulator(1, false)
+	deleted, err := simulator.Step(context.Background())
+	if err != nil {
+		t.Fatalf("Step() error = %v", err)
+	}
+	if deleted == nil || deleted.Name != "web-1" || remaining(client) != 3 {
+		t.Errorf("Step() deleted %v, leaving %d pods; want web-1 deleted", deleted, remaining(client))
+	}
+	if !strings.HasSuffix(out.String(), "pod default/web-1 deleted\n") {
+		t.Errorf("output = %q", out.String())
+	}
+	if deleted, _ := simulator.Step(context.Background()); deleted != nil {
+		t.Errorf("Step() without candidates deleted %s", deleted.Name)
+	}
+
+	simulator, _, _ = newSimulator(0, false)
+	for range 5 {
+		if deleted, _ := simulator.Step(context.Background()); deleted != nil {
+			t.Fatalf("Step() with probability 0 deleted %s", deleted.Name)
+		}
+	}
+
+	simulator, client, out = newSimulator(1, true)
+	if deleted, _ := simulator.Step(context.Background()); deleted == nil || remaining(client) != 4 {
+		t.Errorf("dry run Step() = %v, leaving %d pods; want web-1 picked and nothing deleted", deleted, remaining(client))
+	}
+	if !strings.HasSuffix(out.String(), "pod default/web-1 would be deleted (dry run)\n") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+
+	// A seeded source makes the dice rolls repeatable
+	rolls := func() []bool {
+		simulator, _, _ := newSimulator(0.5, true)
+		var triggered []bool
+		for range 8 {
+			deleted, _ := simulator.Step(context.Background())
+			triggered = append(triggered, deleted != nil)
+		}
+		return triggered
+	}
+	if first, again := rolls(), rolls(); !slices.Equal(first, again) {
+		t.Errorf("seeded rolls %v, then %v", first, again)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"Kubernetes_Programming/pkg/config"
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// Wire formats accepted by --content-type
+const (
+	contentTypeProtobuf = "protobuf"
+	contentTypeJSON     = "json"
+)
+
+// clientOptions holds the flags that control how the client is created and
+// how long its requests may take
+type clientOptions struct {
+	config.Options
+	Verbose bool
+	// ContentType is the wire format: protobuf (the default) or json
+	ContentType string
+	Log         logging.Options
+	// Logger is built from Log by setupLogger once the flags are parsed
+	Logger *slog.Logger
+}
+
+// AddFlags registers the config flags, --content-type and the logging flags
+// on fs
+func (o *clientOptions) AddFlags(fs *flag.FlagSet) {
+	o.Options.AddFlags(fs)
+	o.Log.AddFlags(fs)
+	o.ContentType = contentTypeProtobuf
+	fs.Func("content-type", "wire format for API responses: protobuf (faster to decode, falls back to JSON for types the server cannot encode) or json (default protobuf)", func(value string) error {
+		switch value {
+		case contentTypeProtobuf, contentTypeJSON:
+			o.ContentType = value
+			return nil
+		}
+		return fmt.Errorf("unknown content type %q (want protobuf or json)", value)
+	})
+}
+
+// setupLogger builds Logger from the logging flags, logging JSON by default
+// when the command prints JSON
+func (o *clientOptions) setupLogger(output string) error {
+	defaultFormat := logging.FormatText
+	if output == outputJSON {
+		defaultFormat = logging.FormatJSON
+	}
+	logger, err := o.Log.NewLogger(os.Stderr, defaultFormat)
+	if err != nil {
+		return err
+	}
+	o.Logger = logger
+	return nil
+}
+
+// logger returns Logger, or a text logger on stderr when the command failed
+// before setupLogger ran (e.g. on an invalid --log-level)
+func (o *clientOptions) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, nil))
+}
+
+// applyContentType sets the wire format of restConfig. Protobuf is requested
+// with JSON as a fallback in the Accept header, so servers (or aggregated
+// APIs and CRDs) that reject protobuf for a type answer in JSON instead of
+// failing the request.
+func applyContentType(restConfig *rest.Config, contentType string) {
+	switch contentType {
+	case contentTypeJSON:
+		restConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		restConfig.ContentType = runtime.ContentTypeJSON
+	case contentTypeProtobuf:
+		restConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+		restConfig.ContentType = runtime.ContentTypeProtobuf
+	}
+}
+
+// requestContext returns a context bounded by timeout, or without a deadline
+// when timeout is 0
+func requestContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return config.WithTimeout(parent, timeout)
+}
+
+// timeoutError returns err, replaced by an "API call timed out after X
+// seconds" error when it was caused by the timeout deadline rather than the
+// API server
+func timeoutError(err error, timeout time.Duration) error {
+	return config.WrapTimeout(err, timeout)
+}
+
+// timeoutErrors applies timeoutError to each of errs
+func timeoutErrors(errs []error, timeout time.Duration) []error {
+	for i, err := range errs {
+		errs[i] = timeoutError(err, timeout)
+	}
+	return errs
+}
+
+// createKubernetesClient creates and returns a Kubernetes client together
+// with the config it was built from
+func createKubernetesClient(opts clientOptions) (*kubernetes.Clientset, *rest.Config, error) {
+	resolved, err := config.Build(opts.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applyContentType(resolved.Config, opts.ContentType)
+
+	if opts.Verbose {
+		if resolved.Context != "" {
+			opts.logger().Info("using kubeconfig context", "context", resolved.Context, "server", resolved.Config.Host, "contentType", opts.ContentType)
+		} else {
+			opts.logger().Info("using in-cluster config", "server", resolved.Config.Host, "contentType", opts.ContentType)
+		}
+	}
+
+	client, err := kubernetes.NewForConfig(resolved.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return client, resolved.Config, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRequestContext(t *testing.T) {
+	ctx, cancel := requestContext(context.Background(), 0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("a zero timeout should not set a deadline")
+	}
+
+	ctx, cancel = requestContext(context.Background(), time.Minute)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("deadline = %v, %t, want within a minute", deadline, ok)
+	}
+}
+
+func TestTimeoutError(t *testing.T) {
+	// The fake clientset honors neither deadlines nor latency, so the
+	// reactor fails the list the way an expired request context does
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("Get \"https://example/api/v1/pods\": %w", context.DeadlineExceeded)
+	})
+	_, err := client.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+
+	got := timeoutError(err, 5*time.Second)
+	if got.Error() != "API call timed out after 5 seconds" {
+		t.Errorf("timeoutError() = %q, want %q", got, "API call timed out after 5 seconds")
+	}
+	if !errors.Is(got, context.DeadlineExceeded) {
+		t.Error("timeoutError() should wrap the deadline error")
+	}
+
+	forbidden := apierrors.NewForbidden(v1.Resource("pods"), "", nil)
+	if got := timeoutError(forbidden, 5*time.Second); got != forbidden {
+		t.Errorf("timeoutError() = %v, want API errors unchanged", got)
+	}
+	if got := timeoutError(nil, 5*time.Second); got != nil {
+		t.Errorf("timeoutError(nil) = %v, want nil", got)
+	}
+}
+
+func TestContentTypeFlag(t *testing.T) {
+	var opts clientOptions
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.AddFlags(fs)
+	if opts.ContentType != contentTypeProtobuf {
+		t.Errorf("default content type = %q, want protobuf", opts.ContentType)
+	}
+
+	restConfig := &rest.Config{}
+	applyContentType(restConfig, opts.ContentType)
+	if restConfig.AcceptContentTypes != "application/vnd.kubernetes.protobuf,application/json" {
+		t.Errorf("AcceptContentTypes = %q, want protobuf with a JSON fallback", restConfig.AcceptContentTypes)
+	}
+	if restConfig.ContentType != "application/vnd.kubernetes.protobuf" {
+		t.Errorf("ContentType = %q, want protobuf", restConfig.ContentType)
+	}
+
+	if err := fs.Parse([]string{"--content-type", "json"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	applyContentType(restConfig, opts.ContentType)
+	if restConfig.AcceptContentTypes != "application/json" || restConfig.ContentType != "application/json" {
+		t.Errorf("content types = %q/%q, want JSON only", restConfig.AcceptContentTypes, restConfig.ContentType)
+	}
+	if err := fs.Parse([]string{"--content-type", "xml"}); err == nil {
+		t.Error("expected an unknown --content-type to be rejected")
+	}
+}
+
+// benchmarkDecodePodList decodes a list of 1000 pods encoded as mediaType
+func benchmarkDecodePodList(b *testing.B, mediaType string) {
+	info, ok := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), mediaType)
+	if !ok {
+		b.Fatalf("no serializer for %s", mediaType)
+	}
+	list := &v1.PodList{}
+	for i := range 1000 {
+		pod := newContainersPod("pod-"+strconv.Itoa(i), "app", "sidecar")
+		pod.Labels = map[string]string{"app": "web", "pod-template-hash": "5d78c9869d"}
+		pod.Status.Phase = v1.PodRunning
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "app", Ready: true}, {Name: "sidecar", Ready: true}}
+		list.Items = append(list.Items, *pod)
+	}
+	encoder := scheme.Codecs.EncoderForVersion(info.Serializer, v1.SchemeGroupVersion)
+	data, err := runtime.Encode(encoder, list)
+	if err != nil {
+		b.Fatalf("encode failed: %v", err)
+	}
+	decoder := scheme.Codecs.DecoderToVersion(info.Serializer, v1.SchemeGroupVersion)
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for range b.N {
+		if _, err := runtime.Decode(decoder, data); err != nil {
+			b.Fatalf("decode failed: %v", err)
+		}
+	}
+}
+
+// Run with go test -bench DecodePodList -benchmem to compare the formats
+func BenchmarkDecodePodListJSON(b *testing.B) {
+	benchmarkDecodePodList(b, runtime.ContentTypeJSON)
+}
+
+func BenchmarkDecodePodListProtobuf(b *testing.B) {
+	benchmarkDecodePodList(b, runtime.ContentTypeProtobuf)
+}
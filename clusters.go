@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"Kubernetes_Programming/pkg/config"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// cluster is a cluster to list pods from
+type cluster struct {
+	// Name is the kubeconfig context, empty when a single cluster is listed
+	Name   string
+	Client kubernetes.Interface
+	Config *rest.Config
+}
+
+// clusterPods holds the pods listed from one cluster
+type clusterPods struct {
+	Cluster cluster
+	Pods    []v1.Pod
+}
+
+// resolveClusters returns the single cluster selected by opts, or one
+// cluster per kubeconfig context when allContexts is set. Contexts whose
+// client cannot be built are returned as warnings.
+func resolveClusters(opts clientOptions, allContexts bool) ([]cluster, []error, error) {
+	if !allContexts {
+		client, restConfig, err := createKubernetesClient(opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return []cluster{{Client: client, Config: restConfig}}, nil, nil
+	}
+
+	contexts, err := config.Contexts(opts.Options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var clusters []cluster
+	var warnings []error
+	for _, name := range contexts {
+		contextOpts := opts
+		contextOpts.Context = name
+		client, restConfig, err := createKubernetesClient(contextOpts)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("context %q: %w", name, err))
+			continue
+		}
+		clusters = append(clusters, cluster{Name: name, Client: client, Config: restConfig})
+	}
+	return clusters, warnings, nil
+}
+
+// listClusters lists pods from every cluster concurrently, with at most
+// concurrency namespaces of each cluster listed at once. Results keep the
+// order of clusters; a cluster that fails is reported as a warning and
+// left out so the others are still shown. It only fails when no cluster
+// could be listed.
+func listClusters(ctx context.Context, clusters []cluster, namespaces []string, opts metav1.ListOptions, concurrency int) ([]clusterPods, []error, error) {
+	type result struct {
+		pods     []v1.Pod
+		warnings []error
+		err      error
+	}
+	results := make([]result, len(clusters))
+
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pods, warnings, err := listPods(ctx, clusters[i].Client, namespaces, opts, concurrency)
+			results[i] = result{pods: pods, warnings: warnings, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	var listed []clusterPods
+	var warnings []error
+	var lastErr error
+	for i, r := range results {
+		prefix := func(err error) error {
+			if clusters[i].Name == "" {
+				return err
+			}
+			return fmt.Errorf("context %q: %w", clusters[i].Name, err)
+		}
+		for _, w := range r.warnings {
+			warnings = append(warnings, prefix(w))
+		}
+		if r.err != nil {
+			lastErr = prefix(r.err)
+			if len(clusters) > 1 {
+				warnings = append(warnings, prefix(fmt.Errorf("failed to list pods: %w", r.err)))
+			}
+			continue
+		}
+		listed = append(listed, clusterPods{Cluster: clusters[i], Pods: r.pods})
+	}
+	if len(listed) == 0 && lastErr != nil {
+		if len(clusters) > 1 {
+			return nil, warnings, fmt.Errorf("no cluster could be listed")
+		}
+		return nil, warnings, lastErr
+	}
+	return listed, warnings, nil
+}
+
+// listPods lists pods with a podinfo.Lister, listing up to concurrency
+// namespaces at once and explaining RBAC and missing namespace errors with
+// handleAPIError
+func listPods(ctx context.Context, client kubernetes.Interface, namespaces []string, opts metav1.ListOptions, concurrency int) ([]v1.Pod, []error, error) {
+	lister := podinfo.NewLister(client)
+	lister.Concurrency = concurrency
+	lister.APIError = func(err error, namespace string) error {
+		return handleAPIError(err, "pods", namespace)
+	}
+	return lister.List(ctx, namespaces, opts)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"Kubernetes_Programming/pkg/config"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+const multiContextKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: admin
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+- name: broken
+  context:
+    cluster: missing-cluster
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+func TestResolveAllContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(multiContextKubeconfig), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	clusters, warnings, err := resolveClusters(clientOptions{Options: config.Options{Kubeconfig: path}}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hosts := make(map[string]string)
+	for _, c := range clusters {
+		hosts[c.Name] = c.Config.Host
+	}
+	if len(hosts) != 2 || hosts["dev"] != "https://dev.example.com" || hosts["prod"] != "https://prod.example.com" {
+		t.Errorf("clusters = %v, want dev and prod", hosts)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), `context "broken"`) {
+		t.Errorf("warnings = %v, want one for the broken context", warnings)
+	}
+}
+
+func TestListClusters(t *testing.T) {
+	failing := fake.NewSimpleClientset()
+	failing.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	clusters := []cluster{
+		{Name: "dev", Client: fake.NewSimpleClientset(newTestPod("default", "web"))},
+		{Name: "staging", Client: failing},
+		{Name: "prod", Client: fake.NewSimpleClientset(newTestPod("default", "api"), newTestPod("default", "db"))},
+	}
+
+	listed, warnings, err := listClusters(context.Background(), clusters, nil, metav1.ListOptions{}, podinfo.DefaultConcurrency)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Cluster.Name != "dev" || listed[1].Cluster.Name != "prod" {
+		t.Fatalf("listed %d clusters, want dev and prod in order", len(listed))
+	}
+	if len(listed[0].Pods) != 1 || len(listed[1].Pods) != 2 {
+		t.Errorf("got %d and %d pods, want 1 and 2", len(listed[0].Pods), len(listed[1].Pods))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), `context "staging"`) {
+		t.Errorf("warnings = %v, want one for staging", warnings)
+	}
+
+	if _, _, err := listClusters(context.Background(), []cluster{{Client: failing}}, nil, metav1.ListOptions{}, podinfo.DefaultConcurrency); err == nil {
+		t.Error("expected an error when the only cluster fails")
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"Kubernetes_Programming/api/v1beta1"
+)
+
+// ArgsAnnotation keeps the v1beta1 Args of an At stored as v1alpha1 when
+// joining them into Command loses information, e.g. an argument holding a
+// space, so converting back to v1beta1 restores them.
+const ArgsAnnotation = "cnat.programming-kubernetes.info/v1beta1-args"
+
+var _ conversion.Convertible = &At{}
+
+// ConvertTo converts this At to the v1beta1 hub version. Command is split on
+// spaces into Args, the way the controller runs it.
+func (src *At) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.At)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 At but got %T", dstRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = v1beta1.AtSpec{
+		RunAt:           src.Spec.Schedule,
+		Args:            splitCommand(src.Spec.Command),
+		Recurring:       src.Spec.Recurring,
+		MaxRetries:      src.Spec.MaxRetries,
+		PodTemplateSpec: src.Spec.PodTemplateSpec.DeepCopy(),
+	}
+	if saved, ok := dst.Annotations[ArgsAnnotation]; ok {
+		var args []string
+		if err := json.Unmarshal([]byte(saved), &args); err != nil {
+			return fmt.Errorf("decoding annotation %s: %w", ArgsAnnotation, err)
+		}
+		// The annotation is stale once Command was edited as v1alpha1
+		if strings.Join(args, " ") == src.Spec.Command {
+			dst.Spec.Args = args
+		}
+		delete(dst.Annotations, ArgsAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	dst.Status = v1beta1.AtStatus{
+		Phase:       src.Status.Phase,
+		LastRunTime: src.Status.LastRunTime.DeepCopy(),
+		Conditions:  slices.Clone(src.Status.Conditions),
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub version to this At. Args are joined
+// with spaces into Command.
+func (dst *At) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.At)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 At but got %T", srcRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = AtSpec{
+		Schedule:        src.Spec.RunAt,
+		Command:         strings.Join(src.Spec.Args, " "),
+		Recurring:       src.Spec.Recurring,
+		MaxRetries:      src.Spec.MaxRetries,
+		PodTemplateSpec: src.Spec.PodTemplateSpec.DeepCopy(),
+	}
+	if !slices.Equal(splitCommand(dst.Spec.Command), src.Spec.Args) {
+		saved, err := json.Marshal(src.Spec.Args)
+		if err != nil {
+			return fmt.Errorf("encoding annotation %s: %w", ArgsAnnotation, err)
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[ArgsAnnotation] = string(saved)
+	}
+	dst.Status = AtStatus{
+		Phase:       src.Status.Phase,
+		LastRunTime: src.Status.LastRunTime.DeepCopy(),
+		Conditions:  slices.Clone(src.Status.Conditions),
+	}
+	return nil
+}
+
+// splitCommand splits a v1alpha1 Command into v1beta1 Args, nil for an empty
+// command
+func splitCommand(command string) []string {
+	if command == "" {
+		return nil
+	}
+	return strings.Split(command, " ")
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"Kubernetes_Programming/api/v1beta1"
+)
+
+// alphaAt returns a v1alpha1 At with every field set
+func alphaAt() *At {
+	lastRun := metav1.NewTime(time.Date(2026, 7, 3, 2, 0, 0, 0, time.UTC))
+	at := &At{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nightly",
+			Namespace:   "default",
+			Labels:      map[string]string{"team": "ops"},
+			Annotations: map[string]string{PauseAnnotation: "true"},
+		},
+		Spec: AtSpec{
+			Schedule:   "0 2 * * *",
+			Command:    "echo hello world",
+			Recurring:  true,
+			MaxRetries: 3,
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Name: "job", Image: "alpine"}},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			},
+		},
+		Status: AtStatus{Phase: PhaseDone, LastRunTime: &lastRun},
+	}
+	meta.SetStatusCondition(&at.Status.Conditions, metav1.Condition{
+		Type: ConditionReady, Status: metav1.ConditionTrue, Reason: "Succeeded", LastTransitionTime: lastRun,
+	})
+	return at
+}
+
+func TestConvertToHub(t *testing.T) {
+	src := alphaAt()
+	dst := &v1beta1.At{}
+	if err := src.ConvertTo(dst); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+
+	if dst.Spec.RunAt != src.Spec.Schedule {
+		t.Errorf("RunAt = %q, want %q", dst.Spec.RunAt, src.Spec.Schedule)
+	}
+	if want := []string{"echo", "hello", "world"}; !reflect.DeepEqual(dst.Spec.Args, want) {
+		t.Errorf("Args = %q, want %q", dst.Spec.Args, want)
+	}
+	if dst.Spec.Recurring != src.Spec.Recurring || dst.Spec.MaxRetries != src.Spec.MaxRetries {
+		t.Errorf("Recurring, MaxRetries = %t, %d, want %t, %d",
+			dst.Spec.Recurring, dst.Spec.MaxRetries, src.Spec.Recurring, src.Spec.MaxRetries)
+	}
+	if !reflect.DeepEqual(dst.Spec.PodTemplateSpec, src.Spec.PodTemplateSpec) {
+		t.Errorf("PodTemplateSpec = %+v, want %+v", dst.Spec.PodTemplateSpec, src.Spec.PodTemplateSpec)
+	}
+	if !reflect.DeepEqual(dst.ObjectMeta, src.ObjectMeta) {
+		t.Errorf("ObjectMeta = %+v, want %+v", dst.ObjectMeta, src.ObjectMeta)
+	}
+	if dst.Status.Phase != src.Status.Phase || !dst.Status.LastRunTime.Equal(src.Status.LastRunTime) ||
+		!reflect.DeepEqual(dst.Status.Conditions, src.Status.Conditions) {
+		t.Errorf("Status = %+v, want %+v", dst.Status, src.Status)
+	}
+
+	// The hub does not share memory with the spoke
+	dst.Spec.PodTemplateSpec.Spec.Containers[0].Image = "busybox"
+	dst.Labels["team"] = "dev"
+	if src.Spec.PodTemplateSpec.Spec.Containers[0].Image != "alpine" || src.Labels["team"] != "ops" {
+		t.Error("ConvertTo shares memory between the versions")
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(at *At)
+	}{
+		{name: "every field set", modify: func(*At) {}},
+		{name: "one-word command", modify: func(at *At) { at.Spec.Command = "date" }},
+		{name: "repeated spaces", modify: func(at *At) { at.Spec.Command = "echo  two   spaces " }},
+		{name: "no template or status", modify: func(at *At) {
+			at.Spec.PodTemplateSpec = nil
+			at.Status = AtStatus{}
+			at.Annotations = nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := alphaAt()
+			tt.modify(src)
+
+			hub := &v1beta1.At{}
+			if err := src.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo: %v", err)
+			}
+			back := &At{}
+			if err := back.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom: %v", err)
+			}
+			if !reflect.DeepEqual(back, src) {
+				t.Errorf("round trip = %+v, want %+v", back, src)
+			}
+		})
+	}
+}
+
+func TestHubRoundTrip(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantAnnotation bool
+	}{
+		{name: "plain arguments", args: []string{"echo", "hello"}},
+		{name: "argument with a space", args: []string{"sh", "-c", "echo hello"}, wantAnnotation: true},
+		{name: "trailing empty argument", args: []string{"printf", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1beta1.At{
+				ObjectMeta: metav1.ObjectMeta{Name: "args"},
+				Spec:       v1beta1.AtSpec{RunAt: "2026-07-03T02:00:00Z", Args: tt.args},
+			}
+			spoke := &At{}
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom: %v", err)
+			}
+			if _, ok := spoke.Annotations[ArgsAnnotation]; ok != tt.wantAnnotation {
+				t.Errorf("annotation %s set = %t, want %t", ArgsAnnotation, ok, tt.wantAnnotation)
+			}
+
+			back := &v1beta1.At{}
+			if err := spoke.ConvertTo(back); err != nil {
+				t.Fatalf("ConvertTo: %v", err)
+			}
+			if !reflect.DeepEqual(back, hub) {
+				t.Errorf("round trip = %+v, want %+v", back, hub)
+			}
+		})
+	}
+}
+
+func TestConvertToIgnoresStaleArgs(t *testing.T) {
+	// Command was edited as v1alpha1 after the Args were saved
+	src := &At{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ArgsAnnotation: `["sh","-c","echo hello"]`}},
+		Spec:       AtSpec{Schedule: "2026-07-03T02:00:00Z", Command: "echo bye"},
+	}
+	dst := &v1beta1.At{}
+	if err := src.ConvertTo(dst); err != nil {
+		t.Fatalf("ConvertTo: %v", err)
+	}
+	if want := []string{"echo", "bye"}; !reflect.DeepEqual(dst.Spec.Args, want) {
+		t.Errorf("Args = %q, want %q", dst.Spec.Args, want)
+	}
+	if dst.Annotations != nil {
+		t.Errorf("Annotations = %v, want the saved args dropped", dst.Annotations)
+	}
+}
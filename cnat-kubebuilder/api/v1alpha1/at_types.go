@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,13 +30,57 @@ const (
 	PhaseDone    = "DONE"
 )
 
+// Condition types reported in AtStatus.Conditions.
+const (
+	// ConditionReady is True once the command has run to completion.
+	ConditionReady = "Ready"
+	// ConditionScheduled is True when the schedule is valid and a run is
+	// (or was) planned from it.
+	ConditionScheduled = "Scheduled"
+)
+
+// Annotations that adjust a scheduled run without editing the spec.
+const (
+	// PauseAnnotation set to "true" keeps the At in PENDING, even once the
+	// schedule is due. Removing it (or setting any other value) resumes it.
+	PauseAnnotation = "cnat.programming-kubernetes.info/pause"
+	// OverrideScheduleAnnotation holds an RFC3339 timestamp that is used
+	// instead of Spec.Schedule for the next run. For recurring resources the
+	// cron expression applies again once the overridden run has happened.
+	OverrideScheduleAnnotation = "cnat.programming-kubernetes.info/override-schedule"
+)
+
 // AtSpec defines the desired state of At
+// +kubebuilder:validation:XValidation:rule="(has(self.recurring) && self.recurring) != self.schedule.matches('^[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}([.][0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})$')",message="schedule must be an RFC3339 timestamp, or a cron expression when recurring is set"
 type AtSpec struct {
-	// Schedule is the desired time the command is supposed to be executed.
-	// Note: the format used here is UTC time https://www.utctime.net
+	// Schedule is the desired time the command is supposed to be executed,
+	// as an RFC3339 timestamp (e.g. "2026-07-03T02:00:00Z").
+	// When Recurring is set, Schedule is a standard cron expression instead
+	// (e.g. "*/5 * * * *").
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
 	Schedule string `json:"schedule,omitempty"`
 	// Command is the desired command (executed in a Bash shell) to be executed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
 	Command string `json:"command,omitempty"`
+	// Recurring runs the command on every occurrence of the cron expression in
+	// Schedule instead of only once. A recurring At needs a cron expression:
+	// a timestamp would be due again right after every run.
+	Recurring bool `json:"recurring,omitempty"`
+	// MaxRetries is how many times a failing command is restarted before the
+	// At gives up and is marked DONE with Ready=False. 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// PodTemplateSpec describes the pod that runs the command, for a custom
+	// image, resources or environment. Command replaces the command of its
+	// first container. When unset, the command runs in a busybox container.
+	// +kubebuilder:validation:XValidation:rule="size(self.spec.containers) > 0",message="podTemplateSpec must have at least one container"
+	// +kubebuilder:validation:XValidation:rule="!has(self.spec.restartPolicy) || self.spec.restartPolicy != 'Always'",message="podTemplateSpec.spec.restartPolicy must be OnFailure or Never, a pod that is always restarted never completes"
+	// +optional
+	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec,omitempty"`
 }
 
 // AtStatus defines the observed state of At
@@ -43,10 +88,22 @@ type AtStatus struct {
 	// Phase represents the state of the schedule: until the command is executed
 	// it is PENDING, afterwards it is DONE.
 	Phase string `json:"phase,omitempty"`
+	// LastRunTime is the time the command was last started.
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// Conditions represent the latest available observations of the At's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Command",type=string,JSONPath=`.spec.command`,description="The command run by the At"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // At is the Schema for the ats API.
 type At struct {
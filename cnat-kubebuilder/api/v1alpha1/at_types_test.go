@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// loadCRD decodes the generated At CRD manifest and returns its definition
+// of this API version, which is the one stored
+func loadCRD(t *testing.T) apiextensionsv1.CustomResourceDefinitionVersion {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "crd", "bases", "cnat.programming-kubernetes.info_ats.yaml"))
+	if err != nil {
+		t.Fatalf("reading CRD manifest: %v", err)
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		t.Fatalf("decoding CRD manifest: %v", err)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name == GroupVersion.Version {
+			if !version.Storage {
+				t.Fatalf("CRD version %s is not the storage version", version.Name)
+			}
+			return version
+		}
+	}
+	t.Fatalf("CRD versions = %v, want one named %s", crd.Spec.Versions, GroupVersion.Version)
+	return apiextensionsv1.CustomResourceDefinitionVersion{}
+}
+
+// TestCRDPrinterColumns checks the generated CRD carries the columns
+// declared by the printcolumn markers on At.
+func TestCRDPrinterColumns(t *testing.T) {
+	want := []apiextensionsv1.CustomResourceColumnDefinition{
+		{Name: "Schedule", Type: "string", JSONPath: ".spec.schedule"},
+		{Name: "Phase", Type: "string", JSONPath: ".status.phase"},
+		{Name: "Command", Type: "string", JSONPath: ".spec.command"},
+		{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+	}
+	columns := loadCRD(t).AdditionalPrinterColumns
+	if len(columns) != len(want) {
+		t.Fatalf("got %d printer columns, want %d: %+v", len(columns), len(want), columns)
+	}
+	for i, column := range columns {
+		if column.Name != want[i].Name || column.Type != want[i].Type || column.JSONPath != want[i].JSONPath {
+			t.Errorf("column %d = %s (%s, %s), want %s (%s, %s)", i,
+				column.Name, column.Type, column.JSONPath, want[i].Name, want[i].Type, want[i].JSONPath)
+		}
+	}
+}
+
+// TestCRDValidation checks the validation markers on AtSpec made it into the
+// generated schema.
+func TestCRDValidation(t *testing.T) {
+	spec := loadCRD(t).Schema.OpenAPIV3Schema.Properties["spec"]
+
+	if want := []string{"command", "schedule"}; strings.Join(spec.Required, ",") != strings.Join(want, ",") {
+		t.Errorf("required = %v, want %v", spec.Required, want)
+	}
+	for _, field := range []string{"command", "schedule"} {
+		if minLength := spec.Properties[field].MinLength; minLength == nil || *minLength != 1 {
+			t.Errorf("%s minLength = %v, want 1", field, minLength)
+		}
+	}
+	maxRetries := spec.Properties["maxRetries"]
+	if maxRetries.Type != "integer" || maxRetries.Maximum == nil || *maxRetries.Maximum != 10 ||
+		maxRetries.Minimum == nil || *maxRetries.Minimum != 0 {
+		t.Errorf("maxRetries = %s [%v, %v], want integer [0, 10]", maxRetries.Type, maxRetries.Minimum, maxRetries.Maximum)
+	}
+
+	if len(spec.XValidations) != 1 {
+		t.Fatalf("got %d x-kubernetes-validations on spec, want 1", len(spec.XValidations))
+	}
+	// The API server evaluates the rule's regex with RE2, like Go's regexp
+	rule := spec.XValidations[0].Rule
+	start, end := strings.Index(rule, "matches('"), strings.LastIndex(rule, "')")
+	if start < 0 || end < start {
+		t.Fatalf("rule %q does not match the schedule against a regex", rule)
+	}
+	rfc3339 := regexp.MustCompile(rule[start+len("matches('") : end])
+	for schedule, want := range map[string]bool{
+		"2026-07-03T02:00:00Z":          true,
+		"2026-07-03T02:00:00.5Z":        true,
+		"2026-07-03T02:00:00+02:00":     true,
+		"2026-07-03 02:00:00":           false,
+		"2026-07-03T02:00:00":           false,
+		"tomorrow":                      false,
+		"*/5 * * * *":                   false,
+		"x2026-07-03T02:00:00Zanything": false,
+	} {
+		if got := rfc3339.MatchString(schedule); got != want {
+			t.Errorf("schedule %q matches = %t, want %t", schedule, got, want)
+		}
+	}
+}
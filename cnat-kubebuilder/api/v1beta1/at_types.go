@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AtSpec defines the desired state of At. Compared to v1alpha1, Schedule is
+// renamed RunAt and the Command string is replaced by an Args list.
+// +kubebuilder:validation:XValidation:rule="(has(self.recurring) && self.recurring) != self.runAt.matches('^[0-9]{4}-[0-9]{2}-[0-9]{2}T[0-9]{2}:[0-9]{2}:[0-9]{2}([.][0-9]+)?(Z|[+-][0-9]{2}:[0-9]{2})$')",message="runAt must be an RFC3339 timestamp, or a cron expression when recurring is set"
+type AtSpec struct {
+	// RunAt is the desired time the command is supposed to be executed,
+	// as an RFC3339 timestamp (e.g. "2026-07-03T02:00:00Z").
+	// When Recurring is set, RunAt is a standard cron expression instead
+	// (e.g. "*/5 * * * *").
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	RunAt string `json:"runAt,omitempty"`
+	// Args is the command to execute followed by its arguments, e.g.
+	// ["echo", "hello"].
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Args []string `json:"args,omitempty"`
+	// Recurring runs the command on every occurrence of the cron expression in
+	// RunAt instead of only once, which must then be a cron expression.
+	Recurring bool `json:"recurring,omitempty"`
+	// MaxRetries is how many times a failing command is restarted before the
+	// At gives up and is marked DONE with Ready=False. 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// PodTemplateSpec describes the pod that runs the command, for a custom
+	// image, resources or environment. Args replaces the command of its
+	// first container. When unset, the command runs in a busybox container.
+	// +kubebuilder:validation:XValidation:rule="size(self.spec.containers) > 0",message="podTemplateSpec must have at least one container"
+	// +kubebuilder:validation:XValidation:rule="!has(self.spec.restartPolicy) || self.spec.restartPolicy != 'Always'",message="podTemplateSpec.spec.restartPolicy must be OnFailure or Never, a pod that is always restarted never completes"
+	// +optional
+	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec,omitempty"`
+}
+
+// AtStatus defines the observed state of At
+type AtStatus struct {
+	// Phase represents the state of the schedule: until the command is executed
+	// it is PENDING, afterwards it is DONE.
+	Phase string `json:"phase,omitempty"`
+	// LastRunTime is the time the command was last started.
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// Conditions represent the latest available observations of the At's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Run At",type=string,JSONPath=`.spec.runAt`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Args",type=string,JSONPath=`.spec.args`,description="The command run by the At and its arguments"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// At is the Schema for the ats API.
+type At struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AtSpec   `json:"spec,omitempty"`
+	Status AtStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AtList contains a list of At.
+type AtList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []At `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&At{}, &AtList{})
+}
@@ -0,0 +1,67 @@
+{{/*
+Name of the chart, used in the app.kubernetes.io/name label.
+*/}}
+{{- define "cnat.name" -}}
+{{- .Chart.Name | trunc 63 | trimSuffix "-" }}
+{{- end }}
+
+{{/*
+Prefix of every resource name: the release name, followed by the chart name
+unless the release name already contains it.
+*/}}
+{{- define "cnat.fullname" -}}
+{{- if contains .Chart.Name .Release.Name }}
+{{- .Release.Name | trunc 63 | trimSuffix "-" }}
+{{- else }}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" }}
+{{- end }}
+{{- end }}
+
+{{/*
+Labels of every resource.
+*/}}
+{{- define "cnat.labels" -}}
+helm.sh/chart: {{ printf "%s-%s" .Chart.Name .Chart.Version }}
+{{ include "cnat.selectorLabels" . }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end }}
+
+{{/*
+Labels selecting the manager pods.
+*/}}
+{{- define "cnat.selectorLabels" -}}
+control-plane: controller-manager
+app.kubernetes.io/name: {{ include "cnat.name" . }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
+
+{{/*
+Name of the manager ServiceAccount.
+*/}}
+{{- define "cnat.serviceAccountName" -}}
+{{ include "cnat.fullname" . }}-controller-manager
+{{- end }}
+
+{{/*
+Name of the webhook Service, which the serving certificate is issued for.
+*/}}
+{{- define "cnat.webhookServiceName" -}}
+{{ include "cnat.fullname" . }}-webhook-service
+{{- end }}
+
+{{/*
+Conversion webhook of the At CRD, which the manager serves at /convert.
+*/}}
+{{- define "cnat.crdConversion" -}}
+conversion:
+  strategy: Webhook
+  webhook:
+    clientConfig:
+      service:
+        name: {{ include "cnat.webhookServiceName" . }}
+        namespace: {{ .Release.Namespace }}
+        path: /convert
+    conversionReviewVersions:
+    - v1
+{{- end }}
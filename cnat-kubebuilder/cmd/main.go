@@ -19,26 +19,37 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+	cnatv1beta1 "Kubernetes_Programming/api/v1beta1"
 	"Kubernetes_Programming/internal/controller"
+	cnatwebhook "Kubernetes_Programming/internal/webhook"
+	webhookcnatv1alpha1 "Kubernetes_Programming/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -47,10 +58,46 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// leaderElectionID is the name of the Lease replicas compete for, so only one
+// of them reconciles At resources at a time.
+const leaderElectionID = "cnat-controller-leader"
+
+// shutdownCancelGrace is how long the manager waits, past --shutdown-timeout,
+// for the reconciles cancelled at the timeout to return.
+const shutdownCancelGrace = 5 * time.Second
+
+// leaderElectionNamespace returns the namespace holding the leader election
+// Lease: the pod's own namespace, exposed through the downward API as
+// POD_NAMESPACE. When empty, controller-runtime falls back to the in-cluster
+// service account namespace.
+func leaderElectionNamespace() string {
+	return os.Getenv("POD_NAMESPACE")
+}
+
+// newLogHandler returns the slog handler behind both the controller-runtime
+// logger and slog.Default, at level (debug, info, warn or error) and in
+// format (text or json). controller-runtime's V(1) debug messages are logged
+// at the debug level.
+func newLogHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid --log-level %q (want debug, info, warn or error)", level)
+	}
+	handlerOpts := &slog.HandlerOptions{Level: slogLevel}
+	switch format {
+	case "text":
+		return slog.NewTextHandler(w, handlerOpts), nil
+	case "json":
+		return slog.NewJSONHandler(w, handlerOpts), nil
+	}
+	return nil, fmt.Errorf("invalid --log-format %q (want text or json)", format)
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(cnatv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(cnatv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -63,13 +110,18 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var reconcileTimeout time.Duration
+	var shutdownTimeout time.Duration
+	var dryRun bool
+	var logLevel, logFormat string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+			"Enabling this will ensure there is only one active controller manager. "+
+			"Use --leader-elect=false for local development.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -81,13 +133,25 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
-	opts := zap.Options{
-		Development: true,
-	}
-	opts.BindFlags(flag.CommandLine)
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", 30*time.Second,
+		"Maximum duration of a single At reconcile loop. Use 0 to disable the timeout.")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 20*time.Second,
+		"How long to wait on SIGTERM for in-flight At reconciles to complete before cancelling them. "+
+			"Keep it below the pod's terminationGracePeriodSeconds.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, pods and At statuses are only written as server-side dry runs: "+
+			"the reconciler logs what it would do without changing the cluster.")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level of the log messages: debug, info, warn or error.")
+	flag.StringVar(&logFormat, "log-format", "text", "Format of the log messages: text or json.")
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logHandler, err := newLogHandler(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	slog.SetDefault(slog.New(logHandler))
+	ctrl.SetLogger(logr.FromSlogHandler(logHandler))
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -178,13 +242,31 @@ func main() {
 		})
 	}
 
+	// Only cache the controller ConfigMap, not every ConfigMap in the cluster
+	configNamespace := leaderElectionNamespace()
+	cacheOptions := cache.Options{}
+	if configNamespace != "" {
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{configNamespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", controller.ConfigMapName),
+			},
+		}
+	}
+
+	// The manager waits for the controllers' workers, and so for the
+	// reconciles the shutdown tracker lets run to --shutdown-timeout
+	gracefulShutdownTimeout := shutdownTimeout + shutdownCancelGrace
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsServerOptions,
-		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "aa5dccff.programming-kubernetes.info",
+		Scheme:                  scheme,
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
+		Cache:                   cacheOptions,
+		Metrics:                 metricsServerOptions,
+		WebhookServer:           webhookServer,
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        leaderElectionID,
+		LeaderElectionNamespace: leaderElectionNamespace(),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -202,13 +284,48 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The controller ConfigMap lives in the pod's namespace; without one (e.g.
+	// when running locally) the flags are the configuration
+	var configWatcher *controller.ConfigMapWatcher
+	if configNamespace != "" {
+		configWatcher = controller.NewConfigMapWatcher(mgr.GetClient(), configNamespace, controller.ControllerConfig{
+			DefaultImage:     controller.DefaultImage,
+			ReconcileTimeout: reconcileTimeout,
+		})
+		if err = configWatcher.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ConfigMapWatcher")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("POD_NAMESPACE is not set, not watching the controller ConfigMap", "configmap", controller.ConfigMapName)
+	}
+
+	if dryRun {
+		setupLog.Info("dry-run mode: pods and At statuses are not persisted")
+	}
+	tracker := controller.NewShutdownTracker()
 	if err = (&controller.AtReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		ReconcileTimeout: reconcileTimeout,
+		Config:           configWatcher,
+		DryRun:           dryRun,
+		Tracker:          tracker,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "At")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookcnatv1alpha1.SetupAtWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "At")
+			os.Exit(1)
+		}
+		if err = cnatwebhook.SetupAtConversionWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create conversion webhook", "webhook", "At")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -236,9 +353,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	// On SIGTERM the controllers stop taking work from their queues while
+	// the tracker waits for the reconciles already running
+	ctx := ctrl.SetupSignalHandler()
+	drained := make(chan []controller.InFlightReconcile, 1)
+	go func() {
+		<-ctx.Done()
+		setupLog.Info("shutting down, waiting for in-flight reconciles", "timeout", shutdownTimeout)
+		drained <- tracker.Drain(shutdownTimeout)
+	}()
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	if ctx.Err() != nil {
+		logShutdownSummary(<-drained)
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// logShutdownSummary logs the reconciles cancelled because they outlived
+// --shutdown-timeout; they run again under the next leader.
+func logShutdownSummary(cancelled []controller.InFlightReconcile) {
+	if len(cancelled) == 0 {
+		setupLog.Info("all in-flight reconciles completed")
+		return
+	}
+	now := time.Now()
+	for _, r := range cancelled {
+		setupLog.Info("cancelled in-flight reconcile", "at", r.Request.NamespacedName.String(),
+			"running", now.Sub(r.Started).Round(time.Millisecond))
+	}
+	setupLog.Info("shutdown timeout reached, reconciles cancelled", "count", len(cancelled))
+}
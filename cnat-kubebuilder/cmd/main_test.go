@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// TestLeaderElectionSingleReconciler runs two managers' leader electors
+// against one shared fake API server and checks only one of them starts
+// reconciling.
+func TestLeaderElectionSingleReconciler(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "cnat-system")
+	client := fake.NewClientset()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var running atomic.Int32
+	var wg sync.WaitGroup
+	for _, identity := range []string{"manager-a", "manager-b"} {
+		lock, err := resourcelock.New(resourcelock.LeasesResourceLock,
+			leaderElectionNamespace(), leaderElectionID,
+			client.CoreV1(), client.CoordinationV1(),
+			resourcelock.ResourceLockConfig{Identity: identity})
+		if err != nil {
+			t.Fatalf("failed to create lock: %v", err)
+		}
+
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:          lock,
+			LeaseDuration: time.Second,
+			RenewDeadline: 500 * time.Millisecond,
+			RetryPeriod:   100 * time.Millisecond,
+			Callbacks: leaderelection.LeaderCallbacks{
+				// Stands in for the manager starting its reconcilers
+				OnStartedLeading: func(ctx context.Context) {
+					running.Add(1)
+					<-ctx.Done()
+				},
+				OnStoppedLeading: func() {},
+			},
+		})
+		if err != nil {
+			t.Fatalf("failed to create leader elector: %v", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			elector.Run(ctx)
+		}()
+	}
+
+	// Give both electors several retry periods to compete for the lease
+	time.Sleep(time.Second)
+	if got := running.Load(); got != 1 {
+		t.Errorf("%d reconcilers running, want exactly 1", got)
+	}
+
+	lease, err := client.CoordinationV1().Leases("cnat-system").Get(context.Background(), leaderElectionID, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("lease %s not found in POD_NAMESPACE: %v", leaderElectionID, err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		t.Errorf("lease has no holder")
+	}
+
+	cancel()
+	wg.Wait()
+}
+
+// TestLogHandlerStructuredFields checks that controller-runtime style logr
+// calls come out of the slog handler as JSON fields, and that V(1) messages
+// are only logged at the debug level.
+func TestLogHandlerStructuredFields(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := newLogHandler(&buf, "info", "json")
+	if err != nil {
+		t.Fatalf("newLogHandler() error = %v", err)
+	}
+	logger := logr.FromSlogHandler(handler).WithName("setup")
+	logger.V(1).Info("debug only")
+	logger.Error(errors.New("boom"), "unable to create controller", "controller", "At")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected a single JSON record, got %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":      "ERROR",
+		"msg":        "unable to create controller",
+		"logger":     "setup",
+		"controller": "At",
+		"err":        "boom",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v (record %v)", key, record[key], value, record)
+		}
+	}
+
+	if _, err := newLogHandler(&buf, "verbose", "json"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if _, err := newLogHandler(&buf, "info", "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
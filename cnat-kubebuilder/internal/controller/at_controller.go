@@ -22,8 +22,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,11 +43,34 @@ import (
 type AtReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// ReconcileTimeout bounds each reconcile loop, so a hung API server
+	// fails the reconcile (and requeues it) instead of blocking forever.
+	// Zero means no timeout.
+	ReconcileTimeout time.Duration
+	// Config, if set, supplies the configuration read from the controller
+	// ConfigMap on every reconcile and takes precedence over ReconcileTimeout.
+	Config *ConfigMapWatcher
+	// DryRun sends every write (pod creation and deletion, status updates)
+	// as a server-side dry run: the API server validates it but persists
+	// nothing, so the reconciler can be checked against a live cluster.
+	DryRun bool
+	// Tracker, if set, counts the reconciles in flight so shutdown can wait
+	// for them.
+	Tracker *ShutdownTracker
+}
+
+// config returns the configuration for this reconcile
+func (r *AtReconciler) config() ControllerConfig {
+	if r.Config != nil {
+		return r.Config.Config()
+	}
+	return ControllerConfig{DefaultImage: DefaultImage, ReconcileTimeout: r.ReconcileTimeout}
 }
 
 // +kubebuilder:rbac:groups=cnat.programming-kubernetes.info,resources=ats,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cnat.programming-kubernetes.info,resources=ats/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cnat.programming-kubernetes.info,resources=ats/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;delete
 
 // Reconcile is the CORE of the controller - it's called automatically by Kubernetes whenever:
 // 1. An At resource is created, updated, or deleted
@@ -74,12 +100,26 @@ type AtReconciler struct {
 //
 //  5. reconcile.Result{RequeueAfter: duration}, err
 //     → Error wins! Ignores RequeueAfter, uses error backoff
-func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := log.FromContext(ctx).WithValues("namespace", req.Namespace, "at", req.Name)
 	reqLogger.Info("=== Reconciling At")
+	// Read the configuration once, so a ConfigMap change applies from the next reconcile
+	config := r.config()
+	if config.ReconcileTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.ReconcileTimeout)
+		defer cancel()
+	}
+	// Record the reconcile outcome (labeled by the phase we started in) and
+	// refresh the active resources gauge whichever way we return
+	phaseLabel := phaseUnknown
+	defer func() {
+		recordReconcile(phaseLabel, err)
+		r.updateActiveResources(ctx, req.Namespace)
+	}()
 	// Fetch the At instance
 	instance := &cnatv1alpha1.At{}
-	err := r.Get(context.TODO(), req.NamespacedName, instance)
+	err = r.Get(ctx, req.NamespacedName, instance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after
@@ -89,10 +129,20 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		// Error reading the object—requeue the request:
 		return reconcile.Result{}, err
 	}
+	// Remember the At as read, so status-only changes (conditions) made on
+	// paths that don't transition phases are still persisted
+	original := instance.DeepCopy()
 	// If no phase set, default to pending (the initial phase):
 	if instance.Status.Phase == "" {
 		instance.Status.Phase = cnatv1alpha1.PhasePending
 	}
+	phaseLabel = instance.Status.Phase
+	phaseEnteredAt := phaseStartTime(instance)
+	// Annotations can pause the At or move its next run without touching the
+	// spec. Adding or removing them is an update of the At itself, so the
+	// For() watch in SetupWithManager reconciles again right away: removing
+	// the pause annotation resumes a due schedule without waiting.
+	paused := instance.Annotations[cnatv1alpha1.PauseAnnotation] == "true"
 	// STATE MACHINE: PENDING -> RUNNING -> DONE
 	// Each reconcile call processes current phase and potentially transitions to next
 	switch instance.Status.Phase {
@@ -101,15 +151,37 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		// PENDING: Resource created but scheduled time hasn't arrived yet
 		reqLogger.Info("Checking schedule", "Target", instance.Spec.Schedule)
 
-		// Calculate how long until the scheduled time
-		d, err := timeUntilSchedule(instance.Spec.Schedule)
+		// Calculate how long until the scheduled time (the override annotation
+		// if set, otherwise for recurring schedules the next cron occurrence
+		// after the last run)
+		d, err := timeUntilNextRun(instance)
 		if err != nil {
 			reqLogger.Error(err, "Schedule parsing failure")
+			setCondition(instance, cnatv1alpha1.ConditionScheduled, metav1.ConditionFalse, "InvalidSchedule", err.Error())
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "InvalidSchedule", "The schedule could not be parsed")
+			if updateErr := r.updateStatusIfChanged(ctx, instance, original); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
 			// RETURN: reconcile.Result{}, err
 			// → Requeue with exponential backoff until user fixes the schedule
 			return reconcile.Result{}, err
 		}
 		reqLogger.Info("Schedule parsing done", "diff", fmt.Sprintf("%v", d))
+		setCondition(instance, cnatv1alpha1.ConditionScheduled, metav1.ConditionTrue, "ScheduleValid",
+			fmt.Sprintf("Next run at %s", time.Now().Add(d).Round(time.Second).UTC().Format(time.RFC3339)))
+
+		if paused {
+			// Paused: stay PENDING whether or not the schedule is due
+			// RETURN: reconcile.Result{}, nil
+			// → Don't requeue, removing the annotation triggers the next reconcile
+			reqLogger.Info("Paused", "annotation", cnatv1alpha1.PauseAnnotation)
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "Paused",
+				fmt.Sprintf("Paused by the %s annotation", cnatv1alpha1.PauseAnnotation))
+			if err := r.updateStatusIfChanged(ctx, instance, original); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, nil
+		}
 
 		if d > 0 {
 			// Schedule is in the future (e.g., 5 minutes from now)
@@ -117,18 +189,25 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 			// → Sleep for exactly 'd' duration, then Reconcile will run again
 			// → This is EFFICIENT - we don't poll, Kubernetes wakes us up at the right time
 			reqLogger.Info("Scheduling reconcile", "after", d)
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "Pending", "Waiting for the scheduled time")
+			if err := r.updateStatusIfChanged(ctx, instance, original); err != nil {
+				return reconcile.Result{}, err
+			}
 			return reconcile.Result{RequeueAfter: d}, nil
 		}
 
 		// Time has arrived! Transition to RUNNING phase
 		reqLogger.Info("It's time!", "Ready to execute", instance.Spec.Command)
 		instance.Status.Phase = cnatv1alpha1.PhaseRunning
+		now := metav1.Now()
+		instance.Status.LastRunTime = &now
+		setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "Running", "The command is running")
 		// Note: We DON'T return here - we fall through to update status at the end
 	case cnatv1alpha1.PhaseRunning:
 		reqLogger.Info("Phase: RUNNING")
 		// RUNNING: We need to create a Pod to execute the command
 
-		pod := newPodForCR(instance)
+		pod := newPodForCR(instance, config.DefaultImage)
 		// Set At instance as the owner - when At is deleted, Pod is auto-deleted (Garbage Collection)
 		err := controllerutil.SetControllerReference(instance, pod, r.Scheme)
 		if err != nil {
@@ -140,11 +219,11 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		// Check if the pod already exists
 		found := &corev1.Pod{}
 		nsName := types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}
-		err = r.Get(context.TODO(), nsName, found)
+		err = r.Get(ctx, nsName, found)
 
 		if err != nil && errors.IsNotFound(err) {
 			// Pod doesn't exist yet - create it!
-			err = r.Create(context.TODO(), pod)
+			err = r.createPod(ctx, pod)
 			if err != nil {
 				// RETURN: reconcile.Result{}, err
 				// → Creation failed, requeue with backoff
@@ -158,12 +237,38 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 			// RETURN: reconcile.Result{}, err
 			// → Error getting pod, requeue with backoff
 			return reconcile.Result{}, err
+		} else if found.Status.Phase == corev1.PodSucceeded && instance.Spec.Recurring {
+			// Recurring run finished: remove the pod so the next run can create
+			// it again, and go back to PENDING. The cron expression stays in
+			// Spec.Schedule; the next occurrence is computed from LastRunTime.
+			reqLogger.Info("Recurring run completed, waiting for next occurrence", "lastRunTime", instance.Status.LastRunTime)
+			err = r.deletePod(ctx, found)
+			if err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			instance.Status.Phase = cnatv1alpha1.PhasePending
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "Pending", "Waiting for the next occurrence")
+			// Note: We DON'T return here - we fall through to update status at the end
 		} else if found.Status.Phase == corev1.PodFailed ||
 			found.Status.Phase == corev1.PodSucceeded {
 			// Pod finished executing! Transition to DONE
 			reqLogger.Info("Container terminated", "reason",
 				found.Status.Reason, "message", found.Status.Message)
 			instance.Status.Phase = cnatv1alpha1.PhaseDone
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionTrue, "Completed",
+				fmt.Sprintf("The command finished with pod phase %s", found.Status.Phase))
+			// Note: We DON'T return here - we fall through to update status at the end
+		} else if restarts, maxRetries := podRestarts(found), maxRetries(instance, config); maxRetries > 0 && restarts > int32(maxRetries) {
+			// The command keeps failing (the pod restarts it OnFailure): give
+			// up once it was retried MaxRetries times, and stop the pod
+			reqLogger.Info("Retries exhausted", "restarts", restarts, "maxRetries", maxRetries)
+			err = r.deletePod(ctx, found)
+			if err != nil && !errors.IsNotFound(err) {
+				return reconcile.Result{}, err
+			}
+			instance.Status.Phase = cnatv1alpha1.PhaseDone
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "RetriesExhausted",
+				fmt.Sprintf("The command failed %d times", restarts))
 			// Note: We DON'T return here - we fall through to update status at the end
 		} else {
 			// Pod is still running (Pending/Running phase)
@@ -172,6 +277,10 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 			// → Kubernetes will automatically call Reconcile when Pod status changes
 			//   (because we set owner reference and watch Pods in SetupWithManager)
 			reqLogger.Info("Pod still running", "phase", found.Status.Phase)
+			setCondition(instance, cnatv1alpha1.ConditionReady, metav1.ConditionFalse, "Running", "The command is running")
+			if err := r.updateStatusIfChanged(ctx, instance, original); err != nil {
+				return reconcile.Result{}, err
+			}
 			return reconcile.Result{}, nil
 		}
 	case cnatv1alpha1.PhaseDone:
@@ -188,12 +297,15 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 
 	// Update the At instance status in Kubernetes
 	// This is called when we transition phases (PENDING→RUNNING or RUNNING→DONE)
-	err = r.Status().Update(context.TODO(), instance)
+	err = r.updateStatus(ctx, instance, original)
 	if err != nil {
 		// RETURN: reconcile.Result{}, err
 		// → Status update failed, requeue with backoff
 		return reconcile.Result{}, err
 	}
+	if instance.Status.Phase != phaseLabel {
+		observePhaseTransition(phaseLabel, instance.Status.Phase, time.Since(phaseEnteredAt))
+	}
 
 	// RETURN: reconcile.Result{}, nil
 	// → Status updated successfully
@@ -201,16 +313,76 @@ func (r *AtReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	return reconcile.Result{}, nil
 }
 
+// updateStatusIfChanged writes the status subresource when it differs from the
+// status of original, the At read at the start of the reconcile.
+func (r *AtReconciler) updateStatusIfChanged(ctx context.Context, instance, original *cnatv1alpha1.At) error {
+	if equality.Semantic.DeepEqual(&original.Status, &instance.Status) {
+		return nil
+	}
+	return r.updateStatus(ctx, instance, original)
+}
+
+// updateStatus writes the status subresource. In dry-run mode it sends a
+// dry-run merge patch from original instead, which leaves the stored At as is.
+func (r *AtReconciler) updateStatus(ctx context.Context, instance, original *cnatv1alpha1.At) error {
+	if r.DryRun {
+		log.FromContext(ctx).Info("Dry run: would update status", "phase", instance.Status.Phase)
+		return r.Status().Patch(ctx, instance, client.MergeFrom(original), client.DryRunAll)
+	}
+	return r.Status().Update(ctx, instance)
+}
+
+// createPod creates the pod running the command, as a dry run in dry-run mode
+func (r *AtReconciler) createPod(ctx context.Context, pod *corev1.Pod) error {
+	if r.DryRun {
+		log.FromContext(ctx).Info("Dry run: would create pod", "name", pod.Name)
+		return r.Create(ctx, pod, client.DryRunAll)
+	}
+	return r.Create(ctx, pod)
+}
+
+// deletePod deletes a pod of the At, as a dry run in dry-run mode
+func (r *AtReconciler) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	if r.DryRun {
+		log.FromContext(ctx).Info("Dry run: would delete pod", "name", pod.Name)
+		return r.Delete(ctx, pod, client.DryRunAll)
+	}
+	return r.Delete(ctx, pod)
+}
+
+// setCondition sets a condition on the At status. The transition time only
+// changes when the condition status does (see apimeta.SetStatusCondition).
+func setCondition(instance *cnatv1alpha1.At, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: instance.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *AtReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var reconciler reconcile.Reconciler = r
+	if r.Tracker != nil {
+		reconciler = r.Tracker.Track(r)
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&cnatv1alpha1.At{}).
+		Owns(&corev1.Pod{}).
 		Named("at").
-		Complete(r)
+		Complete(reconciler)
 }
 
-// newPodForCR returns a busybox pod with the same name/namespace as the cr
-func newPodForCR(cr *cnatv1alpha1.At) *corev1.Pod {
+// newPodForCR returns a pod running the cr's command, with the same
+// name/namespace as the cr. The pod is built from Spec.PodTemplateSpec when
+// set, with the command replacing the one of its first container (and image
+// used when that container has none); otherwise it runs in image.
+func newPodForCR(cr *cnatv1alpha1.At, image string) *corev1.Pod {
+	if cr.Spec.PodTemplateSpec != nil {
+		return newPodFromTemplate(cr, image)
+	}
 	labels := map[string]string{
 		"app": cr.Name,
 	}
@@ -224,7 +396,7 @@ func newPodForCR(cr *cnatv1alpha1.At) *corev1.Pod {
 			Containers: []corev1.Container{
 				{
 					Name:    "busybox",
-					Image:   "busybox",
+					Image:   image,
 					Command: strings.Split(cr.Spec.Command, " "),
 				},
 			},
@@ -233,14 +405,104 @@ func newPodForCR(cr *cnatv1alpha1.At) *corev1.Pod {
 	}
 }
 
+// newPodFromTemplate returns a pod from a deep copy of the cr's pod template.
+// The name, namespace and app label are the ones of the legacy pod, so the
+// reconciler finds the pod the same way whichever path created it.
+func newPodFromTemplate(cr *cnatv1alpha1.At, image string) *corev1.Pod {
+	template := cr.Spec.PodTemplateSpec.DeepCopy()
+	labels := template.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels["app"] = cr.Name
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cr.Name + "-pod",
+			Namespace:   cr.Namespace,
+			Labels:      labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	if len(pod.Spec.Containers) > 0 {
+		container := &pod.Spec.Containers[0]
+		container.Command = strings.Split(cr.Spec.Command, " ")
+		if container.Image == "" {
+			container.Image = image
+		}
+	}
+	if pod.Spec.RestartPolicy == "" {
+		pod.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	}
+	return pod
+}
+
 // timeUntilSchedule parses the schedule string and returns the time until the schedule.
+// The schedule is an RFC3339 timestamp or, for recurring resources, a cron expression
+// whose next occurrence after lastRun is used. A recurring resource with a timestamp
+// is an error: the timestamp would be due again right after every run.
 // When it is overdue, the duration is negative.
-func timeUntilSchedule(schedule string) (time.Duration, error) {
+func timeUntilSchedule(schedule string, recurring bool, lastRun time.Time) (time.Duration, error) {
 	now := time.Now().UTC()
-	layout := "2006-01-02T15:04:05Z"
+	layout := time.RFC3339
 	s, err := time.Parse(layout, schedule)
-	if err != nil {
-		return time.Duration(0), err
+	if !recurring {
+		if err != nil {
+			return time.Duration(0), err
+		}
+		return s.Sub(now), nil
+	}
+	if err == nil {
+		return time.Duration(0), fmt.Errorf("schedule %q is a %s timestamp, a recurring At needs a cron expression", schedule, layout)
+	}
+	cronSchedule, cronErr := cron.ParseStandard(schedule)
+	if cronErr != nil {
+		return time.Duration(0), fmt.Errorf("schedule %q is not a cron expression: %w", schedule, cronErr)
+	}
+	return cronSchedule.Next(lastRun).Sub(now), nil
+}
+
+// timeUntilNextRun returns the time until the At's next run. A timestamp in
+// the override-schedule annotation takes precedence over Spec.Schedule until
+// a run has started at or after it.
+func timeUntilNextRun(instance *cnatv1alpha1.At) (time.Duration, error) {
+	lastRun := lastRunTime(instance)
+	if override, ok := instance.Annotations[cnatv1alpha1.OverrideScheduleAnnotation]; ok {
+		t, err := time.Parse(time.RFC3339, override)
+		if err != nil {
+			return time.Duration(0), fmt.Errorf("annotation %s: %q is not an RFC3339 timestamp: %w",
+				cnatv1alpha1.OverrideScheduleAnnotation, override, err)
+		}
+		if instance.Status.LastRunTime == nil || lastRun.Before(t) {
+			return time.Until(t), nil
+		}
+	}
+	return timeUntilSchedule(instance.Spec.Schedule, instance.Spec.Recurring, lastRun)
+}
+
+// maxRetries returns the retry limit of the At: Spec.MaxRetries, or the
+// configured default when the spec doesn't set one. 0 means no limit.
+func maxRetries(instance *cnatv1alpha1.At, config ControllerConfig) int {
+	if instance.Spec.MaxRetries > 0 {
+		return instance.Spec.MaxRetries
+	}
+	return config.MaxRetries
+}
+
+// podRestarts returns the total restart count of the pod's containers
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+// lastRunTime returns the time the command last ran, or the creation time of
+// the resource if it has not run yet.
+func lastRunTime(instance *cnatv1alpha1.At) time.Time {
+	if instance.Status.LastRunTime != nil {
+		return instance.Status.LastRunTime.Time
 	}
-	return s.Sub(now), nil
+	return instance.CreationTimestamp.Time
 }
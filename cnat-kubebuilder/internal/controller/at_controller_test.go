@@ -18,11 +18,22 @@ package controller
 
 import (
 	"context"
+	goerrors "errors"
+	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -51,7 +62,7 @@ var _ = Describe("At Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: cnatv1alpha1.AtSpec{Schedule: "2099-01-01T00:00:00Z", Command: "echo YAY"},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -82,3 +93,498 @@ var _ = Describe("At Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("At validation", func() {
+	ctx := context.Background()
+
+	// create submits an At built by mutate from a valid one to the API server
+	create := func(name string, mutate func(*cnatv1alpha1.At)) error {
+		at := newTestAt(name, "2099-01-01T00:00:00Z")
+		mutate(at)
+		err := k8sClient.Create(ctx, at)
+		if err == nil {
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, at)).To(Succeed()) })
+		}
+		return err
+	}
+	// expectUnprocessable asserts err is a 422 Unprocessable Entity
+	expectUnprocessable := func(err error) {
+		Expect(errors.IsInvalid(err)).To(BeTrue(), "expected a validation error, got %v", err)
+		var status errors.APIStatus
+		Expect(goerrors.As(err, &status)).To(BeTrue())
+		Expect(status.Status().Code).To(BeEquivalentTo(http.StatusUnprocessableEntity))
+	}
+
+	It("accepts a valid At", func() {
+		Expect(create("valid", func(*cnatv1alpha1.At) {})).To(Succeed())
+	})
+
+	It("rejects a schedule that is not an RFC3339 timestamp", func() {
+		expectUnprocessable(create("invalid-schedule", func(at *cnatv1alpha1.At) { at.Spec.Schedule = "tomorrow" }))
+	})
+
+	It("accepts a cron schedule for a recurring At", func() {
+		Expect(create("recurring", func(at *cnatv1alpha1.At) {
+			at.Spec.Schedule = "*/5 * * * *"
+			at.Spec.Recurring = true
+		})).To(Succeed())
+	})
+
+	It("rejects a timestamp for a recurring At", func() {
+		expectUnprocessable(create("recurring-timestamp", func(at *cnatv1alpha1.At) { at.Spec.Recurring = true }))
+	})
+
+	It("rejects an empty command", func() {
+		expectUnprocessable(create("no-command", func(at *cnatv1alpha1.At) { at.Spec.Command = "" }))
+	})
+
+	It("rejects more than 10 retries", func() {
+		expectUnprocessable(create("too-many-retries", func(at *cnatv1alpha1.At) { at.Spec.MaxRetries = 11 }))
+	})
+
+	It("rejects a pod template without containers", func() {
+		expectUnprocessable(create("no-containers", func(at *cnatv1alpha1.At) {
+			at.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{}
+		}))
+	})
+
+	It("rejects a pod template that always restarts", func() {
+		expectUnprocessable(create("always-restart", func(at *cnatv1alpha1.At) {
+			at.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Name: "runner", Image: "alpine"}},
+					RestartPolicy: corev1.RestartPolicyAlways,
+				},
+			}
+		}))
+	})
+})
+
+var _ = Describe("timeUntilSchedule", func() {
+	It("parses a one-shot UTC timestamp", func() {
+		at := time.Now().UTC().Add(time.Hour).Format("2006-01-02T15:04:05Z")
+		d, err := timeUntilSchedule(at, false, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(BeNumerically("~", time.Hour, 2*time.Second))
+	})
+
+	It("rejects a cron expression when the At is not recurring", func() {
+		_, err := timeUntilSchedule("*/5 * * * *", false, time.Now())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("computes the next cron occurrence after the last run", func() {
+		lastRun := time.Now().Add(-time.Minute).Truncate(time.Minute)
+		d, err := timeUntilSchedule("*/5 * * * *", true, lastRun)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(BeNumerically(">", -5*time.Minute))
+		Expect(d).To(BeNumerically("<=", 5*time.Minute))
+	})
+
+	It("reports an invalid recurring schedule", func() {
+		_, err := timeUntilSchedule("every tuesday", true, time.Now())
+		Expect(err).To(MatchError(ContainSubstring("not a cron expression")))
+	})
+
+	It("rejects a timestamp when the At is recurring", func() {
+		_, err := timeUntilSchedule("2000-01-01T00:00:00Z", true, time.Now())
+		Expect(err).To(MatchError(ContainSubstring("needs a cron expression")))
+	})
+})
+
+// newFakeReconciler returns an AtReconciler backed by a fake client seeded with objs
+func newFakeReconciler(objs ...client.Object) *AtReconciler {
+	fakeScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(fakeScheme))
+	utilruntime.Must(cnatv1alpha1.AddToScheme(fakeScheme))
+
+	return &AtReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(fakeScheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&cnatv1alpha1.At{}).
+			Build(),
+		Scheme: fakeScheme,
+	}
+}
+
+// newTestAt returns an At in the default namespace with the given schedule
+func newTestAt(name, schedule string) *cnatv1alpha1.At {
+	return &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: schedule, Command: "echo YAY"},
+	}
+}
+
+var _ = Describe("At status conditions", func() {
+	ctx := context.Background()
+
+	// reconcileAndGet runs one reconcile for the At and returns its stored state
+	reconcileAndGet := func(r *AtReconciler, name string) (*cnatv1alpha1.At, error) {
+		key := types.NamespacedName{Name: name, Namespace: "default"}
+		_, reconcileErr := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		at := &cnatv1alpha1.At{}
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		return at, reconcileErr
+	}
+
+	It("marks a future schedule as Scheduled but not Ready", func() {
+		future := time.Now().UTC().Add(time.Hour).Format("2006-01-02T15:04:05Z")
+		r := newFakeReconciler(newTestAt("future", future))
+
+		at, err := reconcileAndGet(r, "future")
+		Expect(err).NotTo(HaveOccurred())
+
+		scheduled := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionScheduled)
+		Expect(scheduled).NotTo(BeNil())
+		Expect(scheduled.Status).To(Equal(metav1.ConditionTrue))
+		ready := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("Pending"))
+	})
+
+	It("marks an invalid schedule as not Scheduled", func() {
+		r := newFakeReconciler(newTestAt("invalid", "tomorrow"))
+
+		at, err := reconcileAndGet(r, "invalid")
+		Expect(err).To(HaveOccurred())
+
+		scheduled := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionScheduled)
+		Expect(scheduled).NotTo(BeNil())
+		Expect(scheduled.Status).To(Equal(metav1.ConditionFalse))
+		Expect(scheduled.Reason).To(Equal("InvalidSchedule"))
+	})
+
+	It("stops a recurring At with a timestamp instead of rerunning it", func() {
+		at := newTestAt("rerun", "2000-01-01T00:00:00Z")
+		at.Spec.Recurring = true
+		r := newFakeReconciler(at)
+
+		By("not running it")
+		at, err := reconcileAndGet(r, "rerun")
+		Expect(err).To(MatchError(ContainSubstring("needs a cron expression")))
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhasePending))
+		scheduled := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionScheduled)
+		Expect(scheduled).NotTo(BeNil())
+		Expect(scheduled.Status).To(Equal(metav1.ConditionFalse))
+		Expect(scheduled.Reason).To(Equal("InvalidSchedule"))
+		Expect(r.Get(ctx, types.NamespacedName{Name: "rerun-pod", Namespace: "default"}, &corev1.Pod{})).NotTo(Succeed())
+
+		By("not running it again after a run that started before the check")
+		at.Status.Phase = cnatv1alpha1.PhaseRunning
+		Expect(r.Status().Update(ctx, at)).To(Succeed())
+		pod := newPodForCR(at, DefaultImage)
+		Expect(r.Create(ctx, pod)).To(Succeed())
+		pod.Status.Phase = corev1.PodSucceeded
+		Expect(r.Status().Update(ctx, pod)).To(Succeed())
+		at, err = reconcileAndGet(r, "rerun")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhasePending))
+		at, err = reconcileAndGet(r, "rerun")
+		Expect(err).To(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhasePending))
+		ready := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal("InvalidSchedule"))
+	})
+
+	It("moves through RUNNING to DONE and becomes Ready", func() {
+		past := time.Now().UTC().Add(-time.Minute).Format("2006-01-02T15:04:05Z")
+		r := newFakeReconciler(newTestAt("past", past))
+
+		By("transitioning to RUNNING once the schedule is due")
+		at, err := reconcileAndGet(r, "past")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+		ready := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("Running"))
+
+		By("launching the pod")
+		_, err = reconcileAndGet(r, "past")
+		Expect(err).NotTo(HaveOccurred())
+		pod := &corev1.Pod{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "past-pod", Namespace: "default"}, pod)).To(Succeed())
+
+		By("completing once the pod succeeded")
+		pod.Status.Phase = corev1.PodSucceeded
+		Expect(r.Status().Update(ctx, pod)).To(Succeed())
+		at, err = reconcileAndGet(r, "past")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseDone))
+		ready = apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		Expect(ready.Reason).To(Equal("Completed"))
+	})
+})
+
+var _ = Describe("MaxRetries", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "flaky", Namespace: "default"}
+
+	// runningAt returns a reconciler with a RUNNING At whose pod has
+	// restarted the given number of times
+	runningAt := func(maxRetries int, restarts int32) *AtReconciler {
+		at := newTestAt(key.Name, "2000-01-01T00:00:00Z")
+		at.Spec.MaxRetries = maxRetries
+		at.Status.Phase = cnatv1alpha1.PhaseRunning
+		pod := newPodForCR(at, DefaultImage)
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "busybox", RestartCount: restarts}}
+		return newFakeReconciler(at, pod)
+	}
+	reconcileAndGet := func(r *AtReconciler) *cnatv1alpha1.At {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		at := &cnatv1alpha1.At{}
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		return at
+	}
+
+	It("keeps waiting while the pod is within its retries", func() {
+		at := reconcileAndGet(runningAt(3, 3))
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+
+	It("gives up once the pod restarted more than MaxRetries times", func() {
+		r := runningAt(3, 4)
+		at := reconcileAndGet(r)
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseDone))
+		ready := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("RetriesExhausted"))
+
+		err := r.Get(ctx, types.NamespacedName{Name: "flaky-pod", Namespace: "default"}, &corev1.Pod{})
+		Expect(errors.IsNotFound(err)).To(BeTrue(), "expected the pod to be deleted, got %v", err)
+	})
+
+	It("never gives up without a limit", func() {
+		at := reconcileAndGet(runningAt(0, 100))
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+})
+
+var _ = Describe("Reconcile timeout", func() {
+	// hangingReconciler returns a reconciler whose client blocks every Get
+	// until the request context is done, like a hung API server would
+	hangingReconciler := func(timeout time.Duration) *AtReconciler {
+		r := newFakeReconciler(newTestAt("hung", "2099-01-01T00:00:00Z"))
+		r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		})
+		r.ReconcileTimeout = timeout
+		return r
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "hung", Namespace: "default"}}
+
+	It("fails instead of blocking when the context has already expired", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 0)
+		defer cancel()
+
+		_, err := hangingReconciler(0).Reconcile(ctx, req)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("applies ReconcileTimeout to the incoming context", func() {
+		start := time.Now()
+		_, err := hangingReconciler(50*time.Millisecond).Reconcile(context.Background(), req)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
+
+var _ = Describe("Schedule annotations", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "annotated", Namespace: "default"}
+	past := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+
+	// annotatedAt returns an At with the given schedule and annotations
+	annotatedAt := func(schedule string, annotations map[string]string) *cnatv1alpha1.At {
+		at := newTestAt(key.Name, schedule)
+		at.Annotations = annotations
+		return at
+	}
+	reconcileAndGet := func(r *AtReconciler) (reconcile.Result, *cnatv1alpha1.At, error) {
+		result, reconcileErr := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		at := &cnatv1alpha1.At{}
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		return result, at, reconcileErr
+	}
+
+	It("keeps a paused At pending until the annotation is removed", func() {
+		r := newFakeReconciler(annotatedAt(past, map[string]string{cnatv1alpha1.PauseAnnotation: "true"}))
+
+		By("staying PENDING while paused, even though the schedule is due")
+		result, at, err := reconcileAndGet(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhasePending))
+		ready := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal("Paused"))
+
+		By("running once the annotation is removed")
+		delete(at.Annotations, cnatv1alpha1.PauseAnnotation)
+		Expect(r.Update(ctx, at)).To(Succeed())
+		_, at, err = reconcileAndGet(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+
+	It("ignores a pause annotation that is not \"true\"", func() {
+		r := newFakeReconciler(annotatedAt(past, map[string]string{cnatv1alpha1.PauseAnnotation: "false"}))
+
+		_, at, err := reconcileAndGet(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+
+	It("delays a due schedule to the override timestamp", func() {
+		r := newFakeReconciler(annotatedAt(past, map[string]string{cnatv1alpha1.OverrideScheduleAnnotation: future}))
+
+		result, at, err := reconcileAndGet(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhasePending))
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+	})
+
+	It("runs early when the override timestamp is due", func() {
+		r := newFakeReconciler(annotatedAt(future, map[string]string{cnatv1alpha1.OverrideScheduleAnnotation: past}))
+
+		_, at, err := reconcileAndGet(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+
+	It("reports an invalid override timestamp", func() {
+		r := newFakeReconciler(annotatedAt(future, map[string]string{cnatv1alpha1.OverrideScheduleAnnotation: "tomorrow"}))
+
+		_, at, err := reconcileAndGet(r)
+		Expect(err).To(HaveOccurred())
+		scheduled := apimeta.FindStatusCondition(at.Status.Conditions, cnatv1alpha1.ConditionScheduled)
+		Expect(scheduled).NotTo(BeNil())
+		Expect(scheduled.Reason).To(Equal("InvalidSchedule"))
+	})
+
+	It("returns to the cron schedule once the overridden run has happened", func() {
+		at := annotatedAt("0 0 1 1 *", map[string]string{cnatv1alpha1.OverrideScheduleAnnotation: past})
+		at.Spec.Recurring = true
+		lastRun := metav1.NewTime(time.Now())
+		at.Status.LastRunTime = &lastRun
+
+		d, err := timeUntilNextRun(at)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("newPodForCR", func() {
+	It("runs the command in a busybox container without a pod template", func() {
+		at := newTestAt("legacy", "2099-01-01T00:00:00Z")
+
+		pod := newPodForCR(at, "busybox:1.36")
+		Expect(pod.Name).To(Equal("legacy-pod"))
+		Expect(pod.Labels).To(HaveKeyWithValue("app", "legacy"))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyOnFailure))
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Containers[0].Image).To(Equal("busybox:1.36"))
+		Expect(pod.Spec.Containers[0].Command).To(Equal([]string{"echo", "YAY"}))
+	})
+
+	It("builds the pod from the template, overriding the first container's command", func() {
+		at := newTestAt("templated", "2099-01-01T00:00:00Z")
+		at.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "ops"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name:    "runner",
+						Image:   "alpine:3.20",
+						Command: []string{"sleep", "60"},
+						Env:     []corev1.EnvVar{{Name: "GREETING", Value: "hello"}},
+					},
+					{Name: "sidecar", Image: "nginx", Command: []string{"nginx"}},
+				},
+				RestartPolicy: corev1.RestartPolicyNever,
+			},
+		}
+
+		pod := newPodForCR(at, DefaultImage)
+		Expect(pod.Name).To(Equal("templated-pod"))
+		Expect(pod.Namespace).To(Equal("default"))
+		Expect(pod.Labels).To(Equal(map[string]string{"app": "templated", "team": "ops"}))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.Containers[0].Image).To(Equal("alpine:3.20"))
+		Expect(pod.Spec.Containers[0].Command).To(Equal([]string{"echo", "YAY"}))
+		Expect(pod.Spec.Containers[0].Env).To(ConsistOf(corev1.EnvVar{Name: "GREETING", Value: "hello"}))
+		Expect(pod.Spec.Containers[1].Command).To(Equal([]string{"nginx"}))
+
+		By("leaving the template of the At untouched")
+		Expect(at.Spec.PodTemplateSpec.Labels).To(Equal(map[string]string{"team": "ops"}))
+		Expect(at.Spec.PodTemplateSpec.Spec.Containers[0].Command).To(Equal([]string{"sleep", "60"}))
+	})
+
+	It("fills in the image and restart policy the template leaves empty", func() {
+		at := newTestAt("bare", "2099-01-01T00:00:00Z")
+		at.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "runner"}}},
+		}
+
+		pod := newPodForCR(at, "busybox:1.36")
+		Expect(pod.Labels).To(Equal(map[string]string{"app": "bare"}))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyOnFailure))
+		Expect(pod.Spec.Containers[0].Image).To(Equal("busybox:1.36"))
+	})
+})
+
+var _ = Describe("Dry run", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "dry", Namespace: "default"}
+
+	It("computes the transitions without persisting the status or the pod", func() {
+		r := newFakeReconciler(newTestAt(key.Name, "2000-01-01T00:00:00Z"))
+		r.DryRun = true
+
+		By("moving a due At to RUNNING without storing the phase")
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		at := &cnatv1alpha1.At{}
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		Expect(at.Status.Phase).To(BeEmpty())
+		Expect(at.Status.Conditions).To(BeEmpty())
+
+		By("not creating the pod of a RUNNING At")
+		at.Status.Phase = cnatv1alpha1.PhaseRunning
+		r = newFakeReconciler(at)
+		r.DryRun = true
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		pods := &corev1.PodList{}
+		Expect(r.List(ctx, pods)).To(Succeed())
+		Expect(pods.Items).To(BeEmpty())
+	})
+
+	It("does not delete the pod once retries are exhausted", func() {
+		at := newTestAt(key.Name, "2000-01-01T00:00:00Z")
+		at.Spec.MaxRetries = 1
+		at.Status.Phase = cnatv1alpha1.PhaseRunning
+		pod := newPodForCR(at, DefaultImage)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "busybox", RestartCount: 2}}
+		r := newFakeReconciler(at, pod)
+		r.DryRun = true
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{})).To(Succeed())
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseRunning))
+	})
+})
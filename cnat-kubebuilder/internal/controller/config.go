@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ConfigMapName is the ConfigMap, in the controller's namespace, holding
+	// the controller configuration.
+	ConfigMapName = "cnat-controller-config"
+	// DefaultImage runs the command when no image is configured.
+	DefaultImage = "busybox"
+
+	// ConfigMap keys read into ControllerConfig
+	configKeyDefaultImage     = "defaultImage"
+	configKeyMaxRetries       = "maxRetries"
+	configKeyReconcileTimeout = "reconcileTimeout"
+)
+
+// ControllerConfig is the At controller behavior that can change at runtime.
+type ControllerConfig struct {
+	// DefaultImage is the image of the pods running the commands.
+	DefaultImage string
+	// MaxRetries applies to At resources that don't set Spec.MaxRetries.
+	// 0 means no limit.
+	MaxRetries int
+	// ReconcileTimeout bounds each reconcile loop. Zero means no timeout.
+	ReconcileTimeout time.Duration
+}
+
+// parseControllerConfig returns defaults overridden by the keys set in data.
+func parseControllerConfig(data map[string]string, defaults ControllerConfig) (ControllerConfig, error) {
+	config := defaults
+	if image, ok := data[configKeyDefaultImage]; ok {
+		if image == "" {
+			return ControllerConfig{}, fmt.Errorf("%s must not be empty", configKeyDefaultImage)
+		}
+		config.DefaultImage = image
+	}
+	if value, ok := data[configKeyMaxRetries]; ok {
+		maxRetries, err := strconv.Atoi(value)
+		if err != nil || maxRetries < 0 {
+			return ControllerConfig{}, fmt.Errorf("%s must be a non-negative integer, got %q", configKeyMaxRetries, value)
+		}
+		config.MaxRetries = maxRetries
+	}
+	if value, ok := data[configKeyReconcileTimeout]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return ControllerConfig{}, fmt.Errorf("%s must be a non-negative duration, got %q", configKeyReconcileTimeout, value)
+		}
+		config.ReconcileTimeout = timeout
+	}
+	return config, nil
+}
+
+// ConfigMapWatcher watches the controller ConfigMap and keeps the current
+// ControllerConfig, so the AtReconciler picks up changes without a restart.
+// Keys missing from the ConfigMap (or the whole ConfigMap) use the defaults;
+// an invalid ConfigMap is reported and the previous configuration is kept.
+type ConfigMapWatcher struct {
+	client.Client
+	// Key is the namespace/name of the watched ConfigMap.
+	Key      types.NamespacedName
+	defaults ControllerConfig
+	current  atomic.Value // ControllerConfig
+}
+
+// NewConfigMapWatcher returns a ConfigMapWatcher for the ConfigMapName
+// ConfigMap in namespace, serving defaults until the ConfigMap is read.
+func NewConfigMapWatcher(c client.Client, namespace string, defaults ControllerConfig) *ConfigMapWatcher {
+	w := &ConfigMapWatcher{
+		Client:   c,
+		Key:      types.NamespacedName{Namespace: namespace, Name: ConfigMapName},
+		defaults: defaults,
+	}
+	w.current.Store(defaults)
+	return w
+}
+
+// Config returns the current controller configuration.
+func (w *ConfigMapWatcher) Config() ControllerConfig {
+	return w.current.Load().(ControllerConfig)
+}
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Reconcile reloads the configuration from the ConfigMap.
+func (w *ConfigMapWatcher) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("configmap", req.NamespacedName)
+	cm := &corev1.ConfigMap{}
+	if err := w.Get(ctx, req.NamespacedName, cm); err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Controller ConfigMap not found, using defaults")
+			w.current.Store(w.defaults)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	config, err := parseControllerConfig(cm.Data, w.defaults)
+	if err != nil {
+		// Keep the previous configuration; retrying won't help until the
+		// ConfigMap is fixed, which triggers another reconcile
+		logger.Error(err, "Invalid controller ConfigMap, keeping the previous configuration")
+		return reconcile.Result{}, nil
+	}
+	if config != w.Config() {
+		logger.Info("Controller configuration updated", "defaultImage", config.DefaultImage,
+			"maxRetries", config.MaxRetries, "reconcileTimeout", config.ReconcileTimeout)
+	}
+	w.current.Store(config)
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the watcher with the Manager, only reconciling
+// events for the watched ConfigMap.
+func (w *ConfigMapWatcher) SetupWithManager(mgr ctrl.Manager) error {
+	isConfigMap := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == w.Key.Namespace && obj.GetName() == w.Key.Name
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isConfigMap)).
+		Named("configmap-watcher").
+		Complete(w)
+}
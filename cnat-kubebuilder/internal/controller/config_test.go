@@ -0,0 +1,122 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+)
+
+var _ = Describe("ConfigMapWatcher", func() {
+	ctx := context.Background()
+	defaults := ControllerConfig{DefaultImage: DefaultImage, ReconcileTimeout: 30 * time.Second}
+	configKey := types.NamespacedName{Namespace: "cnat-system", Name: ConfigMapName}
+
+	newConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: configKey.Namespace, Name: configKey.Name},
+			Data:       data,
+		}
+	}
+	reload := func(w *ConfigMapWatcher) {
+		_, err := w.Reconcile(ctx, reconcile.Request{NamespacedName: configKey})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("serves the defaults until the ConfigMap exists", func() {
+		r := newFakeReconciler()
+		w := NewConfigMapWatcher(r.Client, configKey.Namespace, defaults)
+		Expect(w.Config()).To(Equal(defaults))
+		reload(w)
+		Expect(w.Config()).To(Equal(defaults))
+	})
+
+	It("overrides the defaults with the keys set in the ConfigMap", func() {
+		r := newFakeReconciler(newConfigMap(map[string]string{"maxRetries": "4", "reconcileTimeout": "5s"}))
+		w := NewConfigMapWatcher(r.Client, configKey.Namespace, defaults)
+		reload(w)
+		Expect(w.Config()).To(Equal(ControllerConfig{DefaultImage: DefaultImage, MaxRetries: 4, ReconcileTimeout: 5 * time.Second}))
+	})
+
+	It("keeps the previous configuration when the ConfigMap is invalid", func() {
+		cm := newConfigMap(map[string]string{"maxRetries": "2"})
+		r := newFakeReconciler(cm)
+		w := NewConfigMapWatcher(r.Client, configKey.Namespace, defaults)
+		reload(w)
+
+		cm.Data = map[string]string{"maxRetries": "-1"}
+		Expect(r.Update(ctx, cm)).To(Succeed())
+		reload(w)
+		Expect(w.Config().MaxRetries).To(Equal(2))
+	})
+
+	It("is picked up by the reconciler without a restart", func() {
+		past := "2000-01-01T00:00:00Z"
+		cm := newConfigMap(map[string]string{"defaultImage": "alpine:3.20"})
+		r := newFakeReconciler(cm, newTestAt("first", past), newTestAt("second", past))
+		w := NewConfigMapWatcher(r.Client, configKey.Namespace, defaults)
+		r.Config = w
+		reload(w)
+
+		// podImage reconciles the At through PENDING and into RUNNING, and
+		// returns the image of the pod it launched
+		podImage := func(name string) string {
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+			for range 2 {
+				_, err := r.Reconcile(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			pod := &corev1.Pod{}
+			Expect(r.Get(ctx, types.NamespacedName{Namespace: "default", Name: name + "-pod"}, pod)).To(Succeed())
+			return pod.Spec.Containers[0].Image
+		}
+
+		Expect(podImage("first")).To(Equal("alpine:3.20"))
+
+		By("updating the ConfigMap")
+		cm.Data["defaultImage"] = "alpine:3.21"
+		Expect(r.Update(ctx, cm)).To(Succeed())
+		reload(w)
+		Expect(podImage("second")).To(Equal("alpine:3.21"))
+	})
+
+	It("applies the configured retry limit to At resources without one", func() {
+		at := newTestAt("flaky", "2000-01-01T00:00:00Z")
+		at.Status.Phase = cnatv1alpha1.PhaseRunning
+		pod := newPodForCR(at, DefaultImage)
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "busybox", RestartCount: 3}}
+		r := newFakeReconciler(newConfigMap(map[string]string{"maxRetries": "2"}), at, pod)
+		r.Config = NewConfigMapWatcher(r.Client, configKey.Namespace, defaults)
+		reload(r.Config)
+
+		key := types.NamespacedName{Namespace: "default", Name: "flaky"}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Get(ctx, key, at)).To(Succeed())
+		Expect(at.Status.Phase).To(Equal(cnatv1alpha1.PhaseDone))
+	})
+})
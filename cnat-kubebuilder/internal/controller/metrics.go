@@ -0,0 +1,116 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+)
+
+const (
+	// phaseUnknown labels reconciles that failed before the At could be read
+	phaseUnknown = "UNKNOWN"
+
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	// reconcileTotal counts reconciles by the phase the At was in and whether
+	// the reconcile returned an error.
+	reconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cnat_at_reconcile_total",
+			Help: "Total number of At reconciles, by starting phase and result.",
+		},
+		[]string{"phase", "result"},
+	)
+
+	// phaseTransitionDuration observes how long an At spent in a phase before
+	// moving to the next one.
+	phaseTransitionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cnat_at_phase_transition_duration_seconds",
+			Help:    "Time an At spent in a phase before transitioning, by from and to phase.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"from", "to"},
+	)
+
+	// activeResources tracks At resources that have not reached DONE yet.
+	activeResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cnat_at_active_resources",
+			Help: "Number of At resources that are not DONE, per namespace.",
+		},
+		[]string{"namespace"},
+	)
+)
+
+func init() {
+	// Register with the controller-runtime registry so the metrics are served
+	// by the manager's metrics server alongside the built-in ones.
+	metrics.Registry.MustRegister(reconcileTotal, phaseTransitionDuration, activeResources)
+}
+
+// recordReconcile counts a finished reconcile
+func recordReconcile(phase string, err error) {
+	result := resultSuccess
+	if err != nil {
+		result = resultError
+	}
+	reconcileTotal.WithLabelValues(phase, result).Inc()
+}
+
+// observePhaseTransition records the time spent in from before moving to to
+func observePhaseTransition(from, to string, inPhase time.Duration) {
+	phaseTransitionDuration.WithLabelValues(from, to).Observe(inPhase.Seconds())
+}
+
+// phaseStartTime approximates when the At entered its current phase: the
+// last run for RUNNING (and for PENDING once a recurring At has run),
+// otherwise its creation.
+func phaseStartTime(instance *cnatv1alpha1.At) time.Time {
+	if instance.Status.LastRunTime != nil {
+		return instance.Status.LastRunTime.Time
+	}
+	return instance.CreationTimestamp.Time
+}
+
+// updateActiveResources recounts the At resources in namespace that are not
+// DONE. The list is served from the manager's cache.
+func (r *AtReconciler) updateActiveResources(ctx context.Context, namespace string) {
+	ats := &cnatv1alpha1.AtList{}
+	if err := r.List(ctx, ats, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to count active At resources", "namespace", namespace)
+		return
+	}
+	active := 0
+	for i := range ats.Items {
+		if ats.Items[i].Status.Phase != cnatv1alpha1.PhaseDone {
+			active++
+		}
+	}
+	activeResources.WithLabelValues(namespace).Set(float64(active))
+}
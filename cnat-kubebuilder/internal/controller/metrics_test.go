@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+)
+
+// transitionCount returns how many from→to phase transitions were observed
+func transitionCount(from, to string) uint64 {
+	metric := &dto.Metric{}
+	histogram := phaseTransitionDuration.WithLabelValues(from, to).(prometheus.Histogram)
+	Expect(histogram.Write(metric)).To(Succeed())
+	return metric.GetHistogram().GetSampleCount()
+}
+
+var _ = Describe("At metrics", func() {
+	ctx := context.Background()
+	const namespace = "metrics"
+
+	reconcileAt := func(r *AtReconciler, name string) error {
+		key := types.NamespacedName{Name: name, Namespace: namespace}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		return err
+	}
+
+	It("counts reconciles by phase and result", func() {
+		past := time.Now().UTC().Add(-time.Minute).Format("2006-01-02T15:04:05Z")
+		due := newTestAt("due", past)
+		due.Namespace = namespace
+		invalid := newTestAt("invalid", "tomorrow")
+		invalid.Namespace = namespace
+		r := newFakeReconciler(due, invalid)
+
+		pendingSuccess := testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhasePending, resultSuccess))
+		pendingError := testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhasePending, resultError))
+		runningSuccess := testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhaseRunning, resultSuccess))
+		transitions := transitionCount(cnatv1alpha1.PhasePending, cnatv1alpha1.PhaseRunning)
+
+		Expect(reconcileAt(r, "due")).To(Succeed())
+		Expect(reconcileAt(r, "due")).To(Succeed())
+		Expect(reconcileAt(r, "invalid")).NotTo(Succeed())
+
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhasePending, resultSuccess))).
+			To(Equal(pendingSuccess + 1))
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhaseRunning, resultSuccess))).
+			To(Equal(runningSuccess + 1))
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(cnatv1alpha1.PhasePending, resultError))).
+			To(Equal(pendingError + 1))
+
+		By("observing the PENDING to RUNNING transition")
+		Expect(transitionCount(cnatv1alpha1.PhasePending, cnatv1alpha1.PhaseRunning)).To(Equal(transitions + 1))
+
+		By("counting both At resources as active")
+		Expect(testutil.ToFloat64(activeResources.WithLabelValues(namespace))).To(Equal(2.0))
+	})
+
+	It("counts reconciles of missing At resources as unknown", func() {
+		r := newFakeReconciler()
+		before := testutil.ToFloat64(reconcileTotal.WithLabelValues(phaseUnknown, resultSuccess))
+
+		Expect(reconcileAt(r, "gone")).To(Succeed())
+
+		Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues(phaseUnknown, resultSuccess))).To(Equal(before + 1))
+	})
+})
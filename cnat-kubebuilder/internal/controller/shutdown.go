@@ -0,0 +1,134 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// InFlightReconcile is a reconcile still running when shutdown started
+type InFlightReconcile struct {
+	Request reconcile.Request
+	Started time.Time
+}
+
+// inFlight is a running reconcile and the cancel func of its context
+type inFlight struct {
+	InFlightReconcile
+	cancel context.CancelFunc
+}
+
+// ShutdownTracker counts the reconciles in flight so that, on SIGTERM, the
+// manager can let them finish instead of cutting them off mid-way (e.g.
+// between creating a pod and recording it in the At status).
+//
+// Track wraps a reconciler so every Reconcile call is counted. Once Drain
+// is called, new calls are turned away and Drain waits for the running
+// ones, up to a timeout after which they are cancelled.
+type ShutdownTracker struct {
+	wg sync.WaitGroup
+
+	mu       sync.Mutex
+	draining bool
+	nextID   uint64
+	running  map[uint64]inFlight
+}
+
+// NewShutdownTracker returns a tracker with no reconcile in flight.
+func NewShutdownTracker() *ShutdownTracker {
+	return &ShutdownTracker{running: map[uint64]inFlight{}}
+}
+
+// Track returns r wrapped so the tracker sees each of its reconciles. The
+// wrapped reconcile runs under a context detached from the manager's, which
+// controller-runtime cancels as soon as shutdown starts; the tracker cancels
+// it itself when Drain times out. Requests arriving while draining are
+// requeued for the next leader without running.
+func (t *ShutdownTracker) Track(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		id, ok := t.begin(req, cancel)
+		if !ok {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		defer t.end(id)
+		return r.Reconcile(ctx, req)
+	})
+}
+
+// begin records a reconcile of req, unless the tracker is draining
+func (t *ShutdownTracker) begin(req reconcile.Request, cancel context.CancelFunc) (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return 0, false
+	}
+	t.nextID++
+	t.running[t.nextID] = inFlight{
+		InFlightReconcile: InFlightReconcile{Request: req, Started: time.Now()},
+		cancel:            cancel,
+	}
+	t.wg.Add(1)
+	return t.nextID, true
+}
+
+// end records that the reconcile id returned
+func (t *ShutdownTracker) end(id uint64) {
+	t.mu.Lock()
+	delete(t.running, id)
+	t.mu.Unlock()
+	t.wg.Done()
+}
+
+// Drain stops the tracker from accepting new reconciles and waits up to
+// timeout for the running ones to return. The reconciles still running then
+// are cancelled and returned, oldest first; nil means every reconcile
+// completed. A zero timeout cancels the running reconciles right away.
+func (t *ShutdownTracker) Drain(timeout time.Duration) []InFlightReconcile {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var cancelled []InFlightReconcile
+	for _, running := range t.running {
+		running.cancel()
+		cancelled = append(cancelled, running.InFlightReconcile)
+	}
+	sort.Slice(cancelled, func(i, j int) bool { return cancelled[i].Started.Before(cancelled[j].Started) })
+	return cancelled
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// slowReconciler takes duration to reconcile, or returns early with the
+// error of its context when that is cancelled
+type slowReconciler struct {
+	duration time.Duration
+	started  chan struct{}
+	finished chan error
+}
+
+func newSlowReconciler(duration time.Duration) *slowReconciler {
+	return &slowReconciler{duration: duration, started: make(chan struct{}, 1), finished: make(chan error, 1)}
+}
+
+func (r *slowReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	r.started <- struct{}{}
+	select {
+	case <-time.After(r.duration):
+		r.finished <- nil
+	case <-ctx.Done():
+		r.finished <- ctx.Err()
+	}
+	return reconcile.Result{}, nil
+}
+
+// shutdownHelperEnv makes the test binary act as a manager running one slow
+// reconcile: it prints "started", drains on SIGTERM the way cmd/main.go
+// does, prints the outcome and exits. The variable holds the reconcile
+// duration and the shutdown timeout, e.g. "300ms,5s".
+const shutdownHelperEnv = "CNAT_TEST_SHUTDOWN_HELPER"
+
+// shutdownHelper is the helper process of shutdownHelperEnv
+func shutdownHelper(spec string) {
+	durations := strings.Split(spec, ",")
+	duration, _ := time.ParseDuration(durations[0])
+	timeout, _ := time.ParseDuration(durations[1])
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+	slow := newSlowReconciler(duration)
+	tracker := NewShutdownTracker()
+	go tracker.Track(slow).Reconcile(ctx, reconcile.Request{}) //nolint:errcheck
+	<-slow.started
+	fmt.Println("started")
+
+	<-ctx.Done()
+	cancelled := tracker.Drain(timeout)
+	fmt.Printf("reconcile returned: %v\n", <-slow.finished)
+	fmt.Printf("cancelled: %d\n", len(cancelled))
+	os.Exit(0)
+}
+
+func TestMain(m *testing.M) {
+	if spec := os.Getenv(shutdownHelperEnv); spec != "" {
+		shutdownHelper(spec)
+	}
+	os.Exit(m.Run())
+}
+
+var _ = Describe("ShutdownTracker", func() {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "slow"}}
+
+	// sigtermHelper starts the shutdown helper with the reconcile duration
+	// and shutdown timeout, sends it SIGTERM once its reconcile is running
+	// and returns its output and how long it took to exit after the signal
+	sigtermHelper := func(duration, timeout time.Duration) (string, time.Duration) {
+		cmd := exec.Command(os.Args[0])
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s,%s", shutdownHelperEnv, duration, timeout))
+		stdout, err := cmd.StdoutPipe()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Start()).To(Succeed())
+		DeferCleanup(func() { _ = cmd.Process.Kill() })
+
+		reader := bufio.NewReader(stdout)
+		line, err := reader.ReadString('\n')
+		Expect(err).NotTo(HaveOccurred())
+		Expect(line).To(Equal("started\n"))
+
+		Expect(cmd.Process.Signal(syscall.SIGTERM)).To(Succeed())
+		signalled := time.Now()
+		rest, err := io.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.Wait()).To(Succeed())
+		return string(rest), time.Since(signalled)
+	}
+
+	It("waits on SIGTERM for the in-flight reconcile to complete before exiting", func() {
+		output, waited := sigtermHelper(500*time.Millisecond, 10*time.Second)
+
+		// The reconcile ran to completion, although the signal cancelled the
+		// context the manager passed it
+		Expect(output).To(Equal("reconcile returned: <nil>\ncancelled: 0\n"))
+		Expect(waited).To(BeNumerically(">=", 400*time.Millisecond))
+		Expect(waited).To(BeNumerically("<", 10*time.Second))
+	})
+
+	It("cancels the reconciles still running at the shutdown timeout", func() {
+		output, waited := sigtermHelper(time.Minute, 300*time.Millisecond)
+
+		Expect(output).To(Equal("reconcile returned: context canceled\ncancelled: 1\n"))
+		Expect(waited).To(BeNumerically(">=", 300*time.Millisecond))
+		Expect(waited).To(BeNumerically("<", time.Minute))
+	})
+
+	It("reports the cancelled requests, oldest first", func() {
+		tracker := NewShutdownTracker()
+		first, second := newSlowReconciler(time.Minute), newSlowReconciler(time.Minute)
+		go tracker.Track(first).Reconcile(context.Background(), request) //nolint:errcheck
+		Eventually(first.started).Should(Receive())
+		other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+		go tracker.Track(second).Reconcile(context.Background(), other) //nolint:errcheck
+		Eventually(second.started).Should(Receive())
+
+		cancelled := tracker.Drain(50 * time.Millisecond)
+		Expect(cancelled).To(HaveLen(2))
+		Expect(cancelled[0].Request).To(Equal(request))
+		Expect(cancelled[1].Request).To(Equal(other))
+		Eventually(first.finished).Should(Receive(MatchError(context.Canceled)))
+		Eventually(second.finished).Should(Receive(MatchError(context.Canceled)))
+	})
+
+	It("turns away reconciles once draining", func() {
+		tracker := NewShutdownTracker()
+		Expect(tracker.Drain(time.Second)).To(BeEmpty())
+
+		slow := newSlowReconciler(0)
+		result, err := tracker.Track(slow).Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(slow.started).NotTo(Receive())
+	})
+})
@@ -0,0 +1,34 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook holds the webhooks that span several At API versions.
+package webhook
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	cnatv1beta1 "Kubernetes_Programming/api/v1beta1"
+)
+
+// SetupAtConversionWebhookWithManager serves the /convert endpoint the API
+// server calls to convert At resources between v1alpha1 and v1beta1.
+//
+// v1beta1 is the hub (see its Hub method) and v1alpha1 converts to and from
+// it with ConvertTo and ConvertFrom; Go keeps those methods next to the types.
+// Both versions must be registered in the manager's scheme.
+func SetupAtConversionWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&cnatv1beta1.At{}).Complete()
+}
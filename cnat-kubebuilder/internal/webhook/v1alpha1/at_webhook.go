@@ -0,0 +1,72 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var atlog = logf.Log.WithName("at-resource")
+
+// SetupAtWebhookWithManager registers the webhook for At in the manager.
+func SetupAtWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&cnatv1alpha1.At{}).
+		WithDefaulter(&AtCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-cnat-programming-kubernetes-info-v1alpha1-at,mutating=true,failurePolicy=fail,sideEffects=None,groups=cnat.programming-kubernetes.info,resources=ats,verbs=create;update,versions=v1alpha1,name=mat-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// AtCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind At when those are created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+type AtCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &AtCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind At.
+func (d *AtCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	at, ok := obj.(*cnatv1alpha1.At)
+
+	if !ok {
+		return fmt.Errorf("expected an At object but got %T", obj)
+	}
+	atlog.Info("Defaulting for At", "name", at.GetName())
+
+	if template := at.Spec.PodTemplateSpec; template != nil {
+		// The command runs with the same restart policy as in the default
+		// busybox pod, so MaxRetries applies to templated pods too
+		if template.Spec.RestartPolicy == "" {
+			template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	cnatv1alpha1 "Kubernetes_Programming/api/v1alpha1"
+)
+
+var _ = Describe("At Webhook", func() {
+	var (
+		obj       *cnatv1alpha1.At
+		defaulter AtCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &cnatv1alpha1.At{
+			Spec: cnatv1alpha1.AtSpec{Schedule: "2099-01-01T00:00:00Z", Command: "echo YAY"},
+		}
+		defaulter = AtCustomDefaulter{}
+	})
+
+	Context("When creating At under Defaulting Webhook", func() {
+		It("leaves an At without a pod template unchanged", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodTemplateSpec).To(BeNil())
+		})
+
+		It("defaults the restart policy of the pod template to OnFailure", func() {
+			obj.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "runner", Image: "alpine"}}},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodTemplateSpec.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyOnFailure))
+		})
+
+		It("keeps the restart policy set in the pod template", func() {
+			obj.Spec.PodTemplateSpec = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Name: "runner", Image: "alpine"}},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.PodTemplateSpec.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		})
+
+		It("rejects objects that are not an At", func() {
+			Expect(defaulter.Default(ctx, &corev1.Pod{})).NotTo(Succeed())
+		})
+	})
+})
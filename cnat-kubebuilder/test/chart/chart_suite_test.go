@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// helmBinary is the helm used to render the chart: $HELM (set by make test),
+// bin/helm (make helm) or helm from the PATH. The specs are skipped without one.
+var helmBinary string
+
+// TestChart renders the Helm chart with helm template and checks the manifests.
+func TestChart(t *testing.T) {
+	RegisterFailHandler(Fail)
+	_, _ = GinkgoWriter.Write([]byte("Starting cnat-kubebuilder chart suite\n"))
+	RunSpecs(t, "chart suite")
+}
+
+var _ = BeforeSuite(func() {
+	candidates := []string{os.Getenv("HELM"), filepath.Join("..", "..", "bin", "helm"), "helm"}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		if path, err := exec.LookPath(candidate); err == nil {
+			helmBinary = path
+			return
+		}
+	}
+})
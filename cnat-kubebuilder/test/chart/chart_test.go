@@ -0,0 +1,194 @@
+/*
+Copyright 2026 Programming Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+// manifest is the part of a rendered document the specs look at
+type manifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace"`
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Replicas   *int `yaml:"replicas"`
+		Conversion *struct {
+			Strategy string `yaml:"strategy"`
+		} `yaml:"conversion"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string   `yaml:"image"`
+					Args  []string `yaml:"args"`
+					Env   []struct {
+						Name  string `yaml:"name"`
+						Value string `yaml:"value"`
+					} `yaml:"env"`
+					Resources map[string]map[string]string `yaml:"resources"`
+				} `yaml:"containers"`
+			} `yaml:"spec"`
+		} `yaml:"template"`
+	} `yaml:"spec"`
+}
+
+// render runs helm template on the chart with the given --set values and
+// decodes every document of the output
+func render(set ...string) []manifest {
+	if helmBinary == "" {
+		Skip("helm is not installed, run make helm")
+	}
+	args := []string{"template", "cnat", filepath.Join("..", "..", "chart"), "--namespace", "cnat-system"}
+	for _, value := range set {
+		args = append(args, "--set", value)
+	}
+	cmd := exec.Command(helmBinary, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	Expect(err).NotTo(HaveOccurred(), "helm template: %s", stderr.String())
+
+	var manifests []manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(out))
+	for {
+		var m manifest
+		err := decoder.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		Expect(err).NotTo(HaveOccurred(), "rendered YAML is invalid:\n%s", out)
+		if m.Kind == "" {
+			// An empty document, e.g. a template disabled by its values
+			continue
+		}
+		Expect(m.APIVersion).NotTo(BeEmpty(), "%s without apiVersion", m.Kind)
+		Expect(m.Metadata.Name).NotTo(BeEmpty(), "%s without a name", m.Kind)
+		manifests = append(manifests, m)
+	}
+	return manifests
+}
+
+// kinds returns the kinds of manifests, in order
+func kinds(manifests []manifest) []string {
+	var kinds []string
+	for _, m := range manifests {
+		kinds = append(kinds, m.Kind)
+	}
+	return kinds
+}
+
+// deployment returns the manager Deployment of manifests
+func deployment(manifests []manifest) manifest {
+	for _, m := range manifests {
+		if m.Kind == "Deployment" {
+			Expect(m.Spec.Template.Spec.Containers).To(HaveLen(1))
+			return m
+		}
+	}
+	Fail("the chart renders no Deployment")
+	return manifest{}
+}
+
+// customResourceDefinition returns the At CRD of manifests
+func customResourceDefinition(manifests []manifest) manifest {
+	for _, m := range manifests {
+		if m.Kind == "CustomResourceDefinition" {
+			return m
+		}
+	}
+	Fail("the chart renders no CustomResourceDefinition")
+	return manifest{}
+}
+
+var _ = Describe("Helm chart", func() {
+	It("renders every resource with the default values", func() {
+		manifests := render()
+		Expect(kinds(manifests)).To(ContainElements(
+			"ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding",
+			"CustomResourceDefinition", "MutatingWebhookConfiguration", "Certificate", "Service", "Deployment"))
+
+		manager := deployment(manifests)
+		Expect(manager.Metadata.Namespace).To(Equal("cnat-system"))
+		Expect(*manager.Spec.Replicas).To(Equal(1))
+		container := manager.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("controller:0.1.0"))
+		Expect(container.Args).To(ContainElements("--leader-elect", "--metrics-bind-address=:8443",
+			"--webhook-cert-path=/tmp/k8s-webhook-server/serving-certs"))
+		Expect(container.Resources["limits"]).To(HaveKeyWithValue("memory", "128Mi"))
+
+		crd := customResourceDefinition(manifests)
+		Expect(crd.Metadata.Annotations).To(HaveKeyWithValue("helm.sh/resource-policy", "keep"))
+		Expect(crd.Metadata.Annotations).To(HaveKeyWithValue("cert-manager.io/inject-ca-from", "cnat-system/cnat-cnat-kubebuilder-serving-cert"))
+		Expect(crd.Spec.Conversion).NotTo(BeNil())
+		Expect(crd.Spec.Conversion.Strategy).To(Equal("Webhook"))
+	})
+
+	It("applies the image, replicas and resources values", func() {
+		manager := deployment(render("image.repository=example.com/cnat", "image.tag=v2",
+			"replicaCount=3", "resources.limits.memory=256Mi"))
+		Expect(*manager.Spec.Replicas).To(Equal(3))
+		container := manager.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal("example.com/cnat:v2"))
+		Expect(container.Resources["limits"]).To(HaveKeyWithValue("memory", "256Mi"))
+	})
+
+	It("turns leader election off explicitly, since the manager defaults to it", func() {
+		manifests := render("leaderElection.enabled=false")
+		Expect(kinds(manifests)).NotTo(ContainElement("Role"))
+		args := deployment(manifests).Spec.Template.Spec.Containers[0].Args
+		Expect(args).To(ContainElement("--leader-elect=false"))
+		Expect(args).NotTo(ContainElement("--leader-elect"))
+	})
+
+	It("leaves out the metrics endpoint", func() {
+		manifests := render("metrics.enabled=false")
+		for _, m := range manifests {
+			Expect(m.Metadata.Name).NotTo(ContainSubstring("metrics"))
+		}
+		Expect(deployment(manifests).Spec.Template.Spec.Containers[0].Args).NotTo(
+			ContainElement(HavePrefix("--metrics-bind-address")))
+	})
+
+	It("disables the webhooks in the manager and the CRD without them", func() {
+		manifests := render("webhook.enabled=false")
+		Expect(kinds(manifests)).NotTo(ContainElements("MutatingWebhookConfiguration", "Certificate"))
+		crd := customResourceDefinition(manifests)
+		Expect(crd.Metadata.Annotations).NotTo(HaveKey("cert-manager.io/inject-ca-from"))
+		Expect(crd.Spec.Conversion).To(BeNil())
+		container := deployment(manifests).Spec.Template.Spec.Containers[0]
+		Expect(container.Args).NotTo(ContainElement(HavePrefix("--webhook-cert-path")))
+		Expect(container.Env).To(ContainElement(HaveField("Name", "ENABLE_WEBHOOKS")))
+	})
+
+	It("renders only the manager with every option off", func() {
+		manifests := render("leaderElection.enabled=false", "metrics.enabled=false",
+			"webhook.enabled=false", "crd.enabled=false")
+		Expect(kinds(manifests)).To(ConsistOf("ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Deployment"))
+	})
+})
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	"golang.org/x/term"
+)
+
+// Modes accepted by --color
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// ANSI escape sequences used to highlight pods
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiDim    = "\x1b[2m"
+)
+
+// colorEnabled resolves a --color mode. In auto mode color is only used when
+// stdout is a terminal and NO_COLOR is not set (https://no-color.org); an
+// explicit --color=always wins over NO_COLOR.
+func colorEnabled(mode string, noColor bool, terminal bool) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto:
+		return terminal && !noColor, nil
+	}
+	return false, fmt.Errorf("unknown --color mode %q (want always, never or auto)", mode)
+}
+
+// stdoutColorEnabled resolves a --color mode for the process' stdout
+func stdoutColorEnabled(mode string) (bool, error) {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return colorEnabled(mode, noColor, term.IsTerminal(int(os.Stdout.Fd())))
+}
+
+// problemReasons are pod statuses shown in red
+var problemReasons = map[string]bool{
+	"CrashLoopBackOff": true,
+	"Error":            true,
+	"Failed":           true,
+	"Unknown":          true,
+}
+
+// podColor returns the color for a pod: green when running with every
+// container ready, yellow while pending, red for failed or crashing pods and
+// dim for completed ones. Other pods are not colored.
+func podColor(info podinfo.PodInfo) string {
+	switch {
+	case problemReasons[info.Reason] || info.Phase == "Failed" || info.Phase == "Unknown":
+		return ansiRed
+	case info.Phase == "Succeeded" || info.Reason == "Completed":
+		return ansiDim
+	case info.Phase == "Pending":
+		return ansiYellow
+	case info.Phase == "Running" && info.ReadyContainers == info.TotalContainers:
+		return ansiGreen
+	}
+	return ""
+}
+
+// colorize wraps s in color, or returns it unchanged when color is empty
+func colorize(s, color string) string {
+	if color == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// writeColoredLines copies table output to w, coloring line i with colors[i].
+// Coloring whole lines after the tabwriter has aligned them keeps the escape
+// sequences out of the column width computation.
+func writeColoredLines(w io.Writer, table []byte, colors []string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(table))
+	for i := 0; scanner.Scan(); i++ {
+		line := scanner.Text()
+		if i < len(colors) {
+			line = colorize(line, colors[i])
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		mode     string
+		noColor  bool
+		terminal bool
+		want     bool
+		wantErr  bool
+	}{
+		{mode: colorAuto, terminal: true, want: true},
+		{mode: colorAuto, terminal: false, want: false},
+		{mode: colorAuto, terminal: true, noColor: true, want: false},
+		{mode: colorAlways, terminal: false, want: true},
+		{mode: colorAlways, noColor: true, want: true},
+		{mode: colorNever, terminal: true, want: false},
+		{mode: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := colorEnabled(tt.mode, tt.noColor, tt.terminal)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("colorEnabled(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("colorEnabled(%q, noColor=%t, terminal=%t) = %t, want %t", tt.mode, tt.noColor, tt.terminal, got, tt.want)
+		}
+	}
+}
+
+func TestPodColor(t *testing.T) {
+	tests := []struct {
+		name string
+		info podinfo.PodInfo
+		want string
+	}{
+		{name: "running and ready", info: podinfo.PodInfo{Phase: "Running", Reason: "Running", ReadyContainers: 2, TotalContainers: 2}, want: ansiGreen},
+		{name: "running not ready", info: podinfo.PodInfo{Phase: "Running", Reason: "Running", ReadyContainers: 1, TotalContainers: 2}, want: ""},
+		{name: "crash looping", info: podinfo.PodInfo{Phase: "Running", Reason: "CrashLoopBackOff", TotalContainers: 1}, want: ansiRed},
+		{name: "pending", info: podinfo.PodInfo{Phase: "Pending", Reason: "ContainerCreating"}, want: ansiYellow},
+		{name: "failed", info: podinfo.PodInfo{Phase: "Failed", Reason: "Evicted"}, want: ansiRed},
+		{name: "unknown", info: podinfo.PodInfo{Phase: "Unknown", Reason: "Unknown"}, want: ansiRed},
+		{name: "succeeded", info: podinfo.PodInfo{Phase: "Succeeded", Reason: "Completed"}, want: ansiDim},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podColor(tt.info); got != tt.want {
+				t.Errorf("podColor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteColoredLines(t *testing.T) {
+	table := []byte("NAME   STATUS\nweb    Running\njob    Completed\n")
+	var out bytes.Buffer
+	if err := writeColoredLines(&out, table, []string{"", ansiGreen, ""}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "NAME   STATUS\n" + ansiGreen + "web    Running" + ansiReset + "\njob    Completed\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/yaml"
+
+	"Kubernetes_Programming/pkg/textdiff"
+)
+
+// exitDrift is the cm diff --exit-code status when the live ConfigMap
+// differs from the file, as with diff(1) and git diff --exit-code
+const exitDrift = 1
+
+// readConfigMapFile reads a ConfigMap manifest in YAML or JSON
+func readConfigMapFile(path string) (*v1.ConfigMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cm v1.ConfigMap
+	if err := yaml.Unmarshal(data, &cm); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if cm.Kind != "" && cm.Kind != "ConfigMap" {
+		return nil, fmt.Errorf("%s describes a %s, not a ConfigMap", path, cm.Kind)
+	}
+	if cm.Name == "" {
+		return nil, fmt.Errorf("%s has no metadata.name", path)
+	}
+	return &cm, nil
+}
+
+// FetchConfigMap returns the live ConfigMap namespace/name
+func FetchConfigMap(ctx context.Context, client kubernetes.Interface, namespace, name string) (*v1.ConfigMap, error) {
+	cm, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "configmaps", namespace)
+	}
+	return cm, nil
+}
+
+// configMapContent renders the data and binaryData of cm as YAML with
+// sorted keys, leaving out the metadata (managed fields, resource version)
+// that always differs between a manifest and the live object
+func configMapContent(cm *v1.ConfigMap) (string, error) {
+	var b strings.Builder
+	sections := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"data", cm.Data, len(cm.Data) == 0},
+		{"binaryData", cm.BinaryData, len(cm.BinaryData) == 0},
+	}
+	for _, section := range sections {
+		if section.empty {
+			continue
+		}
+		data, err := yaml.Marshal(map[string]interface{}{section.name: section.value})
+		if err != nil {
+			return "", err
+		}
+		b.Write(data)
+	}
+	return b.String(), nil
+}
+
+// DiffConfigMaps returns a unified diff from the data and binaryData of
+// remote (the live object) to those of local, or "" when they match
+func DiffConfigMaps(local, remote *v1.ConfigMap) string {
+	// Maps of strings and bytes always marshal
+	from, _ := configMapContent(remote)
+	to, _ := configMapContent(local)
+	return textdiff.Unified("live/"+remote.Namespace+"/"+remote.Name, "local/"+local.Namespace+"/"+local.Name, from, to)
+}
+
+// runConfigMaps implements the cm subcommand and its actions
+func runConfigMaps(clientOpts *clientOptions, args []string) error {
+	if len(args) == 0 || args[0] != "diff" {
+		fmt.Fprintf(os.Stderr, "Usage: %s cm diff [flags]\n", os.Args[0])
+		return errors.New("cm requires an action: diff")
+	}
+	return runConfigMapDiff(clientOpts, args[1:])
+}
+
+// runConfigMapDiff implements cm diff
+func runConfigMapDiff(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("cm diff", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	filename := fs.String("filename", "", "YAML or JSON file describing the ConfigMap")
+	fs.StringVar(filename, "f", "", "shorthand for --filename")
+	namespace := fs.String("namespace", "", "namespace of the ConfigMap (defaults to the file's, or default)")
+	exitCode := fs.Bool("exit-code", false, fmt.Sprintf("exit with status %d when the live ConfigMap differs from the file", exitDrift))
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(outputText); err != nil {
+		return err
+	}
+	if *filename == "" {
+		return errors.New("--filename is required")
+	}
+
+	local, err := readConfigMapFile(*filename)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *namespace == "" && local.Namespace == "":
+		local.Namespace = metav1.NamespaceDefault
+	case *namespace == "":
+	case local.Namespace == "":
+		local.Namespace = *namespace
+	case local.Namespace != *namespace:
+		return fmt.Errorf("the namespace of %s (%s) does not match --namespace %s", *filename, local.Namespace, *namespace)
+	}
+
+	client, _, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+	defer cancel()
+
+	remote, err := FetchConfigMap(ctx, client, local.Namespace, local.Name)
+	if err != nil {
+		return fmt.Errorf("error getting configmap: %w", timeoutError(err, clientOpts.Timeout))
+	}
+
+	diff := DiffConfigMaps(local, remote)
+	err = replaceOutput(*outputFile, func(out io.Writer) error {
+		_, err := io.WriteString(out, diff)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if diff != "" && *exitCode {
+		// fatal exits with the status without logging an error
+		return utilexec.CodeExitError{Err: errors.New("the live ConfigMap differs from the file"), Code: exitDrift}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestConfigMap returns a ConfigMap default/app-config with data
+func newTestConfigMap(data map[string]string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-config"},
+		Data:       data,
+	}
+}
+
+func TestDiffConfigMapsNoDrift(t *testing.T) {
+	local := newTestConfigMap(map[string]string{"mode": "prod", "config.ini": "[server]\nport = 80\n"})
+	remote := newTestConfigMap(map[string]string{"mode": "prod", "config.ini": "[server]\nport = 80\n"})
+	// Metadata the server sets must not count as drift
+	remote.ResourceVersion = "12345"
+	remote.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate}}
+	remote.Annotations = map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+
+	if diff := DiffConfigMaps(local, remote); diff != "" {
+		t.Errorf("DiffConfigMaps() = %q, want no diff", diff)
+	}
+}
+
+func TestDiffConfigMaps(t *testing.T) {
+	local := newTestConfigMap(map[string]string{
+		"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "6", "g": "7", "h": "8", "i": "9", "j": "10", "k": "11",
+		"mode": "prod",
+	})
+	remote := newTestConfigMap(map[string]string{
+		"a": "1", "b": "two", "c": "3", "d": "4", "e": "5", "f": "6", "g": "7", "h": "8", "i": "9", "j": "10", "k": "11",
+	})
+	remote.BinaryData = map[string][]byte{"logo.png": {0x89, 0x50}}
+
+	want := `--- live/default/app-config
++++ local/default/app-config
+@@ -1,6 +1,6 @@
+ data:
+   a: "1"
+-  b: two
++  b: "2"
+   c: "3"
+   d: "4"
+   e: "5"
+@@ -10,5 +10,4 @@
+   i: "9"
+   j: "10"
+   k: "11"
+-binaryData:
+-  logo.png: iVA=
++  mode: prod
+`
+	if diff := DiffConfigMaps(local, remote); diff != want {
+		t.Errorf("DiffConfigMaps() =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+func TestReadConfigMapFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	yamlPath := write("cm.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n  namespace: web\ndata:\n  mode: prod\n")
+	cm, err := readConfigMapFile(yamlPath)
+	if err != nil {
+		t.Fatalf("readConfigMapFile() error = %v", err)
+	}
+	if cm.Name != "app-config" || cm.Namespace != "web" || cm.Data["mode"] != "prod" {
+		t.Errorf("readConfigMapFile() = %+v", cm)
+	}
+
+	jsonPath := write("cm.json", `{"kind": "ConfigMap", "metadata": {"name": "app-config"}, "binaryData": {"blob": "AQI="}}`)
+	cm, err = readConfigMapFile(jsonPath)
+	if err != nil {
+		t.Fatalf("readConfigMapFile() error = %v", err)
+	}
+	if string(cm.BinaryData["blob"]) != "\x01\x02" {
+		t.Errorf("binaryData = %q, want the decoded bytes", cm.BinaryData["blob"])
+	}
+
+	for name, content := range map[string]string{
+		"secret.yaml":  "kind: Secret\nmetadata:\n  name: creds\n",
+		"noname.yaml":  "kind: ConfigMap\ndata:\n  mode: prod\n",
+		"invalid.yaml": "data: [",
+	} {
+		if _, err := readConfigMapFile(write(name, content)); err == nil {
+			t.Errorf("readConfigMapFile(%s) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestFetchConfigMap(t *testing.T) {
+	client := fake.NewSimpleClientset(newTestConfigMap(map[string]string{"mode": "prod"}))
+
+	cm, err := FetchConfigMap(context.Background(), client, "default", "app-config")
+	if err != nil {
+		t.Fatalf("FetchConfigMap() error = %v", err)
+	}
+	if cm.Data["mode"] != "prod" {
+		t.Errorf("FetchConfigMap() data = %v", cm.Data)
+	}
+
+	_, err = FetchConfigMap(context.Background(), client, "default", "missing")
+	var status apierrors.APIStatus
+	if !apierrors.IsNotFound(err) || !errors.As(err, &status) {
+		t.Errorf("FetchConfigMap() of a missing ConfigMap error = %v, want NotFound", err)
+	}
+}
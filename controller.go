@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
+
 	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
 	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
 	cnatscheme "Kubernetes_Programming/pkg/generated/clientset/versioned/scheme"
 	informers "Kubernetes_Programming/pkg/generated/informers/externalversions/cnat/v1alpha1"
 	listers "Kubernetes_Programming/pkg/generated/listers/cnat/v1alpha1"
-	"context"
 
 	"fmt"
 	"reflect"
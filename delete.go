@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// deletePreview is how many pod names the --delete confirmation lists
+const deletePreview = 5
+
+// deleteOptions holds the flags of --delete, passed through to the API
+// server like kubectl delete does
+type deleteOptions struct {
+	// GracePeriod overrides the pods' termination grace period in seconds,
+	// negative to keep it
+	GracePeriod int
+	// Force deletes the pods immediately, skipping graceful termination
+	Force bool
+	// DryRun has the API server validate the deletions without persisting
+	// them
+	DryRun bool
+}
+
+// validate rejects a zero grace period without --force: the API server
+// would treat it as the minimum of one second rather than as immediate
+func (o deleteOptions) validate() error {
+	switch {
+	case o.Force && o.GracePeriod > 0:
+		return fmt.Errorf("--force deletes pods immediately and cannot be combined with --grace-period %d", o.GracePeriod)
+	case !o.Force && o.GracePeriod == 0:
+		return errors.New("--grace-period 0 requires --force, which skips graceful termination")
+	}
+	return nil
+}
+
+// apiOptions returns the DeleteOptions sent with each deletion
+func (o deleteOptions) apiOptions() metav1.DeleteOptions {
+	var opts metav1.DeleteOptions
+	switch {
+	case o.Force:
+		zero := int64(0)
+		opts.GracePeriodSeconds = &zero
+	case o.GracePeriod > 0:
+		seconds := int64(o.GracePeriod)
+		opts.GracePeriodSeconds = &seconds
+	}
+	if o.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	return opts
+}
+
+// podRef names a pod as "namespace/name", prefixed by its cluster when
+// several are listed
+func podRef(info podinfo.PodInfo) string {
+	ref := info.Namespace + "/" + info.Name
+	if info.Cluster != "" {
+		ref = info.Cluster + ":" + ref
+	}
+	return ref
+}
+
+// confirmDelete shows how many pods --delete is about to remove, with the
+// first few names, and reads the answer from in. Only y or yes confirms.
+func confirmDelete(in io.Reader, out io.Writer, infos []podinfo.PodInfo) (bool, error) {
+	names := make([]string, 0, deletePreview)
+	for _, info := range infos[:min(len(infos), deletePreview)] {
+		names = append(names, podRef(info))
+	}
+	preview := strings.Join(names, ", ")
+	if more := len(infos) - len(names); more > 0 {
+		preview += fmt.Sprintf(" and %d more", more)
+	}
+	fmt.Fprintf(out, "Delete %d pods (%s)? [y/N] ", len(infos), preview)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("reading confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// deletePods deletes the pods of infos through the client of their cluster,
+// printing one line per pod so partial failures (e.g. RBAC denials in some
+// namespaces) are visible. It returns how many deletions failed.
+func deletePods(ctx context.Context, out io.Writer, clusters []cluster, infos []podinfo.PodInfo, opts deleteOptions) int {
+	clients := make(map[string]kubernetes.Interface, len(clusters))
+	for _, c := range clusters {
+		clients[c.Name] = c.Client
+	}
+	suffix := ""
+	if opts.DryRun {
+		suffix = " (server dry run)"
+	}
+	apiOpts := opts.apiOptions()
+	failed := 0
+	for _, info := range infos {
+		err := clients[info.Cluster].CoreV1().Pods(info.Namespace).Delete(ctx, info.Name, apiOpts)
+		if err != nil {
+			fmt.Fprintf(out, "pod %s not deleted%s: %v\n", podRef(info), suffix, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "pod %s deleted%s\n", podRef(info), suffix)
+	}
+	return failed
+}
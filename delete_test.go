@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestDeleteOptions(t *testing.T) {
+	int64Ptr := func(v int64) *int64 { return &v }
+	tests := []struct {
+		name    string
+		opts    deleteOptions
+		want    metav1.DeleteOptions
+		wantErr bool
+	}{
+		{name: "defaults", opts: deleteOptions{GracePeriod: -1}},
+		{name: "grace period", opts: deleteOptions{GracePeriod: 30}, want: metav1.DeleteOptions{GracePeriodSeconds: int64Ptr(30)}},
+		{name: "force", opts: deleteOptions{GracePeriod: -1, Force: true}, want: metav1.DeleteOptions{GracePeriodSeconds: int64Ptr(0)}},
+		{name: "force with grace period 0", opts: deleteOptions{Force: true}, want: metav1.DeleteOptions{GracePeriodSeconds: int64Ptr(0)}},
+		{name: "dry run", opts: deleteOptions{GracePeriod: -1, DryRun: true}, want: metav1.DeleteOptions{DryRun: []string{metav1.DryRunAll}}},
+		{name: "grace period 0 without force", opts: deleteOptions{GracePeriod: 0}, wantErr: true},
+		{name: "force with a grace period", opts: deleteOptions{GracePeriod: 10, Force: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		err := tt.opts.validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if got := tt.opts.apiOptions(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: apiOptions() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// podInfos returns infos for the "namespace/name" pods
+func podInfos(refs ...string) []podinfo.PodInfo {
+	var infos []podinfo.PodInfo
+	for _, ref := range refs {
+		namespace, name, _ := strings.Cut(ref, "/")
+		infos = append(infos, podinfo.PodInfo{Namespace: namespace, Name: name})
+	}
+	return infos
+}
+
+func TestConfirmDelete(t *testing.T) {
+	infos := podInfos("batch/a", "batch/b", "batch/c", "batch/d", "batch/e", "batch/f", "batch/g")
+	tests := []struct {
+		answer string
+		want   bool
+	}{
+		{answer: "y\n", want: true},
+		{answer: "YES\n", want: true},
+		{answer: "n\n"},
+		{answer: "\n"},
+		{answer: ""},
+		{answer: "yep\n"},
+	}
+	for _, tt := range tests {
+		var prompt bytes.Buffer
+		got, err := confirmDelete(strings.NewReader(tt.answer), &prompt, infos)
+		if err != nil {
+			t.Fatalf("confirmDelete(%q) error = %v", tt.answer, err)
+		}
+		if got != tt.want {
+			t.Errorf("confirmDelete(%q) = %t, want %t", tt.answer, got, tt.want)
+		}
+		want := "Delete 7 pods (batch/a, batch/b, batch/c, batch/d, batch/e and 2 more)? [y/N] "
+		if prompt.String() != want {
+			t.Errorf("prompt = %q, want %q", prompt.String(), want)
+		}
+	}
+}
+
+func TestDeletePods(t *testing.T) {
+	pod := func(namespace, name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	client := fake.NewSimpleClientset(pod("batch", "failed-1"), pod("batch", "failed-2"), pod("locked", "failed-3"))
+	var sent []metav1.DeleteOptions
+	client.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		sent = append(sent, action.(k8stesting.DeleteAction).GetDeleteOptions())
+		if action.GetNamespace() == "locked" {
+			return true, nil, apierrors.NewForbidden(v1.Resource("pods"), "failed-3", nil)
+		}
+		return false, nil, nil
+	})
+
+	var out bytes.Buffer
+	infos := podInfos("batch/failed-1", "locked/failed-3", "batch/failed-2")
+	opts := deleteOptions{GracePeriod: 5}
+	failed := deletePods(context.Background(), &out, []cluster{{Client: client}}, infos, opts)
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || lines[0] != "pod batch/failed-1 deleted" || lines[2] != "pod batch/failed-2 deleted" ||
+		!strings.HasPrefix(lines[1], "pod locked/failed-3 not deleted: ") || !strings.Contains(lines[1], "forbidden") {
+		t.Errorf("output =\n%s", out.String())
+	}
+	for _, o := range sent {
+		if o.GracePeriodSeconds == nil || *o.GracePeriodSeconds != 5 {
+			t.Errorf("sent DeleteOptions %+v, want a grace period of 5s", o)
+		}
+	}
+	remaining, _ := client.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	if len(remaining.Items) != 1 || remaining.Items[0].Name != "failed-3" {
+		t.Errorf("remaining pods = %v, want only failed-3", remaining.Items)
+	}
+}
+
+func TestDeletePodsDryRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		// The API server validates a dry run without deleting anything
+		opts := action.(k8stesting.DeleteAction).GetDeleteOptions()
+		if !reflect.DeepEqual(opts.DryRun, []string{metav1.DryRunAll}) {
+			t.Errorf("DryRun = %v, want [All]", opts.DryRun)
+		}
+		return true, nil, nil
+	})
+
+	var out bytes.Buffer
+	infos := []podinfo.PodInfo{{Cluster: "prod", Namespace: "batch", Name: "failed-1"}}
+	clusters := []cluster{{Name: "prod", Client: client}}
+	if failed := deletePods(context.Background(), &out, clusters, infos, deleteOptions{GracePeriod: -1, DryRun: true}); failed != 0 {
+		t.Errorf("failed = %d, want 0", failed)
+	}
+	if want := "pod prod:batch/failed-1 deleted (server dry run)\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
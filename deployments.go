@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// degradedMarker flags deployments with fewer available replicas than desired
+const degradedMarker = "[DEGRADED]"
+
+// DeploymentInfo holds formatted deployment information
+type DeploymentInfo struct {
+	Name              string        `json:"name"`
+	Namespace         string        `json:"namespace"`
+	DesiredReplicas   int32         `json:"desiredReplicas"`
+	ReadyReplicas     int32         `json:"readyReplicas"`
+	UpdatedReplicas   int32         `json:"updatedReplicas"`
+	AvailableReplicas int32         `json:"availableReplicas"`
+	Strategy          string        `json:"strategy"`
+	Images            []string      `json:"images"`
+	Degraded          bool          `json:"degraded"`
+	Age               time.Duration `json:"-"`
+	CreatedAt         time.Time     `json:"createdAt"`
+}
+
+// extractDeploymentInfo extracts relevant information from a deployment
+func extractDeploymentInfo(deployment *appsv1.Deployment, now time.Time) DeploymentInfo {
+	// An unset replica count defaults to 1, as in the API server
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	strategy := string(deployment.Spec.Strategy.Type)
+	if strategy == "" {
+		strategy = string(appsv1.RollingUpdateDeploymentStrategyType)
+	}
+	images := []string{}
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return DeploymentInfo{
+		Name:              deployment.Name,
+		Namespace:         deployment.Namespace,
+		DesiredReplicas:   desired,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		Strategy:          strategy,
+		Images:            images,
+		Degraded:          deployment.Status.AvailableReplicas < desired,
+		Age:               now.Sub(deployment.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt:         deployment.CreationTimestamp.Time,
+	}
+}
+
+// readyString returns the ready replicas as "ready/desired"
+func (info DeploymentInfo) readyString() string {
+	return fmt.Sprintf("%d/%d", info.ReadyReplicas, info.DesiredReplicas)
+}
+
+// printDeploymentTable prints one row per deployment, marking degraded ones.
+// The NAMESPACE column is shown when listing across namespaces.
+func printDeploymentTable(out io.Writer, infos []DeploymentInfo, showNamespace bool, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tREADY\tUP-TO-DATE\tAVAILABLE\tSTRATEGY\tAGE\tIMAGES")
+	for _, info := range infos {
+		name := info.Name
+		if info.Degraded {
+			name += " " + degradedMarker
+		}
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+			name, info.readyString(), info.UpdatedReplicas, info.AvailableReplicas, info.Strategy,
+			formatAge(ageFormat, info.Age, info.CreatedAt), strings.Join(info.Images, ","))
+	}
+	return w.Flush()
+}
+
+// printDeploymentInfo prints formatted deployment information
+func printDeploymentInfo(out io.Writer, info DeploymentInfo, ageFormat string) {
+	header := "Deployment: " + info.Name
+	if info.Degraded {
+		header += " " + degradedMarker
+	}
+	fmt.Fprintln(out, header)
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
+	fmt.Fprintf(out, "  Ready: %s\n", info.readyString())
+	fmt.Fprintf(out, "  Up-to-date: %d\n", info.UpdatedReplicas)
+	fmt.Fprintf(out, "  Available: %d\n", info.AvailableReplicas)
+	fmt.Fprintf(out, "  Strategy: %s\n", info.Strategy)
+	fmt.Fprintf(out, "  Images: %s\n", strings.Join(info.Images, ", "))
+	fmt.Fprintf(out, "  Age: %s\n", formatAge(ageFormat, info.Age, info.CreatedAt))
+	fmt.Fprintln(out)
+}
+
+// runDeployments implements the deployments subcommand
+func runDeployments(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("deployments", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list deployments from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter deployments, e.g. app=web,tier!=cache")
+	output := fs.String("output", outputTable, "output format: table, text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table and text output show deployment age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputText, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, text, json or yaml)", *output)
+		}
+		if err := validateAgeFormat(*ageFormat); err != nil {
+			return err
+		}
+		if _, err := labels.Parse(*labelSelector); err != nil {
+			return fmt.Errorf("invalid --label-selector: %w", err)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		deployments, err := client.AppsV1().Deployments(*namespace).List(ctx, metav1.ListOptions{LabelSelector: *labelSelector})
+		if err != nil {
+			return fmt.Errorf("error listing deployments: %w", timeoutError(handleAPIError(err, "deployments", *namespace), clientOpts.Timeout))
+		}
+
+		now := time.Now()
+		infos := []DeploymentInfo{}
+		for i := range deployments.Items {
+			infos = append(infos, extractDeploymentInfo(&deployments.Items[i], now))
+		}
+		switch *output {
+		case outputJSON, outputYAML:
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No deployments found")
+			return nil
+		}
+		if *output == outputText {
+			for _, info := range infos {
+				printDeploymentInfo(out, info, *ageFormat)
+			}
+			return nil
+		}
+		return printDeploymentTable(out, infos, *namespace == "", *ageFormat)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestDeployment returns a deployment with the given replica counts
+func newTestDeployment(name string, desired *int32, available int32, images ...string) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": name}},
+		Spec:       appsv1.DeploymentSpec{Replicas: desired},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: available, UpdatedReplicas: available, AvailableReplicas: available},
+	}
+	for _, image := range images {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, v1.Container{Image: image})
+	}
+	return deployment
+}
+
+func TestExtractDeploymentInfo(t *testing.T) {
+	now := time.Now()
+	three := int32(3)
+	deployment := newTestDeployment("web", &three, 2, "nginx:1.25", "envoy:1.28")
+	deployment.CreationTimestamp = metav1.NewTime(now.Add(-5 * 24 * time.Hour))
+	deployment.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+
+	info := extractDeploymentInfo(deployment, now)
+	if info.readyString() != "2/3" || !info.Degraded {
+		t.Errorf("ready = %s, degraded = %t, want 2/3 and degraded", info.readyString(), info.Degraded)
+	}
+	if info.Strategy != "Recreate" {
+		t.Errorf("strategy = %q, want Recreate", info.Strategy)
+	}
+	if want := []string{"nginx:1.25", "envoy:1.28"}; !reflect.DeepEqual(info.Images, want) {
+		t.Errorf("images = %v, want %v", info.Images, want)
+	}
+	if got := formatAge(ageCompact, info.Age, info.CreatedAt); got != "5d" {
+		t.Errorf("age = %q, want 5d", got)
+	}
+}
+
+func TestExtractDeploymentInfoDefaults(t *testing.T) {
+	info := extractDeploymentInfo(newTestDeployment("single", nil, 1, "app:1"), time.Now())
+	if info.DesiredReplicas != 1 || info.Degraded {
+		t.Errorf("desired = %d, degraded = %t, want 1 and not degraded", info.DesiredReplicas, info.Degraded)
+	}
+	if info.Strategy != "RollingUpdate" {
+		t.Errorf("strategy = %q, want RollingUpdate", info.Strategy)
+	}
+
+	zero := int32(0)
+	if info := extractDeploymentInfo(newTestDeployment("scaled-down", &zero, 0), time.Now()); info.Degraded {
+		t.Error("a deployment scaled to zero is not degraded")
+	}
+}
+
+func TestPrintDeploymentTable(t *testing.T) {
+	three := int32(3)
+	web := extractDeploymentInfo(newTestDeployment("web", &three, 2, "nginx:1.25"), time.Now())
+
+	var out bytes.Buffer
+	if err := printDeploymentTable(&out, []DeploymentInfo{web}, false, ageCompact); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "NAME") || !strings.Contains(lines[1], "web "+degradedMarker) {
+		t.Errorf("table =\n%s\nwant a header and a degraded web row", out.String())
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxPodEvents is how many of a pod's most recent events --show-events prints
+const maxPodEvents = 5
+
+// failedSchedulingReason is the reason of the scheduler's events for pods
+// it could not place
+const failedSchedulingReason = "FailedScheduling"
+
+// needsEvents reports whether --show-events fetches events for a pod: only
+// pods that are neither running nor done, so the API load stays bounded
+func needsEvents(pod *v1.Pod) bool {
+	return pod.Status.Phase != v1.PodRunning && pod.Status.Phase != v1.PodSucceeded
+}
+
+// eventTime returns when an event last happened, falling back through the
+// fields set by the different event producers
+func eventTime(event *v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventFetcher lists the events of pods in one cluster. Once the events API
+// is forbidden it stops asking, so missing RBAC costs one warning rather
+// than one per pod.
+type eventFetcher struct {
+	client kubernetes.Interface
+	denied bool
+}
+
+// list returns the pod's events, only those with reason if it is set,
+// sorted oldest first. It returns nothing after events were denied once.
+func (f *eventFetcher) list(ctx context.Context, pod *v1.Pod, reason string) ([]v1.Event, error) {
+	if f.denied {
+		return nil, nil
+	}
+	set := fields.Set{"involvedObject.uid": string(pod.UID), "involvedObject.name": pod.Name}
+	if reason != "" {
+		set["reason"] = reason
+	}
+	list, err := f.client.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{FieldSelector: set.AsSelector().String()})
+	if apierrors.IsForbidden(err) {
+		f.denied = true
+		return nil, fmt.Errorf("events are not shown: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing events of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	events := list.Items
+	sort.SliceStable(events, func(i, j int) bool { return eventTime(&events[i]).Before(eventTime(&events[j])) })
+	return events, nil
+}
+
+// fetch returns the pod's most recent events, oldest first. It returns no
+// events and no error after events were denied once.
+func (f *eventFetcher) fetch(ctx context.Context, pod *v1.Pod, now time.Time) ([]podinfo.EventInfo, error) {
+	if f.denied {
+		return nil, nil
+	}
+	events, err := f.list(ctx, pod, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > maxPodEvents {
+		events = events[len(events)-maxPodEvents:]
+	}
+	infos := make([]podinfo.EventInfo, 0, len(events))
+	for i := range events {
+		lastSeen := eventTime(&events[i])
+		infos = append(infos, podinfo.EventInfo{
+			Type:     events[i].Type,
+			Reason:   events[i].Reason,
+			Message:  events[i].Message,
+			Count:    events[i].Count,
+			Age:      now.Sub(lastSeen).Truncate(time.Second),
+			LastSeen: lastSeen,
+		})
+	}
+	return infos, nil
+}
+
+// lastFailedScheduling returns the most recent FailedScheduling event of
+// pod, or nil if there is none (or events were denied)
+func (f *eventFetcher) lastFailedScheduling(ctx context.Context, pod *v1.Pod) (*v1.Event, error) {
+	events, err := f.list(ctx, pod, failedSchedulingReason)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Reason == failedSchedulingReason {
+			return &events[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// printEvents prints a pod's events indented under it
+func printEvents(out io.Writer, events []podinfo.EventInfo, ageFormat string) {
+	fmt.Fprintf(out, "  Events:\n")
+	if len(events) == 0 {
+		fmt.Fprintf(out, "    <none>\n")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, e := range events {
+		fmt.Fprintf(w, "    %s\t%s\t%s\t%s\n", e.Type, e.Reason, formatAge(ageFormat, e.Age, e.LastSeen), e.Message)
+	}
+	w.Flush()
+}
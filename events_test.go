@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNeedsEvents(t *testing.T) {
+	for phase, want := range map[v1.PodPhase]bool{
+		v1.PodPending:   true,
+		v1.PodFailed:    true,
+		v1.PodUnknown:   true,
+		v1.PodRunning:   false,
+		v1.PodSucceeded: false,
+	} {
+		if got := needsEvents(&v1.Pod{Status: v1.PodStatus{Phase: phase}}); got != want {
+			t.Errorf("needsEvents(%s) = %t, want %t", phase, got, want)
+		}
+	}
+}
+
+func TestFetchEvents(t *testing.T) {
+	now := time.Now()
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web", UID: types.UID("uid-1")}}
+	var objects []runtime.Object
+	for i := 0; i < 7; i++ {
+		objects = append(objects, &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("web.%d", i)},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "web", UID: pod.UID},
+			Type:           v1.EventTypeWarning,
+			Reason:         fmt.Sprintf("Reason%d", i),
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Duration(7-i) * time.Minute)),
+		})
+	}
+	client := fake.NewSimpleClientset(objects...)
+	var fieldSelector string
+	client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		fieldSelector = action.(k8stesting.ListAction).GetListRestrictions().Fields.String()
+		return false, nil, nil
+	})
+
+	events, err := (&eventFetcher{client: client}).fetch(context.Background(), pod, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "involvedObject.name=web,involvedObject.uid=uid-1"; fieldSelector != want {
+		t.Errorf("field selector = %q, want %q", fieldSelector, want)
+	}
+	if len(events) != maxPodEvents {
+		t.Fatalf("got %d events, want the last %d", len(events), maxPodEvents)
+	}
+	if events[0].Reason != "Reason2" || events[4].Reason != "Reason6" {
+		t.Errorf("events run from %s to %s, want Reason2 to Reason6", events[0].Reason, events[4].Reason)
+	}
+	if events[4].Age != time.Minute {
+		t.Errorf("newest event age = %s, want 1m", events[4].Age)
+	}
+}
+
+func TestFetchEventsForbiddenWarnsOnce(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	calls := 0
+	client.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, apierrors.NewForbidden(v1.Resource("events"), "", nil)
+	})
+
+	fetcher := &eventFetcher{client: client}
+	var warnings []error
+	for _, name := range []string{"a", "b", "c"} {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+		if _, err := fetcher.fetch(context.Background(), pod, time.Now()); err != nil {
+			warnings = append(warnings, err)
+		}
+	}
+	if len(warnings) != 1 || calls != 1 {
+		t.Errorf("got %d warnings after %d calls, want 1 warning after 1 call: %v", len(warnings), calls, warnings)
+	}
+}
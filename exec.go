@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// execOptions holds the flags of the exec subcommand, mirroring kubectl exec
+type execOptions struct {
+	Container string
+	Stdin     bool
+	TTY       bool
+}
+
+// execStreams are the local ends of the remote process's standard streams
+type execStreams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// fixedSizeQueue reports one terminal size and then blocks until ctx is
+// done, so the remote TTY starts out with the local window's dimensions
+type fixedSizeQueue struct {
+	ctx  context.Context
+	size *remotecommand.TerminalSize
+}
+
+func (q *fixedSizeQueue) Next() *remotecommand.TerminalSize {
+	if size := q.size; size != nil {
+		q.size = nil
+		return size
+	}
+	<-q.ctx.Done()
+	return nil
+}
+
+// execInPod runs command in a container of pod namespace/podName over SPDY,
+// wiring the process to streams. lookupCtx bounds looking up the pod; the
+// session runs until the process exits or ctx is done. A non-zero exit of
+// the remote process is returned as a k8s.io/client-go/util/exec.ExitError.
+func execInPod(ctx, lookupCtx context.Context, config *rest.Config, client kubernetes.Interface, namespace, podName string, opts execOptions, command []string, streams execStreams, sizes remotecommand.TerminalSizeQueue) error {
+	pod, err := client.CoreV1().Pods(namespace).Get(lookupCtx, podName, metav1.GetOptions{})
+	if err != nil {
+		return handleAPIError(err, "pods", namespace)
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return fmt.Errorf("cannot exec into pod %s: it has %s", podName, pod.Status.Phase)
+	}
+	container, err := resolveContainer(pod, opts.Container)
+	if err != nil {
+		return err
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdout:            streams.Out,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: sizes,
+	}
+	if opts.Stdin {
+		streamOpts.Stdin = streams.In
+	}
+	// A TTY merges stderr into stdout
+	if !opts.TTY {
+		streamOpts.Stderr = streams.Err
+	}
+	err = streamExec(ctx, config, client, namespace, podName, &v1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     opts.Stdin,
+		Stdout:    true,
+		Stderr:    !opts.TTY,
+		TTY:       opts.TTY,
+	}, streamOpts)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// streamExec starts the exec session execOpts describes in pod
+// namespace/podName over SPDY and streams it until the process exits or ctx
+// is done. A non-zero exit of the remote process is returned as a
+// k8s.io/client-go/util/exec.ExitError.
+func streamExec(ctx context.Context, config *rest.Config, client kubernetes.Interface, namespace, podName string, execOpts *v1.PodExecOptions, streamOpts remotecommand.StreamOptions) error {
+	req := client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(namespace).
+		SubResource("exec").
+		VersionedParams(execOpts, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("creating the exec session: %w", err)
+	}
+	if err := executor.StreamWithContext(ctx, streamOpts); err != nil {
+		return handleAPIError(err, "pods/exec", namespace)
+	}
+	return nil
+}
+
+// runExec implements the exec subcommand
+func runExec(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s exec [flags] POD -- COMMAND [ARGS...]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	var opts execOptions
+	namespace := fs.String("namespace", metav1.NamespaceDefault, "namespace of the pod")
+	fs.StringVar(&opts.Container, "container", "", "container to run the command in (required when the pod has several)")
+	fs.StringVar(&opts.Container, "c", "", "shorthand for --container")
+	fs.BoolVar(&opts.Stdin, "stdin", false, "pass stdin to the command")
+	fs.BoolVar(&opts.Stdin, "i", false, "shorthand for --stdin")
+	fs.BoolVar(&opts.TTY, "tty", false, "allocate a TTY for the command")
+	fs.BoolVar(&opts.TTY, "t", false, "shorthand for --tty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Allow flags after the pod name, as kubectl does; the command follows --
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("a pod name is required")
+	}
+	podName := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	command := fs.Args()
+	if len(command) == 0 {
+		fs.Usage()
+		return errors.New("a command to run is required, e.g. exec POD -- sh")
+	}
+	if err := clientOpts.setupLogger(outputText); err != nil {
+		return err
+	}
+
+	stdinFd := int(os.Stdin.Fd())
+	if opts.TTY && !(opts.Stdin && term.IsTerminal(stdinFd)) {
+		clientOpts.logger().Warn("unable to use a TTY: --stdin is not set or stdin is not a terminal")
+		opts.TTY = false
+	}
+
+	client, config, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	lookupCtx, cancel := requestContext(ctx, clientOpts.Timeout)
+	defer cancel()
+
+	var sizes remotecommand.TerminalSizeQueue
+	if opts.TTY {
+		sizeQueue := &fixedSizeQueue{ctx: ctx}
+		if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			sizeQueue.size = &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+		}
+		sizes = sizeQueue
+		// Raw mode passes keystrokes such as Ctrl-C to the remote process
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("setting the terminal to raw mode: %w", err)
+		}
+		defer term.Restore(stdinFd, state)
+	}
+
+	streams := execStreams{In: os.Stdin, Out: os.Stdout, Err: os.Stderr}
+	if err := execInPod(ctx, lookupCtx, config, client, *namespace, podName, opts, command, streams, sizes); err != nil {
+		return fmt.Errorf("error executing in pod: %w", timeoutError(err, clientOpts.Timeout))
+	}
+	return nil
+}
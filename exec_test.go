@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// fakeExecServer serves GET on pod "web" and its exec subresource over
+// SPDY. The exec handler echoes stdin (if attached) to stdout, writes
+// "oops" to stderr and exits with exitCode.
+type fakeExecServer struct {
+	t        *testing.T
+	pod      *v1.Pod
+	exitCode int
+	// query is the query string of the last exec request
+	query string
+}
+
+func (s *fakeExecServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/default/pods/web":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.pod)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/default/pods/web/exec":
+		s.query = r.URL.RawQuery
+		s.serveExec(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveExec negotiates the v4 streaming protocol, collects the client's
+// streams by type and runs the fake process
+func (s *fakeExecServer) serveExec(w http.ResponseWriter, r *http.Request) {
+	if _, err := httpstream.Handshake(r, w, []string{remotecommandconsts.StreamProtocolV4Name}); err != nil {
+		s.t.Errorf("handshake: %v", err)
+		return
+	}
+	streams := make(chan httpstream.Stream, 4)
+	conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(stream httpstream.Stream, replySent <-chan struct{}) error {
+		streams <- stream
+		return nil
+	})
+	if conn == nil {
+		s.t.Error("SPDY upgrade failed")
+		return
+	}
+	defer conn.Close()
+
+	want := map[string]bool{v1.StreamTypeError: true, v1.StreamTypeStdout: true}
+	query := r.URL.Query()
+	if query.Get("stdin") == "true" {
+		want[v1.StreamTypeStdin] = true
+	}
+	if query.Get("stderr") == "true" {
+		want[v1.StreamTypeStderr] = true
+	}
+	byType := map[string]httpstream.Stream{}
+	for len(byType) < len(want) {
+		select {
+		case stream := <-streams:
+			byType[stream.Headers().Get(v1.StreamType)] = stream
+		case <-time.After(5 * time.Second):
+			s.t.Errorf("got streams %v, want %v", byType, want)
+			return
+		}
+	}
+
+	stdout := byType[v1.StreamTypeStdout]
+	if stdin, ok := byType[v1.StreamTypeStdin]; ok {
+		io.Copy(stdout, stdin)
+	} else {
+		io.WriteString(stdout, "hello\n")
+	}
+	if stderr, ok := byType[v1.StreamTypeStderr]; ok {
+		io.WriteString(stderr, "oops\n")
+		stderr.Close()
+	}
+	stdout.Close()
+
+	status := metav1.Status{Status: metav1.StatusSuccess}
+	if s.exitCode != 0 {
+		status = metav1.Status{
+			Status: metav1.StatusFailure,
+			Reason: remotecommandconsts.NonZeroExitCodeReason,
+			Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{
+				Type:    remotecommandconsts.ExitCodeCauseType,
+				Message: strconv.Itoa(s.exitCode),
+			}}},
+		}
+	}
+	errorStream := byType[v1.StreamTypeError]
+	json.NewEncoder(errorStream).Encode(status)
+	errorStream.Close()
+}
+
+// startExecServer starts a fakeExecServer for pod and returns a client for it
+func startExecServer(t *testing.T, pod *v1.Pod, exitCode int) (*fakeExecServer, *rest.Config, kubernetes.Interface) {
+	t.Helper()
+	fake := &fakeExecServer{t: t, pod: pod, exitCode: exitCode}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config := &rest.Config{Host: server.URL}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	return fake, config, client
+}
+
+func TestExecInPod(t *testing.T) {
+	pod := newContainersPod("web", "app", "sidecar")
+	pod.Status.Phase = v1.PodRunning
+	fake, config, client := startExecServer(t, pod, 0)
+	ctx := context.Background()
+
+	var out, errOut bytes.Buffer
+	streams := execStreams{Out: &out, Err: &errOut}
+	err := execInPod(ctx, ctx, config, client, "default", "web", execOptions{Container: "sidecar"}, []string{"echo", "hello"}, streams, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "hello\n" || errOut.String() != "oops\n" {
+		t.Errorf("stdout %q, stderr %q, want hello and oops", out.String(), errOut.String())
+	}
+	for _, param := range []string{"container=sidecar", "command=echo", "command=hello", "stdout=true", "stderr=true"} {
+		if !strings.Contains(fake.query, param) {
+			t.Errorf("exec query %q is missing %s", fake.query, param)
+		}
+	}
+}
+
+func TestExecInPodStdin(t *testing.T) {
+	pod := newContainersPod("web", "app")
+	pod.Status.Phase = v1.PodRunning
+	_, config, client := startExecServer(t, pod, 0)
+	ctx := context.Background()
+
+	var out, errOut bytes.Buffer
+	streams := execStreams{In: strings.NewReader("from stdin\n"), Out: &out, Err: &errOut}
+	err := execInPod(ctx, ctx, config, client, "default", "web", execOptions{Stdin: true}, []string{"cat"}, streams, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "from stdin\n" {
+		t.Errorf("stdout = %q, want stdin echoed back", out.String())
+	}
+}
+
+func TestExecInPodExitCode(t *testing.T) {
+	pod := newContainersPod("web", "app")
+	pod.Status.Phase = v1.PodRunning
+	_, config, client := startExecServer(t, pod, 3)
+	ctx := context.Background()
+
+	streams := execStreams{Out: io.Discard, Err: io.Discard}
+	err := execInPod(ctx, ctx, config, client, "default", "web", execOptions{}, []string{"false"}, streams, nil)
+	var exitErr utilexec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 3 {
+		t.Fatalf("error = %v, want the remote exit code 3", err)
+	}
+}
+
+func TestExecInPodCompleted(t *testing.T) {
+	pod := newContainersPod("web", "app")
+	pod.Status.Phase = v1.PodSucceeded
+	_, config, client := startExecServer(t, pod, 0)
+	ctx := context.Background()
+
+	err := execInPod(ctx, ctx, config, client, "default", "web", execOptions{}, []string{"sh"}, execStreams{Out: io.Discard, Err: io.Discard}, nil)
+	if err == nil || !strings.Contains(err.Error(), "it has Succeeded") {
+		t.Errorf("error = %v, want a completed pod to be rejected", err)
+	}
+}
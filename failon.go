@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// exitConditionMatched is the exit code when a --fail-on condition matched.
+// 1 is left for execution errors (fatal).
+const exitConditionMatched = 2
+
+// failCondition is one --fail-on condition, evaluated against each pod
+type failCondition struct {
+	// Name is the condition as written, e.g. "restarts>5"
+	Name  string
+	match func(podinfo.PodInfo) bool
+}
+
+// parseFailCondition parses one condition: pending, failed, crashloop,
+// unscheduled or restarts>N (case-insensitive)
+func parseFailCondition(value string) (failCondition, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	cond := failCondition{Name: name}
+	switch name {
+	case "pending":
+		cond.match = func(info podinfo.PodInfo) bool { return info.Phase == "Pending" }
+	case "failed":
+		cond.match = func(info podinfo.PodInfo) bool { return info.Phase == "Failed" }
+	case "crashloop":
+		cond.match = func(info podinfo.PodInfo) bool {
+			return info.Reason == "CrashLoopBackOff" || info.Reason == "Init:CrashLoopBackOff"
+		}
+	case "unscheduled":
+		cond.match = func(info podinfo.PodInfo) bool { return info.Phase == "Pending" && info.NodeName == "" }
+	default:
+		arg, ok := strings.CutPrefix(name, "restarts>")
+		if !ok {
+			return failCondition{}, fmt.Errorf("unknown --fail-on condition %q (want pending, failed, crashloop, unscheduled or restarts>N)", value)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 32)
+		if err != nil || limit < 0 {
+			return failCondition{}, fmt.Errorf("invalid --fail-on condition %q: the restart count must be a non-negative integer", value)
+		}
+		cond.Name = "restarts>" + strconv.FormatInt(limit, 10)
+		cond.match = func(info podinfo.PodInfo) bool { return int64(info.Restarts) > limit }
+	}
+	return cond, nil
+}
+
+// parseFailOn parses a comma-separated --fail-on value
+func parseFailOn(value string) ([]failCondition, error) {
+	var conds []failCondition
+	for _, item := range parseList(value) {
+		cond, err := parseFailCondition(item)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// failMatch is a condition together with the pods that triggered it
+type failMatch struct {
+	Condition string
+	Pods      []string
+}
+
+// evaluateFailOn returns the conditions matched by at least one pod, in the
+// order they were given
+func evaluateFailOn(conds []failCondition, infos []podinfo.PodInfo) []failMatch {
+	var matches []failMatch
+	for _, cond := range conds {
+		var pods []string
+		for _, info := range infos {
+			if cond.match(info) {
+				pods = append(pods, podDisplayName(info))
+			}
+		}
+		if len(pods) > 0 {
+			matches = append(matches, failMatch{Condition: cond.Name, Pods: pods})
+		}
+	}
+	return matches
+}
+
+// podDisplayName returns namespace/name, prefixed by the cluster if any
+func podDisplayName(info podinfo.PodInfo) string {
+	name := info.Namespace + "/" + info.Name
+	if info.Cluster != "" {
+		name = info.Cluster + "/" + name
+	}
+	return name
+}
+
+// printFailMatches reports the matched conditions and their pods
+func printFailMatches(w io.Writer, matches []failMatch) {
+	for _, m := range matches {
+		fmt.Fprintf(w, "--fail-on %s matched %d pods: %s\n", m.Condition, len(m.Pods), strings.Join(m.Pods, ", "))
+	}
+}
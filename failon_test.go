@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "pending,failed", want: []string{"pending", "failed"}},
+		{value: " CrashLoop , Unscheduled ", want: []string{"crashloop", "unscheduled"}},
+		{value: "restarts>5", want: []string{"restarts>5"}},
+		{value: "restarts> 05,pending", want: []string{"restarts>5", "pending"}},
+		{value: "restarts>0", want: []string{"restarts>0"}},
+		{value: "restarts>", wantErr: true},
+		{value: "restarts>-1", wantErr: true},
+		{value: "restarts>many", wantErr: true},
+		{value: "restarts<5", wantErr: true},
+		{value: "pending,oom", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			conds, err := parseFailOn(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFailOn(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			var names []string
+			for _, cond := range conds {
+				names = append(names, cond.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("parseFailOn(%q) = %v, want %v", tt.value, names, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateFailOn(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "web", Name: "ok", Phase: "Running", NodeName: "node-1", Restarts: 1},
+		{Namespace: "web", Name: "crashing", Phase: "Running", Reason: "CrashLoopBackOff", NodeName: "node-1", Restarts: 9},
+		{Namespace: "web", Name: "waiting", Phase: "Pending", Reason: "Pending"},
+		{Namespace: "web", Name: "pulling", Phase: "Pending", Reason: "ImagePullBackOff", NodeName: "node-2"},
+		{Cluster: "prod", Namespace: "jobs", Name: "broken", Phase: "Failed", NodeName: "node-2", Restarts: 6},
+	}
+	conds, err := parseFailOn("pending,failed,crashloop,unscheduled,restarts>5,restarts>100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []failMatch{
+		{Condition: "pending", Pods: []string{"web/waiting", "web/pulling"}},
+		{Condition: "failed", Pods: []string{"prod/jobs/broken"}},
+		{Condition: "crashloop", Pods: []string{"web/crashing"}},
+		{Condition: "unscheduled", Pods: []string{"web/waiting"}},
+		{Condition: "restarts>5", Pods: []string{"web/crashing", "prod/jobs/broken"}},
+	}
+	matches := evaluateFailOn(conds, infos)
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("evaluateFailOn() =\n%+v\nwant\n%+v", matches, want)
+	}
+
+	var out bytes.Buffer
+	printFailMatches(&out, matches[1:2])
+	if got, want := out.String(), "--fail-on failed matched 1 pods: prod/jobs/broken\n"; got != want {
+		t.Errorf("printFailMatches() = %q, want %q", got, want)
+	}
+
+	if matches := evaluateFailOn(conds, infos[:1]); matches != nil {
+		t.Errorf("healthy pods matched %+v", matches)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// unknownMetric stands for a metric value the autoscaler has not reported
+const unknownMetric = "<unknown>"
+
+// HPAInfo holds formatted horizontal pod autoscaler information
+type HPAInfo struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Reference is the scaled workload as Kind/Name
+	Reference string `json:"reference"`
+	// Targets holds "current/target" per metric, in spec order
+	Targets   []string      `json:"targets"`
+	MinPods   int32         `json:"minPods"`
+	MaxPods   int32         `json:"maxPods"`
+	Replicas  int32         `json:"replicas"`
+	Age       time.Duration `json:"-"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// extractHPAInfo extracts relevant information from an autoscaler. Each
+// metric of the spec is paired with the current metric at the same index,
+// as kubectl does; a metric without one shows <unknown> as current value.
+func extractHPAInfo(hpa *autoscalingv2.HorizontalPodAutoscaler, now time.Time) HPAInfo {
+	// An unset minimum defaults to 1, as in the API server
+	minPods := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minPods = *hpa.Spec.MinReplicas
+	}
+	targets := []string{}
+	for i, spec := range hpa.Spec.Metrics {
+		var current *autoscalingv2.MetricStatus
+		if i < len(hpa.Status.CurrentMetrics) && hpa.Status.CurrentMetrics[i].Type == spec.Type {
+			current = &hpa.Status.CurrentMetrics[i]
+		}
+		targets = append(targets, formatMetric(spec, current))
+	}
+	return HPAInfo{
+		Name:      hpa.Name,
+		Namespace: hpa.Namespace,
+		Reference: hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		Targets:   targets,
+		MinPods:   minPods,
+		MaxPods:   hpa.Spec.MaxReplicas,
+		Replicas:  hpa.Status.CurrentReplicas,
+		Age:       now.Sub(hpa.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt: hpa.CreationTimestamp.Time,
+	}
+}
+
+// formatMetric returns "current/target" for a metric of the Resource, Pods
+// or External type, current being nil when it has not been reported
+func formatMetric(spec autoscalingv2.MetricSpec, current *autoscalingv2.MetricStatus) string {
+	var target autoscalingv2.MetricTarget
+	var value *autoscalingv2.MetricValueStatus
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource == nil {
+			return unknownMetric
+		}
+		target = spec.Resource.Target
+		if current != nil && current.Resource != nil {
+			value = &current.Resource.Current
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods == nil {
+			return unknownMetric
+		}
+		target = spec.Pods.Target
+		if current != nil && current.Pods != nil {
+			value = &current.Pods.Current
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External == nil {
+			return unknownMetric
+		}
+		target = spec.External.Target
+		if current != nil && current.External != nil {
+			value = &current.External.Current
+		}
+	default:
+		return fmt.Sprintf("%s metric (unsupported)", spec.Type)
+	}
+	return formatMetricValue(target, value) + "/" + formatMetricTarget(target)
+}
+
+// formatMetricTarget formats the target of a metric: a percentage for
+// utilization, the quantity otherwise
+func formatMetricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.Type == autoscalingv2.UtilizationMetricType && target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.Type == autoscalingv2.AverageValueMetricType && target.AverageValue != nil:
+		return target.AverageValue.String()
+	case target.Type == autoscalingv2.ValueMetricType && target.Value != nil:
+		return target.Value.String()
+	}
+	return unknownMetric
+}
+
+// formatMetricValue formats the current value of a metric the way its
+// target is expressed, or <unknown> when it has not been reported
+func formatMetricValue(target autoscalingv2.MetricTarget, value *autoscalingv2.MetricValueStatus) string {
+	switch {
+	case value == nil:
+	case target.Type == autoscalingv2.UtilizationMetricType && value.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case target.Type == autoscalingv2.AverageValueMetricType && value.AverageValue != nil:
+		return value.AverageValue.String()
+	case target.Type == autoscalingv2.ValueMetricType && value.Value != nil:
+		return value.Value.String()
+	}
+	return unknownMetric
+}
+
+// targetsString returns the TARGETS column of info
+func (info HPAInfo) targetsString() string {
+	if len(info.Targets) == 0 {
+		return "<none>"
+	}
+	return strings.Join(info.Targets, ", ")
+}
+
+// hpaInfos extracts the information of hpas, sorted by namespace and name
+func hpaInfos(hpas []autoscalingv2.HorizontalPodAutoscaler, now time.Time) []HPAInfo {
+	infos := []HPAInfo{}
+	for i := range hpas {
+		infos = append(infos, extractHPAInfo(&hpas[i], now))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Namespace != infos[j].Namespace {
+			return infos[i].Namespace < infos[j].Namespace
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// printHPATable prints one row per autoscaler. The NAMESPACE column is
+// shown when listing across namespaces.
+func printHPATable(out io.Writer, infos []HPAInfo, showNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tREFERENCE\tTARGETS\tMIN-PODS\tMAX-PODS\tREPLICAS\tAGE")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			info.Name, info.Reference, info.targetsString(), info.MinPods, info.MaxPods, info.Replicas,
+			formatAge(ageCompact, info.Age, info.CreatedAt))
+	}
+	return w.Flush()
+}
+
+// printHPAInfo prints formatted autoscaler information
+func printHPAInfo(out io.Writer, info HPAInfo) {
+	fmt.Fprintf(out, "HorizontalPodAutoscaler: %s\n", info.Name)
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
+	fmt.Fprintf(out, "  Reference: %s\n", info.Reference)
+	fmt.Fprintf(out, "  Targets: %s\n", info.targetsString())
+	fmt.Fprintf(out, "  Min/max pods: %d/%d\n", info.MinPods, info.MaxPods)
+	fmt.Fprintf(out, "  Replicas: %d\n", info.Replicas)
+	fmt.Fprintf(out, "  Age: %s\n", formatAge(ageCompact, info.Age, info.CreatedAt))
+	fmt.Fprintln(out)
+}
+
+// watchHPAs calls render with the current autoscalers and again after
+// every change until ctx is cancelled. A RetryWatcher re-establishes
+// watches the server closes; when the resource version it resumes from has
+// expired, the autoscalers are listed again. timeout bounds each list.
+func watchHPAs(ctx context.Context, client kubernetes.Interface, namespace, selector string, timeout time.Duration, render func([]HPAInfo) error) error {
+	hpas := client.AutoscalingV2().HorizontalPodAutoscalers(namespace)
+	for {
+		listCtx, cancel := requestContext(ctx, timeout)
+		list, err := hpas.List(listCtx, metav1.ListOptions{LabelSelector: selector})
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return timeoutError(handleAPIError(err, "horizontalpodautoscalers", namespace), timeout)
+		}
+		current := make(map[string]autoscalingv2.HorizontalPodAutoscaler, len(list.Items))
+		for _, hpa := range list.Items {
+			current[hpa.Namespace+"/"+hpa.Name] = hpa
+		}
+		if err := renderHPAs(current, render); err != nil {
+			return err
+		}
+
+		watcher, err := watchtools.NewRetryWatcherWithContext(ctx, list.ResourceVersion, &cache.ListWatch{
+			WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+				opts.LabelSelector = selector
+				return hpas.Watch(ctx, opts)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error watching horizontal pod autoscalers: %w", err)
+		}
+		expired, err := consumeHPAEvents(ctx, watcher, current, render)
+		watcher.Stop()
+		if err != nil || !expired {
+			return err
+		}
+		logging.FromContext(ctx).Info("resource version expired, listing the horizontal pod autoscalers again")
+	}
+}
+
+// consumeHPAEvents applies the events of watcher to current, rendering
+// after each one, until ctx is cancelled. It reports whether it stopped
+// because the resource version expired.
+func consumeHPAEvents(ctx context.Context, watcher *watchtools.RetryWatcher, current map[string]autoscalingv2.HorizontalPodAutoscaler, render func([]HPAInfo) error) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-watcher.Done():
+			if ctx.Err() != nil {
+				return false, nil
+			}
+			return false, errors.New("watch of horizontal pod autoscalers stopped")
+		case event := <-watcher.ResultChan():
+			if event.Type == watch.Error {
+				err := apierrors.FromObject(event.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					return true, nil
+				}
+				return false, fmt.Errorf("watch failed: %w", err)
+			}
+			hpa, ok := event.Object.(*autoscalingv2.HorizontalPodAutoscaler)
+			if !ok || event.Type == watch.Bookmark {
+				continue
+			}
+			key := hpa.Namespace + "/" + hpa.Name
+			if event.Type == watch.Deleted {
+				delete(current, key)
+			} else {
+				current[key] = *hpa
+			}
+			if err := renderHPAs(current, render); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// renderHPAs calls render with the autoscalers of current
+func renderHPAs(current map[string]autoscalingv2.HorizontalPodAutoscaler, render func([]HPAInfo) error) error {
+	hpas := make([]autoscalingv2.HorizontalPodAutoscaler, 0, len(current))
+	for _, hpa := range current {
+		hpas = append(hpas, hpa)
+	}
+	return render(hpaInfos(hpas, time.Now()))
+}
+
+// runHPA implements the hpa subcommand
+func runHPA(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("hpa", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list horizontal pod autoscalers from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter horizontal pod autoscalers, e.g. app=web,tier!=cache")
+	watchChanges := fs.Bool("watch", false, "reprint the autoscalers whenever one changes, until interrupted (table and text output)")
+	output := fs.String("output", outputTable, "output format: table, text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	switch *output {
+	case outputTable, outputText, outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("unknown --output format %q (want table, text, json or yaml)", *output)
+	}
+	if _, err := labels.Parse(*labelSelector); err != nil {
+		return fmt.Errorf("invalid --label-selector: %w", err)
+	}
+	if *watchChanges {
+		switch {
+		case *outputFile != "" && *outputFile != outputStdout:
+			return errors.New("--output-file cannot be combined with --watch")
+		case *output == outputJSON || *output == outputYAML:
+			return errors.New("--watch only supports table and text output")
+		}
+	}
+
+	client, _, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	render := func(out io.Writer, infos []HPAInfo) error {
+		switch *output {
+		case outputJSON, outputYAML:
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No horizontal pod autoscalers found")
+			return nil
+		}
+		if *output == outputText {
+			for _, info := range infos {
+				printHPAInfo(out, info)
+			}
+			return nil
+		}
+		return printHPATable(out, infos, *namespace == "")
+	}
+
+	if *watchChanges {
+		ctx, stop := signal.NotifyContext(logging.NewContext(context.Background(), clientOpts.logger()), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return replaceOutput(*outputFile, func(out io.Writer) error {
+			first := true
+			return watchHPAs(ctx, client, *namespace, *labelSelector, clientOpts.Timeout, func(infos []HPAInfo) error {
+				if !first {
+					fmt.Fprintln(out)
+				}
+				first = false
+				return render(out, infos)
+			})
+		})
+	}
+
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		hpas, err := client.AutoscalingV2().HorizontalPodAutoscalers(*namespace).List(ctx, metav1.ListOptions{LabelSelector: *labelSelector})
+		if err != nil {
+			return fmt.Errorf("error listing horizontal pod autoscalers: %w", timeoutError(handleAPIError(err, "horizontalpodautoscalers", *namespace), clientOpts.Timeout))
+		}
+		return render(out, hpaInfos(hpas.Items, time.Now()))
+	})
+}
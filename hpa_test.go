@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestHPA returns an autoscaler of Deployment/web with the given metrics
+func newTestHPA(name string, metrics []autoscalingv2.MetricSpec, current []autoscalingv2.MetricStatus) *autoscalingv2.HorizontalPodAutoscaler {
+	two := int32(2)
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "web"},
+			MinReplicas:    &two,
+			MaxReplicas:    10,
+			Metrics:        metrics,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, CurrentMetrics: current},
+	}
+}
+
+func cpuMetric(target int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name:   v1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &target},
+		},
+	}
+}
+
+func cpuStatus(current int32) autoscalingv2.MetricStatus {
+	return autoscalingv2.MetricStatus{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricStatus{
+			Name:    v1.ResourceCPU,
+			Current: autoscalingv2.MetricValueStatus{AverageUtilization: &current},
+		},
+	}
+}
+
+func TestExtractHPAInfoResource(t *testing.T) {
+	now := time.Now()
+	hpa := newTestHPA("web", []autoscalingv2.MetricSpec{cpuMetric(80)}, []autoscalingv2.MetricStatus{cpuStatus(45)})
+	hpa.CreationTimestamp = metav1.NewTime(now.Add(-3 * time.Hour))
+
+	info := extractHPAInfo(hpa, now)
+	if want := []string{"45%/80%"}; !reflect.DeepEqual(info.Targets, want) {
+		t.Errorf("targets = %q, want %q", info.Targets, want)
+	}
+	if info.Reference != "Deployment/web" || info.MinPods != 2 || info.MaxPods != 10 || info.Replicas != 3 {
+		t.Errorf("info = %+v, want Deployment/web with 2-10 pods and 3 replicas", info)
+	}
+	if got := formatAge(ageCompact, info.Age, info.CreatedAt); got != "3h" {
+		t.Errorf("age = %q, want 3h", got)
+	}
+}
+
+func TestExtractHPAInfoPods(t *testing.T) {
+	target := resource.MustParse("1k")
+	current := resource.MustParse("1500")
+	metric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &target},
+		},
+	}
+	status := autoscalingv2.MetricStatus{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricStatus{
+			Metric:  autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+			Current: autoscalingv2.MetricValueStatus{AverageValue: &current},
+		},
+	}
+
+	info := extractHPAInfo(newTestHPA("web", []autoscalingv2.MetricSpec{metric}, []autoscalingv2.MetricStatus{status}), time.Now())
+	if want := []string{"1500/1k"}; !reflect.DeepEqual(info.Targets, want) {
+		t.Errorf("targets = %q, want %q", info.Targets, want)
+	}
+}
+
+func TestExtractHPAInfoExternal(t *testing.T) {
+	target := resource.MustParse("30")
+	current := resource.MustParse("42")
+	metric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: "queue_messages_ready"},
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.ValueMetricType, Value: &target},
+		},
+	}
+	status := autoscalingv2.MetricStatus{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricStatus{
+			Metric:  autoscalingv2.MetricIdentifier{Name: "queue_messages_ready"},
+			Current: autoscalingv2.MetricValueStatus{Value: &current},
+		},
+	}
+
+	info := extractHPAInfo(newTestHPA("worker", []autoscalingv2.MetricSpec{cpuMetric(70), metric}, []autoscalingv2.MetricStatus{cpuStatus(90), status}), time.Now())
+	if want := []string{"90%/70%", "42/30"}; !reflect.DeepEqual(info.Targets, want) {
+		t.Errorf("targets = %q, want %q", info.Targets, want)
+	}
+	if got := info.targetsString(); got != "90%/70%, 42/30" {
+		t.Errorf("targetsString() = %q, want 90%%/70%%, 42/30", got)
+	}
+}
+
+func TestExtractHPAInfoUnknown(t *testing.T) {
+	// A new autoscaler has no current metrics yet
+	hpa := newTestHPA("new", []autoscalingv2.MetricSpec{cpuMetric(80)}, nil)
+	hpa.Spec.MinReplicas = nil
+
+	info := extractHPAInfo(hpa, time.Now())
+	if want := []string{"<unknown>/80%"}; !reflect.DeepEqual(info.Targets, want) {
+		t.Errorf("targets = %q, want %q", info.Targets, want)
+	}
+	if info.MinPods != 1 {
+		t.Errorf("min pods = %d, want the default of 1", info.MinPods)
+	}
+
+	if got := extractHPAInfo(newTestHPA("none", nil, nil), time.Now()).targetsString(); got != "<none>" {
+		t.Errorf("targetsString() without metrics = %q, want <none>", got)
+	}
+}
+
+func TestPrintHPATable(t *testing.T) {
+	infos := hpaInfos([]autoscalingv2.HorizontalPodAutoscaler{
+		*newTestHPA("web", []autoscalingv2.MetricSpec{cpuMetric(80)}, []autoscalingv2.MetricStatus{cpuStatus(45)}),
+		*newTestHPA("api", []autoscalingv2.MetricSpec{cpuMetric(60)}, nil),
+	}, time.Now())
+
+	var out bytes.Buffer
+	if err := printHPATable(&out, infos, false); err != nil {
+		t.Fatalf("printHPATable() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), out.String())
+	}
+	if got := strings.Fields(lines[0]); !reflect.DeepEqual(got, []string{"NAME", "REFERENCE", "TARGETS", "MIN-PODS", "MAX-PODS", "REPLICAS", "AGE"}) {
+		t.Errorf("header = %q", lines[0])
+	}
+	if got := strings.Fields(lines[1])[:6]; !reflect.DeepEqual(got, []string{"api", "Deployment/web", "<unknown>/60%", "2", "10", "3"}) {
+		t.Errorf("first row = %q, want api sorted first", lines[1])
+	}
+}
+
+func TestWatchHPAs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	hpa := newTestHPA("web", []autoscalingv2.MetricSpec{cpuMetric(80)}, []autoscalingv2.MetricStatus{cpuStatus(45)})
+	client.PrependReactor("list", "horizontalpodautoscalers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &autoscalingv2.HorizontalPodAutoscalerList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "7"},
+			Items:    []autoscalingv2.HorizontalPodAutoscaler{*hpa},
+		}, nil
+	})
+	watcher := watch.NewFake()
+	client.PrependWatchReactor("horizontalpodautoscalers", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watcher, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rendered := make(chan []HPAInfo)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchHPAs(ctx, client, "default", "", time.Minute, func(infos []HPAInfo) error {
+			rendered <- infos
+			return nil
+		})
+	}()
+
+	if infos := <-rendered; len(infos) != 1 || infos[0].Replicas != 3 {
+		t.Fatalf("first render = %+v, want web with 3 replicas", infos)
+	}
+	scaled := hpa.DeepCopy()
+	scaled.ResourceVersion = "8"
+	scaled.Status.CurrentReplicas = 5
+	watcher.Modify(scaled)
+	if infos := <-rendered; len(infos) != 1 || infos[0].Replicas != 5 {
+		t.Errorf("render after MODIFIED = %+v, want web with 5 replicas", infos)
+	}
+	watcher.Delete(scaled)
+	if infos := <-rendered; len(infos) != 0 {
+		t.Errorf("render after DELETED = %+v, want no autoscalers", infos)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("watchHPAs() after cancel = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchHPAs() did not return after the context was cancelled")
+	}
+}
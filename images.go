@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"Kubernetes_Programming/pkg/config"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// vulnMarker flags the pods running an image the --scanner-path binary
+// reported vulnerable
+const vulnMarker = "[VULN]"
+
+// ScanImage runs scannerBin with image as its only argument and reports
+// whether the scanner found vulnerabilities, which it signals with a
+// non-zero exit code. A scanner that cannot be run, or is killed (e.g. by
+// ctx), is an error rather than a verdict.
+func ScanImage(ctx context.Context, scannerBin, image string) (bool, error) {
+	cmd := exec.CommandContext(ctx, scannerBin, image)
+	err := cmd.Run()
+	if err == nil {
+		return false, nil
+	}
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("scanning image %s: %w", image, ctx.Err())
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return true, nil
+	}
+	return false, fmt.Errorf("scanning image %s with %s: %w", image, scannerBin, err)
+}
+
+// imageScanner scans the images of listed pods with --scanner-path,
+// remembering each verdict so --refresh and --serve scan an image only once.
+// Scans are not bound by the --timeout of the API calls; each one gets
+// timeout (--scan-timeout) instead, 0 meaning no limit.
+type imageScanner struct {
+	path     string
+	timeout  time.Duration
+	verdicts map[string]bool
+}
+
+func newImageScanner(path string, timeout time.Duration) *imageScanner {
+	return &imageScanner{path: path, timeout: timeout, verdicts: map[string]bool{}}
+}
+
+// withoutDeadline returns a context canceled along with ctx, but not when
+// ctx only runs into its deadline
+func withoutDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	canceled := func() {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			cancel()
+		}
+	}
+	if ctx.Err() != nil {
+		// AfterFunc would cancel asynchronously
+		canceled()
+	}
+	stop := context.AfterFunc(ctx, canceled)
+	return detached, func() {
+		stop()
+		cancel()
+	}
+}
+
+// scan runs ScanImage on image, killing the scanner after s.timeout
+func (s *imageScanner) scan(ctx context.Context, image string) (bool, error) {
+	scanCtx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	vulnerable, err := ScanImage(scanCtx, s.path, image)
+	if err != nil && ctx.Err() == nil && errors.Is(scanCtx.Err(), context.DeadlineExceeded) {
+		return false, fmt.Errorf("scanning image %s: no verdict within --scan-timeout %s", image, s.timeout)
+	}
+	return vulnerable, err
+}
+
+// annotate sets the VulnerableImages of infos, scanning the images not seen
+// before. Images the scanner failed on are left out and scanned again next
+// time; the failures are returned. The scans outlive ctx's deadline, that of
+// the API calls, but stop when ctx is canceled.
+func (s *imageScanner) annotate(ctx context.Context, infos []podinfo.PodInfo) []error {
+	ctx, cancel := withoutDeadline(ctx)
+	defer cancel()
+	var errs []error
+	failed := map[string]bool{}
+	for i := range infos {
+		infos[i].VulnerableImages = nil
+		for _, image := range infos[i].ContainerImages {
+			vulnerable, ok := s.verdicts[image]
+			if !ok && !failed[image] {
+				if ctx.Err() != nil {
+					return append(errs, ctx.Err())
+				}
+				var err error
+				if vulnerable, err = s.scan(ctx, image); err != nil {
+					errs = append(errs, err)
+					failed[image] = true
+					continue
+				}
+				s.verdicts[image] = vulnerable
+			}
+			if vulnerable {
+				infos[i].VulnerableImages = append(infos[i].VulnerableImages, image)
+			}
+		}
+	}
+	return errs
+}
+
+// vulnName is the pod name followed by vulnMarker when one of its images
+// was flagged
+func vulnName(info podinfo.PodInfo) string {
+	if len(info.VulnerableImages) == 0 {
+		return info.Name
+	}
+	return info.Name + " " + vulnMarker
+}
+
+// printVulnerableImages lists the flagged images below a pod's text block
+func printVulnerableImages(out io.Writer, info podinfo.PodInfo) {
+	if len(info.VulnerableImages) > 0 {
+		fmt.Fprintf(out, "  Vulnerable images: %s\n", strings.Join(info.VulnerableImages, ", "))
+	}
+}
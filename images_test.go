@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// scannerHelperEnv makes the test binary act as an image scanner: it exits 1
+// for images under "vulnerable/", hangs for "slow/" and exits 0 otherwise.
+// Each scanned image is appended to the file the variable names.
+const scannerHelperEnv = "PODS_TEST_SCANNER_LOG"
+
+func scannerHelper() {
+	image := os.Args[len(os.Args)-1]
+	if f, err := os.OpenFile(os.Getenv(scannerHelperEnv), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644); err == nil {
+		f.WriteString(image + "\n")
+		f.Close()
+	}
+	switch {
+	case strings.HasPrefix(image, "vulnerable/"):
+		os.Exit(1)
+	case strings.HasPrefix(image, "slow/"):
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(scannerHelperEnv) != "" {
+		scannerHelper()
+	}
+	os.Exit(m.Run())
+}
+
+// fakeScanner points the scanner helper at a log file in a temporary
+// directory and returns the binary to run and the log
+func fakeScanner(t *testing.T) (string, string) {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "scanned")
+	t.Setenv(scannerHelperEnv, log)
+	return os.Args[0], log
+}
+
+// scannedImages returns the images the scanner helper was run on
+func scannedImages(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestScanImage(t *testing.T) {
+	scanner, _ := fakeScanner(t)
+	tests := []struct {
+		image          string
+		wantVulnerable bool
+	}{
+		{image: "nginx:1.27"},
+		{image: "vulnerable/nginx:1.10", wantVulnerable: true},
+	}
+	for _, tt := range tests {
+		vulnerable, err := ScanImage(context.Background(), scanner, tt.image)
+		if err != nil {
+			t.Errorf("ScanImage(%s) error = %v", tt.image, err)
+		}
+		if vulnerable != tt.wantVulnerable {
+			t.Errorf("ScanImage(%s) = %t, want %t", tt.image, vulnerable, tt.wantVulnerable)
+		}
+	}
+}
+
+func TestScanImageErrors(t *testing.T) {
+	scanner, _ := fakeScanner(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if vulnerable, err := ScanImage(ctx, scanner, "slow/image"); err == nil || vulnerable {
+		t.Errorf("ScanImage past its deadline = %t, %v, want an error", vulnerable, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "no-such-scanner")
+	if vulnerable, err := ScanImage(context.Background(), missing, "nginx"); err == nil || vulnerable {
+		t.Errorf("ScanImage with a missing scanner = %t, %v, want an error", vulnerable, err)
+	}
+}
+
+func TestImageScannerDeadlines(t *testing.T) {
+	scanner, log := fakeScanner(t)
+	s := newImageScanner(scanner, 100*time.Millisecond)
+	infos := []podinfo.PodInfo{{Name: "web", ContainerImages: []string{"slow/app", "vulnerable/nginx"}}}
+
+	// The --timeout of the API calls has run out: the scans still run, each
+	// under --scan-timeout
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	errs := s.annotate(ctx, infos)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "no verdict within --scan-timeout 100ms") {
+		t.Errorf("annotate() errors = %v, want slow/app timed out", errs)
+	}
+	if want := []string{"vulnerable/nginx"}; !reflect.DeepEqual(infos[0].VulnerableImages, want) {
+		t.Errorf("VulnerableImages = %q, want %q", infos[0].VulnerableImages, want)
+	}
+
+	// Canceling stops the scans
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	infos = []podinfo.PodInfo{{Name: "worker", ContainerImages: []string{"busybox"}}}
+	if errs := s.annotate(ctx, infos); len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("annotate() canceled errors = %v, want context.Canceled", errs)
+	}
+	if scanned, want := scannedImages(t, log), []string{"slow/app", "vulnerable/nginx"}; !reflect.DeepEqual(scanned, want) {
+		t.Errorf("scanned %q, want %q", scanned, want)
+	}
+}
+
+func TestImageScannerAnnotate(t *testing.T) {
+	scanner, log := fakeScanner(t)
+	s := newImageScanner(scanner, 0)
+	infos := []podinfo.PodInfo{
+		{Name: "web", ContainerImages: []string{"busybox", "vulnerable/nginx"}},
+		{Name: "worker", ContainerImages: []string{"busybox"}},
+		{Name: "proxy", ContainerImages: []string{"vulnerable/nginx", "vulnerable/envoy"}},
+	}
+	if errs := s.annotate(context.Background(), infos); len(errs) > 0 {
+		t.Fatalf("annotate() errors = %v", errs)
+	}
+	var got [][]string
+	for _, info := range infos {
+		got = append(got, info.VulnerableImages)
+	}
+	want := [][]string{{"vulnerable/nginx"}, nil, {"vulnerable/nginx", "vulnerable/envoy"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VulnerableImages = %q, want %q", got, want)
+	}
+
+	// Each image is scanned once, even across runs
+	s.annotate(context.Background(), infos)
+	if scanned, want := scannedImages(t, log), []string{"busybox", "vulnerable/nginx", "vulnerable/envoy"}; !reflect.DeepEqual(scanned, want) {
+		t.Errorf("scanned %q, want %q", scanned, want)
+	}
+}
+
+func TestPrintVulnerablePods(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "default", Name: "web", Phase: "Running", Reason: "Running", VulnerableImages: []string{"nginx:1.10"}},
+		{Namespace: "default", Name: "worker", Phase: "Running", Reason: "Running"},
+	}
+
+	var table bytes.Buffer
+	if err := printPodTable(&table, infos, printOptions{AgeFormat: ageCompact}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(table.String(), "\n")
+	if !strings.Contains(lines[1], "web "+vulnMarker) || strings.Contains(lines[2], vulnMarker) {
+		t.Errorf("table does not mark only the vulnerable pod:\n%s", table.String())
+	}
+
+	var text bytes.Buffer
+	printPodInfo(&text, infos[0], printOptions{AgeFormat: ageCompact})
+	for _, want := range []string{"Pod: web " + vulnMarker + "\n", "  Vulnerable images: nginx:1.10\n"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text output lacks %q:\n%s", want, text.String())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// maxColumnValueWidth is the widest label or annotation value shown in table
+// output; longer values are cut with an ellipsis. JSON and YAML keep them whole.
+const maxColumnValueWidth = 32
+
+// ellipsis marks a truncated table value
+const ellipsis = "…"
+
+// validateMetadataKeys checks that every key of --label-columns or
+// --annotation-columns (flagName) is a valid label or annotation key
+func validateMetadataKeys(flagName string, keys []string) error {
+	for _, key := range keys {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid %s key %q: %s", flagName, key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// truncateValue shortens s to at most width characters, ending in an
+// ellipsis when it was cut
+func truncateValue(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + ellipsis
+}
+
+// selectKeys returns the entries of m whose keys are listed, or nil if
+// there are none
+func selectKeys(m map[string]string, keys []string) map[string]string {
+	var selected map[string]string
+	for _, key := range keys {
+		if value, ok := m[key]; ok {
+			if selected == nil {
+				selected = make(map[string]string, len(keys))
+			}
+			selected[key] = value
+		}
+	}
+	return selected
+}
+
+// labelsString renders labels sorted by key as k=v pairs like kubectl
+// --show-labels, or "<none>". A positive width truncates each value.
+func labelsString(labels map[string]string, width int) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := labels[key]
+		if width > 0 {
+			value = truncateValue(value, width)
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// metadataHeaders returns the table headers of the --label-columns and
+// --annotation-columns columns, upper-cased like kubectl -L, followed by
+// LABELS with --show-labels
+func metadataHeaders(opts printOptions) []string {
+	var headers []string
+	for _, key := range opts.LabelColumns {
+		headers = append(headers, strings.ToUpper(key))
+	}
+	for _, key := range opts.AnnotationColumns {
+		headers = append(headers, strings.ToUpper(key))
+	}
+	if opts.ShowLabels {
+		headers = append(headers, "LABELS")
+	}
+	return headers
+}
+
+// metadataCells returns info's cells for the metadataHeaders columns, with
+// long values truncated. A missing label or annotation is an empty cell.
+func metadataCells(info podinfo.PodInfo, opts printOptions) []string {
+	var cells []string
+	for _, key := range opts.LabelColumns {
+		cells = append(cells, truncateValue(info.Labels[key], maxColumnValueWidth))
+	}
+	for _, key := range opts.AnnotationColumns {
+		cells = append(cells, truncateValue(info.Annotations[key], maxColumnValueWidth))
+	}
+	if opts.ShowLabels {
+		cells = append(cells, labelsString(info.Labels, maxColumnValueWidth))
+	}
+	return cells
+}
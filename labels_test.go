@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestTruncateValue(t *testing.T) {
+	tests := []struct {
+		value string
+		width int
+		want  string
+	}{
+		{value: "payments", width: 10, want: "payments"},
+		{value: "payments", width: 8, want: "payments"},
+		{value: "payments-platform", width: 8, want: "payment…"},
+		{value: "zürich-münchen", width: 7, want: "zürich…"},
+	}
+	for _, tt := range tests {
+		if got := truncateValue(tt.value, tt.width); got != tt.want {
+			t.Errorf("truncateValue(%q, %d) = %q, want %q", tt.value, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestLabelsString(t *testing.T) {
+	labels := map[string]string{"tier": "backend", "app": "web", "team": strings.Repeat("x", 40)}
+	if got, want := labelsString(labels, 0), "app=web,team="+strings.Repeat("x", 40)+",tier=backend"; got != want {
+		t.Errorf("labelsString() = %q, want %q", got, want)
+	}
+	if got, want := labelsString(labels, 5), "app=web,team=xxxx…,tier=back…"; got != want {
+		t.Errorf("truncated labelsString() = %q, want %q", got, want)
+	}
+	if got := labelsString(nil, 0); got != "<none>" {
+		t.Errorf("labelsString(nil) = %q, want <none>", got)
+	}
+}
+
+func TestMetadataColumns(t *testing.T) {
+	opts := printOptions{ShowLabels: true, LabelColumns: []string{"team", "cost-center"}, AnnotationColumns: []string{"owner.example.com/oncall"}}
+	if got, want := metadataHeaders(opts), []string{"TEAM", "COST-CENTER", "OWNER.EXAMPLE.COM/ONCALL", "LABELS"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("headers = %v, want %v", got, want)
+	}
+
+	long := strings.Repeat("a", 50)
+	info := podinfo.PodInfo{
+		Labels:      map[string]string{"team": long},
+		Annotations: map[string]string{"owner.example.com/oncall": "alice"},
+	}
+	cells := metadataCells(info, opts)
+	truncated := strings.Repeat("a", maxColumnValueWidth-1) + ellipsis
+	if want := []string{truncated, "", "alice", "team=" + truncated}; !reflect.DeepEqual(cells, want) {
+		t.Errorf("cells = %q, want %q", cells, want)
+	}
+
+	if headers := metadataHeaders(printOptions{}); headers != nil {
+		t.Errorf("headers = %v, want none without the flags", headers)
+	}
+}
+
+func TestValidateMetadataKeys(t *testing.T) {
+	if err := validateMetadataKeys("--label-columns", []string{"team", "app.kubernetes.io/name"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := validateMetadataKeys("--label-columns", []string{"team", "cost center"})
+	if err == nil || !strings.Contains(err.Error(), `invalid --label-columns key "cost center"`) {
+		t.Errorf("error = %v, want the invalid key reported", err)
+	}
+}
+
+func TestProcessLabelsAndAnnotations(t *testing.T) {
+	pod := newTestPod("default", "web")
+	long := strings.Repeat("v", 60)
+	pod.Labels = map[string]string{"team": "payments", "cost-center": long}
+	pod.Annotations = map[string]string{"owner": "alice", "kubectl.kubernetes.io/last-applied-configuration": "{}"}
+	listed := []clusterPods{{Pods: []v1.Pod{*pod}}}
+
+	result, err := podQuery{}.process(context.Background(), listed, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info := result.Infos[0]; info.Labels != nil || info.Annotations != nil {
+		t.Errorf("labels %v, annotations %v, want neither by default", info.Labels, info.Annotations)
+	}
+
+	query := podQuery{Labels: true, Annotations: []string{"owner", "missing"}}
+	result, err = query.process(context.Background(), listed, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info := result.Infos[0]
+	if !reflect.DeepEqual(info.Labels, pod.Labels) {
+		t.Errorf("labels = %v, want %v", info.Labels, pod.Labels)
+	}
+	if want := map[string]string{"owner": "alice"}; !reflect.DeepEqual(info.Annotations, want) {
+		t.Errorf("annotations = %v, want only the requested keys %v", info.Annotations, want)
+	}
+
+	// Structured output keeps long values whole
+	out, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(out), `"cost-center":"`+long+`"`) {
+		t.Errorf("JSON %s does not contain the complete cost-center label", out)
+	}
+}
+
+func TestPrintOptionsMetadata(t *testing.T) {
+	pod := newTestPod("default", "web")
+	pod.Labels = map[string]string{"team": "payments", "tier": "backend"}
+	pod.Annotations = map[string]string{"owner": "alice", "runbook": "https://runbooks/web"}
+	listed := []clusterPods{{Pods: []v1.Pod{*pod}}}
+
+	tests := []struct {
+		name            string
+		opts            printOptions
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		{name: "none", opts: printOptions{}},
+		{name: "show labels", opts: printOptions{ShowLabels: true}, wantLabels: pod.Labels},
+		{name: "label columns", opts: printOptions{LabelColumns: []string{"team"}}, wantLabels: pod.Labels},
+		{
+			name:            "annotation columns",
+			opts:            printOptions{AnnotationColumns: []string{"owner"}},
+			wantAnnotations: map[string]string{"owner": "alice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query podQuery
+			query.Labels, query.Annotations = tt.opts.metadata()
+			result, err := query.process(context.Background(), listed, time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			info := result.Infos[0]
+			if !reflect.DeepEqual(info.Labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", info.Labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(info.Annotations, tt.wantAnnotations) {
+				t.Errorf("annotations = %v, want %v", info.Annotations, tt.wantAnnotations)
+			}
+		})
+	}
+}
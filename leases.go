@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// leaseExpiryFactor stretches the lease duration before a lease counts as
+// expired, to tolerate renewals that are a little late
+const leaseExpiryFactor = 1.1
+
+// LeaseInfo holds formatted lease information
+type LeaseInfo struct {
+	Name        string     `json:"name"`
+	Namespace   string     `json:"namespace"`
+	Holder      string     `json:"holder,omitempty"`
+	AcquireTime *time.Time `json:"acquireTime,omitempty"`
+	RenewTime   *time.Time `json:"renewTime,omitempty"`
+	// DurationSeconds is how long the holder owns the lease after renewing
+	// it, 0 when not set
+	DurationSeconds int32 `json:"durationSeconds"`
+	Expired         bool  `json:"expired"`
+}
+
+// IsLeaseExpired reports whether the holder of lease has stopped renewing
+// it: now is past the renew time plus leaseExpiryFactor times the lease
+// duration. A lease that was never renewed or has no duration is expired,
+// since nobody can be holding it.
+func IsLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	grace := time.Duration(float64(*lease.Spec.LeaseDurationSeconds) * leaseExpiryFactor * float64(time.Second))
+	return now.After(lease.Spec.RenewTime.Add(grace))
+}
+
+// extractLeaseInfo extracts relevant information from a lease
+func extractLeaseInfo(lease *coordinationv1.Lease, now time.Time) LeaseInfo {
+	info := LeaseInfo{
+		Name:      lease.Name,
+		Namespace: lease.Namespace,
+		Expired:   IsLeaseExpired(lease, now),
+	}
+	if lease.Spec.HolderIdentity != nil {
+		info.Holder = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.AcquireTime != nil {
+		acquired := lease.Spec.AcquireTime.Time
+		info.AcquireTime = &acquired
+	}
+	if lease.Spec.RenewTime != nil {
+		renewed := lease.Spec.RenewTime.Time
+		info.RenewTime = &renewed
+	}
+	if lease.Spec.LeaseDurationSeconds != nil {
+		info.DurationSeconds = *lease.Spec.LeaseDurationSeconds
+	}
+	return info
+}
+
+// listLeases lists the leases of namespace, only the expired ones with
+// expiredOnly
+func listLeases(ctx context.Context, client kubernetes.Interface, namespace string, expiredOnly bool, now time.Time) ([]LeaseInfo, error) {
+	leases, err := client.CoordinationV1().Leases(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "leases", namespace)
+	}
+	infos := []LeaseInfo{}
+	for i := range leases.Items {
+		info := extractLeaseInfo(&leases.Items[i], now)
+		if expiredOnly && !info.Expired {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// leaseTime formats an optional lease timestamp
+func leaseTime(t *time.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// printLeaseTable prints one row per lease. The NAMESPACE column is shown
+// when listing across namespaces.
+func printLeaseTable(out io.Writer, infos []LeaseInfo, showNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tHOLDER\tACQUIRED-TIME\tRENEW-TIME\tDURATION\tIS-EXPIRED")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		holder := info.Holder
+		if holder == "" {
+			holder = "<none>"
+		}
+		duration := "<none>"
+		if info.DurationSeconds > 0 {
+			duration = (time.Duration(info.DurationSeconds) * time.Second).String()
+		}
+		expired := "no"
+		if info.Expired {
+			expired = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name, holder, leaseTime(info.AcquireTime), leaseTime(info.RenewTime), duration, expired)
+	}
+	return w.Flush()
+}
+
+// runLeases implements the leases subcommand
+func runLeases(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("leases", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list leases from (empty for all namespaces)")
+	expiredOnly := fs.Bool("show-expired-only", false, fmt.Sprintf("only show leases not renewed within %g times their duration", leaseExpiryFactor))
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err := listLeases(ctx, client, *namespace, *expiredOnly, time.Now())
+		if err != nil {
+			return fmt.Errorf("error listing leases: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No leases found")
+			return nil
+		}
+		return printLeaseTable(out, infos, *namespace == "")
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestLease returns a lease held by holder, renewed at renewed and
+// lasting durationSeconds
+func newTestLease(name, holder string, renewed time.Time, durationSeconds int32) *coordinationv1.Lease {
+	renewTime := metav1.NewMicroTime(renewed)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "operators", Name: name},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			AcquireTime:          &renewTime,
+			RenewTime:            &renewTime,
+			LeaseDurationSeconds: &durationSeconds,
+		},
+	}
+}
+
+func TestIsLeaseExpired(t *testing.T) {
+	renewed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// A 10s lease gets 11s before it expires
+	deadline := renewed.Add(11 * time.Second)
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "just renewed", now: renewed, want: false},
+		{name: "past the duration but within the slack", now: renewed.Add(10500 * time.Millisecond), want: false},
+		{name: "exactly at the deadline", now: deadline, want: false},
+		{name: "a nanosecond past the deadline", now: deadline.Add(time.Nanosecond), want: true},
+		{name: "long past the deadline", now: renewed.Add(time.Hour), want: true},
+	}
+	lease := newTestLease("web-controller", "web-7d9f-abcde", renewed, 10)
+	for _, tt := range tests {
+		if got := IsLeaseExpired(lease, tt.now); got != tt.want {
+			t.Errorf("%s: IsLeaseExpired() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsLeaseExpiredUnset(t *testing.T) {
+	now := time.Now()
+	neverRenewed := newTestLease("never-renewed", "pod-a", now, 15)
+	neverRenewed.Spec.RenewTime = nil
+	if !IsLeaseExpired(neverRenewed, now) {
+		t.Error("IsLeaseExpired() = false for a lease without renew time, want true")
+	}
+	noDuration := newTestLease("no-duration", "pod-a", now, 15)
+	noDuration.Spec.LeaseDurationSeconds = nil
+	if !IsLeaseExpired(noDuration, now) {
+		t.Error("IsLeaseExpired() = false for a lease without duration, want true")
+	}
+}
+
+func TestListLeasesExpiredOnly(t *testing.T) {
+	now := time.Now()
+	client := fake.NewSimpleClientset(
+		newTestLease("fresh", "pod-a", now.Add(-5*time.Second), 15),
+		newTestLease("stale", "pod-b", now.Add(-time.Minute), 15),
+	)
+
+	infos, err := listLeases(context.Background(), client, "", true, now)
+	if err != nil {
+		t.Fatalf("listLeases() error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Name != "stale" || !infos[0].Expired {
+		t.Fatalf("listLeases(expired only) = %+v, want only the stale lease", infos)
+	}
+
+	var out bytes.Buffer
+	if err := printLeaseTable(&out, infos, false); err != nil {
+		t.Fatalf("printLeaseTable() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want header and 1 row:\n%s", len(lines), out.String())
+	}
+	fields := strings.Fields(lines[1])
+	if fields[0] != "stale" || fields[1] != "pod-b" || fields[4] != "15s" || fields[5] != "yes" {
+		t.Errorf("row = %q, want stale held by pod-b for 15s and expired", lines[1])
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// logsOptions holds the flags of the logs subcommand, mirroring kubectl logs
+type logsOptions struct {
+	Container string
+	Follow    bool
+	// Tail is the number of lines to show from the end, -1 for all
+	Tail     int64
+	Since    time.Duration
+	Previous bool
+}
+
+// validate checks the flag values
+func (o logsOptions) validate() error {
+	if o.Tail < -1 {
+		return fmt.Errorf("--tail must be -1 (all lines) or more, got %d", o.Tail)
+	}
+	if o.Since < 0 {
+		return fmt.Errorf("--since must not be negative, got %s", o.Since)
+	}
+	return nil
+}
+
+// podLogOptions returns the API options for streaming container's logs
+func (o logsOptions) podLogOptions(container string) *v1.PodLogOptions {
+	opts := &v1.PodLogOptions{
+		Container: container,
+		Follow:    o.Follow,
+		Previous:  o.Previous,
+	}
+	if o.Tail >= 0 {
+		tail := o.Tail
+		opts.TailLines = &tail
+	}
+	if o.Since > 0 {
+		// The API takes whole seconds; round up so no requested line is lost
+		seconds := int64(math.Ceil(o.Since.Seconds()))
+		opts.SinceSeconds = &seconds
+	}
+	return opts
+}
+
+// resolveContainer returns the container to stream logs from: container if
+// the pod has it, or the pod's only container when container is empty.
+// Errors list the containers to choose from.
+func resolveContainer(pod *v1.Pod, container string) (string, error) {
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	if container != "" {
+		for _, name := range names {
+			if name == container {
+				return container, nil
+			}
+		}
+		return "", fmt.Errorf("container %q not found in pod %s, choose one of: %s", container, pod.Name, strings.Join(names, ", "))
+	}
+	if len(pod.Spec.Containers) == 1 {
+		return pod.Spec.Containers[0].Name, nil
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return "", fmt.Errorf("pod %s has no containers", pod.Name)
+	}
+	return "", fmt.Errorf("pod %s has %d containers, choose one with --container: %s", pod.Name, len(names), strings.Join(names, ", "))
+}
+
+// copyLogs copies stream to out until the stream ends or ctx is done. The
+// stream is closed either way; cancellation (Ctrl-C) is not an error, but
+// running into the ctx deadline is.
+func copyLogs(ctx context.Context, stream io.ReadCloser, out io.Writer) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Unblock a Read waiting for the next line of a followed log
+			stream.Close()
+		case <-done:
+		}
+	}()
+	_, err := io.Copy(out, stream)
+	stream.Close()
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// streamLogs writes the logs of pod namespace/podName to out. lookupCtx
+// bounds looking up the pod; the stream itself runs until it ends or ctx is
+// done, so following a log is not cut short by --timeout.
+func streamLogs(ctx, lookupCtx context.Context, client kubernetes.Interface, namespace, podName string, opts logsOptions, out io.Writer) error {
+	pods := client.CoreV1().Pods(namespace)
+	pod, err := pods.Get(lookupCtx, podName, metav1.GetOptions{})
+	if err != nil {
+		return handleAPIError(err, "pods", namespace)
+	}
+	container, err := resolveContainer(pod, opts.Container)
+	if err != nil {
+		return err
+	}
+	stream, err := pods.GetLogs(podName, opts.podLogOptions(container)).Stream(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		return fmt.Errorf("opening the log stream of container %s: %w", container, handleAPIError(err, "pods/log", namespace))
+	}
+	return copyLogs(ctx, stream, out)
+}
+
+// runLogs implements the logs subcommand
+func runLogs(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s logs [flags] POD\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	var opts logsOptions
+	namespace := fs.String("namespace", metav1.NamespaceDefault, "namespace of the pod")
+	fs.StringVar(&opts.Container, "container", "", "container to show logs from (required when the pod has several)")
+	fs.StringVar(&opts.Container, "c", "", "shorthand for --container")
+	fs.BoolVar(&opts.Follow, "follow", false, "keep streaming new log lines until Ctrl-C")
+	fs.BoolVar(&opts.Follow, "f", false, "shorthand for --follow")
+	fs.Int64Var(&opts.Tail, "tail", -1, "number of lines to show from the end of the log (-1 for all)")
+	fs.DurationVar(&opts.Since, "since", 0, "only show lines newer than this, e.g. 5s, 2m or 3h")
+	fs.BoolVar(&opts.Previous, "previous", false, "show the logs of the previous, terminated instance of the container")
+	fs.BoolVar(&opts.Previous, "p", false, "shorthand for --previous")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// Allow flags after the pod name, as kubectl does
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("a pod name is required")
+	}
+	podName := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments after the pod name: %s", strings.Join(fs.Args(), " "))
+	}
+	if err := clientOpts.setupLogger(outputText); err != nil {
+		return err
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	client, _, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	// Ctrl-C ends the stream and exits cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	lookupCtx, cancel := requestContext(ctx, clientOpts.Timeout)
+	defer cancel()
+	streamCtx := ctx
+	if !opts.Follow {
+		streamCtx = lookupCtx
+	}
+
+	if err := streamLogs(streamCtx, lookupCtx, client, *namespace, podName, opts, os.Stdout); err != nil {
+		return fmt.Errorf("error streaming logs: %w", timeoutError(err, clientOpts.Timeout))
+	}
+	return nil
+}
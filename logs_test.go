@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newContainersPod returns a pod in the default namespace with the named containers
+func newContainersPod(name string, containers ...string) *v1.Pod {
+	pod := newTestPod("default", name)
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+	}
+	return pod
+}
+
+func TestResolveContainer(t *testing.T) {
+	single := newContainersPod("web", "app")
+	multi := newContainersPod("web", "app", "sidecar")
+	multi.Spec.InitContainers = []v1.Container{{Name: "migrate"}}
+
+	tests := []struct {
+		name      string
+		pod       *v1.Pod
+		container string
+		want      string
+		wantErr   string
+	}{
+		{name: "only container is the default", pod: single, want: "app"},
+		{name: "explicit container", pod: multi, container: "sidecar", want: "sidecar"},
+		{name: "init container", pod: multi, container: "migrate", want: "migrate"},
+		{name: "several containers", pod: multi, wantErr: "choose one with --container: migrate, app, sidecar"},
+		{name: "unknown container", pod: multi, container: "db", wantErr: `container "db" not found in pod web, choose one of: migrate, app, sidecar`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveContainer(tt.pod, tt.container)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveContainer() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodLogOptions(t *testing.T) {
+	opts := logsOptions{Follow: true, Tail: 20, Since: 1500 * time.Millisecond, Previous: true}.podLogOptions("app")
+	if opts.Container != "app" || !opts.Follow || !opts.Previous {
+		t.Errorf("options = %+v, want container app, follow and previous", opts)
+	}
+	if opts.TailLines == nil || *opts.TailLines != 20 {
+		t.Errorf("TailLines = %v, want 20", opts.TailLines)
+	}
+	if opts.SinceSeconds == nil || *opts.SinceSeconds != 2 {
+		t.Errorf("SinceSeconds = %v, want 2 (rounded up)", opts.SinceSeconds)
+	}
+
+	defaults := logsOptions{Tail: -1}.podLogOptions("app")
+	if defaults.TailLines != nil || defaults.SinceSeconds != nil {
+		t.Errorf("options = %+v, want the whole log", defaults)
+	}
+}
+
+func TestStreamLogs(t *testing.T) {
+	client := fake.NewSimpleClientset(newContainersPod("web", "app"), newContainersPod("multi", "app", "sidecar"))
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := streamLogs(ctx, ctx, client, "default", "web", logsOptions{Tail: -1}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The fake clientset serves this body for every log request
+	if out.String() != "fake logs" {
+		t.Errorf("output = %q, want the log stream", out.String())
+	}
+
+	if err := streamLogs(ctx, ctx, client, "default", "multi", logsOptions{Tail: -1}, io.Discard); err == nil {
+		t.Error("expected an error for a pod with several containers")
+	}
+	if err := streamLogs(ctx, ctx, client, "default", "missing", logsOptions{Tail: -1}, io.Discard); err == nil {
+		t.Error("expected an error for a missing pod")
+	}
+}
+
+func TestCopyLogsCancel(t *testing.T) {
+	// A followed log blocks until the next line; cancelling must unblock it
+	reader, writer := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() { done <- copyLogs(ctx, reader, &out) }()
+
+	if _, err := writer.Write([]byte("line 1\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("copyLogs() = %v, want nil after cancellation", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("copyLogs did not return after cancellation")
+	}
+	if out.String() != "line 1\n" {
+		t.Errorf("output = %q, want the lines before cancellation", out.String())
+	}
+}
+
+func TestCopyLogsDeadline(t *testing.T) {
+	reader, _ := io.Pipe()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := copyLogs(ctx, reader, io.Discard); err != context.DeadlineExceeded {
+		t.Errorf("copyLogs() = %v, want the deadline error", err)
+	}
+}
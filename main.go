@@ -1,131 +1,905 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
 	"time"
 
+	"golang.org/x/term"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/labels"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/yaml"
+
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// Output formats supported by --output
+const (
+	outputText  = "text"
+	outputTable = "table"
+	outputWide  = "wide" // the table with extra columns, as kubectl get -o wide
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputName  = "name" // namespace/name only, one pod per line
 )
 
-// PodInfo holds formatted pod information
-type PodInfo struct {
-	Name      string
-	Namespace string
-	NodeName  string
-	Phase     string
-	PodIP     string
-	Restarts  int32
-	Age       time.Duration
-	CreatedAt time.Time
+// Groupings supported by --group-by
+const (
+	groupByOwnerKey = "owner"
+	groupByNodeKey  = "node"
+)
+
+// parseList splits a comma-separated flag value such as --namespace into
+// its entries, dropping empty entries and duplicates. For --namespace an
+// empty result means all namespaces.
+func parseList(value string) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// logWarnings logs non-fatal errors with the logger of ctx
+func logWarnings(ctx context.Context, warnings []error) {
+	logger := logging.FromContext(ctx)
+	for _, warning := range warnings {
+		logger.Warn("partial results", "error", warning)
+	}
+}
+
+// fatal logs err and exits with status 1. A remote process's non-zero exit
+// (exec) is passed on as the exit status instead.
+func fatal(logger *slog.Logger, err error) {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitStatus() > 0 {
+		os.Exit(exitErr.ExitStatus())
+	}
+	logger.Error("command failed", "error", err)
+	os.Exit(1)
 }
 
-// getTotalRestarts calculates total restart count for all containers in a pod
-func getTotalRestarts(containerStatuses []v1.ContainerStatus) int32 {
-	var total int32
-	for _, cs := range containerStatuses {
-		total += cs.RestartCount
+// printHidden mentions how many pods were hidden by namespace exclusion
+func printHidden(out io.Writer, hidden int) {
+	if hidden > 0 {
+		fmt.Fprintf(out, "(%d pods hidden by --exclude-namespace)\n", hidden)
 	}
-	return total
 }
 
-// extractPodInfo extracts relevant information from a pod
-func extractPodInfo(pod *v1.Pod, now time.Time) PodInfo {
-	return PodInfo{
-		Name:      pod.Name,
-		Namespace: pod.Namespace,
-		NodeName:  pod.Spec.NodeName,
-		Phase:     string(pod.Status.Phase),
-		PodIP:     pod.Status.PodIP,
-		Restarts:  getTotalRestarts(pod.Status.ContainerStatuses),
-		Age:       now.Sub(pod.CreationTimestamp.Time).Truncate(time.Second),
-		CreatedAt: pod.CreationTimestamp.Time,
+// printTotals prints the pod total, broken down per namespace when more
+// than one namespace was requested
+func printTotals(out io.Writer, pods []v1.Pod, namespaces []string) {
+	switch len(namespaces) {
+	case 0:
+		fmt.Fprintf(out, "Total: %d pods across all namespaces\n", len(pods))
+	case 1:
+		fmt.Fprintf(out, "Total: %d pods in namespace '%s'\n", len(pods), namespaces[0])
+	default:
+		counts := make(map[string]int)
+		for i := range pods {
+			counts[pods[i].Namespace]++
+		}
+		fmt.Fprintf(out, "Total: %d pods across %d namespaces\n", len(pods), len(namespaces))
+		for _, ns := range namespaces {
+			fmt.Fprintf(out, "  %s: %d\n", ns, counts[ns])
+		}
 	}
 }
 
+// printOptions controls how pods are rendered in text and table output
+type printOptions struct {
+	AgeFormat string
+	Color     bool
+	// ShowLabels adds every label; LabelColumns and AnnotationColumns add
+	// one column (or text line) per key
+	ShowLabels        bool
+	LabelColumns      []string
+	AnnotationColumns []string
+	// ShowExitCodes adds the last exit of single-container pods
+	ShowExitCodes bool
+	// ShowVolumes adds each pod's volumes
+	ShowVolumes bool
+	// Resources adds the effective requests and limits of each pod's
+	// Resources, the --resources view
+	Resources bool
+	// ShowResources adds the summed requests and limits of the containers,
+	// the Requests and Limits of each pod's Resources
+	ShowResources bool
+	// Wide adds the columns of -o wide to table output
+	Wide bool
+	// NoHeaders leaves out the table header and the lines around the pods
+	NoHeaders bool
+}
+
+// metadata returns whether pod labels must be gathered and which annotation
+// keys, for the columns these options print
+func (o printOptions) metadata() (bool, []string) {
+	return o.ShowLabels || len(o.LabelColumns) > 0, o.AnnotationColumns
+}
+
 // printPodInfo prints formatted pod information
-func printPodInfo(info PodInfo) {
-	fmt.Printf("Pod: %s\n", info.Name)
-	fmt.Printf("  Namespace: %s\n", info.Namespace)
+func printPodInfo(out io.Writer, info podinfo.PodInfo, opts printOptions) {
+	header := "Pod: " + vulnName(info)
+	if opts.Color {
+		header = colorize(header, podColor(info))
+	}
+	fmt.Fprintln(out, header)
+	if info.Cluster != "" {
+		fmt.Fprintf(out, "  Cluster: %s\n", info.Cluster)
+	}
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
 	if info.NodeName != "" {
-		fmt.Printf("  Node: %s\n", info.NodeName)
+		fmt.Fprintf(out, "  Node: %s\n", info.NodeName)
 	} else {
-		fmt.Printf("  Node: <unscheduled>\n")
+		fmt.Fprintf(out, "  Node: <unscheduled>\n")
+	}
+	fmt.Fprintf(out, "  Phase: %s\n", info.Phase)
+	if info.Reason != info.Phase {
+		fmt.Fprintf(out, "  Status: %s\n", info.Reason)
+	}
+	fmt.Fprintf(out, "  Ready: %s\n", info.ReadyString())
+	fmt.Fprintf(out, "  QoS: %s\n", info.QOSClass)
+	if info.Owner != "" {
+		fmt.Fprintf(out, "  Owner: %s\n", info.Owner)
 	}
-	fmt.Printf("  Phase: %s\n", info.Phase)
 	if info.PodIP != "" {
-		fmt.Printf("  IP: %s\n", info.PodIP)
+		fmt.Fprintf(out, "  IP: %s\n", info.PodIP)
 	} else {
-		fmt.Printf("  IP: <none>\n")
+		fmt.Fprintf(out, "  IP: <none>\n")
+	}
+	fmt.Fprintf(out, "  Restarts: %d\n", info.Restarts)
+	fmt.Fprintf(out, "  Age: %s\n", formatAge(opts.AgeFormat, info.Age, info.CreatedAt))
+	if opts.ShowExitCodes && info.TotalContainers == 1 {
+		fmt.Fprintf(out, "  Last exit: %s\n", lastExitString(info.LastTermination, opts.AgeFormat))
+	}
+	if opts.ShowLabels {
+		fmt.Fprintf(out, "  Labels: %s\n", labelsString(info.Labels, 0))
+	}
+	for _, key := range opts.LabelColumns {
+		fmt.Fprintf(out, "  Label %s: %s\n", key, info.Labels[key])
+	}
+	for _, key := range opts.AnnotationColumns {
+		fmt.Fprintf(out, "  Annotation %s: %s\n", key, info.Annotations[key])
+	}
+	if r := info.Resources; opts.Resources && r != nil {
+		fmt.Fprintf(out, "  CPU: requests %s, limits %s\n", formatCPU(r.CPURequest), formatCPU(r.CPULimit))
+		fmt.Fprintf(out, "  Memory: requests %s, limits %s\n", formatMemory(r.MemoryRequest), formatMemory(r.MemoryLimit))
+	}
+	if r := info.Resources; opts.ShowResources && r != nil {
+		fmt.Fprintf(out, "  Requests: %s\n", formatResourceList(r.Requests))
+		fmt.Fprintf(out, "  Limits: %s\n", formatResourceList(r.Limits))
+	}
+	if info.Usage != nil {
+		if info.Usage.Pending {
+			fmt.Fprintf(out, "  Usage: <pending>\n")
+		} else {
+			cpu, memory := usageStrings(info.Usage)
+			fmt.Fprintf(out, "  Usage: CPU %s, memory %s\n", cpu, memory)
+		}
+	}
+	if len(info.Containers) > 0 {
+		fmt.Fprintf(out, "  Service account: %s\n", orNone(info.ServiceAccountName))
+		fmt.Fprintf(out, "  Containers:\n")
+		for _, c := range info.Containers {
+			printContainerInfo(out, c, opts.AgeFormat)
+		}
+	}
+	printVulnerableImages(out, info)
+	if opts.ShowVolumes {
+		printVolumes(out, info.Volumes, opts.Color)
+	}
+	if info.Scheduling != nil {
+		printScheduling(out, info.Scheduling)
 	}
-	fmt.Printf("  Restarts: %d\n", info.Restarts)
-	fmt.Printf("  Age: %s\n\n", info.Age.String())
+	if info.Events != nil {
+		printEvents(out, info.Events, opts.AgeFormat)
+	}
+	fmt.Fprintln(out)
 }
 
-// createKubernetesClient creates and returns a Kubernetes client
-func createKubernetesClient(kubeconfigPath string) (*kubernetes.Clientset, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build config from kubeconfig: %w", err)
+// exitCodeString renders an exit code with its reason and signal, e.g.
+// "1 (Error)" or "143 (Error, signal 15)". OOM kills lead with the reason,
+// "OOMKilled (137)", so they stand out from ordinary crashes.
+func exitCodeString(t *podinfo.TerminationInfo) string {
+	if t.OOMKilled() {
+		return fmt.Sprintf("%s (%d)", t.Reason, t.ExitCode)
+	}
+	var details []string
+	if t.Reason != "" {
+		details = append(details, t.Reason)
 	}
+	if t.Signal != 0 {
+		details = append(details, fmt.Sprintf("signal %d", t.Signal))
+	}
+	if len(details) == 0 {
+		return strconv.Itoa(int(t.ExitCode))
+	}
+	return fmt.Sprintf("%d (%s)", t.ExitCode, strings.Join(details, ", "))
+}
 
-	client, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+// lastExitString renders a previous termination with when it ended, or
+// "<none>" for a container that has not restarted
+func lastExitString(t *podinfo.TerminationInfo, ageFormat string) string {
+	if t == nil {
+		return "<none>"
+	}
+	if t.FinishedAt.IsZero() {
+		return exitCodeString(t)
 	}
+	finished := formatAge(ageFormat, t.Age, t.FinishedAt)
+	if ageFormat != ageAbsolute {
+		finished += " ago"
+	}
+	return exitCodeString(t) + ", finished " + finished
+}
 
-	return client, nil
+// lastExitCell is the LAST-EXIT table cell: the exit code of the only
+// container's previous run, "-" for pods with several containers (see
+// --containers) and "<none>" when it has not restarted
+func lastExitCell(info podinfo.PodInfo) string {
+	if info.TotalContainers != 1 {
+		return "-"
+	}
+	if info.LastTermination == nil {
+		return "<none>"
+	}
+	return exitCodeString(info.LastTermination)
+}
+
+// printContainerInfo prints a single line describing a container
+func printContainerInfo(out io.Writer, c podinfo.ContainerInfo, ageFormat string) {
+	name := c.Name
+	switch {
+	case c.InitProgress != "":
+		name += " (init, blocking startup at " + c.InitProgress + ")"
+	case c.Init:
+		name += " (init)"
+	}
+	state := c.State
+	if c.Reason != "" {
+		state += " (" + c.Reason + ")"
+	}
+	image := c.Image
+	if c.ImageID != "" && c.ImageID != c.Image {
+		image += " (" + c.ImageID + ")"
+	}
+	fmt.Fprintf(out, "    - %s: image=%s ready=%t restarts=%d state=%s", name, image, c.Ready, c.Restarts, state)
+	if c.LastTermination != nil {
+		fmt.Fprintf(out, " last-exit=%s", lastExitString(c.LastTermination, ageFormat))
+	}
+	fmt.Fprintln(out)
+}
+
+// printPodTable prints one row per pod, aligned with a tabwriter. With color
+// enabled each row is colored by the pod's status.
+func printPodTable(out io.Writer, infos []podinfo.PodInfo, opts printOptions) error {
+	showResources := opts.Resources && len(infos) > 0 && infos[0].Resources != nil
+	showUsage := len(infos) > 0 && infos[0].Usage != nil
+	showCluster := len(infos) > 0 && infos[0].Cluster != ""
+	showOwner := len(infos) > 0 && infos[0].Owner != ""
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 3, ' ', 0)
+	var colors []string
+	if !opts.NoHeaders {
+		colors = append(colors, "")
+		if showCluster {
+			fmt.Fprint(w, "CLUSTER\t")
+		}
+		fmt.Fprint(w, "NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODE")
+		if showOwner {
+			fmt.Fprint(w, "\tOWNER")
+		}
+		if opts.Wide {
+			fmt.Fprint(w, "\tQOS\tSERVICE-ACCOUNT\tIMAGES")
+		}
+		if opts.ShowExitCodes {
+			fmt.Fprint(w, "\tLAST-EXIT")
+		}
+		if showResources {
+			fmt.Fprint(w, "\tCPU-REQ\tCPU-LIM\tMEM-REQ\tMEM-LIM")
+		}
+		if showUsage {
+			fmt.Fprint(w, "\tCPU\tMEMORY")
+		}
+		if opts.ShowVolumes {
+			fmt.Fprint(w, "\tVOLUMES")
+		}
+		for _, header := range metadataHeaders(opts) {
+			fmt.Fprintf(w, "\t%s", header)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, info := range infos {
+		colors = append(colors, podColor(info))
+		node := info.NodeName
+		if node == "" {
+			node = "<none>"
+		}
+		if showCluster {
+			fmt.Fprintf(w, "%s\t", info.Cluster)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s",
+			info.Namespace, vulnName(info), info.ReadyString(), info.Reason, info.Restarts, formatAge(opts.AgeFormat, info.Age, info.CreatedAt), node)
+		if showOwner {
+			fmt.Fprintf(w, "\t%s", info.Owner)
+		}
+		if opts.Wide {
+			fmt.Fprintf(w, "\t%s\t%s\t%s", info.QOSClass, orNone(info.ServiceAccountName), orNone(strings.Join(info.ContainerImages, ",")))
+		}
+		if opts.ShowExitCodes {
+			fmt.Fprintf(w, "\t%s", lastExitCell(info))
+		}
+		if r := info.Resources; showResources && r != nil {
+			fmt.Fprintf(w, "\t%s\t%s\t%s\t%s",
+				formatCPU(r.CPURequest), formatCPU(r.CPULimit), formatMemory(r.MemoryRequest), formatMemory(r.MemoryLimit))
+		}
+		if showUsage {
+			cpu, memory := usageStrings(info.Usage)
+			fmt.Fprintf(w, "\t%s\t%s", cpu, memory)
+		}
+		if opts.ShowVolumes {
+			fmt.Fprintf(w, "\t%s", volumesCell(info.Volumes))
+		}
+		for _, cell := range metadataCells(info, opts) {
+			fmt.Fprintf(w, "\t%s", cell)
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !opts.Color {
+		_, err := out.Write(table.Bytes())
+		return err
+	}
+	return writeColoredLines(out, table.Bytes(), colors)
+}
+
+// printStructured writes v as JSON or YAML
+func printStructured(out io.Writer, v interface{}, format string) error {
+	var data []byte
+	var err error
+	if format == outputJSON {
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(v)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode output as %s: %w", format, err)
+	}
+	_, err = out.Write(data)
+	return err
 }
 
 func main() {
+	// Subcommands take their own flags
+	if len(os.Args) > 1 {
+		var run func(*clientOptions, []string) error
+		switch os.Args[1] {
+		case "nodes":
+			run = runNodes
+		case "deployments":
+			run = runDeployments
+		case "services":
+			run = runServices
+		case "logs":
+			run = runLogs
+		case "namespaces":
+			run = runNamespaces
+		case "secrets":
+			run = runSecrets
+		case "pdb":
+			run = runPDB
+		case "exec":
+			run = runExec
+		case "cm":
+			run = runConfigMaps
+		case "netpol":
+			run = runNetpol
+		case "affinity":
+			run = runAffinity
+		case "summary":
+			run = runSummary
+		case "quota":
+			run = runQuota
+		case "chaos":
+			run = runChaos
+		case "hpa":
+			run = runHPA
+		case "leases":
+			run = runLeases
+		case "audit-sa":
+			run = runAuditSA
+		case "probe":
+			run = runProbe
+		case "statefulsets":
+			run = runStatefulSets
+		}
+		if run != nil {
+			var clientOpts clientOptions
+			if err := run(&clientOpts, os.Args[2:]); err != nil {
+				fatal(clientOpts.logger(), err)
+			}
+			return
+		}
+	}
+
 	// Parse command line flags
-	kubeconfig := flag.String("kubeconfig", "/Users/viskumar/.kube/config", "absolute path to the kubeconfig file")
-	namespace := flag.String("namespace", "", "namespace to list pods from (empty for all namespaces)")
+	var clientOpts clientOptions
+	clientOpts.AddFlags(flag.CommandLine)
+	flag.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(flag.CommandLine)
+	namespace := flag.String("namespace", "", "comma-separated namespaces to list pods from (empty for all namespaces)")
+	excludeNamespace := flag.String("exclude-namespace", "", "comma-separated namespaces or glob patterns (e.g. openshift-*) to hide from the results")
+	showContainers := flag.Bool("containers", false, "show one line per container (including init containers) for each pod")
+	showResources := flag.Bool("resources", false, "show CPU and memory requests/limits per pod, with totals per namespace and node")
+	showResourceLists := flag.Bool("show-resources", false, "show the requests and limits of each pod's containers added up as their specs set them, e.g. cpu: 500m, memory: 256Mi; a resource some container does not limit shows <none> (text output)")
+	showMetrics := flag.Bool("metrics", false, "show live CPU and memory usage from metrics-server, with the percentage of requests")
+	sortBy := flag.String("sort-by", "", "sort pods by a comma-separated list of keys, each with an optional :asc or :desc suffix, e.g. namespace,restarts:desc,name (keys: name, namespace, node, phase, restarts, age, cpu, memory; cpu and memory sort the busiest first and require --metrics)")
+	reverse := flag.Bool("reverse", false, "reverse the direction of every --sort-by key")
+	minRestarts := flag.Int("min-restarts", 0, "only show pods with at least this many container restarts")
+	sinceRestart := flag.Duration("since-restart", 0, "only show pods with a container that restarted within this duration, e.g. 1h")
+	namespaceFile := flag.String("namespace-file", "", "file of namespaces to list pods from, one per line (# starts a comment), added to those of --namespace")
+	concurrency := flag.Int("concurrency", podinfo.DefaultConcurrency, "how many namespaces of --namespace and --namespace-file are listed at once")
+	flag.IntVar(concurrency, "parallel", podinfo.DefaultConcurrency, "same as --concurrency")
+	allContexts := flag.Bool("all-contexts", false, "list pods from every context in the kubeconfig concurrently, adding a CLUSTER column")
+	reason := flag.String("reason", "", "comma-separated pod statuses to show, e.g. CrashLoopBackOff,ImagePullBackOff (case-insensitive)")
+	phase := flag.String("phase", "", "comma-separated pod phases to show, e.g. Pending,Failed (case-insensitive)")
+	qos := flag.String("qos", "", "comma-separated QoS classes to show, e.g. BestEffort,Burstable (case-insensitive)")
+	olderThan := flag.Duration("older-than", 0, "only show pods created longer ago than this, e.g. 10m or 720h")
+	newerThan := flag.Duration("newer-than", 0, "only show pods created more recently than this, e.g. 1h")
+	selector := flag.String("selector", "", "label selector to filter pods, e.g. app=web,tier!=cache")
+	flag.StringVar(selector, "l", "", "shorthand for --selector")
+	node := flag.String("node", "", "only show pods on this node, or on the nodes matching a glob pattern such as worker-*")
+	summary := flag.Bool("summary", false, "print pod counts by phase, QoS class, namespace and node instead of listing pods")
+	top := flag.Int("top", 0, "with --summary, only show the N namespaces with the most pods (0 for all)")
+	whyPending := flag.Bool("why-pending", false, "explain unscheduled pods: node selector, required node affinity, tolerations and the last FailedScheduling message (text, json and yaml output)")
+	showEvents := flag.Bool("show-events", false, "show the last five events of pods that are not Running or Succeeded (text output)")
+	showLabels := flag.Bool("show-labels", false, "show each pod's labels, in a LABELS column in table output")
+	labelColumns := flag.String("label-columns", "", "comma-separated label keys to show as extra columns, e.g. team,cost-center")
+	annotationColumns := flag.String("annotation-columns", "", "comma-separated annotation keys to show as extra columns")
+	showExitCodes := flag.Bool("show-exit-codes", false, "show the exit code of the previous run of single-container pods, in a LAST-EXIT column in table output")
+	showVolumes := flag.Bool("volumes", false, "show each pod's volumes with their type and source (e.g. the claim of PVC volumes), flagging hostPath volumes")
+	serviceAccount := flag.String("service-account", "", "only show pods running as this service account")
+	imageContains := flag.String("image-contains", "", "only show pods with a container image containing this substring, matched against the image in the spec and the digest it resolved to")
+	pvcOnly := flag.Bool("pvc-only", false, "only show pods mounting at least one PersistentVolumeClaim")
+	deleteMatched := flag.Bool("delete", false, "delete the listed pods after printing them, asking for confirmation first")
+	yes := flag.Bool("yes", false, "with --delete, delete without asking for confirmation")
+	gracePeriod := flag.Int("grace-period", -1, "with --delete, seconds each pod gets to terminate gracefully; negative keeps the pod's own period")
+	force := flag.Bool("force", false, "with --delete, remove the pods immediately, skipping graceful termination")
+	dryRun := flag.Bool("dry-run", false, "with --delete, only print what would be deleted, validated by a server-side dry run")
+	scanImages := flag.Bool("scan-images", false, "run --scanner-path on each distinct container image and mark pods whose image it flags with "+vulnMarker)
+	scannerPath := flag.String("scanner-path", "", "scanner binary run by --scan-images with an image as its only argument; a non-zero exit code flags the image")
+	scanTimeout := flag.Duration("scan-timeout", 2*time.Minute, "with --scan-images, how long each scanner run may take before it is killed and its image reported unscanned (0 for no limit); scans run one image at a time, after the API calls and outside their --timeout")
+	tailLogs := flag.Int64("tail-logs", 0, "after the pods, print the last N log lines of each container in CrashLoopBackOff or whose last run exited non-zero (text and table output)")
+	logBytes := flag.Int64("log-bytes", defaultLogBytes, "with --tail-logs, the most log bytes printed per container, keeping the end of the log")
+	snapshotOut := flag.String("snapshot-out", "", "also write the listed pods as JSON to this file, for a later --diff")
+	diffFile := flag.String("diff", "", "instead of listing pods, compare them with a --snapshot-out (or --output json) file taken with the same filters: added, removed, moved and restarted pods (text, json or yaml output)")
+	showOwners := flag.Bool("owners", false, "resolve the top-level workload owning each pod by following its owner references (e.g. deployment/frontend)")
+	flag.BoolVar(showOwners, "show-owner", false, "same as --owners")
+	groupBy := flag.String("group-by", "", "group pods: owner (counts per owner) or node (pods listed under their node)")
+	output := flag.String("output", outputText, "output format: text, table, wide (table with QOS, SERVICE-ACCOUNT and IMAGES columns), json, yaml or name (namespace/name per line)")
+	outputTemplate := flag.String("output-template", "", "print each pod with this Go text/template instead of the text or table output, e.g. '{{.Name}}\\t{{.Phase}}' (\\t and \\n stand for a tab and a newline, tab-separated cells are aligned), or one of the predefined minimal and wide; besides the builtins, templates can use upper, lower, truncate N and colorize COLOR (red, green, yellow or dim)")
+	noHeaders := flag.Bool("no-headers", false, "leave out the table header, the \"Found N pods\" line and the totals, and print nothing when no pod matches (text and table output)")
+	ageFormat := flag.String("age-format", ageCompact, "how text and table output show pod age: compact (like kubectl), full or absolute")
+	failOn := flag.String("fail-on", "", "comma-separated conditions (pending, failed, crashloop, unscheduled, restarts>N) that make the command exit 2 when any pod matches")
+	refresh := flag.Duration("refresh", 0, "keep running and redraw the pods at this interval, e.g. 5s, from a watch instead of repeated lists (text and table output)")
+	outputFile := addOutputFileFlag(flag.CommandLine)
+	appendOutput := flag.Bool("append", false, "with --output-file and text output, add this run to the end of the file under a timestamp header")
+	serve := flag.String("serve", "", "instead of printing, serve Prometheus pod metrics on this address (e.g. :9090) at /metrics, with /healthz")
+	serveInterval := flag.Duration("serve-interval", defaultServeInterval, "with --serve, how often the metrics are recomputed from the watched pods")
+	colorMode := flag.String("color", colorAuto, "color text and table output by pod status: always, never or auto (only on a terminal, unless NO_COLOR is set)")
 	flag.Parse()
 
-	// Create Kubernetes client
-	client, err := createKubernetesClient(*kubeconfig)
+	if err := clientOpts.setupLogger(*output); err != nil {
+		fatal(clientOpts.logger(), err)
+	}
+	logger := clientOpts.Logger
+	rootCtx := logging.NewContext(context.Background(), logger)
+
+	switch *output {
+	case outputText, outputTable, outputWide, outputJSON, outputYAML, outputName:
+	default:
+		fatal(logger, fmt.Errorf("unknown --output format %q (want text, table, wide, json, yaml or name)", *output))
+	}
+	if err := validateAgeFormat(*ageFormat); err != nil {
+		fatal(logger, err)
+	}
+	if err := validateOutputFile(*outputFile, *appendOutput, *output); err != nil {
+		fatal(logger, err)
+	}
+	toFile := *outputFile != "" && *outputFile != outputStdout
+	if *refresh > 0 && toFile {
+		fatal(logger, errors.New("--output-file cannot be combined with --refresh"))
+	}
+	color, err := stdoutColorEnabled(*colorMode)
+	if err != nil {
+		fatal(logger, err)
+	}
+	if toFile && *colorMode == colorAuto {
+		// --color auto looks at the terminal, not at the file
+		color = false
+	}
+	printOpts := printOptions{
+		AgeFormat:         *ageFormat,
+		Color:             color,
+		ShowLabels:        *showLabels,
+		LabelColumns:      parseList(*labelColumns),
+		AnnotationColumns: parseList(*annotationColumns),
+		ShowExitCodes:     *showExitCodes,
+		ShowVolumes:       *showVolumes,
+		Resources:         *showResources,
+		ShowResources:     *showResourceLists,
+		NoHeaders:         *noHeaders,
+	}
+	// -o wide renders like the table, with more columns
+	if *output == outputWide {
+		*output = outputTable
+		printOpts.Wide = true
+	}
+	if err := validateMetadataKeys("--label-columns", printOpts.LabelColumns); err != nil {
+		fatal(logger, err)
+	}
+	if err := validateMetadataKeys("--annotation-columns", printOpts.AnnotationColumns); err != nil {
+		fatal(logger, err)
+	}
+	sortKeys, err := podinfo.ParseSortKeys(*sortBy)
+	if err != nil {
+		fatal(logger, err)
+	}
+	if *reverse {
+		if len(sortKeys) == 0 {
+			fatal(logger, errors.New("--reverse requires --sort-by"))
+		}
+		sortKeys = podinfo.ReverseSortKeys(sortKeys)
+	}
+	for _, key := range sortKeys {
+		if (key.Key == podinfo.SortByCPU || key.Key == podinfo.SortByMemory) && !*showMetrics {
+			fatal(logger, fmt.Errorf("--sort-by %s requires --metrics", key.Key))
+		}
+	}
+	if clientOpts.Timeout < 0 {
+		fatal(logger, fmt.Errorf("--timeout must not be negative, got %s", clientOpts.Timeout))
+	}
+	if *refresh < 0 {
+		fatal(logger, fmt.Errorf("--refresh must not be negative, got %s", *refresh))
+	}
+	if *refresh > 0 && (*output == outputJSON || *output == outputYAML || *output == outputName) {
+		fatal(logger, errors.New("--refresh only supports text and table output"))
+	}
+	failConditions, err := parseFailOn(*failOn)
 	if err != nil {
-		log.Fatalf("Error creating Kubernetes client: %v", err)
+		fatal(logger, err)
+	}
+	if *refresh > 0 && len(failConditions) > 0 {
+		fatal(logger, errors.New("--fail-on cannot be combined with --refresh"))
+	}
+	deleteOpts := deleteOptions{GracePeriod: *gracePeriod, Force: *force, DryRun: *dryRun}
+	if *deleteMatched {
+		switch {
+		case *refresh > 0:
+			fatal(logger, errors.New("--delete cannot be combined with --refresh"))
+		case *serve != "":
+			fatal(logger, errors.New("--delete cannot be combined with --serve"))
+		}
+		if err := deleteOpts.validate(); err != nil {
+			fatal(logger, err)
+		}
+	} else {
+		deleteFlags := []struct {
+			name string
+			set  bool
+		}{{"--yes", *yes}, {"--grace-period", *gracePeriod >= 0}, {"--force", *force}, {"--dry-run", *dryRun}}
+		for _, f := range deleteFlags {
+			if f.set {
+				fatal(logger, fmt.Errorf("%s requires --delete", f.name))
+			}
+		}
+	}
+	if *serve != "" {
+		switch {
+		case *refresh > 0:
+			fatal(logger, errors.New("--serve cannot be combined with --refresh"))
+		case toFile:
+			fatal(logger, errors.New("--serve cannot be combined with --output-file"))
+		case len(failConditions) > 0:
+			fatal(logger, errors.New("--serve cannot be combined with --fail-on"))
+		case *allContexts:
+			fatal(logger, errors.New("--serve exports a single cluster and cannot be combined with --all-contexts"))
+		case *serveInterval <= 0:
+			fatal(logger, fmt.Errorf("--serve-interval must be positive, got %s", *serveInterval))
+		}
+	}
+	if *concurrency < 1 {
+		fatal(logger, fmt.Errorf("--concurrency/--parallel must be at least 1, got %d", *concurrency))
+	}
+	logTailOpts := logTailOptions{Lines: *tailLogs, MaxBytes: *logBytes}
+	if err := logTailOpts.validate(); err != nil {
+		fatal(logger, err)
+	}
+	if *tailLogs > 0 {
+		switch {
+		case *output == outputJSON || *output == outputYAML || *output == outputName:
+			fatal(logger, errors.New("--tail-logs only supports text and table output"))
+		case *refresh > 0:
+			fatal(logger, errors.New("--tail-logs cannot be combined with --refresh"))
+		case *serve != "":
+			fatal(logger, errors.New("--tail-logs cannot be combined with --serve"))
+		}
+	}
+	var snapshot []podinfo.PodInfo
+	if *diffFile != "" {
+		switch {
+		case *refresh > 0:
+			fatal(logger, errors.New("--diff cannot be combined with --refresh"))
+		case *serve != "":
+			fatal(logger, errors.New("--diff cannot be combined with --serve"))
+		case *summary || *groupBy != "":
+			fatal(logger, errors.New("--diff cannot be combined with --summary or --group-by"))
+		case *output == outputName:
+			fatal(logger, errors.New("--diff only supports text, json and yaml output"))
+		}
+		if snapshot, err = readSnapshot(*diffFile); err != nil {
+			fatal(logger, err)
+		}
+	}
+	if *snapshotOut != "" && (*refresh > 0 || *serve != "") {
+		fatal(logger, errors.New("--snapshot-out cannot be combined with --refresh or --serve"))
+	}
+	if *minRestarts < 0 {
+		fatal(logger, fmt.Errorf("--min-restarts must not be negative, got %d", *minRestarts))
+	}
+	if *sinceRestart < 0 {
+		fatal(logger, fmt.Errorf("--since-restart must not be negative, got %s", *sinceRestart))
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	switch *groupBy {
+	case "":
+	case groupByOwnerKey:
+		*showOwners = true
+	case groupByNodeKey:
+		if *sortBy != "" {
+			fatal(logger, fmt.Errorf("--group-by node sorts pods by node and cannot be combined with --sort-by %s", *sortBy))
+		}
+	default:
+		fatal(logger, fmt.Errorf("unknown --group-by %q (want owner or node)", *groupBy))
+	}
+	if _, err := labels.Parse(*selector); err != nil {
+		fatal(logger, fmt.Errorf("invalid --selector: %w", err))
+	}
+	if err := podinfo.ValidateNode(*node); err != nil {
+		fatal(logger, err)
+	}
+	qosClasses, err := podinfo.ParseQOSClasses(parseList(*qos))
+	if err != nil {
+		fatal(logger, err)
+	}
+	if err := podinfo.ValidateAgeRange(*olderThan, *newerThan); err != nil {
+		fatal(logger, err)
+	}
+	if *summary && *groupBy != "" {
+		fatal(logger, errors.New("--summary and --group-by are mutually exclusive"))
+	}
+	if *output == outputName && (*summary || *groupBy != "") {
+		fatal(logger, errors.New("--output name lists pods and cannot be combined with --summary or --group-by"))
+	}
+	var podTemplate *template.Template
+	if *outputTemplate != "" {
+		switch {
+		case *output != outputText && *output != outputTable, printOpts.Wide:
+			fatal(logger, errors.New("--output-template replaces text and table output and cannot be combined with --output json, yaml, wide or name"))
+		case *summary || *groupBy != "":
+			fatal(logger, errors.New("--output-template prints pods and cannot be combined with --summary or --group-by"))
+		case *diffFile != "":
+			fatal(logger, errors.New("--output-template cannot be combined with --diff"))
+		}
+		if podTemplate, err = parseOutputTemplate(*outputTemplate); err != nil {
+			fatal(logger, err)
+		}
+	}
+	if *noHeaders && (*summary || *groupBy != "") {
+		fatal(logger, errors.New("--no-headers cannot be combined with --summary or --group-by"))
+	}
+	var scanner *imageScanner
+	switch {
+	case *scanTimeout < 0:
+		fatal(logger, fmt.Errorf("--scan-timeout must not be negative, got %s", *scanTimeout))
+	case *scanImages && *scannerPath == "":
+		fatal(logger, errors.New("--scan-images requires --scanner-path"))
+	case *scanImages:
+		path, err := exec.LookPath(*scannerPath)
+		if err != nil {
+			fatal(logger, fmt.Errorf("invalid --scanner-path: %w", err))
+		}
+		scanner = newImageScanner(path, *scanTimeout)
+	case *scannerPath != "":
+		fatal(logger, errors.New("--scanner-path requires --scan-images"))
+	}
+	if *allContexts && clientOpts.Context != "" {
+		fatal(logger, errors.New("--context and --all-contexts are mutually exclusive"))
+	}
+
+	namespaces := parseList(*namespace)
+	if *namespaceFile != "" {
+		fileNamespaces, err := ReadNamespacesFile(*namespaceFile)
+		if err != nil {
+			fatal(logger, fmt.Errorf("error reading --namespace-file: %w", err))
+		}
+		// An empty list means all namespaces, which the file never asks for
+		if len(fileNamespaces) == 0 {
+			fatal(logger, fmt.Errorf("--namespace-file %s lists no namespaces", *namespaceFile))
+		}
+		namespaces = unionNamespaces(namespaces, fileNamespaces)
+	}
+	excludes := parseList(*excludeNamespace)
+	if err := podinfo.ValidateExcludes(namespaces, excludes); err != nil {
+		fatal(logger, err)
+	}
+
+	// Create Kubernetes clients, one per context with --all-contexts
+	clusters, warnings, err := resolveClusters(clientOpts, *allContexts)
+	if err != nil {
+		fatal(logger, fmt.Errorf("error creating Kubernetes client: %w", err))
+	}
+	logWarnings(rootCtx, warnings)
+
+	// Filters, enrichments and output shared by one-shot and --refresh runs
+	query := podQuery{
+		Namespaces: namespaces,
+		Filter: podinfo.Filter{
+			Excludes:     excludes,
+			Reasons:      parseList(*reason),
+			Phases:       parseList(*phase),
+			OlderThan:    *olderThan,
+			NewerThan:    *newerThan,
+			MinRestarts:  *minRestarts,
+			SinceRestart: *sinceRestart,
+			QOSClasses:   qosClasses,
+			PVCOnly:      *pvcOnly,
+			Node:         *node,
+
+			ServiceAccount: *serviceAccount,
+			ImageContains:  *imageContains,
+		},
+		Owners:            *showOwners,
+		Containers:        *showContainers,
+		Resources:         *showResources || *showResourceLists,
+		Metrics:           *showMetrics,
+		Events:            *showEvents,
+		SortBy:            sortKeys,
+		Timeout:           clientOpts.Timeout,
+		WhyPending:        *whyPending,
+		Volumes:           *showVolumes,
+		Scanner:           scanner,
+		FailingContainers: *tailLogs > 0,
+	}
+	query.Labels, query.Annotations = printOpts.metadata()
+	view := podView{
+		Output:     *output,
+		GroupBy:    *groupBy,
+		Summary:    *summary,
+		Top:        *top,
+		Resources:  *showResources,
+		Namespaces: namespaces,
+		Node:       *node,
+		Print:      printOpts,
+		Template:   podTemplate,
+	}
+	// An exact --node is selected server-side, a pattern by query.Filter
+	listOpts := metav1.ListOptions{LabelSelector: *selector, FieldSelector: podinfo.NodeFieldSelector(*node)}
+	if *serve != "" {
+		if err := runServe(rootCtx, *serve, *serveInterval, clusters, listOpts, query); err != nil {
+			fatal(logger, err)
+		}
+		return
+	}
+	if *refresh > 0 {
+		if err := runRefresh(rootCtx, *refresh, clusters, listOpts, query, view); err != nil {
+			fatal(logger, err)
+		}
+		return
+	}
+
+	// Create context with timeout, covering the list and any follow-up calls
+	ctx, cancel := requestContext(rootCtx, clientOpts.Timeout)
 	defer cancel()
 
 	// List pods
-	pods, err := client.CoreV1().Pods(*namespace).List(ctx, metav1.ListOptions{})
+	listStart := time.Now()
+	listed, warnings, err := listClusters(ctx, clusters, namespaces, listOpts, *concurrency)
+	logWarnings(ctx, timeoutErrors(warnings, clientOpts.Timeout))
 	if err != nil {
-		log.Fatalf("Error listing pods: %v", err)
+		fatal(logger, fmt.Errorf("error listing pods: %w", timeoutError(err, clientOpts.Timeout)))
 	}
-
-	if len(pods.Items) == 0 {
-		if *namespace != "" {
-			fmt.Printf("No pods found in namespace '%s'\n", *namespace)
-		} else {
-			fmt.Println("No pods found in the cluster")
+	if clientOpts.Verbose {
+		// Compare --content-type protobuf and json on large clusters
+		total := 0
+		for _, c := range listed {
+			total += len(c.Pods)
 		}
-		os.Exit(0)
+		logger.Info("listed pods", "pods", total, "duration", time.Since(listStart).Round(time.Millisecond), "contentType", clientOpts.ContentType)
 	}
 
 	// Process and display pods
 	now := time.Now()
-	fmt.Printf("Found %d pods:\n\n", len(pods.Items))
+	result, err := query.process(ctx, listed, now)
+	if err != nil {
+		fatal(logger, err)
+	}
+	err = writeOutput(*outputFile, *appendOutput, now, func(out io.Writer) error {
+		if *diffFile != "" {
+			diff := diffSnapshots(snapshot, result.Infos)
+			if view.structured() {
+				return printStructured(out, diff, *output)
+			}
+			printPodDiff(out, diff)
+		} else if err := view.render(out, result); err != nil {
+			return err
+		}
+		if *tailLogs > 0 {
+			tailFailingLogs(ctx, out, clusters, result.Infos, result.Failing, logTailOpts)
+		}
+		return nil
+	})
+	if err != nil {
+		fatal(logger, err)
+	}
+	if *snapshotOut != "" {
+		if err := writeSnapshot(*snapshotOut, result.Infos); err != nil {
+			fatal(logger, fmt.Errorf("writing --snapshot-out: %w", err))
+		}
+	}
 
-	for i := range pods.Items {
-		podInfo := extractPodInfo(&pods.Items[i], now)
-		printPodInfo(podInfo)
+	// Act on the listed pods after printing them
+	if *deleteMatched && len(result.Infos) > 0 {
+		if !*yes && !*dryRun {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				fatal(logger, errors.New("--delete asks for confirmation on a terminal, pass --yes to delete without it"))
+			}
+			confirmed, err := confirmDelete(os.Stdin, os.Stderr, result.Infos)
+			if err != nil {
+				fatal(logger, err)
+			}
+			if !confirmed {
+				fmt.Fprintln(os.Stderr, "No pods deleted")
+				return
+			}
+		}
+		// The list's deadline kept running while the prompt waited
+		deleteCtx, cancelDelete := requestContext(rootCtx, clientOpts.Timeout)
+		failed := deletePods(deleteCtx, os.Stdout, clusters, result.Infos, deleteOpts)
+		cancelDelete()
+		if failed > 0 {
+			fatal(logger, fmt.Errorf("%d of %d pods not deleted", failed, len(result.Infos)))
+		}
 	}
 
-	if *namespace != "" {
-		fmt.Printf("Total: %d pods in namespace '%s'\n", len(pods.Items), *namespace)
-	} else {
-		fmt.Printf("Total: %d pods across all namespaces\n", len(pods.Items))
+	// Gate on the listed pods after printing them
+	if matches := evaluateFailOn(failConditions, result.Infos); len(matches) > 0 {
+		printFailMatches(os.Stderr, matches)
+		os.Exit(exitConditionMatched)
 	}
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// newTestPod returns a minimal pod in the given namespace
+func newTestPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+// podNames returns the names of pods in order
+func podNames(pods []v1.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "", want: nil},
+		{value: "default", want: []string{"default"}},
+		{value: "kube-system,monitoring,ingress", want: []string{"kube-system", "monitoring", "ingress"}},
+		{value: "a, b,,a,b , c", want: []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		if got := parseList(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseList(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLogWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	logWarnings(ctx, []error{errors.New("namespace 'secret': forbidden")})
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log output is not JSON: %v\n%s", err, buf.String())
+	}
+	if record["level"] != "WARN" || record["error"] != "namespace 'secret': forbidden" {
+		t.Errorf("logged %v, want a WARN record with the error field", record)
+	}
+}
+
+func TestExitCodeString(t *testing.T) {
+	finished := time.Now().Add(-3 * time.Hour)
+	tests := []struct {
+		termination podinfo.TerminationInfo
+		want        string
+	}{
+		{podinfo.TerminationInfo{ExitCode: 137, Reason: "OOMKilled"}, "OOMKilled (137)"},
+		{podinfo.TerminationInfo{ExitCode: 137, Signal: 9, Reason: "Error"}, "137 (Error, signal 9)"},
+		{podinfo.TerminationInfo{ExitCode: 1, Reason: "Error"}, "1 (Error)"},
+		{podinfo.TerminationInfo{ExitCode: 2}, "2"},
+	}
+	for _, tt := range tests {
+		if got := exitCodeString(&tt.termination); got != tt.want {
+			t.Errorf("exitCodeString(%+v) = %q, want %q", tt.termination, got, tt.want)
+		}
+	}
+
+	oom := &podinfo.TerminationInfo{ExitCode: 137, Reason: "OOMKilled", FinishedAt: finished, Age: 3 * time.Hour}
+	if got, want := lastExitString(oom, ageCompact), "OOMKilled (137), finished 3h ago"; got != want {
+		t.Errorf("lastExitString() = %q, want %q", got, want)
+	}
+	if got, want := lastExitString(nil, ageCompact), "<none>"; got != want {
+		t.Errorf("lastExitString(nil) = %q, want %q", got, want)
+	}
+}
+
+func TestLastExitCell(t *testing.T) {
+	oom := &podinfo.TerminationInfo{ExitCode: 137, Reason: "OOMKilled"}
+	tests := []struct {
+		name string
+		info podinfo.PodInfo
+		want string
+	}{
+		{"single container OOM-killed", podinfo.PodInfo{TotalContainers: 1, LastTermination: oom}, "OOMKilled (137)"},
+		{"single container never restarted", podinfo.PodInfo{TotalContainers: 1}, "<none>"},
+		{"several containers", podinfo.PodInfo{TotalContainers: 2}, "-"},
+	}
+	for _, tt := range tests {
+		if got := lastExitCell(tt.info); got != tt.want {
+			t.Errorf("%s: lastExitCell() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrintContainerInfoInit(t *testing.T) {
+	tests := []struct {
+		container podinfo.ContainerInfo
+		want      string
+	}{
+		{
+			podinfo.ContainerInfo{Name: "config", Image: "busybox", Init: true, State: "terminated"},
+			"    - config (init): image=busybox ready=false restarts=0 state=terminated\n",
+		},
+		{
+			podinfo.ContainerInfo{Name: "migrate", Image: "migrate:1", Init: true, Restarts: 4, State: "waiting", Reason: "CrashLoopBackOff", InitProgress: "Init:1/3"},
+			"    - migrate (init, blocking startup at Init:1/3): image=migrate:1 ready=false restarts=4 state=waiting (CrashLoopBackOff)\n",
+		},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		printContainerInfo(&out, tt.container, ageCompact)
+		if got := out.String(); got != tt.want {
+			t.Errorf("printContainerInfo(%s) = %q, want %q", tt.container.Name, got, tt.want)
+		}
+	}
+}
+
+func TestPrintPodTableExitCodes(t *testing.T) {
+	infos := []podinfo.PodInfo{{
+		Name: "web", Namespace: "default", Phase: "Running", Reason: "Running", ReadyContainers: 1, TotalContainers: 1,
+		LastTermination: &podinfo.TerminationInfo{ExitCode: 137, Reason: "OOMKilled"},
+	}}
+	var out bytes.Buffer
+	if err := printPodTable(&out, infos, printOptions{ShowExitCodes: true}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "LAST-EXIT") || !strings.HasSuffix(lines[1], "OOMKilled (137)") {
+		t.Errorf("table =\n%s\nwant a LAST-EXIT column reporting the OOM kill", out.String())
+	}
+}
+
+func TestPrintPodTableWide(t *testing.T) {
+	infos := []podinfo.PodInfo{{
+		Name: "cache", Namespace: "default", Reason: "Running", TotalContainers: 1, QOSClass: "BestEffort",
+		ServiceAccountName: "cache-sa", ContainerImages: []string{"redis@sha256:abc", "busybox:1.36"},
+	}}
+	for _, wide := range []bool{false, true} {
+		var out bytes.Buffer
+		if err := printPodTable(&out, infos, printOptions{Wide: wide}); err != nil {
+			t.Fatal(err)
+		}
+		header, row, _ := strings.Cut(out.String(), "\n")
+		fields := strings.Fields(row)
+		headers := strings.Fields(header)
+		got := slices.Equal(headers[len(headers)-3:], []string{"QOS", "SERVICE-ACCOUNT", "IMAGES"}) &&
+			slices.Equal(fields[len(fields)-3:], []string{"BestEffort", "cache-sa", "redis@sha256:abc,busybox:1.36"})
+		if got != wide {
+			t.Errorf("wide=%t: table =\n%s", wide, out.String())
+		}
+	}
+}
+
+func TestNoPodsMessage(t *testing.T) {
+	tests := []struct {
+		view podView
+		want string
+	}{
+		{view: podView{}, want: "No pods found in the cluster"},
+		{view: podView{Namespaces: []string{"a", "b"}}, want: "No pods found in namespace 'a,b'"},
+		{view: podView{Node: "worker-1"}, want: "No pods found on node 'worker-1'"},
+		{view: podView{Node: "worker-*", Namespaces: []string{"default"}}, want: "No pods found on nodes matching 'worker-*' in namespace 'default'"},
+	}
+	for _, tt := range tests {
+		if got := tt.view.noPodsMessage(); got != tt.want {
+			t.Errorf("noPodsMessage() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPrintPodNames(t *testing.T) {
+	infos := podInfos("default/web-1", "batch/job-1")
+	var out bytes.Buffer
+	printPodNames(&out, infos, false)
+	if want := "default/web-1\nbatch/job-1\n"; out.String() != want {
+		t.Errorf("printPodNames() = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	infos = []podinfo.PodInfo{{Cluster: "prod", Namespace: "default", Name: "web-1"}, {Namespace: "default", Name: "web-2"}}
+	printPodNames(&out, infos, true)
+	if want := "prod:web-1\nweb-2\n"; out.String() != want {
+		t.Errorf("printPodNames() in a single namespace = %q, want %q", out.String(), want)
+	}
+}
+
+func TestRenderNoHeaders(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "default", Name: "web", Reason: "Running", TotalContainers: 1},
+		{Namespace: "default", Name: "db", Reason: "Running", TotalContainers: 1},
+	}
+	result := podResult{Pods: []v1.Pod{*newTestPod("default", "web"), *newTestPod("default", "db")}, Infos: infos, Hidden: 3}
+
+	for _, output := range []string{outputText, outputTable} {
+		var out bytes.Buffer
+		view := podView{Output: output, Print: printOptions{NoHeaders: true}}
+		if err := view.render(&out, result); err != nil {
+			t.Fatal(err)
+		}
+		got := out.String()
+		if strings.Contains(got, "Found") || strings.Contains(got, "NAMESPACE") || strings.Contains(got, "Total") || strings.Contains(got, "hidden") {
+			t.Errorf("%s output with --no-headers =\n%s", output, got)
+		}
+		if !strings.Contains(got, "web") || !strings.Contains(got, "db") {
+			t.Errorf("%s output with --no-headers is missing pods:\n%s", output, got)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := (podView{Output: outputTable, Print: printOptions{NoHeaders: true}}).render(&out, podResult{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("empty result with --no-headers = %q, want nothing", out.String())
+	}
+	out.Reset()
+	if err := (podView{Output: outputName}).render(&out, podResult{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("empty result with -o name = %q, want nothing", out.String())
+	}
+
+	out.Reset()
+	if err := (podView{Output: outputName, Namespaces: []string{"default"}}).render(&out, result); err != nil {
+		t.Fatal(err)
+	}
+	if want := "web\ndb\n"; out.String() != want {
+		t.Errorf("-o name in a single namespace = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadNamespacesFile reads the namespaces of a --namespace-file: one name
+// per line, skipping blank lines and comment lines starting with #.
+// Surrounding whitespace is trimmed.
+func ReadNamespacesFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var namespaces []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		namespaces = append(namespaces, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return namespaces, nil
+}
+
+// unionNamespaces returns the namespaces of a and then those of b not in a
+func unionNamespaces(a, b []string) []string {
+	union := make([]string, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, ns := range append(append([]string{}, a...), b...) {
+		if !seen[ns] {
+			seen[ns] = true
+			union = append(union, ns)
+		}
+	}
+	return union
+}
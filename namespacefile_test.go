@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadNamespacesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "namespaces")
+	content := "# team namespaces\nweb\n\n  api  \n# staging\n#db\nbatch\nweb\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadNamespacesFile(path)
+	if err != nil {
+		t.Fatalf("ReadNamespacesFile() error: %v", err)
+	}
+	if want := []string{"web", "api", "batch", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadNamespacesFile() = %q, want %q", got, want)
+	}
+
+	if _, err := ReadNamespacesFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("ReadNamespacesFile(missing file) error = nil, want an error")
+	}
+}
+
+func TestUnionNamespaces(t *testing.T) {
+	got := unionNamespaces([]string{"default", "web"}, []string{"web", "api", "api"})
+	if want := []string{"default", "web", "api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unionNamespaces() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// sortByPodCount is the --sort-by key of the namespaces subcommand
+const sortByPodCount = "pod-count"
+
+// maxConcurrentCounts caps the count requests in flight at once
+const maxConcurrentCounts = 10
+
+// ResourceCounts holds the number of resources in a namespace
+type ResourceCounts struct {
+	Pods     int `json:"pods"`
+	Services int `json:"services"`
+}
+
+// NamespaceInfo holds formatted namespace information
+type NamespaceInfo struct {
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	Counts    ResourceCounts `json:"counts"`
+	Age       time.Duration  `json:"-"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// extractNamespaceInfo extracts relevant information from a namespace
+func extractNamespaceInfo(namespace *v1.Namespace, counts ResourceCounts, now time.Time) NamespaceInfo {
+	status := string(namespace.Status.Phase)
+	if status == "" {
+		status = string(v1.NamespaceActive)
+	}
+	return NamespaceInfo{
+		Name:      namespace.Name,
+		Status:    status,
+		Counts:    counts,
+		Age:       now.Sub(namespace.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt: namespace.CreationTimestamp.Time,
+	}
+}
+
+// countPerNamespace calls count for every namespace concurrently and returns
+// the counts by namespace. The first error cancels the remaining calls.
+func countPerNamespace(ctx context.Context, namespaces []string, count func(ctx context.Context, namespace string) (int, error)) (map[string]int, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentCounts)
+	var mu sync.Mutex
+	counts := make(map[string]int, len(namespaces))
+	for _, ns := range namespaces {
+		g.Go(func() error {
+			n, err := count(ctx, ns)
+			if err != nil {
+				return fmt.Errorf("namespace %q: %w", ns, err)
+			}
+			mu.Lock()
+			counts[ns] = n
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// listNamespaces lists the namespaces with their pod and service counts.
+// With top > 0 only the top namespaces with the most pods are returned, so
+// services are only counted for those. Namespaces are sorted by name, or
+// busiest first when sorting by pod count or with top.
+func listNamespaces(ctx context.Context, client kubernetes.Interface, sortBy string, top int, now time.Time) ([]NamespaceInfo, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", handleAPIError(err, "namespaces", ""))
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for i := range namespaces.Items {
+		names = append(names, namespaces.Items[i].Name)
+	}
+
+	podCounts, err := countPerNamespace(ctx, names, func(ctx context.Context, ns string) (int, error) {
+		pods, err := client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("listing pods: %w", handleAPIError(err, "pods", ns))
+		}
+		return len(pods.Items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := namespaces.Items
+	if sortBy == sortByPodCount || top > 0 {
+		sort.SliceStable(items, func(i, j int) bool {
+			return podCounts[items[i].Name] > podCounts[items[j].Name]
+		})
+	}
+	if top > 0 && len(items) > top {
+		items = items[:top]
+	}
+
+	kept := make([]string, 0, len(items))
+	for i := range items {
+		kept = append(kept, items[i].Name)
+	}
+	serviceCounts, err := countPerNamespace(ctx, kept, func(ctx context.Context, ns string) (int, error) {
+		services, err := client.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("listing services: %w", handleAPIError(err, "services", ns))
+		}
+		return len(services.Items), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]NamespaceInfo, 0, len(items))
+	for i := range items {
+		counts := ResourceCounts{Pods: podCounts[items[i].Name], Services: serviceCounts[items[i].Name]}
+		infos = append(infos, extractNamespaceInfo(&items[i], counts, now))
+	}
+	return infos, nil
+}
+
+// printNamespaceTable prints one row per namespace
+func printNamespaceTable(out io.Writer, infos []NamespaceInfo, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tPOD-COUNT\tSERVICE-COUNT\tAGE")
+	for _, info := range infos {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n",
+			info.Name, info.Status, info.Counts.Pods, info.Counts.Services, formatAge(ageFormat, info.Age, info.CreatedAt))
+	}
+	return w.Flush()
+}
+
+// runNamespaces implements the namespaces subcommand
+func runNamespaces(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("namespaces", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	sortBy := fs.String("sort-by", "", "sort namespaces by pod-count (most pods first) instead of by name")
+	top := fs.Int("top", 0, "only show the N namespaces with the most pods, counting services for those only (0 for all)")
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table output shows namespace age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	if err := validateAgeFormat(*ageFormat); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+		switch *sortBy {
+		case "", sortByPodCount:
+		default:
+			return fmt.Errorf("unknown --sort-by key %q (want pod-count)", *sortBy)
+		}
+		if *top < 0 {
+			return fmt.Errorf("--top must not be negative, got %d", *top)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err := listNamespaces(ctx, client, *sortBy, *top, time.Now())
+		if err != nil {
+			return fmt.Errorf("error listing namespaces: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No namespaces found")
+			return nil
+		}
+		return printNamespaceTable(out, infos, *ageFormat)
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newNamespacesClient returns a fake client with three namespaces: "web"
+// (3 pods, 1 service), "quiet" (1 pod, 2 services) and a terminating "old"
+// namespace (2 pods, no services)
+func newNamespacesClient() *fake.Clientset {
+	namespace := func(name string, phase v1.NamespacePhase) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: v1.NamespaceStatus{Phase: phase}}
+	}
+	service := func(namespace, name string) *v1.Service {
+		return &v1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	return fake.NewSimpleClientset(
+		namespace("web", v1.NamespaceActive),
+		namespace("old", v1.NamespaceTerminating),
+		namespace("quiet", ""),
+		newTestPod("web", "a"), newTestPod("web", "b"), newTestPod("web", "c"),
+		newTestPod("old", "a"), newTestPod("old", "b"),
+		newTestPod("quiet", "a"),
+		service("web", "web"),
+		service("quiet", "api"), service("quiet", "db"),
+	)
+}
+
+// namespaceRows returns name, status and counts of infos, in order
+func namespaceRows(infos []NamespaceInfo) []string {
+	rows := []string{}
+	for _, info := range infos {
+		rows = append(rows, fmt.Sprintf("%s %s %d %d", info.Name, info.Status, info.Counts.Pods, info.Counts.Services))
+	}
+	return rows
+}
+
+func TestListNamespaces(t *testing.T) {
+	tests := []struct {
+		name   string
+		sortBy string
+		top    int
+		want   []string
+	}{
+		{name: "by name", want: []string{"old Terminating 2 0", "quiet Active 1 2", "web Active 3 1"}},
+		{name: "by pod count", sortBy: sortByPodCount, want: []string{"web Active 3 1", "old Terminating 2 0", "quiet Active 1 2"}},
+		{name: "top", top: 2, want: []string{"web Active 3 1", "old Terminating 2 0"}},
+		{name: "top larger than the namespaces", top: 5, want: []string{"web Active 3 1", "old Terminating 2 0", "quiet Active 1 2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos, err := listNamespaces(context.Background(), newNamespacesClient(), tt.sortBy, tt.top, time.Now())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := namespaceRows(infos); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("namespaces = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListNamespacesTopCapsServiceRequests(t *testing.T) {
+	client := newNamespacesClient()
+	var serviceLists atomic.Int32
+	client.PrependReactor("list", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+		serviceLists.Add(1)
+		return false, nil, nil
+	})
+
+	if _, err := listNamespaces(context.Background(), client, "", 1, time.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := serviceLists.Load(); got != 1 {
+		t.Errorf("listed services %d times, want 1 with --top 1", got)
+	}
+}
+
+func TestListNamespacesCountError(t *testing.T) {
+	client := newNamespacesClient()
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "old" {
+			return true, nil, context.DeadlineExceeded
+		}
+		return false, nil, nil
+	})
+
+	_, err := listNamespaces(context.Background(), client, "", 0, time.Now())
+	if err == nil || err.Error() != `namespace "old": listing pods: context deadline exceeded` {
+		t.Errorf("error = %v, want the failing namespace", err)
+	}
+}
@@ -0,0 +1,534 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NetworkPolicyInfo holds formatted network policy information
+type NetworkPolicyInfo struct {
+	Name         string   `json:"name"`
+	Namespace    string   `json:"namespace"`
+	PodSelector  string   `json:"podSelector"`
+	PolicyTypes  []string `json:"policyTypes"`
+	IngressRules []string `json:"ingressRules,omitempty"`
+	EgressRules  []string `json:"egressRules,omitempty"`
+}
+
+// ConnectivityInfo is the verdict of --from-pod and --to-pod
+type ConnectivityInfo struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+	// Policies are the policies isolating the egress of From or the
+	// ingress of To, with --show-rules
+	Policies []NetworkPolicyInfo `json:"policies,omitempty"`
+}
+
+// extractNetworkPolicyInfo formats policy, rendering each rule on one line
+func extractNetworkPolicyInfo(policy *networkingv1.NetworkPolicy) NetworkPolicyInfo {
+	info := NetworkPolicyInfo{
+		Name:        policy.Name,
+		Namespace:   policy.Namespace,
+		PodSelector: selectorString(&policy.Spec.PodSelector, "all pods"),
+		PolicyTypes: []string{},
+	}
+	ingress, egress := policyDirections(policy)
+	if ingress {
+		info.PolicyTypes = append(info.PolicyTypes, string(networkingv1.PolicyTypeIngress))
+	}
+	if egress {
+		info.PolicyTypes = append(info.PolicyTypes, string(networkingv1.PolicyTypeEgress))
+	}
+	for _, rule := range policy.Spec.Ingress {
+		info.IngressRules = append(info.IngressRules, "from "+peersString(rule.From)+" on "+portsString(rule.Ports))
+	}
+	for _, rule := range policy.Spec.Egress {
+		info.EgressRules = append(info.EgressRules, "to "+peersString(rule.To)+" on "+portsString(rule.Ports))
+	}
+	return info
+}
+
+// policyDirections reports whether policy isolates the ingress and the
+// egress of the pods it selects. Without policyTypes a policy always
+// isolates ingress, and egress only when it has egress rules.
+func policyDirections(policy *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true, len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		switch t {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return ingress, egress
+}
+
+// selectorString formats a label selector, or returns all for the empty
+// selector that matches everything
+func selectorString(selector *metav1.LabelSelector, all string) string {
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return all
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+// peersString formats the peers of a rule; no peers matches anything
+func peersString(peers []networkingv1.NetworkPolicyPeer) string {
+	if len(peers) == 0 {
+		return "anywhere"
+	}
+	parts := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		switch {
+		case peer.IPBlock != nil:
+			part := "cidr " + peer.IPBlock.CIDR
+			if len(peer.IPBlock.Except) > 0 {
+				part += " except " + strings.Join(peer.IPBlock.Except, ",")
+			}
+			parts = append(parts, part)
+		case peer.NamespaceSelector == nil:
+			parts = append(parts, selectorString(peer.PodSelector, "all pods"))
+		case peer.PodSelector == nil:
+			parts = append(parts, "all pods in "+selectorString(peer.NamespaceSelector, "all namespaces"))
+		default:
+			parts = append(parts, selectorString(peer.PodSelector, "all pods")+" in "+selectorString(peer.NamespaceSelector, "all namespaces"))
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// portsString formats the ports of a rule as kubectl describe does, e.g.
+// "80/TCP, 5000-5010/UDP"; no ports matches every port
+func portsString(ports []networkingv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "all ports"
+	}
+	parts := make([]string, 0, len(ports))
+	for _, port := range ports {
+		protocol := v1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		switch {
+		case port.Port == nil:
+			parts = append(parts, "all ports/"+string(protocol))
+		case port.EndPort != nil:
+			parts = append(parts, fmt.Sprintf("%s-%d/%s", port.Port.String(), *port.EndPort, protocol))
+		default:
+			parts = append(parts, port.Port.String()+"/"+string(protocol))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// selectorMatches reports whether selector matches set. An invalid selector
+// matches nothing.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
+
+// namespaceLabels returns the labels namespace selectors are matched
+// against. Namespaces not in known only carry the kubernetes.io/metadata.name
+// label the API server sets on every namespace.
+func namespaceLabels(known map[string]map[string]string, namespace string) map[string]string {
+	if set, ok := known[namespace]; ok {
+		return set
+	}
+	return map[string]string{v1.LabelMetadataName: namespace}
+}
+
+// peerMatches reports whether pod is one of the peers a rule of a policy in
+// policyNamespace names. A pod selector alone picks pods of the policy's own
+// namespace; an IP block is matched against the pod's IP.
+func peerMatches(peer networkingv1.NetworkPolicyPeer, policyNamespace string, pod *v1.Pod, known map[string]map[string]string) bool {
+	if peer.IPBlock != nil {
+		return ipBlockMatches(peer.IPBlock, pod.Status.PodIP)
+	}
+	if peer.NamespaceSelector == nil {
+		if pod.Namespace != policyNamespace {
+			return false
+		}
+	} else if !selectorMatches(peer.NamespaceSelector, namespaceLabels(known, pod.Namespace)) {
+		return false
+	}
+	return peer.PodSelector == nil || selectorMatches(peer.PodSelector, pod.Labels)
+}
+
+// ipBlockMatches reports whether ip is in the block's CIDR and none of its
+// exceptions. A pod without an IP yet matches no block.
+func ipBlockMatches(block *networkingv1.IPBlock, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	if _, cidr, err := net.ParseCIDR(block.CIDR); err != nil || !cidr.Contains(addr) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, cidr, err := net.ParseCIDR(except); err == nil && cidr.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isolatingPolicies returns the policies selecting pod that isolate it in
+// direction
+func isolatingPolicies(policies []networkingv1.NetworkPolicy, direction networkingv1.PolicyType, pod *v1.Pod) []*networkingv1.NetworkPolicy {
+	var isolating []*networkingv1.NetworkPolicy
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Namespace != pod.Namespace || !selectorMatches(&policy.Spec.PodSelector, pod.Labels) {
+			continue
+		}
+		ingress, egress := policyDirections(policy)
+		if direction == networkingv1.PolicyTypeIngress && ingress || direction == networkingv1.PolicyTypeEgress && egress {
+			isolating = append(isolating, policy)
+		}
+	}
+	return isolating
+}
+
+// podKey names a pod as "namespace/name"
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// policyNames joins the "namespace/name" of policies
+func policyNames(policies []*networkingv1.NetworkPolicy) string {
+	names := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		names = append(names, policy.Namespace+"/"+policy.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// checkEgress reports whether the policies let from send traffic to to
+func checkEgress(policies []networkingv1.NetworkPolicy, from, to *v1.Pod, known map[string]map[string]string) (bool, string) {
+	isolating := isolatingPolicies(policies, networkingv1.PolicyTypeEgress, from)
+	if len(isolating) == 0 {
+		return true, fmt.Sprintf("no policy isolates egress from %s", podKey(from))
+	}
+	for _, policy := range isolating {
+		for i, rule := range policy.Spec.Egress {
+			if !rulePeersMatch(rule.To, policy.Namespace, to, known) {
+				continue
+			}
+			return true, fmt.Sprintf("egress from %s allowed by policy %s/%s, egress rule %d (to %s on %s)",
+				podKey(from), policy.Namespace, policy.Name, i+1, peersString(rule.To), portsString(rule.Ports))
+		}
+	}
+	return false, fmt.Sprintf("egress from %s blocked by %s %s: no egress rule allows %s",
+		podKey(from), pluralPolicies(isolating), policyNames(isolating), podKey(to))
+}
+
+// checkIngress reports whether the policies let to receive traffic from from
+func checkIngress(policies []networkingv1.NetworkPolicy, from, to *v1.Pod, known map[string]map[string]string) (bool, string) {
+	isolating := isolatingPolicies(policies, networkingv1.PolicyTypeIngress, to)
+	if len(isolating) == 0 {
+		return true, fmt.Sprintf("no policy isolates ingress to %s", podKey(to))
+	}
+	for _, policy := range isolating {
+		for i, rule := range policy.Spec.Ingress {
+			if !rulePeersMatch(rule.From, policy.Namespace, from, known) {
+				continue
+			}
+			return true, fmt.Sprintf("ingress to %s allowed by policy %s/%s, ingress rule %d (from %s on %s)",
+				podKey(to), policy.Namespace, policy.Name, i+1, peersString(rule.From), portsString(rule.Ports))
+		}
+	}
+	return false, fmt.Sprintf("ingress to %s blocked by %s %s: no ingress rule allows %s",
+		podKey(to), pluralPolicies(isolating), policyNames(isolating), podKey(from))
+}
+
+// rulePeersMatch reports whether pod is among the peers of a rule; a rule
+// without peers matches every pod
+func rulePeersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, pod *v1.Pod, known map[string]map[string]string) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peerMatches(peer, policyNamespace, pod, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluralPolicies returns "policy" or "policies"
+func pluralPolicies(policies []*networkingv1.NetworkPolicy) string {
+	if len(policies) == 1 {
+		return "policy"
+	}
+	return "policies"
+}
+
+// IsTrafficAllowed simulates whether policies let from open a connection to
+// to: the egress of from and the ingress of to must each be either not
+// isolated by any policy selecting the pod, or allowed by a rule of one of
+// those policies. The string explains the verdict, naming the blocking
+// policies or the rules that allow the traffic.
+//
+// Namespace selectors are matched against the kubernetes.io/metadata.name
+// label only. Ports are not simulated: a rule limited to some ports allows
+// the traffic, and the explanation lists the ports.
+func IsTrafficAllowed(policies []networkingv1.NetworkPolicy, from, to *v1.Pod) (bool, string) {
+	return isTrafficAllowed(policies, from, to, nil)
+}
+
+// isTrafficAllowed is IsTrafficAllowed with the labels of the namespaces in
+// known, by namespace name
+func isTrafficAllowed(policies []networkingv1.NetworkPolicy, from, to *v1.Pod, known map[string]map[string]string) (bool, string) {
+	egressAllowed, egressReason := checkEgress(policies, from, to, known)
+	if !egressAllowed {
+		return false, egressReason
+	}
+	ingressAllowed, ingressReason := checkIngress(policies, from, to, known)
+	if !ingressAllowed {
+		return false, ingressReason
+	}
+	return true, egressReason + "; " + ingressReason
+}
+
+// listNetworkPolicies lists the network policies in namespace
+func listNetworkPolicies(ctx context.Context, client kubernetes.Interface, namespace string) ([]networkingv1.NetworkPolicy, error) {
+	list, err := client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "networkpolicies", namespace)
+	}
+	return list.Items, nil
+}
+
+// splitPodRef splits a --from-pod or --to-pod value, "namespace/name" or a
+// name in defaultNamespace
+func splitPodRef(ref, defaultNamespace string) (string, string, error) {
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = defaultNamespace, ref
+	}
+	if namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid pod %q (want namespace/name or name)", ref)
+	}
+	return namespace, name, nil
+}
+
+// checkConnectivity fetches the two pods, the policies of their namespaces
+// and the namespaces' labels, and simulates traffic between the pods.
+// Namespaces that cannot be read fall back to their name label, with a
+// warning.
+func checkConnectivity(ctx context.Context, client kubernetes.Interface, fromRef, toRef, defaultNamespace string, showRules bool) (ConnectivityInfo, []error, error) {
+	var pods [2]*v1.Pod
+	for i, ref := range []string{fromRef, toRef} {
+		namespace, name, err := splitPodRef(ref, defaultNamespace)
+		if err != nil {
+			return ConnectivityInfo{}, nil, err
+		}
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return ConnectivityInfo{}, nil, handleAPIError(err, "pods", namespace)
+		}
+		pods[i] = pod
+	}
+	from, to := pods[0], pods[1]
+
+	var policies []networkingv1.NetworkPolicy
+	var warnings []error
+	known := map[string]map[string]string{}
+	for _, namespace := range parseList(from.Namespace + "," + to.Namespace) {
+		items, err := listNetworkPolicies(ctx, client, namespace)
+		if err != nil {
+			return ConnectivityInfo{}, nil, err
+		}
+		policies = append(policies, items...)
+
+		ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("namespace selectors only match the name of namespace %s: %w", namespace, handleAPIError(err, "namespaces", "")))
+			continue
+		}
+		known[namespace] = namespaceLabels(nil, namespace)
+		for k, v := range ns.Labels {
+			known[namespace][k] = v
+		}
+	}
+
+	info := ConnectivityInfo{From: podKey(from), To: podKey(to)}
+	info.Allowed, info.Reason = isTrafficAllowed(policies, from, to, known)
+	if showRules {
+		isolating := isolatingPolicies(policies, networkingv1.PolicyTypeEgress, from)
+		for _, policy := range isolatingPolicies(policies, networkingv1.PolicyTypeIngress, to) {
+			if !containsPolicy(isolating, policy) {
+				isolating = append(isolating, policy)
+			}
+		}
+		for _, policy := range isolating {
+			info.Policies = append(info.Policies, extractNetworkPolicyInfo(policy))
+		}
+	}
+	return info, warnings, nil
+}
+
+// containsPolicy reports whether policies holds policy
+func containsPolicy(policies []*networkingv1.NetworkPolicy, policy *networkingv1.NetworkPolicy) bool {
+	for _, p := range policies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// printNetworkPolicyTable prints one row per network policy. The NAMESPACE
+// column is shown when listing across namespaces.
+func printNetworkPolicyTable(out io.Writer, infos []NetworkPolicyInfo, showNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tPOD-SELECTOR\tPOLICY-TYPES\tINGRESS-RULES\tEGRESS-RULES")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n",
+			info.Name, info.PodSelector, strings.Join(info.PolicyTypes, ","), len(info.IngressRules), len(info.EgressRules))
+	}
+	return w.Flush()
+}
+
+// printNetworkPolicyInfo prints formatted network policy information, with
+// each rule when showRules is set
+func printNetworkPolicyInfo(out io.Writer, info NetworkPolicyInfo, showRules bool) {
+	fmt.Fprintf(out, "NetworkPolicy: %s\n", info.Name)
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
+	fmt.Fprintf(out, "  Pod selector: %s\n", info.PodSelector)
+	fmt.Fprintf(out, "  Policy types: %s\n", strings.Join(info.PolicyTypes, ", "))
+	if !showRules {
+		fmt.Fprintf(out, "  Rules: %d ingress, %d egress\n", len(info.IngressRules), len(info.EgressRules))
+		fmt.Fprintln(out)
+		return
+	}
+	for i, rule := range info.IngressRules {
+		fmt.Fprintf(out, "  Ingress rule %d: %s\n", i+1, rule)
+	}
+	for i, rule := range info.EgressRules {
+		fmt.Fprintf(out, "  Egress rule %d: %s\n", i+1, rule)
+	}
+	fmt.Fprintln(out)
+}
+
+// printConnectivityInfo prints the verdict of a connectivity check, followed
+// by the policies involved with --show-rules
+func printConnectivityInfo(out io.Writer, info ConnectivityInfo) {
+	verdict := "BLOCKED"
+	if info.Allowed {
+		verdict = "ALLOWED"
+	}
+	fmt.Fprintf(out, "%s -> %s: %s\n", info.From, info.To, verdict)
+	fmt.Fprintf(out, "  %s\n", info.Reason)
+	fmt.Fprintln(out)
+	for _, policy := range info.Policies {
+		printNetworkPolicyInfo(out, policy, true)
+	}
+}
+
+// runNetpol implements the netpol subcommand
+func runNetpol(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("netpol", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list network policies from (empty for all namespaces), and of --from-pod and --to-pod names without one (default \"default\")")
+	fromPod := fs.String("from-pod", "", "pod sending the traffic to check, as namespace/name or name (requires --to-pod)")
+	toPod := fs.String("to-pod", "", "pod receiving the traffic to check, as namespace/name or name (requires --from-pod)")
+	showRules := fs.Bool("show-rules", false, "print the rules of each policy, or of the policies isolating the two pods (as text blocks)")
+	output := fs.String("output", outputTable, "output format: table, text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputText, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, text, json or yaml)", *output)
+		}
+		if (*fromPod == "") != (*toPod == "") {
+			return errors.New("--from-pod and --to-pod must be given together")
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		if *fromPod != "" {
+			defaultNamespace := *namespace
+			if defaultNamespace == "" {
+				defaultNamespace = v1.NamespaceDefault
+			}
+			info, warnings, err := checkConnectivity(ctx, client, *fromPod, *toPod, defaultNamespace, *showRules)
+			if err != nil {
+				return fmt.Errorf("error checking connectivity: %w", timeoutError(err, clientOpts.Timeout))
+			}
+			logWarnings(ctx, warnings)
+			switch *output {
+			case outputJSON, outputYAML:
+				return printStructured(out, info, *output)
+			}
+			printConnectivityInfo(out, info)
+			return nil
+		}
+
+		policies, err := listNetworkPolicies(ctx, client, *namespace)
+		if err != nil {
+			return fmt.Errorf("error listing network policies: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		infos := []NetworkPolicyInfo{}
+		for i := range policies {
+			infos = append(infos, extractNetworkPolicyInfo(&policies[i]))
+		}
+		switch *output {
+		case outputJSON, outputYAML:
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No network policies found")
+			return nil
+		}
+		if *output == outputText || *showRules {
+			for _, info := range infos {
+				printNetworkPolicyInfo(out, info, *showRules)
+			}
+			return nil
+		}
+		return printNetworkPolicyTable(out, infos, *namespace == "")
+	})
+}
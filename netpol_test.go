@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newNetpolPod returns a pod labelled app=app
+func newNetpolPod(namespace, name, app string) *v1.Pod {
+	pod := newTestPod(namespace, name)
+	pod.Labels = map[string]string{"app": app}
+	return pod
+}
+
+// newTestNetworkPolicy returns a policy in namespace selecting app=app
+func newTestNetworkPolicy(namespace, name, app string, types []networkingv1.PolicyType, ingress []networkingv1.NetworkPolicyIngressRule, egress []networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicy {
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+			PolicyTypes: types,
+			Ingress:     ingress,
+			Egress:      egress,
+		},
+	}
+}
+
+// appPeer selects the pods labelled app=app, in the namespace named by
+// namespace when it is set
+func appPeer(app, namespace string) networkingv1.NetworkPolicyPeer {
+	peer := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}}
+	if namespace != "" {
+		peer.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{v1.LabelMetadataName: namespace}}
+	}
+	return peer
+}
+
+var (
+	ingressOnly = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	egressOnly  = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+	bothTypes   = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+)
+
+func TestIsTrafficAllowed(t *testing.T) {
+	web := newNetpolPod("shop", "web-1", "web")
+	db := newNetpolPod("shop", "db-1", "db")
+	probe := newNetpolPod("monitoring", "probe-1", "probe")
+	port := intstr.FromInt32(5432)
+
+	allowWebToDB := []networkingv1.NetworkPolicyIngressRule{{
+		From:  []networkingv1.NetworkPolicyPeer{appPeer("web", "")},
+		Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+	}}
+	allowDBFromWeb := []networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{appPeer("db", "")}}}
+
+	tests := []struct {
+		name        string
+		policies    []networkingv1.NetworkPolicy
+		from, to    *v1.Pod
+		wantAllowed bool
+		wantReason  []string
+	}{
+		{
+			name:        "no policies",
+			from:        web,
+			to:          db,
+			wantAllowed: true,
+			wantReason:  []string{"no policy isolates egress from shop/web-1", "no policy isolates ingress to shop/db-1"},
+		},
+		{
+			name:        "ingress only, allowed",
+			policies:    []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "db", "db", ingressOnly, allowWebToDB, nil)},
+			from:        web,
+			to:          db,
+			wantAllowed: true,
+			wantReason:  []string{"ingress to shop/db-1 allowed by policy shop/db, ingress rule 1 (from app=web on 5432/TCP)"},
+		},
+		{
+			name:       "ingress only, other pod blocked",
+			policies:   []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "db", "db", ingressOnly, allowWebToDB, nil)},
+			from:       probe,
+			to:         db,
+			wantReason: []string{"ingress to shop/db-1 blocked by policy shop/db: no ingress rule allows monitoring/probe-1"},
+		},
+		{
+			name:       "ingress default deny",
+			policies:   []networkingv1.NetworkPolicy{{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "deny-all"}}},
+			from:       web,
+			to:         db,
+			wantReason: []string{"ingress to shop/db-1 blocked by policy shop/deny-all: no ingress rule allows shop/web-1"},
+		},
+		{
+			name: "ingress from another namespace by namespace selector",
+			policies: []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "db", "db", ingressOnly,
+				[]networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{appPeer("probe", "monitoring")}}}, nil)},
+			from:        probe,
+			to:          db,
+			wantAllowed: true,
+			wantReason:  []string{"allowed by policy shop/db, ingress rule 1 (from app=probe in kubernetes.io/metadata.name=monitoring on all ports)"},
+		},
+		{
+			name:        "egress only, allowed",
+			policies:    []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "web", "web", egressOnly, nil, allowDBFromWeb)},
+			from:        web,
+			to:          db,
+			wantAllowed: true,
+			wantReason:  []string{"egress from shop/web-1 allowed by policy shop/web, egress rule 1 (to app=db on all ports)", "no policy isolates ingress to shop/db-1"},
+		},
+		{
+			name:       "egress only, blocked",
+			policies:   []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "web", "web", egressOnly, nil, allowDBFromWeb)},
+			from:       web,
+			to:         probe,
+			wantReason: []string{"egress from shop/web-1 blocked by policy shop/web: no egress rule allows monitoring/probe-1"},
+		},
+		{
+			name:        "egress only, does not isolate ingress",
+			policies:    []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "web", "web", egressOnly, nil, nil)},
+			from:        probe,
+			to:          web,
+			wantAllowed: true,
+			wantReason:  []string{"no policy isolates ingress to shop/web-1"},
+		},
+		{
+			name: "combined, both directions allowed",
+			policies: []networkingv1.NetworkPolicy{
+				newTestNetworkPolicy("shop", "web", "web", egressOnly, nil, allowDBFromWeb),
+				newTestNetworkPolicy("shop", "db", "db", ingressOnly, allowWebToDB, nil),
+			},
+			from:        web,
+			to:          db,
+			wantAllowed: true,
+			wantReason:  []string{"egress from shop/web-1 allowed by policy shop/web", "; ingress to shop/db-1 allowed by policy shop/db"},
+		},
+		{
+			name: "combined, egress allowed but ingress blocked",
+			policies: []networkingv1.NetworkPolicy{
+				newTestNetworkPolicy("shop", "web", "web", egressOnly, nil, allowDBFromWeb),
+				newTestNetworkPolicy("shop", "db", "db", bothTypes, nil, nil),
+			},
+			from:       web,
+			to:         db,
+			wantReason: []string{"ingress to shop/db-1 blocked by policy shop/db"},
+		},
+		{
+			name: "combined, egress blocked by several policies",
+			policies: []networkingv1.NetworkPolicy{
+				newTestNetworkPolicy("shop", "web-dns", "web", egressOnly, nil, []networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{appPeer("dns", "kube-system")}}}),
+				newTestNetworkPolicy("shop", "web", "web", bothTypes, nil, nil),
+				newTestNetworkPolicy("shop", "db", "db", ingressOnly, allowWebToDB, nil),
+			},
+			from:       web,
+			to:         db,
+			wantReason: []string{"egress from shop/web-1 blocked by policies shop/web-dns, shop/web: no egress rule allows shop/db-1"},
+		},
+		{
+			name: "policies of other namespaces do not apply",
+			policies: []networkingv1.NetworkPolicy{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "deny-all"}},
+			},
+			from:        web,
+			to:          db,
+			wantAllowed: true,
+		},
+		{
+			name: "ip block",
+			policies: []networkingv1.NetworkPolicy{newTestNetworkPolicy("shop", "db", "db", ingressOnly,
+				[]networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}}}}}, nil)},
+			from: func() *v1.Pod {
+				pod := web.DeepCopy()
+				pod.Status.PodIP = "10.1.2.3"
+				return pod
+			}(),
+			to:         db,
+			wantReason: []string{"blocked by policy shop/db"},
+		},
+	}
+	for _, tt := range tests {
+		allowed, reason := IsTrafficAllowed(tt.policies, tt.from, tt.to)
+		if allowed != tt.wantAllowed {
+			t.Errorf("%s: allowed = %t, want %t (%s)", tt.name, allowed, tt.wantAllowed, reason)
+		}
+		for _, want := range tt.wantReason {
+			if !strings.Contains(reason, want) {
+				t.Errorf("%s: reason %q lacks %q", tt.name, reason, want)
+			}
+		}
+	}
+}
+
+func TestExtractNetworkPolicyInfo(t *testing.T) {
+	udp := v1.ProtocolUDP
+	port, endPort := intstr.FromInt32(5000), int32(5010)
+	web := intstr.FromString("http")
+	policy := newTestNetworkPolicy("shop", "web", "web", nil,
+		[]networkingv1.NetworkPolicyIngressRule{{}, {
+			From: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ops"}}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.0.0/16", Except: []string{"192.168.1.0/24"}}},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{{Port: &web}, {Protocol: &udp, Port: &port, EndPort: &endPort}},
+		}},
+		[]networkingv1.NetworkPolicyEgressRule{{To: []networkingv1.NetworkPolicyPeer{appPeer("db", "shop")}}})
+
+	got := extractNetworkPolicyInfo(&policy)
+	want := NetworkPolicyInfo{
+		Name:        "web",
+		Namespace:   "shop",
+		PodSelector: "app=web",
+		// Without policyTypes, egress rules make it an egress policy too
+		PolicyTypes: []string{"Ingress", "Egress"},
+		IngressRules: []string{
+			"from anywhere on all ports",
+			"from all pods in team=ops or cidr 192.168.0.0/16 except 192.168.1.0/24 on http/TCP, 5000-5010/UDP",
+		},
+		EgressRules: []string{"to app=db in kubernetes.io/metadata.name=shop on all ports"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractNetworkPolicyInfo() = %+v, want %+v", got, want)
+	}
+
+	denyAll := networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "deny-all"}}
+	if got := extractNetworkPolicyInfo(&denyAll); got.PodSelector != "all pods" || !reflect.DeepEqual(got.PolicyTypes, []string{"Ingress"}) {
+		t.Errorf("deny-all policy = %+v, want all pods and Ingress", got)
+	}
+}
+
+func TestSplitPodRef(t *testing.T) {
+	tests := []struct {
+		ref           string
+		wantNamespace string
+		wantName      string
+		wantErr       bool
+	}{
+		{ref: "web-1", wantNamespace: "default", wantName: "web-1"},
+		{ref: "shop/web-1", wantNamespace: "shop", wantName: "web-1"},
+		{ref: "shop/", wantErr: true},
+		{ref: "/web-1", wantErr: true},
+		{ref: "a/b/c", wantErr: true},
+	}
+	for _, tt := range tests {
+		namespace, name, err := splitPodRef(tt.ref, "default")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitPodRef(%q) error = %v, wantErr %t", tt.ref, err, tt.wantErr)
+		}
+		if namespace != tt.wantNamespace || name != tt.wantName {
+			t.Errorf("splitPodRef(%q) = %q, %q, want %q, %q", tt.ref, namespace, name, tt.wantNamespace, tt.wantName)
+		}
+	}
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	web := newNetpolPod("shop", "web-1", "web")
+	probe := newNetpolPod("monitoring", "probe-1", "probe")
+	shop := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}
+	monitoring := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"team": "ops"}}}
+	policy := newTestNetworkPolicy("shop", "web", "web", ingressOnly,
+		[]networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ops"}}},
+		}}}, nil)
+	unrelated := newTestNetworkPolicy("shop", "db", "db", ingressOnly, nil, nil)
+
+	client := fake.NewSimpleClientset(web, probe, shop, monitoring, &policy, &unrelated)
+	info, warnings, err := checkConnectivity(context.Background(), client, "monitoring/probe-1", "web-1", "shop", true)
+	if err != nil || len(warnings) > 0 {
+		t.Fatalf("checkConnectivity() error = %v, warnings = %v", err, warnings)
+	}
+	// The namespace selector matches the labels of the namespace object
+	if !info.Allowed || info.From != "monitoring/probe-1" || info.To != "shop/web-1" {
+		t.Errorf("checkConnectivity() = %+v, want probe-1 allowed to reach web-1", info)
+	}
+	if len(info.Policies) != 1 || info.Policies[0].Name != "web" {
+		t.Errorf("Policies = %+v, want only the web policy", info.Policies)
+	}
+
+	var out bytes.Buffer
+	printConnectivityInfo(&out, info)
+	for _, want := range []string{"monitoring/probe-1 -> shop/web-1: ALLOWED\n", "NetworkPolicy: web\n", "  Ingress rule 1: from all pods in team=ops on all ports\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	// Without access to namespaces, only the name label can be matched
+	client.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("namespaces"), "", nil)
+	})
+	info, warnings, err = checkConnectivity(context.Background(), client, "monitoring/probe-1", "shop/web-1", "default", false)
+	if err != nil {
+		t.Fatalf("checkConnectivity() error = %v", err)
+	}
+	if info.Allowed || len(warnings) != 2 || info.Policies != nil {
+		t.Errorf("checkConnectivity() without namespaces = %+v, %v, want blocked with 2 warnings", info, warnings)
+	}
+
+	if _, _, err := checkConnectivity(context.Background(), client, "missing", "shop/web-1", "shop", false); err == nil {
+		t.Error("checkConnectivity() with a missing pod succeeded")
+	}
+}
+
+func TestPrintNetworkPolicyTable(t *testing.T) {
+	infos := []NetworkPolicyInfo{
+		{Name: "deny-all", Namespace: "shop", PodSelector: "all pods", PolicyTypes: []string{"Ingress", "Egress"}},
+		{Name: "web", Namespace: "shop", PodSelector: "app=web", PolicyTypes: []string{"Ingress"}, IngressRules: []string{"from anywhere on 80/TCP"}},
+	}
+	var out bytes.Buffer
+	if err := printNetworkPolicyTable(&out, infos, true); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || strings.Join(strings.Fields(lines[0]), " ") != "NAMESPACE NAME POD-SELECTOR POLICY-TYPES INGRESS-RULES EGRESS-RULES" ||
+		strings.Join(strings.Fields(lines[1]), " ") != "shop deny-all all pods Ingress,Egress 0 0" ||
+		strings.Join(strings.Fields(lines[2]), " ") != "shop web app=web Ingress 1 0" {
+		t.Errorf("table =\n%s", out.String())
+	}
+}
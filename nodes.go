@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Node labels roles are inferred from
+const (
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	nodeRoleLabel       = "kubernetes.io/role"
+)
+
+// NodeInfo holds formatted node information
+type NodeInfo struct {
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	Roles            []string          `json:"roles"`
+	InternalIP       string            `json:"internalIP,omitempty"`
+	ExternalIP       string            `json:"externalIP,omitempty"`
+	KernelVersion    string            `json:"kernelVersion"`
+	OSImage          string            `json:"osImage"`
+	ContainerRuntime string            `json:"containerRuntime"`
+	CPUCapacity      resource.Quantity `json:"cpuCapacity"`
+	MemoryCapacity   resource.Quantity `json:"memoryCapacity"`
+	Age              time.Duration     `json:"-"`
+	CreatedAt        time.Time         `json:"createdAt"`
+}
+
+// extractNodeInfo extracts relevant information from a node
+func extractNodeInfo(node *v1.Node, now time.Time) NodeInfo {
+	info := NodeInfo{
+		Name:             node.Name,
+		Status:           nodeStatus(node),
+		Roles:            nodeRoles(node.Labels),
+		KernelVersion:    node.Status.NodeInfo.KernelVersion,
+		OSImage:          node.Status.NodeInfo.OSImage,
+		ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+		CPUCapacity:      node.Status.Capacity[v1.ResourceCPU],
+		MemoryCapacity:   node.Status.Capacity[v1.ResourceMemory],
+		Age:              now.Sub(node.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt:        node.CreationTimestamp.Time,
+	}
+	for _, addr := range node.Status.Addresses {
+		switch {
+		case addr.Type == v1.NodeInternalIP && info.InternalIP == "":
+			info.InternalIP = addr.Address
+		case addr.Type == v1.NodeExternalIP && info.ExternalIP == "":
+			info.ExternalIP = addr.Address
+		}
+	}
+	return info
+}
+
+// nodeStatus returns Ready or NotReady from the node's Ready condition,
+// with SchedulingDisabled appended for cordoned nodes as kubectl does
+func nodeStatus(node *v1.Node) string {
+	status := "NotReady"
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady && cond.Status == v1.ConditionTrue {
+			status = "Ready"
+		}
+	}
+	if node.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+	return status
+}
+
+// nodeRoles returns the sorted roles found in node labels, from
+// node-role.kubernetes.io/<role> keys and the legacy kubernetes.io/role
+// label
+func nodeRoles(labels map[string]string) []string {
+	seen := make(map[string]bool)
+	roles := []string{}
+	add := func(role string) {
+		if role != "" && !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	for key, value := range labels {
+		switch {
+		case strings.HasPrefix(key, nodeRoleLabelPrefix):
+			add(strings.TrimPrefix(key, nodeRoleLabelPrefix))
+		case key == nodeRoleLabel:
+			add(value)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// printNodeTable prints one row per node
+func printNodeTable(out io.Writer, infos []NodeInfo, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tROLES\tINTERNAL-IP\tEXTERNAL-IP\tOS-IMAGE\tKERNEL-VERSION\tCONTAINER-RUNTIME\tCPU\tMEMORY\tAGE")
+	for _, info := range infos {
+		cpu, memory := info.CPUCapacity, info.MemoryCapacity
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name, info.Status, orNone(strings.Join(info.Roles, ",")), orNone(info.InternalIP), orNone(info.ExternalIP),
+			info.OSImage, info.KernelVersion, info.ContainerRuntime, formatCPU(&cpu), formatMemory(&memory), formatAge(ageFormat, info.Age, info.CreatedAt))
+	}
+	return w.Flush()
+}
+
+// runNodes implements the nodes subcommand
+func runNodes(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("nodes", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	nodeSelector := fs.String("node-selector", "", "label selector to filter nodes, e.g. node-role.kubernetes.io/worker")
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table output shows node age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	if err := validateAgeFormat(*ageFormat); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: *nodeSelector})
+		if err != nil {
+			return fmt.Errorf("error listing nodes: %w", timeoutError(handleAPIError(err, "nodes", ""), clientOpts.Timeout))
+		}
+
+		now := time.Now()
+		infos := []NodeInfo{}
+		for i := range nodes.Items {
+			infos = append(infos, extractNodeInfo(&nodes.Items[i], now))
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No nodes found")
+			return nil
+		}
+		return printNodeTable(out, infos, *ageFormat)
+	})
+}
+
+// unscheduledGroup names the group of pods not yet bound to a node
+const unscheduledGroup = "<unscheduled>"
+
+// nodeGroup holds the pods running on one node
+type nodeGroup struct {
+	Cluster  string            `json:"cluster,omitempty"`
+	Node     string            `json:"node"`
+	Pods     int               `json:"pods"`
+	Restarts int32             `json:"restarts"`
+	Items    []podinfo.PodInfo `json:"items"`
+}
+
+// groupByNode groups pods by node, sorting nodes by pod count (most first)
+// and pods within a node by namespace/name. Unscheduled pods come last.
+func groupByNode(infos []podinfo.PodInfo) []nodeGroup {
+	index := make(map[[2]string]int)
+	groups := []nodeGroup{}
+	for _, info := range infos {
+		node := info.NodeName
+		if node == "" {
+			node = unscheduledGroup
+		}
+		key := [2]string{info.Cluster, node}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nodeGroup{Cluster: info.Cluster, Node: node})
+		}
+		groups[i].Pods++
+		groups[i].Restarts += info.Restarts
+		groups[i].Items = append(groups[i].Items, info)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.Node == unscheduledGroup) != (b.Node == unscheduledGroup) {
+			return b.Node == unscheduledGroup
+		}
+		if a.Pods != b.Pods {
+			return a.Pods > b.Pods
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Node < b.Node
+	})
+	for _, g := range groups {
+		sort.Slice(g.Items, func(i, j int) bool {
+			if g.Items[i].Namespace != g.Items[j].Namespace {
+				return g.Items[i].Namespace < g.Items[j].Namespace
+			}
+			return g.Items[i].Name < g.Items[j].Name
+		})
+	}
+	return groups
+}
+
+// printNodeGroups prints a header per node followed by its pods, as a table
+// or as text blocks
+func printNodeGroups(out io.Writer, groups []nodeGroup, format string, opts printOptions) error {
+	for _, g := range groups {
+		header := g.Node
+		if g.Cluster != "" {
+			header = g.Cluster + "/" + g.Node
+		}
+		fmt.Fprintf(out, "Node: %s (%d pods, %d restarts)\n", header, g.Pods, g.Restarts)
+		if format == outputTable {
+			if err := printPodTable(out, g.Items, opts); err != nil {
+				return err
+			}
+			fmt.Fprintln(out)
+			continue
+		}
+		fmt.Fprintln(out)
+		for _, info := range g.Items {
+			printPodInfo(out, info, opts)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeRoles(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}{
+		{name: "no labels", labels: nil, want: []string{}},
+		{name: "no role labels", labels: map[string]string{"kubernetes.io/hostname": "node-1"}, want: []string{}},
+		{name: "control plane", labels: map[string]string{"node-role.kubernetes.io/control-plane": ""}, want: []string{"control-plane"}},
+		{
+			name: "multiple roles are sorted",
+			labels: map[string]string{
+				"node-role.kubernetes.io/worker":        "",
+				"node-role.kubernetes.io/control-plane": "",
+				"node-role.kubernetes.io/infra":         "true",
+			},
+			want: []string{"control-plane", "infra", "worker"},
+		},
+		{name: "legacy role label", labels: map[string]string{"kubernetes.io/role": "master"}, want: []string{"master"}},
+		{
+			name: "legacy and new labels are deduplicated",
+			labels: map[string]string{
+				"kubernetes.io/role":             "worker",
+				"node-role.kubernetes.io/worker": "",
+			},
+			want: []string{"worker"},
+		},
+		{name: "empty role name is ignored", labels: map[string]string{"node-role.kubernetes.io/": ""}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeRoles(tt.labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodeRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractNodeInfo(t *testing.T) {
+	now := time.Now()
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "node-1",
+			Labels:            map[string]string{"node-role.kubernetes.io/worker": ""},
+			CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+		},
+		Spec: v1.NodeSpec{Unschedulable: true},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeHostName, Address: "node-1"},
+				{Type: v1.NodeInternalIP, Address: "10.0.0.5"},
+				{Type: v1.NodeExternalIP, Address: "203.0.113.7"},
+			},
+			Capacity: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			NodeInfo: v1.NodeSystemInfo{KernelVersion: "6.1.0", OSImage: "Ubuntu 22.04", ContainerRuntimeVersion: "containerd://1.7.0"},
+		},
+	}
+
+	info := extractNodeInfo(node, now)
+	if info.Status != "Ready,SchedulingDisabled" {
+		t.Errorf("status = %q", info.Status)
+	}
+	if info.InternalIP != "10.0.0.5" || info.ExternalIP != "203.0.113.7" {
+		t.Errorf("IPs = %q/%q", info.InternalIP, info.ExternalIP)
+	}
+	if formatCPU(&info.CPUCapacity) != "4" || formatMemory(&info.MemoryCapacity) != "16Gi" {
+		t.Errorf("capacity = %s/%s", info.CPUCapacity.String(), info.MemoryCapacity.String())
+	}
+	if info.Age != 48*time.Hour || !reflect.DeepEqual(info.Roles, []string{"worker"}) {
+		t.Errorf("age = %s, roles = %v", info.Age, info.Roles)
+	}
+
+	node.Status.Conditions[0].Status = v1.ConditionUnknown
+	node.Spec.Unschedulable = false
+	if status := extractNodeInfo(node, now).Status; status != "NotReady" {
+		t.Errorf("status = %q, want NotReady", status)
+	}
+}
+
+func TestGroupByNode(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "web", Name: "b", NodeName: "small", Restarts: 1},
+		{Namespace: "web", Name: "z", NodeName: "big"},
+		{Namespace: "batch", Name: "pending"},
+		{Namespace: "web", Name: "a", NodeName: "big", Restarts: 2},
+		{Namespace: "db", Name: "y", NodeName: "big", Restarts: 1},
+	}
+
+	groups := groupByNode(infos)
+	var got []string
+	for _, g := range groups {
+		got = append(got, g.Node)
+	}
+	if want := []string{"big", "small", "<unscheduled>"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("node order = %v, want %v", got, want)
+	}
+	big := groups[0]
+	if big.Pods != 3 || big.Restarts != 3 {
+		t.Errorf("big has %d pods and %d restarts, want 3 and 3", big.Pods, big.Restarts)
+	}
+	var names []string
+	for _, info := range big.Items {
+		names = append(names, info.Namespace+"/"+info.Name)
+	}
+	if want := []string{"db/y", "web/a", "web/z"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("pods on big = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"Kubernetes_Programming/pkg/output"
+)
+
+// outputStdout is the --output-file value that explicitly means stdout
+const outputStdout = output.Stdout
+
+// addOutputFileFlag registers --output-file on fs
+func addOutputFileFlag(fs *flag.FlagSet) *string {
+	return fs.String("output-file", "", "write the output to this file, replaced atomically once the run succeeds (- for stdout)")
+}
+
+// validateOutputFile checks the --output-file and --append combination
+func validateOutputFile(path string, appendMode bool, format string) error {
+	if !appendMode {
+		return nil
+	}
+	if output.IsStdout(path) {
+		return fmt.Errorf("--append requires --output-file with a file path")
+	}
+	if format != outputText {
+		return fmt.Errorf("--append only supports text output, not %s", format)
+	}
+	return nil
+}
+
+// writeOutput runs render against an output.Writer for path, so a failed
+// or killed run leaves any previous file as it was. With appendMode the
+// new output follows the previous contents under a timestamp header. An
+// empty path or "-" renders to stdout.
+func writeOutput(path string, appendMode bool, now time.Time, render func(out io.Writer) error) error {
+	return output.Write(path, output.Options{Append: appendMode, Now: now}, render)
+}
+
+// replaceOutput is writeOutput without --append, for the subcommands
+func replaceOutput(path string, render func(out io.Writer) error) error {
+	return writeOutput(path, false, time.Time{}, render)
+}
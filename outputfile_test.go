@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestValidateOutputFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		append  bool
+		output  string
+		wantErr bool
+	}{
+		{name: "stdout", output: outputJSON},
+		{name: "file", path: "pods.json", output: outputJSON},
+		{name: "append text", path: "pods.log", append: true, output: outputText},
+		{name: "append without a file", append: true, output: outputText, wantErr: true},
+		{name: "append to explicit stdout", path: outputStdout, append: true, output: outputText, wantErr: true},
+		{name: "append json", path: "pods.json", append: true, output: outputJSON, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutputFile(tt.path, tt.append, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateOutputFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
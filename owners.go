@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// noOwner is shown for pods without an owner
+const noOwner = "<none>"
+
+// maxOwnerDepth bounds how many owner references ResolveTopLevelOwner
+// follows, so a cycle of owner references cannot loop forever
+const maxOwnerDepth = 10
+
+// ownerResolver maps pods to the workload that owns them. The owner chain
+// of each distinct controller (e.g. a ReplicaSet shared by many pods) is
+// walked once and reused.
+type ownerResolver struct {
+	client kubernetes.Interface
+	// owners maps namespace/kind/name of a pod's controller to the
+	// formatted top-level owner
+	owners map[string]string
+	warned map[string]bool
+}
+
+// newOwnerResolver returns an ownerResolver using client for lookups
+func newOwnerResolver(client kubernetes.Interface) *ownerResolver {
+	return &ownerResolver{
+		client: client,
+		owners: make(map[string]string),
+		warned: make(map[string]bool),
+	}
+}
+
+// formatOwner renders an owner reference as kind/name, e.g. "job/backup"
+func formatOwner(ref *metav1.OwnerReference) string {
+	return strings.ToLower(ref.Kind) + "/" + ref.Name
+}
+
+// controllerRef returns the managing controller among refs, or the first
+// owner when none is marked as controller
+func controllerRef(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+// controllerOf returns the managing controller of obj, or its first owner
+// when none is marked as controller
+func controllerOf(obj metav1.Object) *metav1.OwnerReference {
+	return controllerRef(obj.GetOwnerReferences())
+}
+
+// builtinOwnerGroups are the API groups of the kinds getOwner can fetch
+var builtinOwnerGroups = map[string]bool{"": true, "apps": true, "batch": true}
+
+// getOwner fetches the object ref points to in namespace. It returns nil
+// and no error for kinds the typed client cannot get (custom resources)
+// and for owners that no longer exist.
+func getOwner(ctx context.Context, client kubernetes.Interface, namespace string, ref *metav1.OwnerReference) (metav1.Object, error) {
+	if ref.APIVersion != "" {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || !builtinOwnerGroups[gv.Group] {
+			return nil, nil
+		}
+	}
+	var obj metav1.Object
+	var err error
+	opts := metav1.GetOptions{}
+	switch ref.Kind {
+	case "ReplicaSet":
+		obj, err = client.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, opts)
+	case "Deployment":
+		obj, err = client.AppsV1().Deployments(namespace).Get(ctx, ref.Name, opts)
+	case "StatefulSet":
+		obj, err = client.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, opts)
+	case "DaemonSet":
+		obj, err = client.AppsV1().DaemonSets(namespace).Get(ctx, ref.Name, opts)
+	case "Job":
+		obj, err = client.BatchV1().Jobs(namespace).Get(ctx, ref.Name, opts)
+	case "CronJob":
+		obj, err = client.BatchV1().CronJobs(namespace).Get(ctx, ref.Name, opts)
+	case "ReplicationController":
+		obj, err = client.CoreV1().ReplicationControllers(namespace).Get(ctx, ref.Name, opts)
+	default:
+		return nil, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleAPIError(err, strings.ToLower(ref.Kind)+"s", namespace)
+	}
+	return obj, nil
+}
+
+// ResolveTopLevelOwner follows the controller among refs upward, one GET
+// per owner, and returns the kind and name of the last one: e.g. a pod's
+// ReplicaSet resolves to Deployment/nginx. The walk stops at owners that
+// have no owner themselves, that are custom resources or that no longer
+// exist. It returns empty strings when refs is empty, and an error after
+// maxOwnerDepth owners, which means the references form a cycle.
+func ResolveTopLevelOwner(ctx context.Context, client kubernetes.Interface, namespace string, refs []metav1.OwnerReference) (kind, name string, err error) {
+	ref := controllerRef(refs)
+	if ref == nil {
+		return "", "", nil
+	}
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		owner, err := getOwner(ctx, client, namespace, ref)
+		if err != nil {
+			return "", "", err
+		}
+		if owner == nil {
+			return ref.Kind, ref.Name, nil
+		}
+		next := controllerOf(owner)
+		if next == nil {
+			return ref.Kind, ref.Name, nil
+		}
+		ref = next
+	}
+	return "", "", fmt.Errorf("owner chain in namespace %s is deeper than %d, the owner references may form a cycle", namespace, maxOwnerDepth)
+}
+
+// resolve returns the top-level workload owning pod, e.g.
+// "deployment/frontend", or "<none>" for naked pods. When the chain cannot
+// be walked the pod's direct owner is shown.
+func (r *ownerResolver) resolve(ctx context.Context, pod *v1.Pod) string {
+	ref := controllerOf(pod)
+	if ref == nil {
+		return noOwner
+	}
+	key := pod.Namespace + "/" + ref.Kind + "/" + ref.Name
+	if owner, ok := r.owners[key]; ok {
+		return owner
+	}
+	owner := formatOwner(ref)
+	kind, name, err := ResolveTopLevelOwner(ctx, r.client, pod.Namespace, []metav1.OwnerReference{*ref})
+	if err != nil {
+		if !r.warned[pod.Namespace] {
+			r.warned[pod.Namespace] = true
+			logging.FromContext(ctx).Warn("failed to resolve owners, showing the direct owners", "namespace", pod.Namespace, "error", err)
+		}
+	} else {
+		owner = formatOwner(&metav1.OwnerReference{Kind: kind, Name: name})
+	}
+	r.owners[key] = owner
+	return owner
+}
+
+// ownerGroup aggregates the pods of one owner
+type ownerGroup struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Owner     string `json:"owner"`
+	Pods      int    `json:"pods"`
+	Restarts  int32  `json:"restarts"`
+}
+
+// groupByOwner aggregates pod counts and restarts per namespace/owner,
+// sorted by cluster, namespace and owner
+func groupByOwner(infos []podinfo.PodInfo) []ownerGroup {
+	index := make(map[[3]string]int)
+	groups := []ownerGroup{}
+	for _, info := range infos {
+		key := [3]string{info.Cluster, info.Namespace, info.Owner}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ownerGroup{Cluster: info.Cluster, Namespace: info.Namespace, Owner: info.Owner})
+		}
+		groups[i].Pods++
+		groups[i].Restarts += info.Restarts
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Owner < b.Owner
+	})
+	return groups
+}
+
+// printOwnerGroups prints one row per owner
+func printOwnerGroups(out io.Writer, groups []ownerGroup) error {
+	showCluster := len(groups) > 0 && groups[0].Cluster != ""
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showCluster {
+		fmt.Fprint(w, "CLUSTER\t")
+	}
+	fmt.Fprintln(w, "NAMESPACE\tOWNER\tPODS\tRESTARTS")
+	for _, g := range groups {
+		if showCluster {
+			fmt.Fprintf(w, "%s\t", g.Cluster)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", g.Namespace, g.Owner, g.Pods, g.Restarts)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// ownedBy returns object metadata with a controller owner reference
+func ownedBy(namespace, name, kind, owner string) metav1.ObjectMeta {
+	isController := true
+	return metav1.ObjectMeta{
+		Namespace:       namespace,
+		Name:            name,
+		OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &isController}},
+	}
+}
+
+func TestOwnerResolver(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("web", "frontend-5d8f", "Deployment", "frontend")},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "orphan-rs"}},
+	)
+	pods := []v1.Pod{
+		{ObjectMeta: ownedBy("web", "frontend-5d8f-a", "ReplicaSet", "frontend-5d8f")},
+		{ObjectMeta: ownedBy("web", "frontend-5d8f-b", "ReplicaSet", "frontend-5d8f")},
+		{ObjectMeta: ownedBy("web", "orphan-rs-a", "ReplicaSet", "orphan-rs")},
+		{ObjectMeta: ownedBy("web", "backup-cron-123", "Job", "backup-cron")},
+		{ObjectMeta: ownedBy("monitoring", "node-exporter-x", "DaemonSet", "node-exporter")},
+		{ObjectMeta: ownedBy("data", "db-0", "StatefulSet", "db")},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "debug"}},
+	}
+
+	resolver := newOwnerResolver(client)
+	var got []string
+	for i := range pods {
+		got = append(got, resolver.resolve(context.Background(), &pods[i]))
+	}
+	want := []string{
+		"deployment/frontend",
+		"deployment/frontend",
+		"replicaset/orphan-rs",
+		"job/backup-cron",
+		"daemonset/node-exporter",
+		"statefulset/db",
+		"<none>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("owners = %v, want %v", got, want)
+	}
+
+	// Each controller's chain is walked once, however many pods share it
+	gets := make(map[string]int)
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "get" {
+			t.Errorf("unexpected %s %s", action.GetVerb(), action.GetResource().Resource)
+			continue
+		}
+		gets[action.GetResource().Resource+"/"+action.(k8stesting.GetAction).GetName()]++
+	}
+	for object, n := range gets {
+		if n != 1 {
+			t.Errorf("fetched %s %d times, want once", object, n)
+		}
+	}
+	if gets["replicasets/frontend-5d8f"] != 1 || gets["deployments/frontend"] != 1 {
+		t.Errorf("GETs = %v, want the chain of frontend-5d8f followed up to its deployment", gets)
+	}
+}
+
+func TestResolveTopLevelOwner(t *testing.T) {
+	isController := true
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("web", "nginx-7c5d", "Deployment", "nginx")},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "nginx"}},
+		&batchv1.Job{ObjectMeta: ownedBy("web", "backup-28990", "CronJob", "backup")},
+		&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "backup"}},
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("web", "cycle-a", "ReplicaSet", "cycle-b")},
+		&appsv1.ReplicaSet{ObjectMeta: ownedBy("web", "cycle-b", "ReplicaSet", "cycle-a")},
+	)
+
+	tests := []struct {
+		name     string
+		refs     []metav1.OwnerReference
+		wantKind string
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "replicaset of a deployment",
+			refs:     ownedBy("web", "pod", "ReplicaSet", "nginx-7c5d").OwnerReferences,
+			wantKind: "Deployment", wantName: "nginx",
+		},
+		{
+			name:     "job of a cronjob",
+			refs:     ownedBy("web", "pod", "Job", "backup-28990").OwnerReferences,
+			wantKind: "CronJob", wantName: "backup",
+		},
+		{
+			name: "controller is preferred over other owners",
+			refs: []metav1.OwnerReference{
+				{Kind: "ConfigMap", Name: "unrelated"},
+				{Kind: "ReplicaSet", Name: "nginx-7c5d", Controller: &isController},
+			},
+			wantKind: "Deployment", wantName: "nginx",
+		},
+		{
+			name:     "deleted owner",
+			refs:     ownedBy("web", "pod", "ReplicaSet", "gone").OwnerReferences,
+			wantKind: "ReplicaSet", wantName: "gone",
+		},
+		{
+			name:     "custom resource",
+			refs:     []metav1.OwnerReference{{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Name: "canary", Controller: &isController}},
+			wantKind: "Rollout", wantName: "canary",
+		},
+		{
+			name:    "cycle",
+			refs:    ownedBy("web", "pod", "ReplicaSet", "cycle-a").OwnerReferences,
+			wantErr: true,
+		},
+		{name: "no owner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name, err := ResolveTopLevelOwner(context.Background(), client, "web", tt.refs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveTopLevelOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Errorf("ResolveTopLevelOwner() = %s/%s, want %s/%s", kind, name, tt.wantKind, tt.wantName)
+			}
+		})
+	}
+
+	var rolloutGets int
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "rollouts" {
+			rolloutGets++
+		}
+	}
+	if rolloutGets != 0 {
+		t.Errorf("fetched a custom resource %d times, want it returned as is", rolloutGets)
+	}
+}
+
+func TestGroupByOwner(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "web", Name: "a", Owner: "deployment/frontend", Restarts: 1},
+		{Namespace: "batch", Name: "b", Owner: "job/backup", Restarts: 0},
+		{Namespace: "web", Name: "c", Owner: "deployment/frontend", Restarts: 3},
+		{Namespace: "web", Name: "d", Owner: "<none>"},
+	}
+
+	want := []ownerGroup{
+		{Namespace: "batch", Owner: "job/backup", Pods: 1},
+		{Namespace: "web", Owner: "<none>", Pods: 1},
+		{Namespace: "web", Owner: "deployment/frontend", Pods: 2, Restarts: 4},
+	}
+	if got := groupByOwner(infos); !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByOwner() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// blockingMarker flags budgets that currently allow no voluntary disruptions
+const blockingMarker = "[BLOCKING]"
+
+// PDBInfo holds formatted pod disruption budget information
+type PDBInfo struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	Pods               int32  `json:"pods"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	Blocking           bool   `json:"blocking"`
+}
+
+// extractPDBInfo computes the budget of pdb over the pods its selector
+// matches. A pod is healthy when it is Ready and not being deleted. The
+// desired healthy count comes from minAvailable, or from the matching pods
+// less maxUnavailable, with percentages rounded up as the disruption
+// controller does. The disruptions allowed are currentHealthy minus
+// desiredHealthy, never below zero; a budget with matching pods and none
+// allowed is blocking evictions.
+func extractPDBInfo(pdb *policyv1.PodDisruptionBudget, pods []v1.Pod) PDBInfo {
+	info := PDBInfo{
+		Name:      pdb.Name,
+		Namespace: pdb.Namespace,
+		Pods:      int32(len(pods)),
+	}
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && podReady(&pods[i]) {
+			info.CurrentHealthy++
+		}
+	}
+
+	switch {
+	case pdb.Spec.MinAvailable != nil:
+		info.MinAvailable = pdb.Spec.MinAvailable.String()
+		info.DesiredHealthy = scaledBudget(pdb.Spec.MinAvailable, info.Pods)
+	case pdb.Spec.MaxUnavailable != nil:
+		info.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		info.DesiredHealthy = max(info.Pods-scaledBudget(pdb.Spec.MaxUnavailable, info.Pods), 0)
+	}
+
+	info.DisruptionsAllowed = max(info.CurrentHealthy-info.DesiredHealthy, 0)
+	info.Blocking = info.Pods > 0 && info.DisruptionsAllowed == 0
+	return info
+}
+
+// scaledBudget resolves an integer or percentage budget against total pods,
+// rounding percentages up. Malformed percentages count as zero.
+func scaledBudget(value *intstr.IntOrString, total int32) int32 {
+	n, err := intstr.GetScaledValueFromIntOrPercent(value, int(total), true)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
+
+// podReady reports whether the pod's Ready condition is true
+func podReady(pod *v1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// listPDBs lists the disruption budgets in namespace matching selector
+// and fetches the pods each one covers via its spec.selector. A budget
+// without a selector covers no pods.
+func listPDBs(ctx context.Context, client kubernetes.Interface, namespace, selector string) ([]PDBInfo, error) {
+	pdbs, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, handleAPIError(err, "poddisruptionbudgets", namespace)
+	}
+	infos := []PDBInfo{}
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		var pods []v1.Pod
+		if pdb.Spec.Selector != nil {
+			podSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("pod disruption budget %s/%s: invalid selector: %w", pdb.Namespace, pdb.Name, err)
+			}
+			list, err := client.CoreV1().Pods(pdb.Namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+			if err != nil {
+				return nil, handleAPIError(err, "pods", pdb.Namespace)
+			}
+			pods = list.Items
+		}
+		infos = append(infos, extractPDBInfo(pdb, pods))
+	}
+	return infos, nil
+}
+
+// orNotSet returns s, or "N/A" for a budget field that is not set
+func orNotSet(s string) string {
+	if s == "" {
+		return "N/A"
+	}
+	return s
+}
+
+// printPDBTable prints one row per disruption budget. The NAMESPACE column
+// is shown when listing across namespaces.
+func printPDBTable(out io.Writer, infos []PDBInfo, showNamespace bool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tMIN-AVAILABLE\tMAX-UNAVAILABLE\tCURRENT-HEALTHY\tDESIRED-HEALTHY\tDISRUPTIONS-ALLOWED\tBLOCKING")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		blocking := "no"
+		if info.Blocking {
+			blocking = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			info.Name, orNotSet(info.MinAvailable), orNotSet(info.MaxUnavailable),
+			info.CurrentHealthy, info.DesiredHealthy, info.DisruptionsAllowed, blocking)
+	}
+	return w.Flush()
+}
+
+// printPDBInfo prints formatted disruption budget information
+func printPDBInfo(out io.Writer, info PDBInfo) {
+	header := "PodDisruptionBudget: " + info.Name
+	if info.Blocking {
+		header += " " + blockingMarker
+	}
+	fmt.Fprintln(out, header)
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
+	fmt.Fprintf(out, "  Min available: %s\n", orNotSet(info.MinAvailable))
+	fmt.Fprintf(out, "  Max unavailable: %s\n", orNotSet(info.MaxUnavailable))
+	fmt.Fprintf(out, "  Matching pods: %d\n", info.Pods)
+	fmt.Fprintf(out, "  Healthy: %d (desired %d)\n", info.CurrentHealthy, info.DesiredHealthy)
+	fmt.Fprintf(out, "  Disruptions allowed: %d\n", info.DisruptionsAllowed)
+	fmt.Fprintln(out)
+}
+
+// runPDB implements the pdb subcommand
+func runPDB(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("pdb", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list pod disruption budgets from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter pod disruption budgets, e.g. app=web,tier!=cache")
+	output := fs.String("output", outputTable, "output format: table, text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputText, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, text, json or yaml)", *output)
+		}
+		if _, err := labels.Parse(*labelSelector); err != nil {
+			return fmt.Errorf("invalid --label-selector: %w", err)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err := listPDBs(ctx, client, *namespace, *labelSelector)
+		if err != nil {
+			return fmt.Errorf("error listing pod disruption budgets: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		switch *output {
+		case outputJSON, outputYAML:
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No pod disruption budgets found")
+			return nil
+		}
+		if *output == outputText {
+			for _, info := range infos {
+				printPDBInfo(out, info)
+			}
+			return nil
+		}
+		return printPDBTable(out, infos, *namespace == "")
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestPDB returns a budget in the default namespace selecting app=name
+func newTestPDB(name string, minAvailable, maxUnavailable *intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+	}
+}
+
+// newPDBPods returns total pods labelled app=app, the first ready of them Ready
+func newPDBPods(app string, total, ready int) []v1.Pod {
+	pods := []v1.Pod{}
+	for i := 0; i < total; i++ {
+		pod := newTestPod("default", app+"-"+string(rune('a'+i)))
+		pod.Labels = map[string]string{"app": app}
+		status := v1.ConditionFalse
+		if i < ready {
+			status = v1.ConditionTrue
+		}
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: status}}
+		pods = append(pods, *pod)
+	}
+	return pods
+}
+
+// intOrString returns a pointer to v
+func intOrString(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
+
+func TestExtractPDBInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		minAvailable   *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+		pods           []v1.Pod
+		wantHealthy    int32
+		wantDesired    int32
+		wantAllowed    int32
+		wantBlocking   bool
+	}{
+		{
+			name:         "min available met with headroom",
+			minAvailable: intOrString(intstr.FromInt32(2)),
+			pods:         newPDBPods("web", 4, 4),
+			wantHealthy:  4, wantDesired: 2, wantAllowed: 2,
+		},
+		{
+			name:         "min available exactly met",
+			minAvailable: intOrString(intstr.FromInt32(3)),
+			pods:         newPDBPods("web", 3, 3),
+			wantHealthy:  3, wantDesired: 3, wantAllowed: 0, wantBlocking: true,
+		},
+		{
+			name:         "min available not met",
+			minAvailable: intOrString(intstr.FromInt32(3)),
+			pods:         newPDBPods("web", 3, 1),
+			wantHealthy:  1, wantDesired: 3, wantAllowed: 0, wantBlocking: true,
+		},
+		{
+			name:         "min available percentage rounds up",
+			minAvailable: intOrString(intstr.FromString("50%")),
+			pods:         newPDBPods("web", 5, 5),
+			wantHealthy:  5, wantDesired: 3, wantAllowed: 2,
+		},
+		{
+			name:           "max unavailable",
+			maxUnavailable: intOrString(intstr.FromInt32(1)),
+			pods:           newPDBPods("web", 3, 3),
+			wantHealthy:    3, wantDesired: 2, wantAllowed: 1,
+		},
+		{
+			name:           "max unavailable already used up",
+			maxUnavailable: intOrString(intstr.FromString("25%")),
+			pods:           newPDBPods("web", 4, 3),
+			wantHealthy:    3, wantDesired: 3, wantAllowed: 0, wantBlocking: true,
+		},
+		{
+			name:         "no matching pods",
+			minAvailable: intOrString(intstr.FromInt32(1)),
+			wantDesired:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := extractPDBInfo(newTestPDB("web", tt.minAvailable, tt.maxUnavailable), tt.pods)
+			if info.CurrentHealthy != tt.wantHealthy || info.DesiredHealthy != tt.wantDesired || info.DisruptionsAllowed != tt.wantAllowed {
+				t.Errorf("healthy %d, desired %d, allowed %d; want %d, %d, %d",
+					info.CurrentHealthy, info.DesiredHealthy, info.DisruptionsAllowed, tt.wantHealthy, tt.wantDesired, tt.wantAllowed)
+			}
+			if info.Blocking != tt.wantBlocking {
+				t.Errorf("blocking = %t, want %t", info.Blocking, tt.wantBlocking)
+			}
+		})
+	}
+}
+
+func TestExtractPDBInfoTerminatingPods(t *testing.T) {
+	pods := newPDBPods("web", 3, 3)
+	deleted := metav1.Now()
+	pods[0].DeletionTimestamp = &deleted
+
+	info := extractPDBInfo(newTestPDB("web", intOrString(intstr.FromInt32(2)), nil), pods)
+	if info.CurrentHealthy != 2 || info.DisruptionsAllowed != 0 || !info.Blocking {
+		t.Errorf("healthy %d, allowed %d, blocking %t; want a terminating pod not to count as healthy",
+			info.CurrentHealthy, info.DisruptionsAllowed, info.Blocking)
+	}
+	if info.MinAvailable != "2" || info.MaxUnavailable != "" {
+		t.Errorf("minAvailable %q, maxUnavailable %q, want 2 and unset", info.MinAvailable, info.MaxUnavailable)
+	}
+}
+
+func TestListPDBs(t *testing.T) {
+	objects := []runtime.Object{
+		newTestPDB("web", intOrString(intstr.FromInt32(2)), nil),
+		newTestPDB("db", nil, intOrString(intstr.FromInt32(1))),
+	}
+	for _, pod := range append(newPDBPods("web", 3, 3), newPDBPods("db", 2, 1)...) {
+		objects = append(objects, pod.DeepCopy())
+	}
+	// A pod in another namespace with matching labels is not covered
+	other := newPDBPods("web", 1, 1)[0]
+	other.Namespace = "staging"
+	objects = append(objects, &other)
+
+	infos, err := listPDBs(context.Background(), fake.NewSimpleClientset(objects...), "default", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := map[string]PDBInfo{}
+	for _, info := range infos {
+		got[info.Name] = info
+	}
+	if web := got["web"]; web.Pods != 3 || web.DisruptionsAllowed != 1 || web.Blocking {
+		t.Errorf("web: pods %d, allowed %d, blocking %t; want 3, 1, not blocking", web.Pods, web.DisruptionsAllowed, web.Blocking)
+	}
+	if db := got["db"]; db.Pods != 2 || db.DesiredHealthy != 1 || db.DisruptionsAllowed != 0 || !db.Blocking {
+		t.Errorf("db: pods %d, desired %d, allowed %d, blocking %t; want 2, 1, 0, blocking",
+			db.Pods, db.DesiredHealthy, db.DisruptionsAllowed, db.Blocking)
+	}
+}
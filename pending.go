@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// explainPending returns the --why-pending details of an unscheduled pod:
+// its node constraints and the message of its most recent FailedScheduling
+// event, falling back to the PodScheduled condition when the events have
+// been garbage-collected or cannot be read. The scheduling info is returned
+// even when looking up the events fails.
+func explainPending(ctx context.Context, events *eventFetcher, pod *v1.Pod) (*podinfo.SchedulingInfo, error) {
+	info := podinfo.ExtractScheduling(pod)
+	event, err := events.lastFailedScheduling(ctx, pod)
+	if err != nil {
+		return info, err
+	}
+	if event != nil {
+		info.Reason = event.Reason
+		info.Message = event.Message
+		info.Source = podinfo.SchedulingFromEvent
+	}
+	return info, nil
+}
+
+// orNoneList joins values with ", ", or returns "<none>"
+func orNoneList(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ", ")
+}
+
+// schedulingMessage returns the scheduler's reason and message, naming the
+// condition when that is where they came from
+func schedulingMessage(s *podinfo.SchedulingInfo) string {
+	if s.Source == "" {
+		return "<no FailedScheduling event or PodScheduled condition>"
+	}
+	message := s.Reason
+	if s.Message != "" {
+		message += ": " + s.Message
+	}
+	if s.Source == podinfo.SchedulingFromCondition {
+		message += " (from the PodScheduled condition)"
+	}
+	return message
+}
+
+// printScheduling prints the --why-pending details indented under a pod
+func printScheduling(out io.Writer, s *podinfo.SchedulingInfo) {
+	fmt.Fprintf(out, "  Why pending:\n")
+	fmt.Fprintf(out, "    Node selector: %s\n", orNone(labels.Set(s.NodeSelector).String()))
+	if len(s.NodeAffinity) == 0 {
+		fmt.Fprintf(out, "    Required node affinity: <none>\n")
+	} else {
+		fmt.Fprintf(out, "    Required node affinity (any of):\n")
+		for _, term := range s.NodeAffinity {
+			fmt.Fprintf(out, "      - %s\n", term)
+		}
+	}
+	fmt.Fprintf(out, "    Tolerations: %s\n", orNoneList(s.Tolerations))
+	fmt.Fprintf(out, "    Scheduler: %s\n", schedulingMessage(s))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// newPendingPod returns an unscheduled pod whose PodScheduled condition is false
+func newPendingPod(name, message string) *v1.Pod {
+	pod := newTestPod("default", name)
+	pod.UID = types.UID("uid-" + name)
+	pod.Status.Phase = v1.PodPending
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: message}}
+	return pod
+}
+
+// newSchedulingEvent returns a FailedScheduling event of pod
+func newSchedulingEvent(pod *v1.Pod, name, message string, at time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: pod.Namespace, Name: name},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: pod.Name, UID: pod.UID},
+		Type:           v1.EventTypeWarning,
+		Reason:         failedSchedulingReason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestProcessWhyPending(t *testing.T) {
+	now := time.Now()
+	withEvents := newPendingPod("with-events", "condition message")
+	withEvents.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	collected := newPendingPod("collected", "0/12 nodes are available: 12 Insufficient memory.")
+	scheduled := newTestPod("default", "scheduled")
+	scheduled.Spec.NodeName = "node-1"
+	scheduled.Status.Phase = v1.PodPending
+
+	// The fake clientset ignores field selectors, so the pod whose events
+	// were collected lives in a second cluster without any
+	var selectors []string
+	recordSelectors := func(client *fake.Clientset) *fake.Clientset {
+		client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			selectors = append(selectors, action.(k8stesting.ListAction).GetListRestrictions().Fields.String())
+			return false, nil, nil
+		})
+		return client
+	}
+	client := recordSelectors(fake.NewSimpleClientset(
+		newSchedulingEvent(withEvents, "e1", "0/12 nodes are available: 12 Insufficient cpu.", now.Add(-10*time.Minute)),
+		newSchedulingEvent(withEvents, "e2", "0/12 nodes are available: 12 node(s) didn't match Pod's node affinity/selector.", now.Add(-time.Minute)),
+	))
+	emptyClient := recordSelectors(fake.NewSimpleClientset())
+
+	listed := []clusterPods{
+		{Cluster: cluster{Client: client}, Pods: []v1.Pod{*withEvents, *scheduled}},
+		{Cluster: cluster{Client: emptyClient}, Pods: []v1.Pod{*collected}},
+	}
+	result, err := podQuery{WhyPending: true}.process(context.Background(), listed, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(selectors) != 2 {
+		t.Errorf("listed events %d times (%v), want once per unscheduled pod", len(selectors), selectors)
+	}
+	for _, selector := range selectors {
+		if !strings.Contains(selector, "reason=FailedScheduling") {
+			t.Errorf("field selector %q does not ask for FailedScheduling events", selector)
+		}
+	}
+
+	byName := map[string]podinfo.PodInfo{}
+	for _, info := range result.Infos {
+		byName[info.Name] = info
+	}
+	if s := byName["with-events"].Scheduling; s == nil || s.Source != podinfo.SchedulingFromEvent ||
+		s.Message != "0/12 nodes are available: 12 node(s) didn't match Pod's node affinity/selector." || s.NodeSelector["disktype"] != "ssd" {
+		t.Errorf("with-events scheduling = %+v, want the newest event and the node selector", s)
+	}
+	if s := byName["collected"].Scheduling; s == nil || s.Source != podinfo.SchedulingFromCondition || s.Message != "0/12 nodes are available: 12 Insufficient memory." {
+		t.Errorf("collected scheduling = %+v, want the PodScheduled condition as fallback", s)
+	}
+	if s := byName["scheduled"].Scheduling; s != nil {
+		t.Errorf("scheduled pod got scheduling details %+v", s)
+	}
+}
+
+func TestSchedulingMessage(t *testing.T) {
+	tests := []struct {
+		info podinfo.SchedulingInfo
+		want string
+	}{
+		{podinfo.SchedulingInfo{Reason: "FailedScheduling", Message: "0/3 nodes are available", Source: podinfo.SchedulingFromEvent}, "FailedScheduling: 0/3 nodes are available"},
+		{podinfo.SchedulingInfo{Reason: "Unschedulable", Message: "0/3 nodes are available", Source: podinfo.SchedulingFromCondition}, "Unschedulable: 0/3 nodes are available (from the PodScheduled condition)"},
+		{podinfo.SchedulingInfo{}, "<no FailedScheduling event or PodScheduled condition>"},
+	}
+	for _, tt := range tests {
+		if got := schedulingMessage(&tt.info); got != tt.want {
+			t.Errorf("schedulingMessage(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
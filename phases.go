@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/output"
+)
+
+// summaryPhases are the phase columns of the summary table, in order
+var summaryPhases = []v1.PodPhase{v1.PodRunning, v1.PodPending, v1.PodFailed, v1.PodSucceeded, v1.PodUnknown}
+
+// AggregatePodPhases counts pods by phase. A pod without a phase has not
+// been reported on yet and counts as Pending.
+func AggregatePodPhases(pods []v1.Pod) map[v1.PodPhase]int {
+	counts := make(map[v1.PodPhase]int)
+	for i := range pods {
+		phase := pods[i].Status.Phase
+		if phase == "" {
+			phase = v1.PodPending
+		}
+		counts[phase]++
+	}
+	return counts
+}
+
+// PrintSummaryTable prints one row of phase counts per namespace, sorted
+// by namespace
+func PrintSummaryTable(summary map[string]map[v1.PodPhase]int, w io.Writer) error {
+	return printPhaseSummary(w, summary, false)
+}
+
+// printPhaseSummary is PrintSummaryTable, printing the rows of namespaces
+// with failed pods in red when highlightFailed is set
+func printPhaseSummary(out io.Writer, summary map[string]map[v1.PodPhase]int, highlightFailed bool) error {
+	namespaces := make([]string, 0, len(summary))
+	for ns := range summary {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tRUNNING\tPENDING\tFAILED\tSUCCEEDED\tUNKNOWN")
+	colors := []string{""}
+	for _, ns := range namespaces {
+		fmt.Fprint(w, ns)
+		for _, phase := range summaryPhases {
+			fmt.Fprintf(w, "\t%d", summary[ns][phase])
+		}
+		fmt.Fprintln(w)
+		color := ""
+		if summary[ns][v1.PodFailed] > 0 {
+			color = ansiRed
+		}
+		colors = append(colors, color)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !highlightFailed {
+		_, err := out.Write(table.Bytes())
+		return err
+	}
+	return writeColoredLines(out, table.Bytes(), colors)
+}
+
+// summarizePhases counts the pods of every namespace by phase. Namespaces
+// without pods are included with zero counts.
+func summarizePhases(ctx context.Context, client kubernetes.Interface) (map[string]map[v1.PodPhase]int, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", handleAPIError(err, "namespaces", ""))
+	}
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", handleAPIError(err, "pods", ""))
+	}
+
+	byNamespace := make(map[string][]v1.Pod)
+	for _, pod := range pods.Items {
+		byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod)
+	}
+	summary := make(map[string]map[v1.PodPhase]int, len(namespaces.Items))
+	for i := range namespaces.Items {
+		summary[namespaces.Items[i].Name] = map[v1.PodPhase]int{}
+	}
+	for ns, nsPods := range byNamespace {
+		summary[ns] = AggregatePodPhases(nsPods)
+	}
+	return summary, nil
+}
+
+// runSummary implements the summary subcommand
+func runSummary(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	highlightFailed := fs.Bool("highlight-failed", false, "print the rows of namespaces with failed pods in red (table output on a terminal, unless NO_COLOR is set)")
+	outputFormat := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*outputFormat); err != nil {
+		return err
+	}
+	switch *outputFormat {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *outputFormat)
+	}
+	color := false
+	if *highlightFailed && output.IsStdout(*outputFile) {
+		// CI logs and pipes get plain text
+		var err error
+		if color, err = stdoutColorEnabled(colorAuto); err != nil {
+			return err
+		}
+	}
+
+	client, _, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+	defer cancel()
+
+	summary, err := summarizePhases(ctx, client)
+	if err != nil {
+		return fmt.Errorf("error summarizing pod phases: %w", timeoutError(err, clientOpts.Timeout))
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		if *outputFormat != outputTable {
+			return printStructured(out, summary, *outputFormat)
+		}
+		if len(summary) == 0 {
+			fmt.Fprintln(out, "No namespaces found")
+			return nil
+		}
+		return printPhaseSummary(out, summary, color)
+	})
+}
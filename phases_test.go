@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAggregatePodPhases(t *testing.T) {
+	pod := func(phase v1.PodPhase) v1.Pod {
+		return v1.Pod{Status: v1.PodStatus{Phase: phase}}
+	}
+	pods := []v1.Pod{pod(v1.PodRunning), pod(v1.PodRunning), pod(v1.PodFailed), pod(v1.PodSucceeded), pod(v1.PodPending), pod("")}
+
+	want := map[v1.PodPhase]int{v1.PodRunning: 2, v1.PodPending: 2, v1.PodFailed: 1, v1.PodSucceeded: 1}
+	if got := AggregatePodPhases(pods); !reflect.DeepEqual(got, want) {
+		t.Errorf("AggregatePodPhases() = %v, want %v", got, want)
+	}
+	if got := AggregatePodPhases(nil); len(got) != 0 {
+		t.Errorf("AggregatePodPhases(nil) = %v, want no counts", got)
+	}
+}
+
+func TestSummarizePhases(t *testing.T) {
+	pod := func(ns, name string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}, Status: v1.PodStatus{Phase: phase}}
+	}
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty"}},
+		pod("default", "web", v1.PodRunning),
+		pod("default", "job", v1.PodSucceeded),
+	)
+
+	got, err := summarizePhases(context.Background(), client)
+	if err != nil {
+		t.Fatalf("summarizePhases() error = %v", err)
+	}
+	want := map[string]map[v1.PodPhase]int{
+		"default": {v1.PodRunning: 1, v1.PodSucceeded: 1},
+		"empty":   {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizePhases() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintSummaryTable(t *testing.T) {
+	summary := map[string]map[v1.PodPhase]int{
+		"kube-system": {v1.PodRunning: 12},
+		"default":     {v1.PodRunning: 3, v1.PodPending: 1, v1.PodFailed: 2},
+		"batch":       {v1.PodSucceeded: 140, v1.PodUnknown: 1},
+		"empty":       {},
+	}
+
+	var out bytes.Buffer
+	if err := PrintSummaryTable(summary, &out); err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "phase_summary.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(golden) {
+		t.Errorf("PrintSummaryTable() =\n%s\nwant\n%s", out.String(), golden)
+	}
+
+	out.Reset()
+	if err := printPhaseSummary(&out, summary, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		red := strings.HasPrefix(line, ansiRed)
+		if wantRed := strings.Contains(line, "default"); red != wantRed {
+			t.Errorf("line %q highlighted = %t, want %t", line, red, wantRed)
+		}
+	}
+}
@@ -15,6 +15,9 @@ type AtSpec struct {
 	Schedule string `json:"schedule,omitempty"`
 	// Command is the desired command (executed in a Bash shell) to be executed.
 	Command string `json:"command,omitempty"`
+	// Recurring reruns the command on every occurrence of Schedule, which
+	// must then be a cron expression; only the controller's CRD has it.
+	Recurring bool `json:"recurring,omitempty"`
 }
 
 // AtStatus defines the observed state of At
@@ -22,6 +25,11 @@ type AtStatus struct {
 	// Phase represents the state of the schedule: until the command is executed
 	// it is PENDING, afterwards it is DONE.
 	Phase string `json:"phase,omitempty"`
+	// LastRunTime is when the command was last started.
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// Conditions are the latest observations of the At's state, such as
+	// Scheduled and Ready, as the controller records them.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
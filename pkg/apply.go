@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// Outcomes of applying an At, as kubectl apply reports them
+const (
+	applyCreated    = "created"
+	applyConfigured = "configured"
+	applyUnchanged  = "unchanged"
+)
+
+// applyFieldManager owns the fields apply sets on the server
+const applyFieldManager = "at-apply"
+
+// atManifest is one At document of a manifest. The spec is kept as written
+// rather than decoded into AtSpec, so fields of the controller's CRD this
+// client doesn't know (maxRetries, podTemplateSpec) reach the server, which
+// validates them against its schema.
+type atManifest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec map[string]interface{} `json:"spec,omitempty"`
+	// Status is accepted so that the output of get -o yaml applies, but is
+	// never sent
+	Status map[string]interface{} `json:"status,omitempty"`
+}
+
+// applyConfiguration returns the server-side apply body of m: its
+// apiVersion, kind, metadata and spec
+func (m *atManifest) applyConfiguration() ([]byte, error) {
+	config := *m
+	config.Status = nil
+	return json.Marshal(config)
+}
+
+// decodeAts decodes the At documents of a YAML or JSON manifest, which may
+// hold several YAML documents separated by ---. Unknown fields outside the
+// spec are rejected, and empty documents skipped. Documents without a
+// namespace get namespace.
+func decodeAts(r io.Reader, namespace string) ([]*atManifest, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	var ats []*atManifest
+	for i := 1; ; i++ {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return ats, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading document %d: %w", i, err)
+		}
+		// Blank and comment-only documents convert to null
+		if data, err := yaml.YAMLToJSON(doc); err == nil && bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			continue
+		}
+
+		at := &atManifest{}
+		if err := yaml.UnmarshalStrict(doc, at); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if gvk := at.GroupVersionKind(); gvk != cnatv1alpha1.SchemeGroupVersion.WithKind("At") {
+			return nil, fmt.Errorf("document %d: apiVersion %q, kind %q is not an At (want apiVersion %s, kind At)",
+				i, at.APIVersion, at.Kind, cnatv1alpha1.SchemeGroupVersion)
+		}
+		if at.Name == "" {
+			return nil, fmt.Errorf("document %d: metadata.name is required", i)
+		}
+		if at.Namespace == "" {
+			at.Namespace = namespace
+		}
+		ats = append(ats, at)
+	}
+}
+
+// applyOptions are the apply flags that change how each At is applied
+type applyOptions struct {
+	// DryRun only validates the apply on the API server
+	DryRun bool
+	// Diff reads the live Ats whose diff is written before applying them;
+	// nil writes no diff
+	Diff dynamic.Interface
+}
+
+// applyAt applies the manifest with a server-side apply, which creates the
+// At when it does not exist yet and otherwise sets only the fields the
+// manifest sets, leaving labels, annotations and spec fields it omits as
+// they are. Unknown spec fields are rejected by the server, and nothing is
+// persisted when dryRun is set. It returns applyCreated, applyConfigured or
+// applyUnchanged.
+func applyAt(ctx context.Context, client clientset.Interface, at *atManifest, dryRun bool) (string, error) {
+	ats := client.CnatV1alpha1().Ats(at.Namespace)
+	live, err := ats.Get(ctx, at.Name, metav1.GetOptions{})
+	created := apierrors.IsNotFound(err)
+	if err != nil && !created {
+		return "", err
+	}
+
+	data, err := at.applyConfiguration()
+	if err != nil {
+		return "", err
+	}
+	opts := metav1.PatchOptions{
+		FieldManager:    applyFieldManager,
+		Force:           ptr.To(true),
+		FieldValidation: metav1.FieldValidationStrict,
+	}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	applied, err := ats.Patch(ctx, at.Name, types.ApplyPatchType, data, opts)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case created:
+		return applyCreated, nil
+	case equality.Semantic.DeepEqual(applied, live):
+		return applyUnchanged, nil
+	default:
+		return applyConfigured, nil
+	}
+}
+
+// applyAts applies every At, printing one line per At, after its diff
+// when opts.Diff is set, so a failure doesn't hide the outcome of the
+// others. It returns how many failed.
+func applyAts(ctx context.Context, out io.Writer, client clientset.Interface, ats []*atManifest, opts applyOptions) int {
+	suffix := ""
+	if opts.DryRun {
+		suffix = " (server dry run)"
+	}
+	failed := 0
+	for _, at := range ats {
+		var err error
+		if opts.Diff != nil {
+			err = diffApply(ctx, out, opts.Diff, at)
+		}
+		var result string
+		if err == nil {
+			result, err = applyAt(ctx, client, at, opts.DryRun)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "at/%s not applied in namespace '%s': %v\n", at.Name, at.Namespace, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "at/%s %s%s\n", at.Name, result, suffix)
+	}
+	return failed
+}
+
+// runApply implements the apply subcommand
+func runApply(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace of the At resources whose manifest sets none")
+	file := fs.String("f", "", "YAML or JSON manifest with one or more At resources (- for stdin)")
+	diff := fs.Bool("diff", false, "print a diff of the labels, annotations and spec of each At against the live one before applying it")
+	dryRun := fs.String("dry-run", "", "set to server to only validate the apply on the API server without persisting it")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-f is required")
+	}
+	if *dryRun != "" && *dryRun != dryRunServer {
+		return fmt.Errorf("--dry-run must be %s, got %q", dryRunServer, *dryRun)
+	}
+
+	in := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	ats, err := decodeAts(in, *namespace)
+	if err != nil {
+		return fmt.Errorf("error decoding %s: %w", *file, err)
+	}
+	if len(ats) == 0 {
+		return fmt.Errorf("no At resources found in %s", *file)
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+	opts := applyOptions{DryRun: *dryRun == dryRunServer}
+	if *diff {
+		if opts.Diff, err = dynamic.NewForConfig(resolved.Config); err != nil {
+			return fmt.Errorf("error creating dynamic client: %w", err)
+		}
+	}
+
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	// The outcome of every At is written, even when some failed
+	var failed int
+	err = output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		failed = applyAts(ctx, out, client, ats, opts)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d At resources could not be applied", failed, len(ats))
+	}
+	return nil
+}
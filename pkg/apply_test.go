@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+const testManifest = `# nightly jobs
+apiVersion: cnat.programming-kubernetes.info/v1alpha1
+kind: At
+metadata:
+  name: backup
+  labels:
+    team: ops
+spec:
+  schedule: "2026-07-03T02:00:00Z"
+  command: echo backup
+---
+---
+# nothing but a comment
+---
+{"apiVersion": "cnat.programming-kubernetes.info/v1alpha1", "kind": "At",
+ "metadata": {"name": "cleanup", "namespace": "batch"},
+ "spec": {"schedule": "0 2 * * *", "command": "echo cleanup", "recurring": true, "maxRetries": 2}}
+`
+
+func TestDecodeAts(t *testing.T) {
+	ats, err := decodeAts(strings.NewReader(testManifest), "jobs")
+	if err != nil {
+		t.Fatalf("decodeAts() error = %v", err)
+	}
+	if len(ats) != 2 {
+		t.Fatalf("decoded %d At resources, want 2", len(ats))
+	}
+	if ats[0].Name != "backup" || ats[0].Namespace != "jobs" || ats[0].Labels["team"] != "ops" {
+		t.Errorf("first At = %+v", ats[0])
+	}
+	if ats[0].Spec["command"] != "echo backup" {
+		t.Errorf("first At spec = %v", ats[0].Spec)
+	}
+	if ats[1].Name != "cleanup" || ats[1].Namespace != "batch" || ats[1].Spec["recurring"] != true {
+		t.Errorf("second At = %+v, want the controller's recurring field kept", ats[1])
+	}
+
+	tests := map[string]string{
+		"unknown field": `apiVersion: cnat.programming-kubernetes.info/v1alpha1
+kind: At
+metadata:
+  name: backup
+  lables:
+    team: ops
+spec:
+  schedule: "2026-07-03T02:00:00Z"
+`,
+		"not an At": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: backup
+`,
+		"no name": `apiVersion: cnat.programming-kubernetes.info/v1alpha1
+kind: At
+spec:
+  command: echo
+`,
+	}
+	wants := map[string]string{
+		"unknown field": `unknown field "lables"`,
+		"not an At":     `kind "ConfigMap" is not an At`,
+		"no name":       "metadata.name is required",
+	}
+	for name, manifest := range tests {
+		_, err := decodeAts(strings.NewReader(manifest), "jobs")
+		if err == nil || !strings.Contains(err.Error(), wants[name]) {
+			t.Errorf("%s: decodeAts() error = %v, want %q", name, err, wants[name])
+		}
+	}
+}
+
+func TestApplyAts(t *testing.T) {
+	live := &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "cleanup", Labels: map[string]string{"owner": "alice"}},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: "0 2 * * *", Command: "echo old"},
+		Status:     cnatv1alpha1.AtStatus{Phase: cnatv1alpha1.PhasePending},
+	}
+	client := fake.NewSimpleClientset(live)
+	var patches []k8stesting.PatchAction
+	client.PrependReactor("patch", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		patches = append(patches, patch)
+		// The fake tracker only applies to existing objects, where the API
+		// server creates missing ones
+		_, err := client.Tracker().Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		if !apierrors.IsNotFound(err) {
+			return false, nil, nil
+		}
+		at := &cnatv1alpha1.At{}
+		if err := json.Unmarshal(patch.GetPatch(), at); err != nil {
+			return true, nil, err
+		}
+		return true, at, client.Tracker().Create(patch.GetResource(), at, patch.GetNamespace())
+	})
+
+	ats, err := decodeAts(strings.NewReader(testManifest), "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if failed := applyAts(context.Background(), &out, client, ats, applyOptions{}); failed != 0 {
+		t.Fatalf("%d At resources failed: %s", failed, out.String())
+	}
+	if want := "at/backup created\nat/cleanup configured\n"; out.String() != want {
+		t.Errorf("first apply =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if len(patches) != 2 {
+		t.Fatalf("got %d patches, want 2", len(patches))
+	}
+	for _, patch := range patches {
+		if patch.GetPatchType() != types.ApplyPatchType {
+			t.Errorf("patch type = %s, want %s", patch.GetPatchType(), types.ApplyPatchType)
+		}
+	}
+	var sent map[string]interface{}
+	if err := json.Unmarshal(patches[1].GetPatch(), &sent); err != nil {
+		t.Fatal(err)
+	}
+	spec := sent["spec"].(map[string]interface{})
+	if spec["recurring"] != true || spec["maxRetries"] != float64(2) {
+		t.Errorf("applied spec = %v, want recurring and maxRetries sent", spec)
+	}
+	if _, ok := sent["status"]; ok {
+		t.Errorf("applied %v, want no status", sent)
+	}
+
+	cleanup, err := client.CnatV1alpha1().Ats("batch").Get(context.Background(), "cleanup", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleanup.Spec.Command != "echo cleanup" || cleanup.Labels["owner"] != "alice" || cleanup.Status.Phase != cnatv1alpha1.PhasePending {
+		t.Errorf("applied At = %+v, want the new spec with the live labels and status kept", cleanup)
+	}
+	backup, err := client.CnatV1alpha1().Ats("jobs").Get(context.Background(), "backup", metav1.GetOptions{})
+	if err != nil || backup.Spec.Command != "echo backup" {
+		t.Errorf("created At = %+v, %v", backup, err)
+	}
+
+	// Applying the same manifest again changes nothing
+	out.Reset()
+	applyAts(context.Background(), &out, client, ats, applyOptions{})
+	if want := "at/backup unchanged\nat/cleanup unchanged\n"; out.String() != want {
+		t.Errorf("second apply =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+)
+
+// atStatusLines renders every field set in status, one "name: value" line
+// each under its JSON name, in the order of the AtStatus struct. The fields
+// are found by reflection, so ones added to AtStatus later show up without
+// changes here: times are shown in UTC, conditions as one indented
+// TYPE=STATUS (REASON) line each, oldest transition first, and any other
+// non-scalar value as compact JSON. An older At with only a phase yields a
+// single line.
+func atStatusLines(status cnatv1alpha1.AtStatus) []string {
+	var lines []string
+	v := reflect.ValueOf(status)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" || v.Field(i).IsZero() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value := v.Field(i).Interface()
+		switch value := value.(type) {
+		case []metav1.Condition:
+			lines = append(lines, name+":")
+			for _, condition := range sortedConditions(value) {
+				lines = append(lines, "  "+conditionString(condition))
+			}
+			continue
+		case *metav1.Time:
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value.UTC().Format(time.RFC3339)))
+			continue
+		case metav1.Time:
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value.UTC().Format(time.RFC3339)))
+			continue
+		}
+		switch v.Field(i).Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64:
+			lines = append(lines, fmt.Sprintf("%s: %v", name, value))
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				data = []byte(fmt.Sprintf("%v", value))
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", name, data))
+		}
+	}
+	return lines
+}
+
+// sortedConditions returns a copy of conditions ordered by their last
+// transition, oldest first
+func sortedConditions(conditions []metav1.Condition) []metav1.Condition {
+	sorted := slices.Clone(conditions)
+	slices.SortStableFunc(sorted, func(a, b metav1.Condition) int {
+		return a.LastTransitionTime.Compare(b.LastTransitionTime.Time)
+	})
+	return sorted
+}
+
+// conditionString renders a condition as TYPE=STATUS (REASON), without the
+// parenthesis when it has no reason
+func conditionString(condition metav1.Condition) string {
+	s := fmt.Sprintf("%s=%s", condition.Type, condition.Status)
+	if condition.Reason != "" {
+		s += " (" + condition.Reason + ")"
+	}
+	return s
+}
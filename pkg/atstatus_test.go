@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+)
+
+func TestAtStatusLines(t *testing.T) {
+	ran := metav1.NewTime(time.Date(2026, 7, 3, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	status := cnatv1alpha1.AtStatus{
+		Phase:       cnatv1alpha1.PhaseDone,
+		LastRunTime: &ran,
+		// Out of order, as the controller may update them
+		Conditions: []metav1.Condition{
+			{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Succeeded", LastTransitionTime: metav1.NewTime(ran.Add(time.Minute))},
+			{Type: "Scheduled", Status: metav1.ConditionTrue, LastTransitionTime: metav1.NewTime(ran.Add(-time.Hour))},
+		},
+	}
+	want := []string{
+		"phase: DONE",
+		"lastRunTime: 2026-07-03T00:00:00Z",
+		"conditions:",
+		"  Scheduled=True",
+		"  Ready=True (Succeeded)",
+	}
+	if got := atStatusLines(status); !reflect.DeepEqual(got, want) {
+		t.Errorf("atStatusLines() =\n%q\nwant\n%q", got, want)
+	}
+	if status.Conditions[0].Type != "Ready" {
+		t.Error("atStatusLines() reordered the conditions of the At")
+	}
+
+	// An older At that only has a phase
+	if got := atStatusLines(cnatv1alpha1.AtStatus{Phase: cnatv1alpha1.PhasePending}); !reflect.DeepEqual(got, []string{"phase: PENDING"}) {
+		t.Errorf("atStatusLines(phase only) = %q", got)
+	}
+	if got := atStatusLines(cnatv1alpha1.AtStatus{}); len(got) != 0 {
+		t.Errorf("atStatusLines(empty) = %q, want none", got)
+	}
+}
+
+func TestPrintAtsShowStatus(t *testing.T) {
+	ats := []cnatv1alpha1.At{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+			Spec:       cnatv1alpha1.AtSpec{Schedule: "2026-07-03T02:00:00Z", Command: "echo backup"},
+			Status: cnatv1alpha1.AtStatus{
+				Phase:      cnatv1alpha1.PhaseRunning,
+				Conditions: []metav1.Condition{{Type: "Scheduled", Status: metav1.ConditionTrue, Reason: "ScheduleValid"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "new"},
+			Spec:       cnatv1alpha1.AtSpec{Schedule: "2026-07-04T02:00:00Z", Command: "echo new"},
+		},
+	}
+
+	var out bytes.Buffer
+	printAts(&out, ats, true)
+	want := `Found 2 At resource(s):
+1. Name: backup
+   Schedule: 2026-07-03T02:00:00Z
+   Command: echo backup
+   Status:
+     phase: RUNNING
+     conditions:
+       Scheduled=True (ScheduleValid)
+
+2. Name: new
+   Schedule: 2026-07-04T02:00:00Z
+   Command: echo new
+   Status: <none>
+
+`
+	if out.String() != want {
+		t.Errorf("printAts(show status) =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printAts(&out, ats[:1], false)
+	want = `Found 1 At resource(s):
+1. Name: backup
+   Schedule: 2026-07-03T02:00:00Z
+   Command: echo backup
+   Phase: RUNNING
+
+`
+	if out.String() != want {
+		t.Errorf("printAts() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,138 @@
+// Package cache provides an informer-backed, indexed cache of pods so
+// long-running tools can answer lookups without hitting the API server.
+package cache
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// LabelIndex is the name of the index mapping "key=value" label pairs to pods
+const LabelIndex = "label"
+
+// PodCache keeps an up to date, indexed copy of the pods in a namespace (or
+// all namespaces). Pods are keyed by namespace/name.
+type PodCache struct {
+	informer cache.SharedIndexInformer
+}
+
+// NewPodCache returns a PodCache watching pods in namespace, or in all
+// namespaces when namespace is empty. The cache is empty until Run is
+// called and it has synced.
+func NewPodCache(client kubernetes.Interface, namespace string, resyncPeriod time.Duration) *PodCache {
+	return NewFilteredPodCache(client, namespace, resyncPeriod, nil)
+}
+
+// NewFilteredPodCache is like NewPodCache, but tweakListOptions (if not nil)
+// adjusts every list and watch request, e.g. to set a label selector so only
+// matching pods are sent to and kept in the cache.
+func NewFilteredPodCache(client kubernetes.Interface, namespace string, resyncPeriod time.Duration, tweakListOptions func(*metav1.ListOptions)) *PodCache {
+	pods := client.CoreV1().Pods(namespace)
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return pods.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			if tweakListOptions != nil {
+				tweakListOptions(&options)
+			}
+			return pods.Watch(ctx, options)
+		},
+	}
+	// Let the reflector fall back to list+watch for clients (such as the fake
+	// clientset) that cannot stream the initial list
+	return newPodCache(cache.ToListWatcherWithWatchListSemantics(lw, client), resyncPeriod)
+}
+
+// newPodCache returns a PodCache fed by lw
+func newPodCache(lw cache.ListerWatcher, resyncPeriod time.Duration) *PodCache {
+	informer := cache.NewSharedIndexInformer(lw, &v1.Pod{}, resyncPeriod, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		LabelIndex:           labelIndexFunc,
+	})
+	return &PodCache{informer: informer}
+}
+
+// labelIndexFunc indexes an object under every "key=value" pair of its labels
+func labelIndexFunc(obj interface{}) ([]string, error) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(object.GetLabels()))
+	for key, value := range object.GetLabels() {
+		keys = append(keys, key+"="+value)
+	}
+	return keys, nil
+}
+
+// Run starts the informer and blocks until stopCh is closed
+func (c *PodCache) Run(stopCh <-chan struct{}) {
+	c.informer.Run(stopCh)
+}
+
+// WaitForCacheSync blocks until the initial list has been loaded into the
+// cache, returning false if stopCh is closed first
+func (c *PodCache) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stopCh, c.informer.HasSynced)
+}
+
+// AddEventHandler registers handler for pod add/update/delete events
+func (c *PodCache) AddEventHandler(handler cache.ResourceEventHandler) error {
+	_, err := c.informer.AddEventHandler(handler)
+	return err
+}
+
+// GetPod returns the cached pod namespace/name. The returned pod is shared
+// with the cache and must not be modified.
+func (c *PodCache) GetPod(namespace, name string) (*v1.Pod, bool) {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !exists {
+		return nil, false
+	}
+	return obj.(*v1.Pod), true
+}
+
+// ListPods returns every cached pod
+func (c *PodCache) ListPods() []*v1.Pod {
+	objs := c.informer.GetIndexer().List()
+	pods := make([]*v1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pods = append(pods, obj.(*v1.Pod))
+	}
+	return pods
+}
+
+// ListPodsInNamespace returns the cached pods in namespace
+func (c *PodCache) ListPodsInNamespace(namespace string) []*v1.Pod {
+	return c.byIndex(cache.NamespaceIndex, namespace)
+}
+
+// ListPodsByLabel returns the cached pods labeled key=value
+func (c *PodCache) ListPodsByLabel(key, value string) []*v1.Pod {
+	return c.byIndex(LabelIndex, key+"="+value)
+}
+
+// byIndex returns the pods stored under value in the named index
+func (c *PodCache) byIndex(index, value string) []*v1.Pod {
+	objs, err := c.informer.GetIndexer().ByIndex(index, value)
+	if err != nil {
+		return nil
+	}
+	pods := make([]*v1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pods = append(pods, obj.(*v1.Pod))
+	}
+	return pods
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	fcache "k8s.io/client-go/tools/cache/testing"
+)
+
+// newPod returns a pod with the given labels
+func newPod(namespace, name string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+// podNames returns the sorted names of pods
+func podNames(pods []*v1.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// startCache runs a PodCache fed by source and waits for it to sync
+func startCache(t *testing.T, source *fcache.FakeControllerSource) *PodCache {
+	t.Helper()
+	c := newPodCache(source, 0)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	go c.Run(stopCh)
+	if !c.WaitForCacheSync(stopCh) {
+		t.Fatal("cache did not sync")
+	}
+	return c
+}
+
+// eventually polls cond until it holds, failing the test after a timeout
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+		func(context.Context) (bool, error) { return cond(), nil })
+	if err != nil {
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestPodCacheEvents(t *testing.T) {
+	source := fcache.NewFakeControllerSource()
+	source.Add(newPod("default", "web", map[string]string{"app": "web"}))
+	source.Add(newPod("kube-system", "coredns", map[string]string{"app": "dns"}))
+	c := startCache(t, source)
+
+	if pod, ok := c.GetPod("default", "web"); !ok || pod.Name != "web" {
+		t.Fatalf("GetPod(default, web) = %v, %v after initial list", pod, ok)
+	}
+	if _, ok := c.GetPod("default", "coredns"); ok {
+		t.Error("GetPod found coredns in the wrong namespace")
+	}
+
+	// Add
+	source.Add(newPod("default", "api", map[string]string{"app": "web"}))
+	eventually(t, "added pod", func() bool { return len(c.ListPodsInNamespace("default")) == 2 })
+	if got := podNames(c.ListPodsByLabel("app", "web")); !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Errorf("ListPodsByLabel(app, web) = %v after add", got)
+	}
+
+	// Update: relabeling moves the pod between label index entries
+	source.Modify(newPod("default", "api", map[string]string{"app": "api"}))
+	eventually(t, "updated pod", func() bool { return len(c.ListPodsByLabel("app", "api")) == 1 })
+	if got := podNames(c.ListPodsByLabel("app", "web")); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("ListPodsByLabel(app, web) = %v after update", got)
+	}
+
+	// Delete
+	source.Delete(newPod("default", "web", nil))
+	eventually(t, "deleted pod", func() bool {
+		_, ok := c.GetPod("default", "web")
+		return !ok
+	})
+	if got := podNames(c.ListPodsInNamespace("default")); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("ListPodsInNamespace(default) = %v after delete", got)
+	}
+	if got := podNames(c.ListPodsInNamespace("kube-system")); !reflect.DeepEqual(got, []string{"coredns"}) {
+		t.Errorf("ListPodsInNamespace(kube-system) = %v", got)
+	}
+}
+
+func TestNewPodCache(t *testing.T) {
+	client := fake.NewSimpleClientset(newPod("default", "web", nil), newPod("other", "db", nil))
+	c := NewPodCache(client, "default", time.Minute)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go c.Run(stopCh)
+	if !c.WaitForCacheSync(stopCh) {
+		t.Fatal("cache did not sync")
+	}
+
+	if _, ok := c.GetPod("default", "web"); !ok {
+		t.Error("GetPod(default, web) not found")
+	}
+	if _, ok := c.GetPod("other", "db"); ok {
+		t.Error("cache scoped to default holds a pod from another namespace")
+	}
+}
+
+func TestNewFilteredPodCache(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPod("default", "web", map[string]string{"app": "web"}),
+		newPod("other", "web", map[string]string{"app": "web"}),
+		newPod("default", "db", map[string]string{"app": "db"}),
+	)
+	c := NewFilteredPodCache(client, "", time.Minute, func(options *metav1.ListOptions) {
+		options.LabelSelector = "app=web"
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go c.Run(stopCh)
+	if !c.WaitForCacheSync(stopCh) {
+		t.Fatal("cache did not sync")
+	}
+
+	if got := podNames(c.ListPods()); !reflect.DeepEqual(got, []string{"web", "web"}) {
+		t.Errorf("ListPods() = %v, want only the pods labeled app=web", got)
+	}
+}
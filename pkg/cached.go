@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	informers "Kubernetes_Programming/pkg/generated/informers/externalversions"
+	listers "Kubernetes_Programming/pkg/generated/listers/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// defaultCacheSyncTimeout is how long --cached waits for the At cache to
+// fill before listing straight from the API server instead
+const defaultCacheSyncTimeout = 10 * time.Second
+
+// AtCache serves At listings of a namespace from the cache of the generated
+// shared informer, so repeated listings and filtering cost no API calls
+// once the cache has synced. When it could not sync, listings go to the
+// API server directly.
+type AtCache struct {
+	client    clientset.Interface
+	namespace string
+	factory   informers.SharedInformerFactory
+	stop      context.CancelFunc
+	// lister is nil when the cache did not sync
+	lister listers.AtNamespaceLister
+}
+
+// NewAtCache starts an At informer for namespace and waits up to
+// syncTimeout for its cache to sync. A cache that doesn't sync in time is
+// logged as a warning and the AtCache falls back to listing directly. The
+// informer runs until ctx is cancelled or Stop is called.
+func NewAtCache(ctx context.Context, client clientset.Interface, namespace string, syncTimeout time.Duration) *AtCache {
+	ctx, stop := context.WithCancel(ctx)
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithNamespace(namespace))
+	ats := factory.Cnat().V1alpha1().Ats()
+	// Informer registers the informer with the factory, so it must be
+	// called before Start
+	informer := ats.Informer()
+	factory.Start(ctx.Done())
+
+	c := &AtCache{client: client, namespace: namespace, factory: factory, stop: stop}
+	syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), informer.HasSynced) {
+		logging.FromContext(ctx).Warn("the At cache did not sync, listing from the API server instead",
+			"namespace", namespace, "timeout", syncTimeout)
+		return c
+	}
+	c.lister = ats.Lister().Ats(namespace)
+	return c
+}
+
+// Synced reports whether listings are served from the cache
+func (c *AtCache) Synced() bool {
+	return c.lister != nil
+}
+
+// List returns the At resources matching selector, sorted by name
+func (c *AtCache) List(ctx context.Context, selector labels.Selector) ([]cnatv1alpha1.At, error) {
+	var ats []cnatv1alpha1.At
+	if c.lister != nil {
+		cached, err := c.lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		// Objects of the cache are shared, so hand out copies
+		for _, at := range cached {
+			ats = append(ats, *at.DeepCopy())
+		}
+	} else {
+		list, err := c.client.CnatV1alpha1().Ats(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		ats = list.Items
+	}
+	sort.Slice(ats, func(i, j int) bool { return ats[i].Name < ats[j].Name })
+	return ats, nil
+}
+
+// Stop stops the informer and waits for it to exit
+func (c *AtCache) Stop() {
+	c.stop()
+	c.factory.Shutdown()
+}
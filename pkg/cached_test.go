@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func cachedAt(namespace, name string, labels map[string]string) *cnatv1alpha1.At {
+	return &cnatv1alpha1.At{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+func atNames(ats []cnatv1alpha1.At) []string {
+	names := make([]string, 0, len(ats))
+	for _, at := range ats {
+		names = append(names, at.Name)
+	}
+	return names
+}
+
+func TestAtCacheListsFromCache(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		cachedAt("default", "nightly", map[string]string{"team": "data"}),
+		cachedAt("default", "backup", map[string]string{"team": "ops"}),
+		cachedAt("other", "elsewhere", nil),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	atCache := NewAtCache(ctx, client, "default", 5*time.Second)
+	defer atCache.Stop()
+	if !atCache.Synced() {
+		t.Fatal("Synced() = false, want the cache to sync")
+	}
+
+	client.ClearActions()
+	for i := 0; i < 3; i++ {
+		ats, err := atCache.List(ctx, labels.Everything())
+		if err != nil {
+			t.Fatalf("List() error: %v", err)
+		}
+		if got, want := atNames(ats), []string{"backup", "nightly"}; !slices.Equal(got, want) {
+			t.Errorf("List() = %q, want %q", got, want)
+		}
+	}
+	ats, err := atCache.List(ctx, labels.SelectorFromSet(labels.Set{"team": "data"}))
+	if err != nil {
+		t.Fatalf("List(team=data) error: %v", err)
+	}
+	if got, want := atNames(ats), []string{"nightly"}; !slices.Equal(got, want) {
+		t.Errorf("List(team=data) = %q, want %q", got, want)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("listing from the cache made %d API calls, want none: %v", len(actions), actions)
+	}
+}
+
+func TestAtCacheFallsBackWhenNotSynced(t *testing.T) {
+	client := fake.NewSimpleClientset(cachedAt("default", "backup", nil))
+	var failing atomic.Bool
+	failing.Store(true)
+	client.PrependReactor("list", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failing.Load() {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	atCache := NewAtCache(ctx, client, "default", 100*time.Millisecond)
+	defer atCache.Stop()
+	if atCache.Synced() {
+		t.Fatal("Synced() = true while every list fails")
+	}
+
+	failing.Store(false)
+	ats, err := atCache.List(ctx, labels.Everything())
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if got, want := atNames(ats), []string{"backup"}; !slices.Equal(got, want) {
+		t.Errorf("List() = %q, want %q", got, want)
+	}
+}
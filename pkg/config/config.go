@@ -0,0 +1,256 @@
+// Package config resolves the rest.Config used to talk to the API server,
+// shared by the pod lister and the At CLI.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Version is the version reported in the User-Agent. Release builds set it
+// with -ldflags "-X Kubernetes_Programming/pkg/config.Version=v1.2.3";
+// otherwise the module version from the build info is used.
+var Version string
+
+// UserAgent returns the User-Agent sent to the API server, e.g.
+// "at-client/v1.2.3 (linux/amd64)", so requests can be told apart in the
+// API server's audit logs.
+func UserAgent() string {
+	return fmt.Sprintf("%s/%s (%s/%s)", filepath.Base(os.Args[0]), version(), runtime.GOOS, runtime.GOARCH)
+}
+
+// version returns Version, or the main module version when it is not set
+func version() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// Options holds the flags that control how the client config is resolved
+type Options struct {
+	Kubeconfig string
+	Context    string
+	InCluster  bool
+	// QPS and Burst tune the client-side rate limiter; zero keeps the
+	// client-go defaults
+	QPS   float32
+	Burst int
+	// Timeout bounds the API calls of a command, see AddTimeoutFlag
+	Timeout time.Duration
+}
+
+// AddFlags registers the config flags on fs
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config, in-cluster config is used when none exists)")
+	fs.StringVar(&o.Context, "context", "", "kubeconfig context to use (defaults to the current-context)")
+	fs.BoolVar(&o.InCluster, "in-cluster", false, "force in-cluster config even if a kubeconfig is available")
+	fs.Func("qps", "maximum queries per second to the API server (default 5)", func(value string) error {
+		qps, err := strconv.ParseFloat(value, 32)
+		if err != nil || qps < 0 {
+			return fmt.Errorf("invalid QPS %q", value)
+		}
+		o.QPS = float32(qps)
+		return nil
+	})
+	fs.IntVar(&o.Burst, "burst", 0, "maximum burst of queries to the API server (default 10)")
+}
+
+// Resolved is a rest.Config together with where it came from
+type Resolved struct {
+	Config *rest.Config
+	// Context is the kubeconfig context in use, empty for in-cluster config
+	Context string
+}
+
+// sources holds the functions used to obtain a rest.Config. They are fields
+// rather than direct calls so tests can swap in fake sources.
+type sources struct {
+	inPod      func() bool
+	inCluster  func() (*rest.Config, error)
+	kubeconfig func(rules *clientcmd.ClientConfigLoadingRules, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig
+}
+
+// defaultSources returns the config sources backed by client-go
+func defaultSources() sources {
+	return sources{
+		inPod: func() bool {
+			return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+		},
+		inCluster: rest.InClusterConfig,
+		kubeconfig: func(rules *clientcmd.ClientConfigLoadingRules, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+			return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+		},
+	}
+}
+
+// BuildConfig returns the in-cluster config when running inside a pod, and
+// otherwise the config from kubeconfigPath (or the default kubeconfig files
+// when empty), falling back to in-cluster config when no kubeconfig exists.
+func BuildConfig(kubeconfigPath string) (*rest.Config, error) {
+	resolved, err := Build(Options{Kubeconfig: kubeconfigPath})
+	if err != nil {
+		return nil, err
+	}
+	return resolved.Config, nil
+}
+
+// Build resolves a rest.Config from opts, see build for the strategies
+// tried, and applies the rate limits and UserAgent
+func Build(opts Options) (*Resolved, error) {
+	resolved, err := build(defaultSources(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.QPS > 0 {
+		resolved.Config.QPS = opts.QPS
+	}
+	if opts.Burst > 0 {
+		resolved.Config.Burst = opts.Burst
+	}
+	resolved.Config.UserAgent = UserAgent()
+	return resolved, nil
+}
+
+// loadingRules returns the default kubeconfig loading rules (KUBECONFIG,
+// including colon-separated lists, then ~/.kube/config), overridden by an
+// explicit path when one is given.
+func loadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfigPath
+	return rules
+}
+
+// kubeconfigFiles returns the kubeconfig files the loading rules would read
+// that actually exist on disk.
+func kubeconfigFiles(rules *clientcmd.ClientConfigLoadingRules) []string {
+	candidates := rules.Precedence
+	if rules.ExplicitPath != "" {
+		candidates = []string{rules.ExplicitPath}
+	}
+	var files []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// build resolves a rest.Config using the following strategies:
+//   - InCluster set: in-cluster config only
+//   - running in a pod without an explicit kubeconfig or context: in-cluster
+//     config, falling back to the kubeconfig when that fails
+//   - no kubeconfig file found: in-cluster config
+//   - otherwise: the merged kubeconfig, using Context or the current-context
+//
+// When every attempted strategy fails, the returned error lists each of them.
+func build(sources sources, opts Options) (*Resolved, error) {
+	if opts.InCluster {
+		config, err := sources.inCluster()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build in-cluster config (forced by --in-cluster): %w", err)
+		}
+		return &Resolved{Config: config}, nil
+	}
+
+	var attempts []string
+	triedInCluster := false
+	if sources.inPod() && opts.Kubeconfig == "" && opts.Context == "" {
+		config, err := sources.inCluster()
+		if err == nil {
+			return &Resolved{Config: config}, nil
+		}
+		triedInCluster = true
+		attempts = append(attempts, fmt.Sprintf("in-cluster: %v", err))
+	}
+
+	rules := loadingRules(opts.Kubeconfig)
+	if len(kubeconfigFiles(rules)) > 0 {
+		return buildKubeconfig(sources, rules, opts.Context)
+	}
+
+	if opts.Kubeconfig != "" {
+		attempts = append(attempts, fmt.Sprintf("kubeconfig: %q does not exist", opts.Kubeconfig))
+	} else {
+		attempts = append(attempts, fmt.Sprintf("kubeconfig: none found in %s", strings.Join(rules.Precedence, ", ")))
+	}
+	if opts.Context != "" {
+		return nil, fmt.Errorf("context %q requested but no kubeconfig is available (%s)", opts.Context, attempts[len(attempts)-1])
+	}
+
+	if !triedInCluster {
+		config, err := sources.inCluster()
+		if err == nil {
+			return &Resolved{Config: config}, nil
+		}
+		attempts = append(attempts, fmt.Sprintf("in-cluster: %v", err))
+	}
+	return nil, errors.New("unable to build client config, tried:\n  " + strings.Join(attempts, "\n  "))
+}
+
+// buildKubeconfig builds a rest.Config from the merged kubeconfig files.
+// A context that does not exist is reported together with the available ones.
+func buildKubeconfig(sources sources, rules *clientcmd.ClientConfigLoadingRules, contextName string) (*Resolved, error) {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	clientConfig := sources.kubeconfig(rules, overrides)
+
+	raw, err := clientConfig.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if contextName == "" {
+		contextName = raw.CurrentContext
+	}
+	if _, ok := raw.Contexts[contextName]; !ok {
+		available := make([]string, 0, len(raw.Contexts))
+		for name := range raw.Contexts {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		if contextName == "" {
+			return nil, fmt.Errorf("kubeconfig has no current-context, select one with --context (available contexts: %s)", strings.Join(available, ", "))
+		}
+		return nil, fmt.Errorf("context %q not found in kubeconfig (available contexts: %s)", contextName, strings.Join(available, ", "))
+	}
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for context %q: %w", contextName, err)
+	}
+	return &Resolved{Config: config, Context: contextName}, nil
+}
+
+// Contexts returns the sorted names of the contexts in the kubeconfig that
+// opts would load
+func Contexts(opts Options) ([]string, error) {
+	rules := loadingRules(opts.Kubeconfig)
+	if len(kubeconfigFiles(rules)) == 0 {
+		return nil, errors.New("no kubeconfig found to read contexts from")
+	}
+	raw, err := defaultSources().kubeconfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	names := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
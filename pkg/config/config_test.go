@@ -0,0 +1,282 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://dev.example.com
+- name: prod-cluster
+  cluster:
+    server: https://prod.example.com
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: admin
+- name: prod
+  context:
+    cluster: prod-cluster
+    user: admin
+users:
+- name: admin
+  user:
+    token: secret
+`
+
+// writeKubeconfig writes content to a kubeconfig file in a temp directory
+func writeKubeconfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	return path
+}
+
+// fakeSources returns config sources with a fake in-cluster strategy that
+// records whether it was used.
+func fakeSources(inPod bool, inClusterErr error, usedInCluster *bool) sources {
+	sources := defaultSources()
+	sources.inPod = func() bool { return inPod }
+	sources.inCluster = func() (*rest.Config, error) {
+		*usedInCluster = true
+		if inClusterErr != nil {
+			return nil, inClusterErr
+		}
+		return &rest.Config{Host: "https://in-cluster"}, nil
+	}
+	return sources
+}
+
+func TestBuildConfig(t *testing.T) {
+	errNotInCluster := errors.New("unable to load in-cluster configuration")
+	kubeconfig := writeKubeconfig(t, "config", testKubeconfig)
+
+	tests := []struct {
+		name          string
+		opts          Options
+		envKubeconfig string
+		inPod         bool
+		inClusterErr  error
+		wantHost      string
+		wantContext   string
+		wantInCluster bool
+		wantErr       []string
+	}{
+		{
+			name:        "explicit kubeconfig uses current-context",
+			opts:        Options{Kubeconfig: kubeconfig},
+			wantHost:    "https://dev.example.com",
+			wantContext: "dev",
+		},
+		{
+			name:        "explicit context",
+			opts:        Options{Kubeconfig: kubeconfig, Context: "prod"},
+			wantHost:    "https://prod.example.com",
+			wantContext: "prod",
+		},
+		{
+			name:          "KUBECONFIG list is honored",
+			envKubeconfig: filepath.Join(t.TempDir(), "missing") + string(os.PathListSeparator) + kubeconfig,
+			opts:          Options{Context: "prod"},
+			wantHost:      "https://prod.example.com",
+			wantContext:   "prod",
+		},
+		{
+			name:    "unknown context lists available contexts",
+			opts:    Options{Kubeconfig: kubeconfig, Context: "staging"},
+			wantErr: []string{`context "staging" not found`, "dev, prod"},
+		},
+		{
+			name:          "no kubeconfig falls back to in-cluster",
+			envKubeconfig: filepath.Join(t.TempDir(), "missing"),
+			wantHost:      "https://in-cluster",
+			wantInCluster: true,
+		},
+		{
+			name:          "missing explicit file falls back to in-cluster",
+			opts:          Options{Kubeconfig: filepath.Join(t.TempDir(), "missing")},
+			wantHost:      "https://in-cluster",
+			wantInCluster: true,
+		},
+		{
+			name:          "forced in-cluster ignores kubeconfig",
+			opts:          Options{Kubeconfig: kubeconfig, InCluster: true},
+			wantHost:      "https://in-cluster",
+			wantInCluster: true,
+		},
+		{
+			name:          "all strategies fail",
+			opts:          Options{Kubeconfig: "/nonexistent/config"},
+			inClusterErr:  errNotInCluster,
+			wantInCluster: true,
+			wantErr:       []string{`kubeconfig: "/nonexistent/config" does not exist`, "in-cluster: " + errNotInCluster.Error()},
+		},
+		{
+			name:          "in a pod in-cluster config is preferred",
+			envKubeconfig: kubeconfig,
+			inPod:         true,
+			wantHost:      "https://in-cluster",
+			wantInCluster: true,
+		},
+		{
+			name:          "in a pod falls back to the kubeconfig",
+			envKubeconfig: kubeconfig,
+			inPod:         true,
+			inClusterErr:  errNotInCluster,
+			wantHost:      "https://dev.example.com",
+			wantContext:   "dev",
+			wantInCluster: true,
+		},
+		{
+			name:        "in a pod an explicit kubeconfig wins",
+			opts:        Options{Kubeconfig: kubeconfig},
+			inPod:       true,
+			wantHost:    "https://dev.example.com",
+			wantContext: "dev",
+		},
+		{
+			name:          "in a pod both strategies fail",
+			envKubeconfig: filepath.Join(t.TempDir(), "missing"),
+			inPod:         true,
+			inClusterErr:  errNotInCluster,
+			wantInCluster: true,
+			wantErr:       []string{"in-cluster: " + errNotInCluster.Error(), "kubeconfig: none found"},
+		},
+		{
+			name:          "forced in-cluster fails",
+			opts:          Options{InCluster: true},
+			inClusterErr:  errNotInCluster,
+			wantInCluster: true,
+			wantErr:       []string{"--in-cluster"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.envKubeconfig)
+
+			var usedInCluster bool
+			resolved, err := build(fakeSources(tt.inPod, tt.inClusterErr, &usedInCluster), tt.opts)
+
+			if usedInCluster != tt.wantInCluster {
+				t.Errorf("in-cluster used = %v, want %v", usedInCluster, tt.wantInCluster)
+			}
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatalf("expected error, got config for %q", resolved.Config.Host)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not mention %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved.Config.Host != tt.wantHost {
+				t.Errorf("host = %q, want %q", resolved.Config.Host, tt.wantHost)
+			}
+			if resolved.Context != tt.wantContext {
+				t.Errorf("context = %q, want %q", resolved.Context, tt.wantContext)
+			}
+		})
+	}
+}
+
+func TestBuildConfigEnvironment(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, "config", testKubeconfig)
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	t.Run("outside a pod", func(t *testing.T) {
+		t.Setenv("KUBERNETES_SERVICE_HOST", "")
+		if defaultSources().inPod() {
+			t.Fatal("detected a pod without KUBERNETES_SERVICE_HOST")
+		}
+		config, err := BuildConfig("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Host != "https://dev.example.com" {
+			t.Errorf("host = %q, want the kubeconfig server", config.Host)
+		}
+	})
+
+	t.Run("inside a pod without a service account", func(t *testing.T) {
+		// rest.InClusterConfig fails without the service account token, so
+		// the kubeconfig is used instead
+		t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+		t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+		if !defaultSources().inPod() {
+			t.Fatal("KUBERNETES_SERVICE_HOST did not signal a pod")
+		}
+		config, err := BuildConfig("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.Host != "https://dev.example.com" {
+			t.Errorf("host = %q, want the kubeconfig server", config.Host)
+		}
+	})
+}
+
+func TestBuildRateLimits(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, "config", testKubeconfig)
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	var opts Options
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.AddFlags(fs)
+	if err := fs.Parse([]string{"--kubeconfig", kubeconfig, "--qps", "50", "--burst", "100"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	resolved, err := Build(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Config.QPS != 50 || resolved.Config.Burst != 100 {
+		t.Errorf("QPS/Burst = %v/%v, want 50/100", resolved.Config.QPS, resolved.Config.Burst)
+	}
+	if err := fs.Parse([]string{"--qps", "-1"}); err == nil {
+		t.Error("expected a negative --qps to be rejected")
+	}
+}
+
+func TestBuildUserAgent(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, "config", testKubeconfig)
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	defer func(v string) { Version = v }(Version)
+	Version = "v1.2.3"
+
+	resolved, err := Build(Options{Kubeconfig: kubeconfig})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Base(os.Args[0]) + "/v1.2.3 (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
+	if resolved.Config.UserAgent != want {
+		t.Errorf("UserAgent = %q, want %q", resolved.Config.UserAgent, want)
+	}
+
+	Version = ""
+	if got := UserAgent(); !strings.HasPrefix(got, filepath.Base(os.Args[0])+"/") || strings.Contains(got, "/ (") {
+		t.Errorf("UserAgent() = %q, want a fallback version", got)
+	}
+}
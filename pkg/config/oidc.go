@@ -0,0 +1,293 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// oidcProvider is the name of the oidc auth provider in a kubeconfig
+const oidcProvider = "oidc"
+
+// Keys of the oidc auth provider config
+const (
+	oidcIssuerURL    = "idp-issuer-url"
+	oidcClientID     = "client-id"
+	oidcClientSecret = "client-secret"
+	oidcIDToken      = "id-token"
+	oidcRefreshToken = "refresh-token"
+)
+
+// OIDCRefresher keeps the bearer token of a kubeconfig user whose tokens
+// expire: one using the oidc auth provider or an exec credential plugin.
+// Wrap installs it on a rest.Config, where it authenticates every request
+// and, when the API server answers 401, fetches a fresh token and retries
+// the request once.
+type OIDCRefresher struct {
+	// refresh obtains a new token: an exec plugin run or an OIDC refresh
+	// token grant
+	refresh func(ctx context.Context) (string, error)
+
+	mu    sync.Mutex
+	token string
+}
+
+// newOIDCRefresher returns an OIDCRefresher for authInfo, or nil when the
+// user authenticates some other way
+func newOIDCRefresher(authInfo *clientcmdapi.AuthInfo) *OIDCRefresher {
+	switch {
+	case authInfo == nil:
+		return nil
+	case authInfo.Exec != nil:
+		plugin := authInfo.Exec
+		return &OIDCRefresher{refresh: func(ctx context.Context) (string, error) {
+			return runExecPlugin(ctx, plugin)
+		}}
+	case authInfo.AuthProvider != nil && authInfo.AuthProvider.Name == oidcProvider:
+		provider := make(map[string]string, len(authInfo.AuthProvider.Config))
+		for k, v := range authInfo.AuthProvider.Config {
+			provider[k] = v
+		}
+		return &OIDCRefresher{
+			token: provider[oidcIDToken],
+			refresh: func(ctx context.Context) (string, error) {
+				return refreshOIDCToken(ctx, provider)
+			},
+		}
+	}
+	return nil
+}
+
+// current returns the token to send, fetching one when there is none yet
+func (r *OIDCRefresher) current(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != "" {
+		return r.token, nil
+	}
+	token, err := r.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.token = token
+	return token, nil
+}
+
+// renew replaces stale with a fresh token. When another request already
+// renewed it, that token is returned instead of fetching yet another one.
+func (r *OIDCRefresher) renew(ctx context.Context, stale string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != stale {
+		return r.token, nil
+	}
+	token, err := r.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	r.token = token
+	return token, nil
+}
+
+// Wrap makes config authenticate with the refresher's token. The config
+// must not carry credentials of its own.
+func (r *OIDCRefresher) Wrap(config *rest.Config) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &refreshingRoundTripper{refresher: r, next: rt}
+	})
+}
+
+// refreshingRoundTripper sets the bearer token of a refresher and retries
+// a request answered with 401 once with a renewed token
+type refreshingRoundTripper struct {
+	refresher *OIDCRefresher
+	next      http.RoundTripper
+}
+
+func (rt *refreshingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.refresher.current(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("getting a token: %w", err)
+	}
+	resp, err := rt.next.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// A body that was already sent can only be replayed through GetBody
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	fresh, err := rt.refresher.renew(req.Context(), token)
+	if err != nil {
+		return resp, nil
+	}
+	retry := withBearer(req, fresh)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return rt.next.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req authenticated with token
+func withBearer(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// runExecPlugin runs an exec credential plugin the way kubectl does,
+// non-interactively, and returns the token of the ExecCredential it prints
+func runExecPlugin(ctx context.Context, plugin *clientcmdapi.ExecConfig) (string, error) {
+	info, err := json.Marshal(map[string]any{
+		"apiVersion": plugin.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]any{"interactive": false},
+	})
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, plugin.Command, plugin.Args...)
+	cmd.Env = append(os.Environ(), "KUBERNETES_EXEC_INFO="+string(info))
+	for _, env := range plugin.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("exec plugin %s: %w: %s", plugin.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var credential struct {
+		Status struct {
+			Token string `json:"token"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(out, &credential); err != nil {
+		return "", fmt.Errorf("exec plugin %s: decoding the ExecCredential: %w", plugin.Command, err)
+	}
+	if credential.Status.Token == "" {
+		return "", fmt.Errorf("exec plugin %s returned no token", plugin.Command)
+	}
+	return credential.Status.Token, nil
+}
+
+// refreshOIDCToken trades the refresh token of an oidc auth provider config
+// for a new ID token at the token endpoint the issuer advertises. A rotated
+// refresh token is kept in provider for the next refresh, but not written
+// back to the kubeconfig.
+func refreshOIDCToken(ctx context.Context, provider map[string]string) (string, error) {
+	issuer := strings.TrimSuffix(provider[oidcIssuerURL], "/")
+	if issuer == "" || provider[oidcRefreshToken] == "" {
+		return "", fmt.Errorf("oidc auth provider needs %s and %s to refresh the token", oidcIssuerURL, oidcRefreshToken)
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", err
+	}
+	if err := doJSON(req, &discovery); err != nil {
+		return "", fmt.Errorf("oidc discovery: %w", err)
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("oidc discovery: issuer %s advertises no token_endpoint", issuer)
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {provider[oidcRefreshToken]},
+		"client_id":     {provider[oidcClientID]},
+	}
+	if secret := provider[oidcClientSecret]; secret != "" {
+		form.Set("client_secret", secret)
+	}
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var tokens struct {
+		IDToken      string `json:"id_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doJSON(req, &tokens); err != nil {
+		return "", fmt.Errorf("oidc token refresh: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return "", errors.New("oidc token refresh: the response has no id_token")
+	}
+	if tokens.RefreshToken != "" {
+		provider[oidcRefreshToken] = tokens.RefreshToken
+	}
+	return tokens.IDToken, nil
+}
+
+// doJSON sends req and decodes a 200 response into v
+func doJSON(req *http.Request, v any) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// NewOIDCRefreshingConfig builds the rest.Config of the current context of
+// the kubeconfig at kubeconfigPath (or the default kubeconfig files when
+// empty). When the context's user authenticates through the oidc auth
+// provider or an exec credential plugin, an OIDCRefresher takes over its
+// credentials so tokens that expire mid-run are refreshed; other users get
+// the plain kubeconfig config.
+func NewOIDCRefreshingConfig(kubeconfigPath string) (*rest.Config, error) {
+	rules := loadingRules(kubeconfigPath)
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	kubeContext, ok := raw.Contexts[raw.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current-context %q not found in kubeconfig", raw.CurrentContext)
+	}
+	authInfo := raw.AuthInfos[kubeContext.AuthInfo]
+	refresher := newOIDCRefresher(authInfo)
+	if refresher != nil {
+		// client-go no longer ships the oidc auth provider, and its exec
+		// support would run the plugin a second time on a 401
+		user := authInfo.DeepCopy()
+		user.AuthProvider = nil
+		user.Exec = nil
+		raw.AuthInfos[kubeContext.AuthInfo] = user
+	}
+
+	config, err := clientcmd.NewDefaultClientConfig(raw, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for context %q: %w", raw.CurrentContext, err)
+	}
+	if refresher != nil {
+		refresher.Wrap(config)
+	}
+	config.UserAgent = UserAgent()
+	return config, nil
+}
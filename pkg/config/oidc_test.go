@@ -0,0 +1,168 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// expiringAPIServer returns an API server that only accepts the bearer
+// token "fresh", answering 401 like a server seeing an expired token
+// otherwise, and counts the requests it got
+func expiringAPIServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v1.PodList{TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// listPods lists pods through a config built by NewOIDCRefreshingConfig
+func listPods(t *testing.T, kubeconfig string) {
+	t.Helper()
+	config, err := NewOIDCRefreshingConfig(kubeconfig)
+	if err != nil {
+		t.Fatalf("NewOIDCRefreshingConfig() error = %v", err)
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CoreV1().Pods("default").List(t.Context(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("List() error = %v, want the retry with a fresh token to succeed", err)
+	}
+}
+
+func TestOIDCRefreshingConfigExecPlugin(t *testing.T) {
+	var requests atomic.Int32
+	server := expiringAPIServer(t, &requests)
+
+	// The plugin hands out an expired token on its first run
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "credentials.sh")
+	script := `#!/bin/sh
+runs=$(cat "$RUNS_FILE" 2>/dev/null || echo 0)
+runs=$((runs + 1))
+echo $runs > "$RUNS_FILE"
+token=fresh
+[ $runs -eq 1 ] && token=expired
+printf '{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"%s"}}' $token
+`
+	if err := os.WriteFile(plugin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	runsFile := filepath.Join(dir, "runs")
+	kubeconfig := writeKubeconfig(t, "config", fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: %s
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: sso
+users:
+- name: sso
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: %s
+      interactiveMode: Never
+      env:
+      - name: RUNS_FILE
+        value: %s
+`, server.URL, plugin, runsFile))
+
+	listPods(t, kubeconfig)
+	if got := requests.Load(); got != 2 {
+		t.Errorf("API server got %d requests, want the expired one and the retry", got)
+	}
+	if runs, _ := os.ReadFile(runsFile); string(runs) != "2\n" {
+		t.Errorf("plugin ran %q times, want 2", runs)
+	}
+}
+
+func TestOIDCRefreshingConfigAuthProvider(t *testing.T) {
+	var requests atomic.Int32
+	server := expiringAPIServer(t, &requests)
+
+	var refreshes atomic.Int32
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"token_endpoint": issuer.URL + "/token"})
+		case "/token":
+			refreshes.Add(1)
+			if r.PostFormValue("grant_type") != "refresh_token" || r.PostFormValue("refresh_token") != "refresh-1" || r.PostFormValue("client_id") != "cli" {
+				http.Error(w, "bad grant", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"id_token": "fresh", "refresh_token": "refresh-2"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer issuer.Close()
+
+	kubeconfig := writeKubeconfig(t, "config", fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: dev
+  cluster:
+    server: %s
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: sso
+users:
+- name: sso
+  user:
+    auth-provider:
+      name: oidc
+      config:
+        idp-issuer-url: %s
+        client-id: cli
+        id-token: expired
+        refresh-token: refresh-1
+`, server.URL, issuer.URL))
+
+	listPods(t, kubeconfig)
+	if got := requests.Load(); got != 2 {
+		t.Errorf("API server got %d requests, want the expired one and the retry", got)
+	}
+	if got := refreshes.Load(); got != 1 {
+		t.Errorf("got %d token refreshes, want 1", got)
+	}
+}
+
+func TestOIDCRefreshingConfigStaticToken(t *testing.T) {
+	kubeconfig := writeKubeconfig(t, "config", testKubeconfig)
+	config, err := NewOIDCRefreshingConfig(kubeconfig)
+	if err != nil {
+		t.Fatalf("NewOIDCRefreshingConfig() error = %v", err)
+	}
+	if config.BearerToken != "secret" || config.WrapTransport != nil {
+		t.Errorf("static token config = token %q, wrapped %t; want the plain kubeconfig config", config.BearerToken, config.WrapTransport != nil)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout bounds the API calls of a command unless --timeout is set
+const DefaultTimeout = 30 * time.Second
+
+// AddTimeoutFlag registers --timeout on fs
+func (o *Options) AddTimeoutFlag(fs *flag.FlagSet) {
+	fs.DurationVar(&o.Timeout, "timeout", DefaultTimeout, "how long the API calls may take in total, e.g. 10s or 5m (0 for no timeout)")
+}
+
+// BuildContext returns the context the API calls of a command run under,
+// bounded by timeout, or without a deadline when timeout is 0
+func BuildContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithTimeout(context.Background(), timeout)
+}
+
+// WithTimeout is BuildContext for a context derived from parent, e.g. one
+// cancelled on Ctrl-C
+func WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// TimeoutError reports that an API call ran into the --timeout deadline
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	seconds := strconv.FormatFloat(e.Timeout.Seconds(), 'f', -1, 64)
+	if seconds == "1" {
+		return "API call timed out after 1 second"
+	}
+	return fmt.Sprintf("API call timed out after %s seconds", seconds)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WrapTimeout returns err, replaced by a *TimeoutError when it was caused
+// by the deadline of a context from BuildContext rather than by the API
+// server
+func WrapTimeout(err error, timeout time.Duration) error {
+	if timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Timeout: timeout, Err: err}
+	}
+	return err
+}
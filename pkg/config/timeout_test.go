@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestBuildContext(t *testing.T) {
+	ctx, cancel := BuildContext(0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("a zero timeout should not set a deadline")
+	}
+
+	ctx, cancel = BuildContext(time.Minute)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("deadline = %v, %t, want within a minute", deadline, ok)
+	}
+}
+
+func TestWrapTimeout(t *testing.T) {
+	// An API server that never answers
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := 50 * time.Millisecond
+	ctx, cancel := BuildContext(timeout)
+	defer cancel()
+	_, err = client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	got := WrapTimeout(err, timeout)
+	if want := "API call timed out after 0.05 seconds"; got == nil || got.Error() != want {
+		t.Fatalf("WrapTimeout() = %v, want %q", got, want)
+	}
+	if !errors.Is(got, context.DeadlineExceeded) {
+		t.Error("WrapTimeout() should wrap the deadline error")
+	}
+
+	tests := map[time.Duration]string{
+		time.Second:      "API call timed out after 1 second",
+		30 * time.Second: "API call timed out after 30 seconds",
+		2 * time.Minute:  "API call timed out after 120 seconds",
+	}
+	for timeout, want := range tests {
+		if got := (&TimeoutError{Timeout: timeout}).Error(); got != want {
+			t.Errorf("TimeoutError{%s} = %q, want %q", timeout, got, want)
+		}
+	}
+
+	// Without a timeout the deadline came from elsewhere
+	if got := WrapTimeout(context.DeadlineExceeded, 0); got != context.DeadlineExceeded {
+		t.Errorf("WrapTimeout() without a timeout = %v, want the error unchanged", got)
+	}
+	if got := WrapTimeout(nil, timeout); got != nil {
+		t.Errorf("WrapTimeout(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// scheduleLayout is the UTC layout the controller parses Spec.Schedule with
+const scheduleLayout = "2006-01-02T15:04:05Z"
+
+// dryRunServer is the --dry-run value that validates a create on the API
+// server without persisting it
+const dryRunServer = "server"
+
+// newAt builds the At to create, checking that schedule parses with the
+// controller's layout so a typo fails here rather than in the controller
+func newAt(namespace, name, schedule, command string) (*cnatv1alpha1.At, error) {
+	switch {
+	case name == "":
+		return nil, errors.New("--name is required")
+	case schedule == "":
+		return nil, errors.New("--schedule is required")
+	case command == "":
+		return nil, errors.New("--command is required")
+	}
+	if _, err := time.Parse(scheduleLayout, schedule); err != nil {
+		return nil, fmt.Errorf("--schedule %q is not a UTC time like %s: %w", schedule, scheduleLayout, err)
+	}
+	return &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: schedule, Command: command},
+	}, nil
+}
+
+// createAt creates at, only validating it on the server when dryRun is set
+func createAt(ctx context.Context, client clientset.Interface, at *cnatv1alpha1.At, dryRun bool) (*cnatv1alpha1.At, error) {
+	opts := metav1.CreateOptions{}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	created, err := client.CnatV1alpha1().Ats(at.Namespace).Create(ctx, at, opts)
+	if apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("At %q already exists in namespace '%s'; delete it first or pick another --name", at.Name, at.Namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating At %q: %w", at.Name, err)
+	}
+	return created, nil
+}
+
+// printCreated prints the name of the created At and how long until it runs
+func printCreated(out io.Writer, at *cnatv1alpha1.At, dryRun bool, now time.Time) {
+	suffix := ""
+	if dryRun {
+		suffix = " (server dry run)"
+	}
+	fmt.Fprintf(out, "at/%s created%s\n", at.Name, suffix)
+
+	// The schedule was validated before the create
+	schedule, _ := time.Parse(scheduleLayout, at.Spec.Schedule)
+	if remaining := schedule.Sub(now); remaining > 0 {
+		fmt.Fprintf(out, "Runs in %s, at %s\n", duration.HumanDuration(remaining), at.Spec.Schedule)
+	} else {
+		fmt.Fprintf(out, "Schedule %s is %s in the past, so it runs right away\n", at.Spec.Schedule, duration.HumanDuration(-remaining))
+	}
+}
+
+// runCreate implements the create subcommand
+func runCreate(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace to create the At resource in")
+	name := fs.String("name", "", "name of the At resource")
+	schedule := fs.String("schedule", "", "when to run the command, as a UTC time like "+scheduleLayout)
+	command := fs.String("command", "", "command to run in a Bash shell")
+	dryRun := fs.String("dry-run", "", "set to server to only validate the At on the API server without creating it")
+	diff := fs.Bool("diff", false, "print a diff of the labels, annotations and spec of the At against the live one, if any, before creating it")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dryRun != "" && *dryRun != dryRunServer {
+		return fmt.Errorf("--dry-run must be %s, got %q", dryRunServer, *dryRun)
+	}
+	at, err := newAt(*namespace, *name, *schedule, *command)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+
+	var dynamicClient dynamic.Interface
+	if *diff {
+		if dynamicClient, err = dynamic.NewForConfig(resolved.Config); err != nil {
+			return fmt.Errorf("error creating dynamic client: %w", err)
+		}
+	}
+
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	return output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		if dynamicClient != nil {
+			if err := diffCreate(ctx, out, dynamicClient, at); err != nil {
+				return config.WrapTimeout(err, configOpts.Timeout)
+			}
+		}
+		created, err := createAt(ctx, client, at, *dryRun == dryRunServer)
+		if err != nil {
+			return config.WrapTimeout(err, configOpts.Timeout)
+		}
+		printCreated(out, created, *dryRun == dryRunServer, time.Now())
+		return nil
+	})
+}
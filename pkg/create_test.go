@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func TestNewAt(t *testing.T) {
+	at, err := newAt("jobs", "backup", "2026-07-03T02:00:00Z", "echo backup")
+	if err != nil {
+		t.Fatalf("newAt() error = %v", err)
+	}
+	if at.Namespace != "jobs" || at.Name != "backup" || at.Spec.Schedule != "2026-07-03T02:00:00Z" || at.Spec.Command != "echo backup" {
+		t.Errorf("newAt() = %+v", at)
+	}
+
+	tests := []struct {
+		name, schedule, command, want string
+	}{
+		{"", "2026-07-03T02:00:00Z", "echo", "--name is required"},
+		{"backup", "", "echo", "--schedule is required"},
+		{"backup", "2026-07-03T02:00:00Z", "", "--command is required"},
+		// The controller only understands UTC times without an offset
+		{"backup", "2026-07-03T02:00:00+02:00", "echo", "is not a UTC time"},
+		{"backup", "tomorrow", "echo", "is not a UTC time"},
+	}
+	for _, tt := range tests {
+		if _, err := newAt("jobs", tt.name, tt.schedule, tt.command); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("newAt(%q, %q, %q) error = %v, want %q", tt.name, tt.schedule, tt.command, err, tt.want)
+		}
+	}
+}
+
+func TestCreateAt(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	at, err := newAt("jobs", "backup", "2026-07-03T02:00:00Z", "echo backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := createAt(context.Background(), client, at, false)
+	if err != nil {
+		t.Fatalf("createAt() error = %v", err)
+	}
+	if created.Name != "backup" {
+		t.Errorf("created %q, want backup", created.Name)
+	}
+	if _, err := client.CnatV1alpha1().Ats("jobs").Get(context.Background(), "backup", metav1.GetOptions{}); err != nil {
+		t.Errorf("the At was not created: %v", err)
+	}
+
+	_, err = createAt(context.Background(), client, at, false)
+	if want := `At "backup" already exists in namespace 'jobs'`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("second createAt() error = %v, want %q", err, want)
+	}
+}
+
+func TestPrintCreated(t *testing.T) {
+	now := time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)
+	at, err := newAt("jobs", "backup", "2026-07-03T02:30:00Z", "echo backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	printCreated(&out, at, false, now)
+	if want := "at/backup created\nRuns in 150m, at 2026-07-03T02:30:00Z\n"; out.String() != want {
+		t.Errorf("printCreated() = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	printCreated(&out, at, true, now.Add(3*time.Hour))
+	if want := "at/backup created (server dry run)\nSchedule 2026-07-03T02:30:00Z is 30m in the past, so it runs right away\n"; out.String() != want {
+		t.Errorf("printCreated() = %q, want %q", out.String(), want)
+	}
+}
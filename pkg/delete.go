@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// Values of --cascade, as in kubectl delete
+const (
+	cascadeBackground = "background"
+	cascadeForeground = "foreground"
+	cascadeOrphan     = "orphan"
+)
+
+// propagationPolicy maps a --cascade value to the propagation policy of
+// the deletion: background and foreground garbage-collect the pods an At
+// owns, after or before the At itself is gone, while orphan keeps them
+func propagationPolicy(cascade string) (metav1.DeletionPropagation, error) {
+	switch cascade {
+	case cascadeBackground:
+		return metav1.DeletePropagationBackground, nil
+	case cascadeForeground:
+		return metav1.DeletePropagationForeground, nil
+	case cascadeOrphan:
+		return metav1.DeletePropagationOrphan, nil
+	}
+	return "", fmt.Errorf("unknown --cascade %q (want background, foreground or orphan)", cascade)
+}
+
+// confirmDeleteAll asks whether to delete every At in names and reads the
+// answer from in. Only y or yes confirms.
+func confirmDeleteAll(in io.Reader, out io.Writer, namespace string, names []string) (bool, error) {
+	fmt.Fprintf(out, "Delete all %d At resources in namespace '%s' (%s)? [y/N] ", len(names), namespace, strings.Join(names, ", "))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("reading confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// deleteAts deletes the named At resources one by one, printing a line per
+// name so a missing At doesn't hide the outcome of the others. It returns
+// how many deletions failed.
+func deleteAts(ctx context.Context, out io.Writer, client clientset.Interface, namespace string, names []string, policy metav1.DeletionPropagation) int {
+	opts := metav1.DeleteOptions{PropagationPolicy: &policy}
+	failed := 0
+	for _, name := range names {
+		err := client.CnatV1alpha1().Ats(namespace).Delete(ctx, name, opts)
+		switch {
+		case apierrors.IsNotFound(err):
+			fmt.Fprintf(out, "at/%s not found in namespace '%s'\n", name, namespace)
+			failed++
+		case err != nil:
+			fmt.Fprintf(out, "at/%s not deleted: %v\n", name, err)
+			failed++
+		default:
+			fmt.Fprintf(out, "at/%s deleted\n", name)
+		}
+	}
+	return failed
+}
+
+// runDelete implements the delete subcommand
+func runDelete(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace to delete At resources from")
+	all := fs.Bool("all", false, "delete every At resource in the namespace, asking for confirmation first")
+	var selector selectorOptions
+	selector.AddFlags(fs)
+	yes := fs.Bool("yes", false, "with --all or --selector, delete without asking for confirmation")
+	cascade := fs.String("cascade", cascadeBackground, "what happens to the pods an At owns: background or foreground deletes them with it, orphan keeps them")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	names := fs.Args()
+	// --selector deletes every matching At, like --all restricted to it
+	matchAll := *all || selector.Selector != ""
+	switch {
+	case matchAll && len(names) > 0:
+		return errors.New("--all and --selector cannot be combined with At names")
+	case !matchAll && len(names) == 0:
+		return errors.New("usage: delete [flags] NAME... or delete --all|--selector SELECTOR")
+	case *yes && !matchAll:
+		return errors.New("--yes requires --all or --selector")
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+	policy, err := propagationPolicy(*cascade)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+
+	// The outcome of every At is written, even when some failed
+	var failed int
+	err = output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		if matchAll {
+			// The confirmation prompt must not eat into the deletions' timeout
+			ctx, cancel := config.BuildContext(configOpts.Timeout)
+			ats, err := client.CnatV1alpha1().Ats(*namespace).List(ctx, selector.ListOptions())
+			cancel()
+			if err != nil {
+				return fmt.Errorf("error listing At resources: %w", config.WrapTimeout(err, configOpts.Timeout))
+			}
+			if len(ats.Items) == 0 {
+				_, err := fmt.Fprintln(out, selector.notFound(*namespace))
+				return err
+			}
+			for _, at := range ats.Items {
+				names = append(names, at.Name)
+			}
+			if !*yes {
+				// The prompt goes to the terminal, not --output-file
+				ok, err := confirmDeleteAll(os.Stdin, os.Stdout, *namespace, names)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					_, err := fmt.Fprintln(out, "Nothing deleted")
+					return err
+				}
+			}
+		}
+
+		ctx, cancel := config.BuildContext(configOpts.Timeout)
+		defer cancel()
+		failed = deleteAts(ctx, out, client, *namespace, names, policy)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d At resources could not be deleted", failed, len(names))
+	}
+	return nil
+}
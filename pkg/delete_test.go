@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func TestPropagationPolicy(t *testing.T) {
+	tests := map[string]metav1.DeletionPropagation{
+		"background": metav1.DeletePropagationBackground,
+		"foreground": metav1.DeletePropagationForeground,
+		"orphan":     metav1.DeletePropagationOrphan,
+	}
+	for cascade, want := range tests {
+		if got, err := propagationPolicy(cascade); err != nil || got != want {
+			t.Errorf("propagationPolicy(%q) = %q, %v, want %q", cascade, got, err, want)
+		}
+	}
+	if _, err := propagationPolicy("true"); err == nil {
+		t.Error("propagationPolicy(true) succeeded, want an error")
+	}
+}
+
+func TestConfirmDeleteAll(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "": false} {
+		var out bytes.Buffer
+		got, err := confirmDeleteAll(strings.NewReader(answer), &out, "jobs", []string{"backup", "cleanup"})
+		if err != nil || got != want {
+			t.Errorf("confirmDeleteAll(%q) = %t, %v, want %t", answer, got, err, want)
+		}
+		if prompt := "Delete all 2 At resources in namespace 'jobs' (backup, cleanup)? [y/N] "; out.String() != prompt {
+			t.Errorf("prompt = %q, want %q", out.String(), prompt)
+		}
+	}
+}
+
+func TestDeleteAts(t *testing.T) {
+	at := func(name string) *cnatv1alpha1.At {
+		return &cnatv1alpha1.At{ObjectMeta: metav1.ObjectMeta{Namespace: "jobs", Name: name}}
+	}
+	client := fake.NewSimpleClientset(at("backup"), at("cleanup"))
+	var policies []metav1.DeletionPropagation
+	client.PrependReactor("delete", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		policies = append(policies, *action.(k8stesting.DeleteActionImpl).DeleteOptions.PropagationPolicy)
+		return false, nil, nil
+	})
+
+	var out bytes.Buffer
+	failed := deleteAts(context.Background(), &out, client, "jobs", []string{"backup", "missing", "cleanup"}, metav1.DeletePropagationOrphan)
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+	want := "at/backup deleted\nat/missing not found in namespace 'jobs'\nat/cleanup deleted\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+	for _, policy := range policies {
+		if policy != metav1.DeletePropagationOrphan {
+			t.Errorf("propagation policy = %q, want Orphan", policy)
+		}
+	}
+	ats, err := client.CnatV1alpha1().Ats("jobs").List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(ats.Items) != 0 {
+		t.Errorf("remaining At resources = %v, %v, want none", ats, err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/textdiff"
+)
+
+// atsResource is the At resource, for the dynamic client --diff reads live
+// Ats with
+var atsResource = cnatv1alpha1.SchemeGroupVersion.WithResource("ats")
+
+// liveAt returns the At as the server stores it, in JSON, or nil when it
+// doesn't exist. It is read with the dynamic client so that fields of the
+// controller's CRD the At types here lack are kept.
+func liveAt(ctx context.Context, client dynamic.Interface, namespace, name string) ([]byte, error) {
+	live, err := client.Resource(atsResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return live.MarshalJSON()
+}
+
+// diffYAML renders the labels, annotations and spec of an At in JSON, the
+// fields create and apply set, as YAML. It returns "" for a nil At.
+func diffYAML(data []byte) (string, error) {
+	if data == nil {
+		return "", nil
+	}
+	var at struct {
+		Metadata struct {
+			Labels      map[string]string `json:"labels,omitempty"`
+			Annotations map[string]string `json:"annotations,omitempty"`
+		} `json:"metadata"`
+		Spec map[string]interface{} `json:"spec,omitempty"`
+	}
+	if err := json.Unmarshal(data, &at); err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(at)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// writeAtDiff writes a unified diff from live, nil when the At doesn't
+// exist, to desired, both At objects in JSON. A missing At diffs from
+// /dev/null, so every line of desired is prefixed with +. Nothing is
+// written when they don't differ.
+func writeAtDiff(out io.Writer, name string, live, desired []byte) error {
+	from, err := diffYAML(live)
+	if err != nil {
+		return fmt.Errorf("rendering live at/%s: %w", name, err)
+	}
+	to, err := diffYAML(desired)
+	if err != nil {
+		return fmt.Errorf("rendering new at/%s: %w", name, err)
+	}
+	fromName := "live/" + name
+	if live == nil {
+		fromName = "/dev/null"
+	}
+	_, err = io.WriteString(out, textdiff.Unified(fromName, "new/"+name, from, to))
+	return err
+}
+
+// diffCreate writes the diff of creating at over the live At of its name
+func diffCreate(ctx context.Context, out io.Writer, client dynamic.Interface, at *cnatv1alpha1.At) error {
+	live, err := liveAt(ctx, client, at.Namespace, at.Name)
+	if err != nil {
+		return fmt.Errorf("error getting at/%s: %w", at.Name, err)
+	}
+	desired, err := json.Marshal(at)
+	if err != nil {
+		return err
+	}
+	return writeAtDiff(out, at.Name, live, desired)
+}
+
+// diffApply writes the diff of applying at. The apply sets only the fields
+// the manifest sets, which a JSON merge patch of the manifest over the live
+// At reproduces: CRDs merge maps and replace lists, having no strategic
+// merge.
+func diffApply(ctx context.Context, out io.Writer, client dynamic.Interface, at *atManifest) error {
+	live, err := liveAt(ctx, client, at.Namespace, at.Name)
+	if err != nil {
+		return fmt.Errorf("error getting at/%s: %w", at.Name, err)
+	}
+	desired, err := at.applyConfiguration()
+	if err != nil {
+		return err
+	}
+	if live != nil {
+		if desired, err = jsonpatch.MergePatch(live, desired); err != nil {
+			return fmt.Errorf("merging at/%s: %w", at.Name, err)
+		}
+	}
+	return writeAtDiff(out, at.Name, live, desired)
+}
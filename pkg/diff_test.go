@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+// liveCleanup is an At as the controller's CRD stores it, with fields the
+// At types here lack
+func liveCleanup() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": cnatv1alpha1.SchemeGroupVersion.String(),
+		"kind":       "At",
+		"metadata": map[string]interface{}{
+			"namespace": "batch",
+			"name":      "cleanup",
+			"labels":    map[string]interface{}{"owner": "alice"},
+		},
+		"spec": map[string]interface{}{
+			"schedule":   "0 2 * * *",
+			"command":    "echo old",
+			"recurring":  true,
+			"maxRetries": int64(1),
+		},
+	}}
+}
+
+func TestDiffApply(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), liveCleanup())
+	ats, err := decodeAts(strings.NewReader(testManifest), "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	for _, at := range ats {
+		if err := diffApply(context.Background(), &out, client, at); err != nil {
+			t.Fatalf("diffApply(%s) error = %v", at.Name, err)
+		}
+	}
+	// backup is new; cleanup keeps its owner label and recurring, which
+	// the manifest sets too
+	want := `--- /dev/null
++++ new/backup
+@@ -0,0 +1,6 @@
++metadata:
++  labels:
++    team: ops
++spec:
++  command: echo backup
++  schedule: "2026-07-03T02:00:00Z"
+--- live/cleanup
++++ new/cleanup
+@@ -2,7 +2,7 @@
+   labels:
+     owner: alice
+ spec:
+-  command: echo old
+-  maxRetries: 1
++  command: echo cleanup
++  maxRetries: 2
+   recurring: true
+   schedule: 0 2 * * *
+`
+	if out.String() != want {
+		t.Errorf("diff =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// Nothing is written once the live At matches
+	live := liveCleanup()
+	if err := unstructured.SetNestedField(live.Object, "echo cleanup", "spec", "command"); err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(live.Object, int64(2), "spec", "maxRetries"); err != nil {
+		t.Fatal(err)
+	}
+	client = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+	out.Reset()
+	if err := diffApply(context.Background(), &out, client, ats[1]); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("diff of an unchanged At =\n%s\nwant none", out.String())
+	}
+}
+
+func TestApplyAtsDiffDryRun(t *testing.T) {
+	client := fake.NewSimpleClientset(&cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "cleanup", Labels: map[string]string{"owner": "alice"}},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: "0 2 * * *", Command: "echo old"},
+	})
+	var dryRuns int
+	client.PrependReactor("patch", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchActionImpl)
+		if opts := patch.PatchOptions; len(opts.DryRun) != 1 || opts.DryRun[0] != metav1.DryRunAll {
+			t.Errorf("patch options = %+v, want a server dry run", opts)
+		}
+		dryRuns++
+		// The API server validates the apply without persisting it
+		return true, &cnatv1alpha1.At{ObjectMeta: metav1.ObjectMeta{Name: patch.GetName()}}, nil
+	})
+	ats, err := decodeAts(strings.NewReader(testManifest), "jobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := applyOptions{DryRun: true, Diff: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), liveCleanup())}
+	if failed := applyAts(context.Background(), &out, client, ats[1:], opts); failed != 0 {
+		t.Fatalf("%d At resources failed: %s", failed, out.String())
+	}
+	if !strings.HasPrefix(out.String(), "--- live/cleanup\n") || !strings.HasSuffix(out.String(), "+  command: echo cleanup\n+  maxRetries: 2\n   recurring: true\n   schedule: 0 2 * * *\nat/cleanup configured (server dry run)\n") {
+		t.Errorf("output =\n%s\nwant the diff followed by the dry-run outcome", out.String())
+	}
+	if dryRuns != 1 {
+		t.Errorf("got %d dry-run patches, want 1", dryRuns)
+	}
+	cleanup, err := client.CnatV1alpha1().Ats("batch").Get(context.Background(), "cleanup", metav1.GetOptions{})
+	if err != nil || cleanup.Spec.Command != "echo old" {
+		t.Errorf("live At = %+v, %v, want it unchanged by the dry run", cleanup, err)
+	}
+}
+
+func TestDiffCreate(t *testing.T) {
+	at, err := newAt("batch", "cleanup", "2026-07-04T02:00:00Z", "echo cleanup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating over an existing At shows everything it would replace
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), liveCleanup())
+	var out bytes.Buffer
+	if err := diffCreate(context.Background(), &out, client, at); err != nil {
+		t.Fatal(err)
+	}
+	want := `--- live/cleanup
++++ new/cleanup
+@@ -1,8 +1,4 @@
+-metadata:
+-  labels:
+-    owner: alice
++metadata: {}
+ spec:
+-  command: echo old
+-  maxRetries: 1
+-  recurring: true
+-  schedule: 0 2 * * *
++  command: echo cleanup
++  schedule: "2026-07-04T02:00:00Z"
+`
+	if out.String() != want {
+		t.Errorf("diff =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// A new At is all additions
+	out.Reset()
+	if err := diffCreate(context.Background(), &out, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), at); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")[3:] {
+		if !strings.HasPrefix(line, "+") {
+			t.Errorf("line %q of a new At's diff is not an addition", line)
+		}
+	}
+}
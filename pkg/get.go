@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	utilexec "k8s.io/utils/exec"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// exitNotFound is the get exit status when the At does not exist, so
+// scripts can tell it apart from API errors, which exit with 1
+const exitNotFound = 3
+
+// findAtPods returns the pods of the namespace with an owner reference to
+// the At, i.e. the ones the controller created for it
+func findAtPods(ctx context.Context, kube kubernetes.Interface, at *cnatv1alpha1.At) ([]v1.Pod, error) {
+	pods, err := kube.CoreV1().Pods(at.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %w", err)
+	}
+	var owned []v1.Pod
+	for _, pod := range pods.Items {
+		for _, ref := range pod.OwnerReferences {
+			if ref.UID == at.UID {
+				owned = append(owned, pod)
+				break
+			}
+		}
+	}
+	return owned, nil
+}
+
+// scheduleString shows the schedule with the time until it, or elapsed
+// since it, relative to now. A cron expression, the schedule of a recurring
+// At, shows its next run after now instead.
+func scheduleString(schedule string, now time.Time) string {
+	t, err := time.Parse(scheduleLayout, schedule)
+	if err != nil {
+		recurring, cronErr := cron.ParseStandard(schedule)
+		if cronErr != nil {
+			return fmt.Sprintf("%s (neither a UTC time like %s nor a cron expression)", schedule, scheduleLayout)
+		}
+		next := recurring.Next(now.UTC())
+		return fmt.Sprintf("%s (cron, next run %s, in %s)", schedule, next.Format(scheduleLayout), duration.HumanDuration(next.Sub(now)))
+	}
+	if t.After(now) {
+		return fmt.Sprintf("%s (in %s)", schedule, duration.HumanDuration(t.Sub(now)))
+	}
+	return fmt.Sprintf("%s (%s ago)", schedule, duration.HumanDuration(now.Sub(t)))
+}
+
+// printAtDetails prints everything about an At and the pods it owns
+func printAtDetails(out io.Writer, at *cnatv1alpha1.At, pods []v1.Pod, now time.Time) {
+	phase := at.Status.Phase
+	if phase == "" {
+		phase = "<none>"
+	}
+	fmt.Fprintf(out, "Name:       %s\n", at.Name)
+	fmt.Fprintf(out, "Namespace:  %s\n", at.Namespace)
+	fmt.Fprintf(out, "Created:    %s (%s ago)\n", at.CreationTimestamp.UTC().Format(time.RFC3339), duration.HumanDuration(now.Sub(at.CreationTimestamp.Time)))
+	fmt.Fprintf(out, "Schedule:   %s\n", scheduleString(at.Spec.Schedule, now))
+	fmt.Fprintf(out, "Command:    %s\n", at.Spec.Command)
+	fmt.Fprintf(out, "Phase:      %s\n", phase)
+	if at.Status.LastRunTime != nil {
+		fmt.Fprintf(out, "Last run:   %s (%s ago)\n", at.Status.LastRunTime.UTC().Format(time.RFC3339), duration.HumanDuration(now.Sub(at.Status.LastRunTime.Time)))
+	}
+
+	if len(at.OwnerReferences) == 0 {
+		fmt.Fprintln(out, "Owners:     <none>")
+	} else {
+		fmt.Fprintln(out, "Owners:")
+		for _, ref := range at.OwnerReferences {
+			controller := ""
+			if ref.Controller != nil && *ref.Controller {
+				controller = " (controller)"
+			}
+			fmt.Fprintf(out, "  %s/%s%s\n", ref.Kind, ref.Name, controller)
+		}
+	}
+
+	if len(pods) == 0 {
+		fmt.Fprintln(out, "Pods:       <none>")
+		return
+	}
+	fmt.Fprintln(out, "Pods:")
+	for _, pod := range pods {
+		fmt.Fprintf(out, "  %s  %s\n", pod.Name, pod.Status.Phase)
+	}
+}
+
+// runGet implements the get subcommand
+func runGet(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace of the At resource")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: get [flags] NAME")
+	}
+	name := fs.Arg(0)
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+	kube, err := kubernetes.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	at, err := client.CnatV1alpha1().Ats(*namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return utilexec.CodeExitError{Err: fmt.Errorf("At %q not found in namespace '%s'", name, *namespace), Code: exitNotFound}
+	}
+	if err != nil {
+		return fmt.Errorf("error getting At %q: %w", name, config.WrapTimeout(err, configOpts.Timeout))
+	}
+	pods, err := findAtPods(ctx, kube, at)
+	if err != nil {
+		return config.WrapTimeout(err, configOpts.Timeout)
+	}
+	return output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		printAtDetails(out, at, pods, time.Now())
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+)
+
+func TestFindAtPods(t *testing.T) {
+	at := &cnatv1alpha1.At{ObjectMeta: metav1.ObjectMeta{Namespace: "jobs", Name: "backup", UID: "at-uid"}}
+	pod := func(ns, name, ownerUID string) *v1.Pod {
+		p := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+		if ownerUID != "" {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: "At", Name: "backup", UID: "other"}, {Kind: "At", Name: "backup", UID: types.UID(ownerUID)}}
+		}
+		return p
+	}
+	kube := kubefake.NewSimpleClientset(
+		pod("jobs", "backup-pod", "at-uid"),
+		// Same name, but owned by an earlier At that was recreated
+		pod("jobs", "stale-pod", "old-uid"),
+		pod("jobs", "unowned", ""),
+		pod("other", "elsewhere", "at-uid"),
+	)
+
+	pods, err := findAtPods(context.Background(), kube, at)
+	if err != nil {
+		t.Fatalf("findAtPods() error = %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "backup-pod" {
+		t.Errorf("findAtPods() = %v, want backup-pod", pods)
+	}
+}
+
+func TestPrintAtDetails(t *testing.T) {
+	now := time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)
+	isController := true
+	at := &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "jobs",
+			Name:              "backup",
+			CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			OwnerReferences:   []metav1.OwnerReference{{Kind: "CronAt", Name: "nightly", Controller: &isController}},
+		},
+		Spec:   cnatv1alpha1.AtSpec{Schedule: "2026-07-03T02:30:00Z", Command: "echo backup"},
+		Status: cnatv1alpha1.AtStatus{Phase: cnatv1alpha1.PhasePending},
+	}
+	pods := []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "backup-pod"}, Status: v1.PodStatus{Phase: v1.PodRunning}}}
+
+	var out bytes.Buffer
+	printAtDetails(&out, at, pods, now)
+	want := `Name:       backup
+Namespace:  jobs
+Created:    2026-07-02T22:00:00Z (120m ago)
+Schedule:   2026-07-03T02:30:00Z (in 150m)
+Command:    echo backup
+Phase:      PENDING
+Owners:
+  CronAt/nightly (controller)
+Pods:
+  backup-pod  Running
+`
+	if out.String() != want {
+		t.Errorf("printAtDetails() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// Past the schedule, without owners or pods
+	at.OwnerReferences = nil
+	out.Reset()
+	printAtDetails(&out, at, nil, now.Add(3*time.Hour))
+	want = `Name:       backup
+Namespace:  jobs
+Created:    2026-07-02T22:00:00Z (5h ago)
+Schedule:   2026-07-03T02:30:00Z (30m ago)
+Command:    echo backup
+Phase:      PENDING
+Owners:     <none>
+Pods:       <none>
+`
+	if out.String() != want {
+		t.Errorf("printAtDetails() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// A recurring At shows its next run
+	at.Spec = cnatv1alpha1.AtSpec{Schedule: "0 2 * * *", Command: "echo backup", Recurring: true}
+	out.Reset()
+	printAtDetails(&out, at, nil, now.Add(3*time.Hour))
+	want = `Name:       backup
+Namespace:  jobs
+Created:    2026-07-02T22:00:00Z (5h ago)
+Schedule:   0 2 * * * (cron, next run 2026-07-04T02:00:00Z, in 23h)
+Command:    echo backup
+Phase:      PENDING
+Owners:     <none>
+Pods:       <none>
+`
+	if out.String() != want {
+		t.Errorf("printAtDetails() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestScheduleString(t *testing.T) {
+	now := time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     string
+	}{
+		{schedule: "2026-07-03T02:30:00Z", want: "2026-07-03T02:30:00Z (in 150m)"},
+		{schedule: "*/15 * * * *", want: "*/15 * * * * (cron, next run 2026-07-03T00:15:00Z, in 15m)"},
+		{schedule: "tomorrow", want: "tomorrow (neither a UTC time like 2006-01-02T15:04:05Z nor a cron expression)"},
+	}
+	for _, tt := range tests {
+		if got := scheduleString(tt.schedule, now); got != tt.want {
+			t.Errorf("scheduleString(%q) = %q, want %q", tt.schedule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	informers "Kubernetes_Programming/pkg/generated/informers/externalversions"
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// Event types logged by AtInformer
+const (
+	eventAdded   = "ADDED"
+	eventUpdated = "UPDATED"
+	eventDeleted = "DELETED"
+)
+
+// atEvent is a work item: what happened to which At, and the phase it had
+// at the time. DELETED events can't look the At up again later, so the
+// phase travels with the event instead of being read from the cache.
+type atEvent struct {
+	Type  string
+	Key   string
+	Phase string
+}
+
+// AtInformer watches At resources through a shared informer and processes
+// their events off a rate limited workqueue, the same pattern the
+// kubebuilder controller uses under the hood:
+//
+//	API server --watch--> informer --event handler--> workqueue --> worker
+//
+// The informer keeps a local cache in sync with the API server and calls the
+// event handlers; the handlers only enqueue, so slow processing never blocks
+// the watch. The worker pops items one at a time and logs them.
+type AtInformer struct {
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+	out      io.Writer
+}
+
+// NewAtInformer returns an AtInformer for the At informer of factory that
+// logs events to out. Start it with Run.
+func NewAtInformer(factory informers.SharedInformerFactory, out io.Writer) *AtInformer {
+	i := &AtInformer{
+		informer: factory.Cnat().V1alpha1().Ats().Informer(),
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Ats"),
+		out:      out,
+	}
+	i.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			i.enqueue(eventAdded, obj)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			i.enqueue(eventUpdated, new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			i.enqueue(eventDeleted, obj)
+		},
+	})
+	return i
+}
+
+// enqueue adds an event for obj to the workqueue. Deletions that were
+// missed while the watch was down arrive wrapped in a tombstone.
+func (i *AtInformer) enqueue(eventType string, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	at, ok := obj.(*cnatv1alpha1.At)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("expected an At in %s event but got %#v", eventType, obj))
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(at)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	i.queue.Add(atEvent{Type: eventType, Key: key, Phase: at.Status.Phase})
+}
+
+// Run starts the informer, waits for its cache to sync and processes events
+// until stopCh is closed, at which point it shuts down the workqueue.
+func (i *AtInformer) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer i.queue.ShutDown()
+
+	go i.informer.Run(stopCh)
+	if ok := cache.WaitForCacheSync(stopCh, i.informer.HasSynced); !ok {
+		return fmt.Errorf("failed to wait for the At cache to sync")
+	}
+
+	go wait.Until(i.runWorker, time.Second, stopCh)
+	<-stopCh
+	return nil
+}
+
+// runWorker processes events until the workqueue is shut down
+func (i *AtInformer) runWorker() {
+	for i.processNextItem() {
+	}
+}
+
+// processNextItem logs one event off the workqueue. Logging can't fail, so
+// every item is forgotten right away; a real controller would requeue with
+// AddRateLimited on errors.
+func (i *AtInformer) processNextItem() bool {
+	item, shutdown := i.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer i.queue.Done(item)
+	defer i.queue.Forget(item)
+
+	event, ok := item.(atEvent)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("expected atEvent in workqueue but got %#v", item))
+		return true
+	}
+	phase := event.Phase
+	if phase == "" {
+		phase = "<none>"
+	}
+	fmt.Fprintf(i.out, "%-8s %s phase=%s\n", event.Type, event.Key, phase)
+	return true
+}
+
+// runInformer implements the informer subcommand
+func runInformer(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("informer", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace to watch At resources in (empty for all namespaces)")
+	var selector selectorOptions
+	selector.AddFlags(fs)
+	resync := fs.Duration("resync", 0, "how often the informer replays every At as an UPDATED event (0 disables resyncs)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+
+	stopCh := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		close(stopCh)
+	}()
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, *resync, informers.WithNamespace(*namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector.Selector
+		}))
+	return NewAtInformer(factory, os.Stdout).Run(stopCh)
+}
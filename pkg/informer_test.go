@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+	informers "Kubernetes_Programming/pkg/generated/informers/externalversions"
+)
+
+// syncBuffer is a bytes.Buffer safe for the worker to write while the test
+// reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAtInformerLogsEvents(t *testing.T) {
+	at := &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup"},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: "2099-01-01T00:00:00Z", Command: "echo YAY"},
+		Status:     cnatv1alpha1.AtStatus{Phase: cnatv1alpha1.PhasePending},
+	}
+	client := fake.NewSimpleClientset(at)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	out := &syncBuffer{}
+	informer := NewAtInformer(factory, out)
+
+	stopCh := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- informer.Run(stopCh) }()
+	defer func() {
+		close(stopCh)
+		if err := <-done; err != nil {
+			t.Errorf("Run() = %v", err)
+		}
+	}()
+
+	// waitFor blocks until the informer has logged line
+	waitFor := func(line string) {
+		t.Helper()
+		err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true,
+			func(context.Context) (bool, error) { return strings.Contains(out.String(), line), nil })
+		if err != nil {
+			t.Fatalf("timed out waiting for %q, got:\n%s", line, out.String())
+		}
+	}
+	ctx := context.Background()
+	ats := client.CnatV1alpha1().Ats("default")
+
+	waitFor("ADDED    default/backup phase=PENDING")
+
+	at.Status.Phase = cnatv1alpha1.PhaseRunning
+	if _, err := ats.UpdateStatus(ctx, at, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("updating status: %v", err)
+	}
+	waitFor("UPDATED  default/backup phase=RUNNING")
+
+	if err := ats.Delete(ctx, "backup", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("deleting At: %v", err)
+	}
+	waitFor("DELETED  default/backup phase=RUNNING")
+}
+
+func TestAtInformerIgnoresUnexpectedObjects(t *testing.T) {
+	informer := NewAtInformer(informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0), &bytes.Buffer{})
+	defer informer.queue.ShutDown()
+
+	informer.enqueue(eventAdded, "not an At")
+	if informer.queue.Len() != 0 {
+		t.Errorf("queue has %d items, want 0", informer.queue.Len())
+	}
+}
@@ -0,0 +1,93 @@
+// Package logging builds the log/slog logger configured by --log-level and
+// --log-format, shared by the pod lister and the At CLI.
+package logging
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats accepted by --log-format
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options holds the logging flags
+type Options struct {
+	Level string
+	// Format is text or json; empty leaves the choice to the caller's default
+	Format string
+}
+
+// AddFlags registers --log-level and --log-format on fs
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Level, "log-level", "info", "minimum level of the messages logged to stderr: debug, info, warn or error")
+	fs.StringVar(&o.Format, "log-format", "", "format of the messages logged to stderr: text or json (default json with --output json, otherwise text)")
+}
+
+// ParseLevel parses a --log-level value (case-insensitive)
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown --log-level %q (want debug, info, warn or error)", value)
+}
+
+// NewHandler returns a handler writing to w at the configured level, in the
+// configured format or defaultFormat when --log-format is not set
+func (o Options) NewHandler(w io.Writer, defaultFormat string) (slog.Handler, error) {
+	level, err := ParseLevel(o.Level)
+	if err != nil {
+		return nil, err
+	}
+	format := o.Format
+	if format == "" {
+		format = defaultFormat
+	}
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	switch format {
+	case FormatText:
+		return slog.NewTextHandler(w, handlerOpts), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, handlerOpts), nil
+	}
+	return nil, fmt.Errorf("unknown --log-format %q (want text or json)", format)
+}
+
+// NewLogger returns a logger over NewHandler
+func (o Options) NewLogger(w io.Writer, defaultFormat string) (*slog.Logger, error) {
+	handler, err := o.NewHandler(w, defaultFormat)
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(handler), nil
+}
+
+// contextKey is the context key of the logger
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or slog.Default() for
+// contexts without one (e.g. in tests)
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{value: "debug", want: slog.LevelDebug},
+		{value: "INFO", want: slog.LevelInfo},
+		{value: "", want: slog.LevelInfo},
+		{value: "warn", want: slog.LevelWarn},
+		{value: "error", want: slog.LevelError},
+		{value: "trace", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.value)
+		if (err != nil) != tt.wantErr || (err == nil && got != tt.want) {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestJSONLoggerFields(t *testing.T) {
+	var opts Options
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.AddFlags(fs)
+	if err := fs.Parse([]string{"--log-level", "warn"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	logger, err := opts.NewLogger(&buf, FormatJSON)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	ctx := NewContext(context.Background(), logger)
+	FromContext(ctx).Info("below the level")
+	FromContext(ctx).Warn("partial results", "namespace", "secret", "error", errors.New("forbidden"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want only the warning:\n%s", len(lines), buf.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("log line is not JSON: %v\n%s", err, lines[0])
+	}
+	want := map[string]any{"level": "WARN", "msg": "partial results", "namespace": "secret", "error": "forbidden"}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+func TestNewHandlerFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		defaultFormat string
+		wantPrefix    string
+		wantErr       bool
+	}{
+		{name: "default text", defaultFormat: FormatText, wantPrefix: "time="},
+		{name: "default json", defaultFormat: FormatJSON, wantPrefix: "{"},
+		{name: "flag overrides the default", format: FormatText, defaultFormat: FormatJSON, wantPrefix: "time="},
+		{name: "unknown format", format: "xml", defaultFormat: FormatText, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler, err := Options{Format: tt.format}.NewHandler(&buf, tt.defaultFormat)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			slog.New(handler).Info("hello")
+			if !strings.HasPrefix(buf.String(), tt.wantPrefix) {
+				t.Errorf("output %q does not start with %q", buf.String(), tt.wantPrefix)
+			}
+		})
+	}
+}
@@ -1,68 +1,214 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"syscall"
+	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/clientcmd"
+	utilexec "k8s.io/utils/exec"
 
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
 	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
 )
 
+// newLogger builds the logger from the logging flags, falling back to text
+// on stderr when they are invalid so the error itself can still be logged
+func newLogger(opts logging.Options) (*slog.Logger, error) {
+	logger, err := opts.NewLogger(os.Stderr, logging.FormatText)
+	if err != nil {
+		return slog.New(slog.NewTextHandler(os.Stderr, nil)), err
+	}
+	return logger, nil
+}
+
+// addOutputFileFlag registers --output-file for the subcommands that print
+// a result, see output.Write
+func addOutputFileFlag(fs *flag.FlagSet) *string {
+	return fs.String("output-file", "", "write the output to this file, replaced atomically once the run succeeds (- for stdout)")
+}
+
+// fatal logs err and exits with status 1, or with the status of a
+// utilexec.ExitError such as the one get returns for a missing At
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitStatus() > 0 {
+		os.Exit(exitErr.ExitStatus())
+	}
+	os.Exit(1)
+}
+
 func main() {
+	// Subcommands take their own flags
+	if len(os.Args) > 1 {
+		var run func(*logging.Options, []string) error
+		switch os.Args[1] {
+		case "status":
+			run = runStatus
+		case "informer":
+			run = runInformer
+		case "create":
+			run = runCreate
+		case "delete":
+			run = runDelete
+		case "apply":
+			run = runApply
+		case "get":
+			run = runGet
+		case "wait":
+			run = runWait
+		case "set-schedule":
+			run = runSetSchedule
+		case "set-command":
+			run = runSetCommand
+		}
+		if run != nil {
+			var logOpts logging.Options
+			if err := run(&logOpts, os.Args[2:]); err != nil {
+				logger, _ := newLogger(logOpts)
+				fatal(logger, "command failed", err)
+			}
+			return
+		}
+	}
+
 	// Parse kubeconfig path
-	kubeconfig := flag.String("kubeconfig", getDefaultKubeconfig(), "path to kubeconfig file")
+	var configOpts config.Options
+	configOpts.AddFlags(flag.CommandLine)
+	configOpts.AddTimeoutFlag(flag.CommandLine)
+	var logOpts logging.Options
+	logOpts.AddFlags(flag.CommandLine)
 	namespace := flag.String("namespace", "default", "namespace to list At resources")
+	var selector selectorOptions
+	selector.AddFlags(flag.CommandLine)
+	outputFile := flag.String("output-file", "", "write the listing to this file, replaced atomically once the run succeeds (- for stdout)")
+	watchAts := flag.Bool("watch", false, "after listing, print a line with the phase of each At resource change until interrupted")
+	watchOnly := flag.String("watch-only", "", "watch only the At resource of this name; implies --watch")
+	cached := flag.Bool("cached", false, "list through a shared informer cache instead of a direct API call, falling back to the API when the cache doesn't sync")
+	showStatus := flag.Bool("show-status", false, "print every status field the controller recorded, such as the last run time and the conditions, instead of only the phase")
+	cacheSyncTimeout := flag.Duration("cache-sync-timeout", defaultCacheSyncTimeout, "with --cached, how long to wait for the cache to sync before falling back")
 	flag.Parse()
 
-	// Build config from kubeconfig
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	logger, err := newLogger(logOpts)
 	if err != nil {
-		log.Fatalf("Error building kubeconfig: %v", err)
+		fatal(logger, "invalid logging flags", err)
+	}
+	if *watchOnly != "" {
+		*watchAts = true
+	}
+	if *watchAts && !output.IsStdout(*outputFile) {
+		fatal(logger, "invalid flags", errors.New("--output-file cannot be combined with --watch"))
+	}
+	if err := selector.Validate(); err != nil {
+		fatal(logger, "invalid flags", err)
+	}
+	if *watchAts && *showStatus {
+		fatal(logger, "invalid flags", errors.New("--show-status cannot be combined with --watch"))
+	}
+	if *watchAts && *cached {
+		fatal(logger, "invalid flags", errors.New("--cached cannot be combined with --watch"))
+	}
+
+	// Build config from kubeconfig, or in-cluster config inside a pod
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		fatal(logger, "error building kubeconfig", err)
 	}
 
 	// Create the generated clientset
-	client, err := clientset.NewForConfig(config)
+	client, err := clientset.NewForConfig(resolved.Config)
 	if err != nil {
-		log.Fatalf("Error creating clientset: %v", err)
+		fatal(logger, "error creating clientset", err)
+	}
+
+	if *watchAts {
+		if err := watchAtResources(logger, client, *namespace, *watchOnly, selector.Selector, configOpts.Timeout); err != nil {
+			fatal(logger, "error watching At resources", err)
+		}
+		return
 	}
 
 	// List At resources in the specified namespace
-	ctx := context.Background()
-	fmt.Printf("Fetching 'At' resources from namespace '%s'...\n", *namespace)
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	ctx = logging.NewContext(ctx, logger)
+	logger.Info("fetching At resources", "namespace", *namespace, "selector", selector.Selector)
 
-	ats, err := client.CnatV1alpha1().Ats(*namespace).List(ctx, metav1.ListOptions{})
+	var ats []cnatv1alpha1.At
+	if *cached {
+		atCache := NewAtCache(ctx, client, *namespace, *cacheSyncTimeout)
+		defer atCache.Stop()
+		ats, err = atCache.List(ctx, selector.Labels())
+	} else {
+		var list *cnatv1alpha1.AtList
+		if list, err = client.CnatV1alpha1().Ats(*namespace).List(ctx, selector.ListOptions()); err == nil {
+			ats = list.Items
+		}
+	}
 	if err != nil {
-		log.Fatalf("Error listing At resources: %v", err)
+		fatal(logger, "error listing At resources", config.WrapTimeout(err, configOpts.Timeout))
 	}
 
 	// Display results
-	if len(ats.Items) == 0 {
-		fmt.Println("No At resources found")
-		return
-	}
-
-	fmt.Printf("Found %d At resource(s):\n", len(ats.Items))
-	for i, at := range ats.Items {
-		fmt.Printf("%d. Name: %s\n", i+1, at.Name)
-		fmt.Printf("   Schedule: %s\n", at.Spec.Schedule)
-		fmt.Printf("   Command: %s\n", at.Spec.Command)
-		if at.Status.Phase != "" {
-			fmt.Printf("   Phase: %s\n", at.Status.Phase)
+	err = output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		if len(ats) == 0 {
+			fmt.Fprintln(out, selector.notFound(*namespace))
+			return nil
 		}
-		fmt.Println()
+		printAts(out, ats, *showStatus)
+		return nil
+	})
+	if err != nil {
+		fatal(logger, "error writing the output", err)
 	}
 }
 
-// getDefaultKubeconfig returns the default kubeconfig path
-func getDefaultKubeconfig() string {
-	if home := os.Getenv("HOME"); home != "" {
-		return filepath.Join(home, ".kube", "config")
+// watchAtResources streams the changes of the At resources of namespace
+// matching selector, or of the one named name, to stdout until interrupted. Lines are buffered
+// and flushed after each event, and once more on the way out.
+func watchAtResources(logger *slog.Logger, client clientset.Interface, namespace, name, selector string, timeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(logging.NewContext(context.Background(), logger), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	w := &atWatcher{client: client, namespace: namespace, name: name, selector: selector, timeout: timeout, out: out, flush: out.Flush}
+	return w.run(ctx)
+}
+
+// printAts prints one block per At resource. With showStatus every status
+// field set is printed, see atStatusLines, rather than only the phase.
+func printAts(out io.Writer, ats []cnatv1alpha1.At, showStatus bool) {
+	fmt.Fprintf(out, "Found %d At resource(s):\n", len(ats))
+	for i, at := range ats {
+		fmt.Fprintf(out, "%d. Name: %s\n", i+1, at.Name)
+		fmt.Fprintf(out, "   Schedule: %s\n", at.Spec.Schedule)
+		fmt.Fprintf(out, "   Command: %s\n", at.Spec.Command)
+		switch {
+		case showStatus:
+			lines := atStatusLines(at.Status)
+			if len(lines) == 0 {
+				fmt.Fprintln(out, "   Status: <none>")
+				break
+			}
+			fmt.Fprintln(out, "   Status:")
+			for _, line := range lines {
+				fmt.Fprintf(out, "     %s\n", line)
+			}
+		case at.Status.Phase != "":
+			fmt.Fprintf(out, "   Phase: %s\n", at.Status.Phase)
+		}
+		fmt.Fprintln(out)
 	}
-	return ""
 }
@@ -0,0 +1,147 @@
+// Package output writes command output to stdout or atomically to a file,
+// so a reader of the file (or a cron job overwriting it) never sees a
+// partial run.
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Stdout is the --output-file value that explicitly means stdout
+const Stdout = "-"
+
+// Options controls how a Writer replaces its file
+type Options struct {
+	// Append keeps the file's previous contents and adds the new output
+	// after a "=== <Now in RFC 3339> ===" header line
+	Append bool
+	Now    time.Time
+}
+
+// Writer is the io.Writer command output is printed to. For a file path
+// the output goes to a temporary file in the same directory, which Commit
+// renames over the path; until then, including when the process is killed
+// mid-write, the previous file stays as it was. For stdout it writes
+// through directly.
+type Writer struct {
+	path string
+	mode fs.FileMode
+	tmp  *os.File
+	out  io.Writer
+}
+
+// IsStdout reports whether path (an --output-file value) means stdout
+func IsStdout(path string) bool {
+	return path == "" || path == Stdout
+}
+
+// Open returns a Writer for path; an empty path or "-" is stdout. With
+// opts.Append the previous contents of path are copied into the temporary
+// file first.
+func Open(path string, opts Options) (w *Writer, err error) {
+	if IsStdout(path) {
+		return &Writer{out: os.Stdout}, nil
+	}
+
+	mode := fs.FileMode(0o644)
+	var previous []byte
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+		if opts.Append {
+			if previous, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("reading --output-file: %w", err)
+			}
+		}
+	} else if !errors.Is(statErr, fs.ErrNotExist) {
+		return nil, fmt.Errorf("checking --output-file: %w", statErr)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary output file: %w", err)
+	}
+	w = &Writer{path: path, mode: mode, tmp: tmp, out: tmp}
+	defer func() {
+		if err != nil {
+			w.Abort()
+		}
+	}()
+
+	if opts.Append {
+		if _, err = tmp.Write(previous); err != nil {
+			return nil, fmt.Errorf("writing temporary output file: %w", err)
+		}
+		if _, err = fmt.Fprintf(tmp, "=== %s ===\n", opts.Now.Format(time.RFC3339)); err != nil {
+			return nil, fmt.Errorf("writing temporary output file: %w", err)
+		}
+	}
+	return w, nil
+}
+
+// Write implements io.Writer
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+// IsFile reports whether w writes to a file rather than stdout
+func (w *Writer) IsFile() bool {
+	return w.tmp != nil
+}
+
+// Commit syncs the temporary file and renames it over the path, keeping
+// the previous file's permissions. It is a no-op for stdout.
+func (w *Writer) Commit() (err error) {
+	if w.tmp == nil {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			w.Abort()
+		}
+	}()
+	if err = w.tmp.Chmod(w.mode); err != nil {
+		return fmt.Errorf("setting output file mode: %w", err)
+	}
+	if err = w.tmp.Sync(); err != nil {
+		return fmt.Errorf("writing temporary output file: %w", err)
+	}
+	if err = w.tmp.Close(); err != nil {
+		return fmt.Errorf("writing temporary output file: %w", err)
+	}
+	if err = os.Rename(w.tmp.Name(), w.path); err != nil {
+		return fmt.Errorf("replacing --output-file: %w", err)
+	}
+	w.tmp = nil
+	return nil
+}
+
+// Abort discards the temporary file, leaving the path untouched. It is a
+// no-op for stdout and after Commit.
+func (w *Writer) Abort() {
+	if w.tmp == nil {
+		return
+	}
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+	w.tmp = nil
+}
+
+// Write runs render with a Writer for path and commits the output only if
+// render succeeds
+func Write(path string, opts Options, render func(io.Writer) error) error {
+	w, err := Open(path, opts)
+	if err != nil {
+		return err
+	}
+	if err := render(w); err != nil {
+		w.Abort()
+		return err
+	}
+	return w.Commit()
+}
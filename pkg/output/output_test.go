@@ -0,0 +1,174 @@
+package output
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dirEntries lists the names in dir, to catch leftover temporary files
+func dirEntries(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestWriteReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pods.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Write(path, Options{}, func(out io.Writer) error {
+		_, err := fmt.Fprintln(out, "new")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != "new\n" {
+		t.Errorf("file = %q, want %q", got, "new\n")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want the previous 0600", info.Mode().Perm())
+	}
+	if names := dirEntries(t, dir); len(names) != 1 {
+		t.Errorf("directory has %v, want only pods.txt", names)
+	}
+}
+
+func TestWriteKeepsFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pods.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	renderErr := errors.New("render failed")
+	err := Write(path, Options{}, func(out io.Writer) error {
+		fmt.Fprintln(out, "half written")
+		return renderErr
+	})
+	if !errors.Is(err, renderErr) {
+		t.Fatalf("Write() error = %v, want %v", err, renderErr)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "old\n" {
+		t.Errorf("file = %q, want it untouched", got)
+	}
+	if names := dirEntries(t, dir); len(names) != 1 {
+		t.Errorf("directory has %v, want the temporary file removed", names)
+	}
+}
+
+func TestWriteAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pods.log")
+	first := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	for i, now := range []time.Time{first, second} {
+		err := Write(path, Options{Append: true, Now: now}, func(out io.Writer) error {
+			_, err := fmt.Fprintf(out, "run %d\n", i+1)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	want := strings.Join([]string{
+		"=== 2026-01-02T03:00:00Z ===", "run 1",
+		"=== 2026-01-02T04:00:00Z ===", "run 2",
+	}, "\n") + "\n"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOpenStdout(t *testing.T) {
+	for _, path := range []string{"", Stdout} {
+		w, err := Open(path, Options{})
+		if err != nil {
+			t.Fatalf("Open(%q) error = %v", path, err)
+		}
+		if w.IsFile() || w.out != os.Stdout {
+			t.Errorf("Open(%q) does not write to stdout", path)
+		}
+		if err := w.Commit(); err != nil {
+			t.Errorf("Commit() on stdout = %v, want nil", err)
+		}
+	}
+}
+
+// helperEnv makes the test binary run killedWriterHelper instead of the tests
+const helperEnv = "OUTPUT_TEST_HELPER_PATH"
+
+// killedWriterHelper writes part of a new output to the path in
+// helperEnv, reports that on stdout and waits to be killed before it can
+// Commit
+func killedWriterHelper() {
+	w, err := Open(os.Getenv(helperEnv), Options{})
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(w, "partial new output")
+	fmt.Println("written")
+	time.Sleep(time.Minute)
+	w.Commit()
+	os.Exit(0)
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) != "" {
+		killedWriterHelper()
+	}
+	os.Exit(m.Run())
+}
+
+func TestKilledMidWriteKeepsPreviousOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pods.txt")
+	previous := "NAME   STATUS\nweb    Running\n"
+	if err := os.WriteFile(path, []byte(previous), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), helperEnv+"="+path)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil || line != "written\n" {
+		cmd.Process.Kill()
+		t.Fatalf("helper said %q (%v), want it to report the partial write", line, err)
+	}
+
+	if err := cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	cmd.Wait()
+
+	if got, _ := os.ReadFile(path); string(got) != previous {
+		t.Errorf("file = %q after the writer was killed, want the previous output %q", got, previous)
+	}
+}
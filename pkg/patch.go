@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	utilexec "k8s.io/utils/exec"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// specField is an At spec field set-schedule or set-command patches
+type specField struct {
+	// name is the subcommand flag and the field's JSON name
+	name string
+	get  func(*cnatv1alpha1.AtSpec) string
+	// validate, if set, checks a new value against the live spec
+	validate func(spec *cnatv1alpha1.AtSpec, value string) error
+}
+
+var (
+	scheduleField = specField{name: "schedule", get: func(spec *cnatv1alpha1.AtSpec) string { return spec.Schedule }, validate: validateSchedule}
+	commandField  = specField{name: "command", get: func(spec *cnatv1alpha1.AtSpec) string { return spec.Command }}
+)
+
+// validateSchedule checks a new schedule the way the controller's CRD does:
+// a UTC time, or a cron expression when the At is recurring
+func validateSchedule(spec *cnatv1alpha1.AtSpec, schedule string) error {
+	if spec.Recurring {
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return fmt.Errorf("--schedule %q is not a cron expression, which a recurring At needs: %w", schedule, err)
+		}
+		return nil
+	}
+	if _, err := time.Parse(scheduleLayout, schedule); err != nil {
+		return fmt.Errorf("--schedule %q is not a UTC time like %s: %w", schedule, scheduleLayout, err)
+	}
+	return nil
+}
+
+// patchAtSpec sets field of the At name to value with a JSON merge patch of
+// that field alone, and returns the value it replaced. The patch carries the
+// resourceVersion the At was read at, so a concurrent change fails it with
+// a conflict; the At is then read again, and its phase checked again,
+// before retrying. An At the controller already ran or is running is left
+// alone unless force is set, since the controller won't execute it again.
+func patchAtSpec(ctx context.Context, client clientset.Interface, namespace, name string, field specField, value string, force bool) (string, error) {
+	ats := client.CnatV1alpha1().Ats(namespace)
+	var old string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		at, err := ats.Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return utilexec.CodeExitError{Err: fmt.Errorf("At %q not found in namespace '%s'", name, namespace), Code: exitNotFound}
+		}
+		if err != nil {
+			return err
+		}
+		switch at.Status.Phase {
+		case cnatv1alpha1.PhaseRunning, cnatv1alpha1.PhaseDone:
+			if !force {
+				return fmt.Errorf("At %q is already %s and the controller won't run it again; use --force to patch it anyway", name, at.Status.Phase)
+			}
+		}
+		if field.validate != nil {
+			if err := field.validate(&at.Spec, value); err != nil {
+				return err
+			}
+		}
+		old = field.get(&at.Spec)
+		if old == value {
+			return nil
+		}
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{"resourceVersion": at.ResourceVersion},
+			"spec":     map[string]any{field.name: value},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = ats.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+		return err
+	})
+	return old, err
+}
+
+// printPatched prints the old and new value of the patched field
+func printPatched(out io.Writer, name string, field specField, old, value string) {
+	if old == value {
+		fmt.Fprintf(out, "at/%s unchanged (%s is already %q)\n", name, field.name, value)
+		return
+	}
+	fmt.Fprintf(out, "at/%s patched\n", name)
+	fmt.Fprintf(out, "  old %s: %s\n", field.name, old)
+	fmt.Fprintf(out, "  new %s: %s\n", field.name, value)
+}
+
+// runSetSchedule implements the set-schedule subcommand
+func runSetSchedule(logOpts *logging.Options, args []string) error {
+	return runPatch(logOpts, args, scheduleField, `the new schedule, a UTC time like `+scheduleLayout+`, or a cron expression like "0 2 * * *" when the At is recurring`)
+}
+
+// runSetCommand implements the set-command subcommand
+func runSetCommand(logOpts *logging.Options, args []string) error {
+	return runPatch(logOpts, args, commandField, "the new command to run in a Bash shell")
+}
+
+// runPatch parses the flags of set-schedule or set-command, which take
+// NAME and --<field>, and patches field
+func runPatch(logOpts *logging.Options, args []string, field specField, usage string) error {
+	fs := flag.NewFlagSet("set-"+field.name, flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace of the At resource")
+	value := fs.String(field.name, "", usage+" (required)")
+	force := fs.Bool("force", false, "patch the At even when it is already RUNNING or DONE, although the controller won't run it again")
+	outputFile := addOutputFileFlag(fs)
+	usageErr := fmt.Errorf("usage: set-%s [flags] NAME --%s VALUE", field.name, field.name)
+	// Flags may come before or after NAME
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageErr
+	}
+	name := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return usageErr
+	}
+	if *value == "" {
+		return errors.New("--" + field.name + " is required")
+	}
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	old, err := patchAtSpec(ctx, client, *namespace, name, field, *value, *force)
+	if err != nil {
+		return fmt.Errorf("error patching the %s of At %q: %w", field.name, name, config.WrapTimeout(err, configOpts.Timeout))
+	}
+	return output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		printPatched(out, name, field, old, *value)
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func newPatchedAt(phase string) *cnatv1alpha1.At {
+	return &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "jobs", Name: "backup", ResourceVersion: "7"},
+		Spec:       cnatv1alpha1.AtSpec{Schedule: "2026-07-04T02:00:00Z", Command: "echo backup"},
+		Status:     cnatv1alpha1.AtStatus{Phase: phase},
+	}
+}
+
+func TestPatchAtSpec(t *testing.T) {
+	client := fake.NewSimpleClientset(newPatchedAt(cnatv1alpha1.PhasePending))
+	var patches []map[string]map[string]string
+	conflicts := 1
+	client.PrependReactor("patch", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.MergePatchType {
+			t.Errorf("patch type = %s, want %s", patch.GetPatchType(), types.MergePatchType)
+		}
+		var decoded map[string]map[string]string
+		if err := json.Unmarshal(patch.GetPatch(), &decoded); err != nil {
+			t.Fatalf("patch %s: %v", patch.GetPatch(), err)
+		}
+		patches = append(patches, decoded)
+		// The first patch runs into a concurrent change
+		if conflicts > 0 {
+			conflicts--
+			return true, nil, apierrors.NewConflict(cnatv1alpha1.Resource("ats"), "backup", errors.New("the object has been modified"))
+		}
+		return false, nil, nil
+	})
+
+	old, err := patchAtSpec(context.Background(), client, "jobs", "backup", scheduleField, "2026-07-05T02:00:00Z", false)
+	if err != nil {
+		t.Fatalf("patchAtSpec() error: %v", err)
+	}
+	if old != "2026-07-04T02:00:00Z" {
+		t.Errorf("patchAtSpec() old = %q, want the previous schedule", old)
+	}
+	if len(patches) != 2 {
+		t.Fatalf("got %d patches, want a retry after the conflict", len(patches))
+	}
+	for _, patch := range patches {
+		if len(patch["spec"]) != 1 || patch["spec"]["schedule"] != "2026-07-05T02:00:00Z" {
+			t.Errorf("patch spec = %v, want only the new schedule", patch["spec"])
+		}
+		if patch["metadata"]["resourceVersion"] != "7" {
+			t.Errorf("patch metadata = %v, want resourceVersion 7", patch["metadata"])
+		}
+	}
+
+	at, err := client.CnatV1alpha1().Ats("jobs").Get(context.Background(), "backup", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at.Spec.Schedule != "2026-07-05T02:00:00Z" || at.Spec.Command != "echo backup" {
+		t.Errorf("patched spec = %+v, want the new schedule and the command kept", at.Spec)
+	}
+}
+
+func TestPatchAtSpecRefusesStartedAt(t *testing.T) {
+	for _, phase := range []string{cnatv1alpha1.PhaseRunning, cnatv1alpha1.PhaseDone} {
+		client := fake.NewSimpleClientset(newPatchedAt(phase))
+		if _, err := patchAtSpec(context.Background(), client, "jobs", "backup", commandField, "echo again", false); err == nil {
+			t.Errorf("patchAtSpec() of a %s At = nil error, want a refusal", phase)
+		}
+		for _, action := range client.Actions() {
+			if action.GetVerb() == "patch" {
+				t.Errorf("patchAtSpec() patched a %s At without --force", phase)
+			}
+		}
+
+		old, err := patchAtSpec(context.Background(), client, "jobs", "backup", commandField, "echo again", true)
+		if err != nil || old != "echo backup" {
+			t.Errorf("patchAtSpec(force) of a %s At = %q, %v, want the old command", phase, old, err)
+		}
+	}
+}
+
+func TestPatchAtSpecSchedule(t *testing.T) {
+	recurring := newPatchedAt(cnatv1alpha1.PhasePending)
+	recurring.Name = "cleanup"
+	recurring.Spec = cnatv1alpha1.AtSpec{Schedule: "0 2 * * *", Command: "echo cleanup", Recurring: true}
+	tests := []struct {
+		name     string
+		at       string
+		schedule string
+		wantErr  string
+	}{
+		{name: "timestamp", at: "backup", schedule: "2026-07-05T02:00:00Z"},
+		{name: "cron for a one-off At", at: "backup", schedule: "0 3 * * *", wantErr: "is not a UTC time"},
+		{name: "cron for a recurring At", at: "cleanup", schedule: "0 3 * * *"},
+		{name: "timestamp for a recurring At", at: "cleanup", schedule: "2026-07-05T02:00:00Z", wantErr: "is not a cron expression, which a recurring At needs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(newPatchedAt(cnatv1alpha1.PhasePending), recurring.DeepCopy())
+			_, err := patchAtSpec(context.Background(), client, "jobs", tt.at, scheduleField, tt.schedule, false)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("patchAtSpec() error: %v", err)
+				}
+				at, err := client.CnatV1alpha1().Ats("jobs").Get(context.Background(), tt.at, metav1.GetOptions{})
+				if err != nil || at.Spec.Schedule != tt.schedule {
+					t.Errorf("patched At = %+v, %v, want schedule %q", at, err, tt.schedule)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("patchAtSpec() error = %v, want %q", err, tt.wantErr)
+			}
+			for _, action := range client.Actions() {
+				if action.GetVerb() == "patch" {
+					t.Errorf("patchAtSpec() patched an invalid schedule")
+				}
+			}
+		})
+	}
+}
+
+func TestPatchAtSpecNotFound(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	_, err := patchAtSpec(context.Background(), client, "jobs", "backup", commandField, "echo again", false)
+	var exitErr utilexec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != exitNotFound {
+		t.Errorf("patchAtSpec() error = %v, want exit status %d", err, exitNotFound)
+	}
+}
+
+func TestPrintPatched(t *testing.T) {
+	var out bytes.Buffer
+	printPatched(&out, "backup", commandField, "echo backup", "echo restore")
+	if want := "at/backup patched\n  old command: echo backup\n  new command: echo restore\n"; out.String() != want {
+		t.Errorf("printPatched() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printPatched(&out, "backup", commandField, "echo backup", "echo backup")
+	if want := "at/backup unchanged (command is already \"echo backup\")\n"; out.String() != want {
+		t.Errorf("printPatched() unchanged = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,279 @@
+package podinfo
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Filter selects pods client-side, after they are listed. The zero value
+// keeps every pod.
+type Filter struct {
+	// Excludes are namespaces or glob patterns (e.g. openshift-*) to hide
+	Excludes []string
+	// Reasons and Phases keep pods with one of these statuses or phases
+	// (case-insensitive)
+	Reasons []string
+	Phases  []string
+	// OlderThan and NewerThan bound the pod age; zero is not applied
+	OlderThan time.Duration
+	NewerThan time.Duration
+	// MinRestarts keeps pods with at least this many container restarts
+	MinRestarts int
+	// SinceRestart keeps pods with a container restarted this recently
+	SinceRestart time.Duration
+	// QOSClasses keeps pods in one of these QoS classes
+	QOSClasses []v1.PodQOSClass
+	// PVCOnly keeps pods mounting at least one PersistentVolumeClaim
+	PVCOnly bool
+	// Node keeps pods scheduled to this node, a name or a glob pattern
+	// (e.g. worker-*)
+	Node string
+	// ServiceAccount keeps pods running as this service account
+	ServiceAccount string
+	// ImageContains keeps pods with a container whose image, as in the
+	// spec or resolved by digest, contains this substring
+	ImageContains string
+}
+
+// Apply returns the pods that pass every filter, reusing the backing array
+// of pods, together with the number hidden by Excludes
+func (f Filter) Apply(pods []v1.Pod, now time.Time) ([]v1.Pod, int) {
+	kept, hidden := filterExcludedNamespaces(pods, f.Excludes)
+	kept = filterByReason(kept, f.Reasons)
+	kept = filterByPhase(kept, f.Phases)
+	kept = filterByAge(kept, now, f.OlderThan, f.NewerThan)
+	kept = filterByRestarts(kept, f.MinRestarts)
+	kept = filterBySinceRestart(kept, now, f.SinceRestart)
+	kept = filterByQOSClass(kept, f.QOSClasses)
+	kept = filterByPVC(kept, f.PVCOnly)
+	kept = filterByNode(kept, f.Node)
+	kept = filterByServiceAccount(kept, f.ServiceAccount)
+	kept = filterByImage(kept, f.ImageContains)
+	return kept, hidden
+}
+
+// ValidateExcludes checks the exclude patterns are valid globs and rejects
+// requested namespaces that are all excluded, since that can only ever
+// produce an empty result.
+func ValidateExcludes(namespaces, excludes []string) error {
+	for _, pattern := range excludes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --exclude-namespace pattern %q: %w", pattern, err)
+		}
+	}
+	if len(namespaces) == 0 || len(excludes) == 0 {
+		return nil
+	}
+	for _, ns := range namespaces {
+		if !namespaceExcluded(ns, excludes) {
+			return nil
+		}
+	}
+	return fmt.Errorf("--namespace %s is excluded by --exclude-namespace, nothing would be listed", strings.Join(namespaces, ","))
+}
+
+// namespaceExcluded reports whether ns matches any of the exclude patterns
+func namespaceExcluded(ns string, excludes []string) bool {
+	for _, pattern := range excludes {
+		if matched, _ := path.Match(pattern, ns); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterExcludedNamespaces drops pods in excluded namespaces and returns
+// the remaining pods together with the number hidden
+func filterExcludedNamespaces(pods []v1.Pod, excludes []string) ([]v1.Pod, int) {
+	if len(excludes) == 0 {
+		return pods, 0
+	}
+	kept := pods[:0]
+	for i := range pods {
+		if !namespaceExcluded(pods[i].Namespace, excludes) {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept, len(pods) - len(kept)
+}
+
+// IsNodePattern reports whether a --node value is a glob pattern rather
+// than a node name
+func IsNodePattern(node string) bool {
+	return strings.ContainsAny(node, "*?[")
+}
+
+// ValidateNode checks that a --node glob pattern is valid
+func ValidateNode(node string) error {
+	if _, err := path.Match(node, ""); err != nil {
+		return fmt.Errorf("invalid --node pattern %q: %w", node, err)
+	}
+	return nil
+}
+
+// NodeFieldSelector returns the field selector listing only the pods on
+// node, or "" when node is empty or a pattern, which the API server cannot
+// select on and Filter.Node matches client-side instead
+func NodeFieldSelector(node string) string {
+	if node == "" || IsNodePattern(node) {
+		return ""
+	}
+	return fields.OneTermEqualSelector("spec.nodeName", node).String()
+}
+
+// filterByNode keeps the pods scheduled to a node matching node, a name or
+// a glob pattern; unscheduled pods never match. An empty node keeps every
+// pod. Exact names are also selected server-side (see NodeFieldSelector),
+// matching them again keeps cached and fake listings correct.
+func filterByNode(pods []v1.Pod, node string) []v1.Pod {
+	if node == "" {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		if pods[i].Spec.NodeName == "" {
+			continue
+		}
+		if matched, _ := path.Match(node, pods[i].Spec.NodeName); matched {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
+
+// filterByServiceAccount keeps the pods running as serviceAccount; empty
+// keeps every pod
+func filterByServiceAccount(pods []v1.Pod, serviceAccount string) []v1.Pod {
+	if serviceAccount == "" {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		if pods[i].Spec.ServiceAccountName == serviceAccount {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
+
+// filterByImage keeps the pods with a container whose spec image or
+// resolved image, the one PodInfo.ContainerImages shows, contains
+// substring; empty keeps every pod
+func filterByImage(pods []v1.Pod, substring string) []v1.Pod {
+	if substring == "" {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		images := append(SpecImages(&pods[i]), RunningImages(&pods[i])...)
+		if slices.ContainsFunc(images, func(image string) bool { return strings.Contains(image, substring) }) {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
+
+// ValidateAgeRange rejects negative thresholds and a --older-than/--newer-than
+// pair that no pod can satisfy
+func ValidateAgeRange(olderThan, newerThan time.Duration) error {
+	if olderThan < 0 || newerThan < 0 {
+		return fmt.Errorf("--older-than and --newer-than must not be negative")
+	}
+	if olderThan > 0 && newerThan > 0 && olderThan >= newerThan {
+		return fmt.Errorf("--older-than %s and --newer-than %s match no pods", olderThan, newerThan)
+	}
+	return nil
+}
+
+// filterByPhase keeps pods in one of the given phases (case-insensitive).
+// No phases keeps every pod.
+func filterByPhase(pods []v1.Pod, phases []string) []v1.Pod {
+	if len(phases) == 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		for _, want := range phases {
+			if strings.EqualFold(string(pods[i].Status.Phase), want) {
+				kept = append(kept, pods[i])
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// filterByAge keeps pods older than olderThan and newer than newerThan; a
+// zero threshold is not applied
+func filterByAge(pods []v1.Pod, now time.Time, olderThan, newerThan time.Duration) []v1.Pod {
+	if olderThan == 0 && newerThan == 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		age := Age(&pods[i], now)
+		if olderThan > 0 && age <= olderThan {
+			continue
+		}
+		if newerThan > 0 && age >= newerThan {
+			continue
+		}
+		kept = append(kept, pods[i])
+	}
+	return kept
+}
+
+// filterByRestarts keeps pods whose total restart count is at least minRestarts
+func filterByRestarts(pods []v1.Pod, minRestarts int) []v1.Pod {
+	if minRestarts <= 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		if PodRestarts(&pods[i]) >= int32(minRestarts) {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
+
+// filterBySinceRestart keeps pods with at least one container whose last
+// restart finished within since; zero keeps every pod
+func filterBySinceRestart(pods []v1.Pod, now time.Time, since time.Duration) []v1.Pod {
+	if since <= 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		last := LastRestartTime(allContainerStatuses(&pods[i]))
+		if last != nil && now.Sub(*last) <= since {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
+
+// filterByReason keeps the pods whose status reason matches one of reasons,
+// compared case-insensitively. No reasons keeps every pod.
+func filterByReason(pods []v1.Pod, reasons []string) []v1.Pod {
+	if len(reasons) == 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		reason := Reason(&pods[i])
+		for _, want := range reasons {
+			if strings.EqualFold(reason, want) {
+				kept = append(kept, pods[i])
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,298 @@
+package podinfo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podNames returns the names of pods in order
+func podNames(pods []v1.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+// agedPod returns a pod in the given phase created age before now
+func agedPod(name string, phase v1.PodPhase, now time.Time, age time.Duration) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestFilterByAgeAndPhase(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	pods := func() []v1.Pod {
+		return []v1.Pod{
+			agedPod("just-created", v1.PodPending, now, time.Minute),
+			agedPod("stuck", v1.PodPending, now, 15*time.Minute),
+			agedPod("fresh", v1.PodRunning, now, time.Hour),
+			agedPod("long-running", v1.PodRunning, now, 45*day),
+		}
+	}
+	tests := []struct {
+		name      string
+		phases    []string
+		olderThan time.Duration
+		newerThan time.Duration
+		want      []string
+	}{
+		{name: "no filters", want: []string{"just-created", "stuck", "fresh", "long-running"}},
+		{name: "stuck pending pods", phases: []string{"pending"}, olderThan: 10 * time.Minute, want: []string{"stuck"}},
+		{name: "running for over 30 days", phases: []string{"Running"}, olderThan: 30 * day, want: []string{"long-running"}},
+		{name: "newer than", newerThan: 10 * time.Minute, want: []string{"just-created"}},
+		{name: "age window", olderThan: 10 * time.Minute, newerThan: 2 * time.Hour, want: []string{"stuck", "fresh"}},
+		{name: "threshold is exclusive", olderThan: time.Hour, want: []string{"long-running"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := filterByAge(filterByPhase(pods(), tt.phases), now, tt.olderThan, tt.newerThan)
+			if got := podNames(kept); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAgeRange(t *testing.T) {
+	tests := []struct {
+		olderThan, newerThan time.Duration
+		wantErr              bool
+	}{
+		{},
+		{olderThan: 10 * time.Minute},
+		{olderThan: 10 * time.Minute, newerThan: time.Hour},
+		{olderThan: time.Hour, newerThan: time.Hour, wantErr: true},
+		{olderThan: -time.Minute, wantErr: true},
+		{newerThan: -time.Minute, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateAgeRange(tt.olderThan, tt.newerThan); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateAgeRange(%s, %s) error = %v, wantErr %v", tt.olderThan, tt.newerThan, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFilterAndSortByRestarts(t *testing.T) {
+	restartedPod := func(name string, restarts ...int32) v1.Pod {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, r := range restarts {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{RestartCount: r})
+		}
+		return pod
+	}
+	pods := []v1.Pod{
+		restartedPod("healthy"),
+		restartedPod("flaky", 2, 3),
+		restartedPod("crashloop", 40),
+		restartedPod("sidecar-crash", 0, 7),
+	}
+	initCrash := restartedPod("init-crash")
+	initCrash.Status.InitContainerStatuses = []v1.ContainerStatus{{RestartCount: 6}}
+	pods = append(pods, initCrash)
+
+	kept := filterByRestarts(pods, 5)
+	if got, want := podNames(kept), []string{"flaky", "crashloop", "sidecar-crash", "init-crash"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("kept %v, want %v", got, want)
+	}
+
+	var infos []PodInfo
+	for i := range kept {
+		infos = append(infos, Extract(&kept[i], time.Now()))
+	}
+	names := func() []string {
+		var names []string
+		for _, info := range infos {
+			names = append(names, info.Name)
+		}
+		return names
+	}
+	Sort(infos, []SortKey{{Key: SortByRestarts, Descending: true}})
+	if got, want := names(), []string{"crashloop", "sidecar-crash", "init-crash", "flaky"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("descending by restarts = %v, want %v", got, want)
+	}
+	Sort(infos, []SortKey{{Key: SortByRestarts}})
+	if got, want := names(), []string{"flaky", "init-crash", "sidecar-crash", "crashloop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by restarts = %v, want %v", got, want)
+	}
+}
+
+func TestFilterBySinceRestart(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	restartedAgo := func(name string, agos ...time.Duration) v1.Pod {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		for _, ago := range agos {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, v1.ContainerStatus{
+				RestartCount: 1,
+				LastTerminationState: v1.ContainerState{
+					Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-ago))},
+				},
+			})
+		}
+		return pod
+	}
+	pods := func() []v1.Pod {
+		return []v1.Pod{
+			restartedAgo("healthy"),
+			restartedAgo("recent", 5*time.Minute),
+			restartedAgo("yesterday", 24*time.Hour),
+			restartedAgo("sidecar", 48*time.Hour, 30*time.Minute),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		since time.Duration
+		want  []string
+	}{
+		{name: "not applied", want: []string{"healthy", "recent", "yesterday", "sidecar"}},
+		{name: "last hour", since: time.Hour, want: []string{"recent", "sidecar"}},
+		{name: "last ten minutes", since: 10 * time.Minute, want: []string{"recent"}},
+		{name: "last two days", since: 48 * time.Hour, want: []string{"recent", "yesterday", "sidecar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podNames(filterBySinceRestart(pods(), now, tt.since)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamespaceExclusion(t *testing.T) {
+	excludes := []string{"kube-system", "kube-node-lease", "openshift-*"}
+	pods := []v1.Pod{
+		*newTestPod("default", "web"),
+		*newTestPod("kube-system", "coredns"),
+		*newTestPod("openshift-monitoring", "prometheus"),
+		*newTestPod("openshift", "not-matched-by-glob"),
+	}
+
+	kept, hidden := filterExcludedNamespaces(pods, excludes)
+	if hidden != 2 {
+		t.Errorf("hidden = %d, want 2", hidden)
+	}
+	var names []string
+	for _, pod := range kept {
+		names = append(names, pod.Name)
+	}
+	if want := []string{"web", "not-matched-by-glob"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("kept %v, want %v", names, want)
+	}
+}
+
+func TestValidateExcludes(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		excludes   []string
+		wantErr    bool
+	}{
+		{name: "all namespaces", excludes: []string{"kube-*"}},
+		{name: "namespace not excluded", namespaces: []string{"default"}, excludes: []string{"kube-*"}},
+		{name: "single namespace excluded", namespaces: []string{"kube-system"}, excludes: []string{"kube-*"}, wantErr: true},
+		{name: "some namespaces excluded", namespaces: []string{"kube-system", "default"}, excludes: []string{"kube-*"}},
+		{name: "invalid pattern", excludes: []string{"kube-["}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExcludes(tt.namespaces, tt.excludes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExcludes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterByNode(t *testing.T) {
+	onNode := func(name, node string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: v1.PodSpec{NodeName: node}}
+	}
+	pods := func() []v1.Pod {
+		return []v1.Pod{
+			onNode("a", "worker-1"),
+			onNode("b", "worker-2"),
+			onNode("c", "control-plane"),
+			onNode("pending", ""),
+		}
+	}
+	tests := []struct {
+		node string
+		want []string
+	}{
+		{node: "", want: []string{"a", "b", "c", "pending"}},
+		{node: "worker-2", want: []string{"b"}},
+		{node: "worker-*", want: []string{"a", "b"}},
+		{node: "*", want: []string{"a", "b", "c"}},
+		{node: "worker-9", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := podNames(filterByNode(pods(), tt.node)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterByNode(%q) kept %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestFilterByServiceAccountAndImage(t *testing.T) {
+	pod := func(name, serviceAccount, image, imageID string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.PodSpec{ServiceAccountName: serviceAccount, Containers: []v1.Container{{Name: "app", Image: image}}},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "app", ImageID: imageID}}},
+		}
+	}
+	pods := func() []v1.Pod {
+		return []v1.Pod{
+			pod("old", "web", "nginx:1.19", ""),
+			pod("new", "web", "nginx:1.25", "docker.io/library/nginx@sha256:new"),
+			pod("pinned", "batch", "registry.local/nginx@sha256:old", ""),
+		}
+	}
+	tests := []struct {
+		filter Filter
+		want   []string
+	}{
+		{filter: Filter{}, want: []string{"old", "new", "pinned"}},
+		{filter: Filter{ServiceAccount: "web"}, want: []string{"old", "new"}},
+		{filter: Filter{ServiceAccount: "missing"}, want: []string{}},
+		{filter: Filter{ImageContains: "nginx:1.19"}, want: []string{"old"}},
+		// Matched against the resolved digest as well as the spec
+		{filter: Filter{ImageContains: "sha256:new"}, want: []string{"new"}},
+		{filter: Filter{ImageContains: "@sha256:"}, want: []string{"new", "pinned"}},
+		{filter: Filter{ServiceAccount: "web", ImageContains: "@sha256:"}, want: []string{"new"}},
+	}
+	for _, tt := range tests {
+		kept, _ := tt.filter.Apply(pods(), time.Now())
+		if got := podNames(kept); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Apply(%+v) kept %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestNodeFieldSelector(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"worker-1": "spec.nodeName=worker-1",
+		"worker-*": "",
+		"node-?":   "",
+	}
+	for node, want := range tests {
+		if got := NodeFieldSelector(node); got != want {
+			t.Errorf("NodeFieldSelector(%q) = %q, want %q", node, got, want)
+		}
+	}
+	if err := ValidateNode("worker-["); err == nil {
+		t.Error("ValidateNode(\"worker-[\") succeeded, want an invalid pattern error")
+	}
+}
@@ -0,0 +1,117 @@
+package podinfo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultConcurrency is how many namespaces a Lister lists at once unless
+// Concurrency is set
+const DefaultConcurrency = 5
+
+// Lister lists pods from one or more namespaces of a cluster
+type Lister struct {
+	client kubernetes.Interface
+	// Concurrency caps the per-namespace List calls in flight at once;
+	// DefaultConcurrency when not positive
+	Concurrency int
+	// APIError, when set, rewrites the error of each failed List, e.g. to
+	// explain which RBAC permission is missing
+	APIError func(err error, namespace string) error
+}
+
+// NewLister returns a Lister using client
+func NewLister(client kubernetes.Interface) *Lister {
+	return &Lister{client: client}
+}
+
+// apiError applies APIError to err
+func (l *Lister) apiError(err error, namespace string) error {
+	if l.APIError == nil {
+		return err
+	}
+	return l.APIError(err, namespace)
+}
+
+// List lists pods matching opts across all namespaces when namespaces is
+// empty, or issues one List per namespace concurrently otherwise, merging
+// the results in the order of namespaces. Failures for individual
+// namespaces are collected and returned as warnings, in the same order, so
+// the remaining namespaces are still listed; an all-namespaces List failure
+// is an error. The deadline of ctx bounds the whole fan-out.
+func (l *Lister) List(ctx context.Context, namespaces []string, opts metav1.ListOptions) ([]v1.Pod, []error, error) {
+	if len(namespaces) == 0 {
+		pods, err := l.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, nil, l.apiError(err, "")
+		}
+		return pods.Items, nil, nil
+	}
+
+	type result struct {
+		pods []v1.Pod
+		err  error
+	}
+	results := make([]result, len(namespaces))
+	limit := l.Concurrency
+	if limit <= 0 {
+		limit = DefaultConcurrency
+	}
+	// A failing namespace must not cancel the others, so the group has no
+	// context of its own and its goroutines never return an error
+	var g errgroup.Group
+	g.SetLimit(limit)
+	for i, ns := range namespaces {
+		g.Go(func() error {
+			pods, err := l.client.CoreV1().Pods(ns).List(ctx, opts)
+			if err != nil {
+				results[i].err = fmt.Errorf("failed to list pods in namespace '%s': %w", ns, l.apiError(err, ns))
+				return nil
+			}
+			results[i].pods = pods.Items
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var all []v1.Pod
+	var warnings []error
+	for _, r := range results {
+		if r.err != nil {
+			warnings = append(warnings, r.err)
+			continue
+		}
+		all = append(all, r.pods...)
+	}
+	return all, warnings, nil
+}
+
+// Result is the outcome of ListInfos
+type Result struct {
+	// Pods and Infos are parallel: Infos[i] describes Pods[i]
+	Pods  []v1.Pod
+	Infos []PodInfo
+	// Hidden is how many pods Filter.Excludes hid
+	Hidden int
+}
+
+// ListInfos lists pods like List, applies filter and extracts the infos of
+// the remaining pods, with ages relative to now
+func (l *Lister) ListInfos(ctx context.Context, namespaces []string, opts metav1.ListOptions, filter Filter, now time.Time) (Result, []error, error) {
+	pods, warnings, err := l.List(ctx, namespaces, opts)
+	if err != nil {
+		return Result{}, warnings, err
+	}
+	result := Result{}
+	result.Pods, result.Hidden = filter.Apply(pods, now)
+	for i := range result.Pods {
+		result.Infos = append(result.Infos, Extract(&result.Pods[i], now))
+	}
+	return result, warnings, nil
+}
@@ -0,0 +1,232 @@
+package podinfo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestListerMultipleNamespaces(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newTestPod("kube-system", "coredns"),
+		newTestPod("monitoring", "prometheus"),
+		newTestPod("monitoring", "grafana"),
+		newTestPod("default", "ignored"),
+	)
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "secret" {
+			return true, nil, apierrors.NewForbidden(v1.Resource("pods"), "", nil)
+		}
+		return false, nil, nil
+	})
+
+	pods, warnings, err := NewLister(client).List(context.Background(), []string{"kube-system", "secret", "monitoring"}, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 3 {
+		t.Errorf("got %d pods, want 3", len(pods))
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1: %v", len(warnings), warnings)
+	}
+	if !apierrors.IsForbidden(warnings[0]) {
+		t.Errorf("warning %v does not wrap the forbidden error", warnings[0])
+	}
+}
+
+func TestListerConcurrency(t *testing.T) {
+	// The fake clientset serializes its calls, so count the Lists in flight
+	// at a real HTTP server instead. Each namespace holds one pod named
+	// after it; later namespaces answer first to shuffle the completions.
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		// /api/v1/namespaces/ns-N/pods
+		ns := strings.Split(r.URL.Path, "/")[4]
+		var index int
+		fmt.Sscanf(ns, "ns-%d", &index)
+		time.Sleep(time.Duration(10-index) * 5 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		if index%4 == 3 {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonForbidden, Code: http.StatusForbidden})
+			return
+		}
+		json.NewEncoder(w).Encode(v1.PodList{
+			TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+			Items:    []v1.Pod{*newTestPod(ns, ns)},
+		})
+	}))
+	defer server.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var namespaces []string
+	for i := range 10 {
+		namespaces = append(namespaces, fmt.Sprintf("ns-%d", i))
+	}
+	lister := NewLister(client)
+	lister.Concurrency = 3
+	pods, warnings, err := lister.List(context.Background(), namespaces, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	if got, want := strings.Join(names, ","), "ns-0,ns-1,ns-2,ns-4,ns-5,ns-6,ns-8,ns-9"; got != want {
+		t.Errorf("pods = %s, want %s in namespace order", got, want)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0].Error(), "'ns-3'") || !strings.Contains(warnings[1].Error(), "'ns-7'") {
+		t.Errorf("warnings = %v, want ns-3 then ns-7", warnings)
+	}
+	if got := maxInFlight.Load(); got > 3 {
+		t.Errorf("%d Lists in flight at once, want at most 3", got)
+	}
+}
+
+func TestListerAllNamespaces(t *testing.T) {
+	client := fake.NewSimpleClientset(newTestPod("a", "one"), newTestPod("b", "two"))
+
+	pods, warnings, err := NewLister(client).List(context.Background(), nil, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 2 || len(warnings) != 0 {
+		t.Errorf("got %d pods and %d warnings, want 2 and 0", len(pods), len(warnings))
+	}
+}
+
+func TestListerAPIError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("pods"), "", nil)
+	})
+	explained := errors.New("explained")
+	lister := NewLister(client)
+	lister.APIError = func(err error, namespace string) error {
+		if namespace != "" {
+			t.Errorf("APIError namespace = %q, want all namespaces", namespace)
+		}
+		return explained
+	}
+
+	if _, _, err := lister.List(context.Background(), nil, metav1.ListOptions{}); !errors.Is(err, explained) {
+		t.Errorf("List() error = %v, want the APIError result", err)
+	}
+}
+
+func TestListerListInfos(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	created := metav1.NewTime(now.Add(-time.Hour))
+
+	multiContainer := newTestPod("web", "frontend")
+	multiContainer.CreationTimestamp = created
+	multiContainer.Spec = v1.PodSpec{
+		NodeName:   "node-a",
+		Containers: []v1.Container{{Name: "app"}, {Name: "proxy"}, {Name: "logger"}},
+	}
+	multiContainer.Status = v1.PodStatus{
+		Phase: v1.PodRunning,
+		ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", Ready: true, RestartCount: 1, State: running()},
+			{Name: "proxy", Ready: true, RestartCount: 2, State: running()},
+			{Name: "logger", RestartCount: 4, State: waiting("CrashLoopBackOff")},
+		},
+	}
+
+	unscheduled := newTestPod("web", "pending")
+	unscheduled.CreationTimestamp = created
+	unscheduled.Spec.Containers = []v1.Container{{Name: "app"}}
+	unscheduled.Status = v1.PodStatus{
+		Phase:      v1.PodPending,
+		Conditions: []v1.PodCondition{{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable}},
+	}
+
+	// A pod the kubelet has not reported on yet: no status at all
+	noStatuses := newTestPod("batch", "fresh")
+	noStatuses.Spec.Containers = []v1.Container{{Name: "job"}, {Name: "sidecar"}}
+
+	client := fake.NewSimpleClientset(multiContainer, unscheduled, noStatuses, newTestPod("kube-system", "coredns"))
+
+	result, warnings, err := NewLister(client).ListInfos(context.Background(), nil, metav1.ListOptions{},
+		Filter{Excludes: []string{"kube-*"}}, now)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("ListInfos() warnings = %v, err = %v", warnings, err)
+	}
+	if result.Hidden != 1 {
+		t.Errorf("hidden = %d, want 1", result.Hidden)
+	}
+	if len(result.Infos) != 3 || len(result.Pods) != 3 {
+		t.Fatalf("got %d infos for %d pods, want 3", len(result.Infos), len(result.Pods))
+	}
+
+	byName := make(map[string]PodInfo)
+	for _, info := range result.Infos {
+		byName[info.Name] = info
+	}
+
+	frontend := byName["frontend"]
+	if frontend.ReadyString() != "2/3" || frontend.Restarts != 7 || frontend.Reason != "CrashLoopBackOff" || frontend.NodeName != "node-a" {
+		t.Errorf("frontend = ready %s, %d restarts, reason %q, node %q; want 2/3, 7, CrashLoopBackOff, node-a",
+			frontend.ReadyString(), frontend.Restarts, frontend.Reason, frontend.NodeName)
+	}
+	if frontend.Age != time.Hour {
+		t.Errorf("frontend age = %s, want 1h", frontend.Age)
+	}
+
+	pending := byName["pending"]
+	if pending.NodeName != "" || pending.Phase != "Pending" || pending.ReadyString() != "0/1" {
+		t.Errorf("pending = node %q, phase %q, ready %s; want unscheduled, Pending, 0/1", pending.NodeName, pending.Phase, pending.ReadyString())
+	}
+
+	fresh := byName["fresh"]
+	if fresh.ReadyString() != "0/2" || fresh.Restarts != 0 || fresh.LastRestartTime != nil || fresh.Reason != "" {
+		t.Errorf("fresh = ready %s, %d restarts, last restart %v, reason %q; want 0/2, 0, nil, empty",
+			fresh.ReadyString(), fresh.Restarts, fresh.LastRestartTime, fresh.Reason)
+	}
+	for _, c := range ExtractContainers(&result.Pods[indexOf(result.Pods, "fresh")], time.Now()) {
+		if c.State != "unknown" {
+			t.Errorf("container %s state = %q, want unknown without a status", c.Name, c.State)
+		}
+	}
+}
+
+// indexOf returns the index of the pod named name
+func indexOf(pods []v1.Pod, name string) int {
+	for i := range pods {
+		if pods[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
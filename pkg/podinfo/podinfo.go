@@ -0,0 +1,394 @@
+// Package podinfo lists pods and turns them into the PodInfo summaries the
+// pod lister prints: extraction, client-side filters and sorting.
+package podinfo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodInfo holds formatted pod information
+type PodInfo struct {
+	Cluster         string          `json:"cluster,omitempty"`
+	Name            string          `json:"name"`
+	Namespace       string          `json:"namespace"`
+	UID             string          `json:"uid,omitempty"`
+	NodeName        string          `json:"nodeName,omitempty"`
+	Phase           string          `json:"phase"`
+	Reason          string          `json:"reason"`
+	PodIP           string          `json:"podIP,omitempty"`
+	QOSClass        string          `json:"qosClass"`
+	Owner           string          `json:"owner,omitempty"`
+	ReadyContainers int             `json:"readyContainers"`
+	TotalContainers int             `json:"totalContainers"`
+	Restarts        int32           `json:"restarts"`
+	InitRestarts    int32           `json:"initRestarts,omitempty"`
+	LastRestartTime *time.Time      `json:"lastRestartTime,omitempty"`
+	Age             time.Duration   `json:"-"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	Containers      []ContainerInfo `json:"containers,omitempty"`
+	// ContainerImages are the images the containers run, the RunningImages
+	// of the pod: each spec image, resolved to a digest when the container's
+	// status reports one. The SpecImages they resolve from are not kept.
+	ContainerImages []string        `json:"containerImages,omitempty"`
+	Resources       *PodResources   `json:"resources,omitempty"`
+	Usage           *ResourceUsage  `json:"usage,omitempty"`
+	Events          []EventInfo     `json:"events,omitempty"`
+	Scheduling      *SchedulingInfo `json:"scheduling,omitempty"`
+	Volumes         []VolumeInfo    `json:"volumes,omitempty"`
+	// VulnerableImages lists the ContainerImages an image scanner flagged;
+	// it is not set by Extract
+	VulnerableImages []string `json:"vulnerableImages,omitempty"`
+	// LastTermination is how the previous run of the only app container
+	// ended, nil for pods with several containers; structured output has it
+	// under each entry of Containers instead
+	LastTermination *TerminationInfo `json:"-"`
+	// Labels and Annotations are only filled in when asked for; they are
+	// not set by Extract
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ServiceAccountName is the service account the pod runs as
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// ContainerInfo holds per-container details of a pod
+type ContainerInfo struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	ImageID  string `json:"imageID,omitempty"`
+	Init     bool   `json:"init,omitempty"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+	State    string `json:"state"`
+	Reason   string `json:"reason,omitempty"`
+	// InitProgress is only set on the init container blocking the start of
+	// the app containers, to the pod's progress the way kubectl shows it,
+	// e.g. "Init:1/3" while the second of three init containers runs
+	InitProgress string `json:"initProgress,omitempty"`
+	// LastTerminationReason is why the previous run of the container ended,
+	// e.g. OOMKilled for a container now in CrashLoopBackOff
+	LastTerminationReason string           `json:"lastTerminationReason,omitempty"`
+	LastTermination       *TerminationInfo `json:"lastTermination,omitempty"`
+}
+
+// oomKilledReason is the termination reason the kubelet reports for a
+// container killed for exceeding its memory limit (exit code 137)
+const oomKilledReason = "OOMKilled"
+
+// TerminationInfo describes how a run of a container ended
+type TerminationInfo struct {
+	ExitCode   int32         `json:"exitCode"`
+	Signal     int32         `json:"signal,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	FinishedAt time.Time     `json:"finishedAt"`
+	Age        time.Duration `json:"-"`
+}
+
+// OOMKilled reports whether the container was killed for running out of
+// memory, rather than exiting or being signalled otherwise
+func (t TerminationInfo) OOMKilled() bool {
+	return t.Reason == oomKilledReason
+}
+
+// ExtractTermination returns the details of a terminated state, with the
+// age of its end relative to now, or nil if state is nil
+func ExtractTermination(state *v1.ContainerStateTerminated, now time.Time) *TerminationInfo {
+	if state == nil {
+		return nil
+	}
+	info := &TerminationInfo{
+		ExitCode:   state.ExitCode,
+		Signal:     state.Signal,
+		Reason:     state.Reason,
+		FinishedAt: state.FinishedAt.Time,
+	}
+	if !state.FinishedAt.IsZero() {
+		info.Age = now.Sub(state.FinishedAt.Time).Truncate(time.Second)
+	}
+	return info
+}
+
+// TotalRestarts calculates total restart count for all containers in a pod
+func TotalRestarts(containerStatuses []v1.ContainerStatus) int32 {
+	var total int32
+	for _, cs := range containerStatuses {
+		total += cs.RestartCount
+	}
+	return total
+}
+
+// PodRestarts sums the restarts of every container of a pod, init containers
+// included, so a pod stuck Pending on a crash-looping init container does
+// not show zero restarts. PodInfo.InitRestarts holds the init containers'
+// share.
+func PodRestarts(pod *v1.Pod) int32 {
+	return TotalRestarts(allContainerStatuses(pod))
+}
+
+// allContainerStatuses returns the statuses of the init containers followed
+// by those of the app containers
+func allContainerStatuses(pod *v1.Pod) []v1.ContainerStatus {
+	statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// crashLoopReason is the waiting reason of a container the kubelet is
+// backing off from restarting after repeated crashes
+const crashLoopReason = "CrashLoopBackOff"
+
+// FailingContainer is a container whose logs explain a failure
+type FailingContainer struct {
+	Name string
+	// Previous is set when the failed run is the one before the current
+	// one, whose logs the API serves with PodLogOptions.Previous
+	Previous bool
+}
+
+// FailingContainers returns the containers of a pod, init containers first,
+// that are in CrashLoopBackOff or whose last run exited non-zero. The logs
+// of the failed run are those of the previous instance once the container
+// has restarted, unless it is terminated now and that run is the failure.
+func FailingContainers(pod *v1.Pod) []FailingContainer {
+	var failing []FailingContainer
+	for _, cs := range allContainerStatuses(pod) {
+		if terminated := cs.State.Terminated; terminated != nil {
+			if terminated.ExitCode != 0 {
+				failing = append(failing, FailingContainer{Name: cs.Name})
+			}
+			continue
+		}
+		crashLooping := cs.State.Waiting != nil && cs.State.Waiting.Reason == crashLoopReason
+		last := cs.LastTerminationState.Terminated
+		if crashLooping || last != nil && last.ExitCode != 0 {
+			failing = append(failing, FailingContainer{Name: cs.Name, Previous: cs.RestartCount > 0})
+		}
+	}
+	return failing
+}
+
+// LastRestartTime returns when the most recent container restart
+// happened, taken from the end of each container's previous run, or nil if
+// no container has restarted
+func LastRestartTime(containerStatuses []v1.ContainerStatus) *time.Time {
+	var last *time.Time
+	for _, cs := range containerStatuses {
+		terminated := cs.LastTerminationState.Terminated
+		if terminated == nil || terminated.FinishedAt.IsZero() {
+			continue
+		}
+		if last == nil || terminated.FinishedAt.After(*last) {
+			finished := terminated.FinishedAt.Time
+			last = &finished
+		}
+	}
+	return last
+}
+
+// ReadyContainers counts the ready containers of a pod. The total comes
+// from the spec, so pods without container statuses yet report 0/N.
+func ReadyContainers(pod *v1.Pod) (ready, total int) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+	}
+	return ready, len(pod.Spec.Containers)
+}
+
+// lastTermination returns the previous termination of the pod's only app
+// container, or nil if it has several or the container has not restarted
+func lastTermination(pod *v1.Pod, now time.Time) *TerminationInfo {
+	if len(pod.Spec.Containers) != 1 {
+		return nil
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == pod.Spec.Containers[0].Name {
+			return ExtractTermination(cs.LastTerminationState.Terminated, now)
+		}
+	}
+	return nil
+}
+
+// Extract extracts relevant information from a pod
+func Extract(pod *v1.Pod, now time.Time) PodInfo {
+	ready, total := ReadyContainers(pod)
+	return PodInfo{
+		Name:            pod.Name,
+		Namespace:       pod.Namespace,
+		UID:             string(pod.UID),
+		NodeName:        pod.Spec.NodeName,
+		Phase:           string(pod.Status.Phase),
+		Reason:          Reason(pod),
+		PodIP:           pod.Status.PodIP,
+		QOSClass:        string(QOSClass(pod)),
+		ReadyContainers: ready,
+		TotalContainers: total,
+		Restarts:        PodRestarts(pod),
+		InitRestarts:    TotalRestarts(pod.Status.InitContainerStatuses),
+		LastRestartTime: LastRestartTime(allContainerStatuses(pod)),
+		LastTermination: lastTermination(pod, now),
+		Age:             Age(pod, now),
+		CreatedAt:       pod.CreationTimestamp.Time,
+		ContainerImages: RunningImages(pod),
+
+		ServiceAccountName: pod.Spec.ServiceAccountName,
+	}
+}
+
+// SpecImages returns the distinct images of the init, app and ephemeral
+// containers of a pod as their specs set them, in that order
+func SpecImages(pod *v1.Pod) []string {
+	var images []string
+	seen := map[string]bool{}
+	add := func(image string) {
+		if image != "" && !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		add(c.Image)
+	}
+	for _, c := range pod.Spec.Containers {
+		add(c.Image)
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		add(c.Image)
+	}
+	return images
+}
+
+// ResolveImage returns the reference of the image a container runs by
+// digest, from the image of its spec and the ImageID of its status, e.g.
+// "docker.io/library/nginx@sha256:..." for "docker-pullable://nginx@sha256:...".
+// A bare digest is appended to the spec image; without an ImageID the spec
+// image is returned.
+func ResolveImage(image, imageID string) string {
+	if _, id, found := strings.Cut(imageID, "://"); found {
+		imageID = id
+	}
+	switch {
+	case strings.Contains(imageID, "@"):
+		return imageID
+	case strings.HasPrefix(imageID, "sha256:"):
+		return image + "@" + imageID
+	}
+	return image
+}
+
+// RunningImages returns the distinct images of the init, app and ephemeral
+// containers of a pod, in that order, resolved to the digest their status
+// reports when there is one (see ResolveImage)
+func RunningImages(pod *v1.Pod) []string {
+	var images []string
+	seen := map[string]bool{}
+	add := func(image string, statuses []v1.ContainerStatus, name string) {
+		for _, cs := range statuses {
+			if cs.Name == name {
+				image = ResolveImage(image, cs.ImageID)
+				break
+			}
+		}
+		if image != "" && !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		add(c.Image, pod.Status.InitContainerStatuses, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		add(c.Image, pod.Status.ContainerStatuses, c.Name)
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		add(c.Image, pod.Status.EphemeralContainerStatuses, c.Name)
+	}
+	return images
+}
+
+// ReadyString renders ready/total containers the way kubectl does, e.g. "2/3"
+func (info PodInfo) ReadyString() string {
+	return fmt.Sprintf("%d/%d", info.ReadyContainers, info.TotalContainers)
+}
+
+// ExtractContainers returns one entry per init container followed by one
+// per app container, matched with its status by name. Containers without a
+// status yet (e.g. before the pod is scheduled) report state "unknown".
+// The init container blocking startup has its InitProgress set. The ages of
+// previous terminations are relative to now.
+func ExtractContainers(pod *v1.Pod, now time.Time) []ContainerInfo {
+	blocking, progress := "", ""
+	if i := blockingInitContainer(pod); i >= 0 {
+		blocking, progress = pod.Status.InitContainerStatuses[i].Name, initProgress(pod, i)
+	}
+	var containers []ContainerInfo
+	add := func(specs []v1.Container, statuses []v1.ContainerStatus, init bool) {
+		byName := make(map[string]v1.ContainerStatus, len(statuses))
+		for _, cs := range statuses {
+			byName[cs.Name] = cs
+		}
+		for _, c := range specs {
+			info := ContainerInfo{Name: c.Name, Image: c.Image, Init: init, State: "unknown"}
+			if init && c.Name == blocking {
+				info.InitProgress = progress
+			}
+			if cs, ok := byName[c.Name]; ok {
+				if cs.ImageID != "" {
+					info.ImageID = ResolveImage(c.Image, cs.ImageID)
+				}
+				info.Ready = cs.Ready
+				info.Restarts = cs.RestartCount
+				switch {
+				case cs.State.Running != nil:
+					info.State = "running"
+				case cs.State.Waiting != nil:
+					info.State = "waiting"
+					info.Reason = cs.State.Waiting.Reason
+				case cs.State.Terminated != nil:
+					info.State = "terminated"
+				}
+				if cs.LastTerminationState.Terminated != nil {
+					info.LastTerminationReason = cs.LastTerminationState.Terminated.Reason
+					info.LastTermination = ExtractTermination(cs.LastTerminationState.Terminated, now)
+				}
+			}
+			containers = append(containers, info)
+		}
+	}
+	add(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)
+	add(pod.Spec.Containers, pod.Status.ContainerStatuses, false)
+	return containers
+}
+
+// Age returns how long ago the pod was created, to the second
+func Age(pod *v1.Pod, now time.Time) time.Duration {
+	return now.Sub(pod.CreationTimestamp.Time).Truncate(time.Second)
+}
+
+// ResourceUsage holds the live CPU and memory usage of a pod as reported by
+// metrics-server, and the usage as a percentage of the pod's requests when
+// it has them. Pending is set for pods metrics-server has no sample for
+// yet, typically because they just started.
+type ResourceUsage struct {
+	CPU           *resource.Quantity `json:"cpu,omitempty"`
+	Memory        *resource.Quantity `json:"memory,omitempty"`
+	CPUPercent    *int64             `json:"cpuPercentOfRequest,omitempty"`
+	MemoryPercent *int64             `json:"memoryPercentOfRequest,omitempty"`
+	Pending       bool               `json:"pending,omitempty"`
+}
+
+// EventInfo holds formatted information about an event of a pod
+type EventInfo struct {
+	Type     string        `json:"type"`
+	Reason   string        `json:"reason"`
+	Message  string        `json:"message"`
+	Count    int32         `json:"count,omitempty"`
+	Age      time.Duration `json:"-"`
+	LastSeen time.Time     `json:"lastSeen"`
+}
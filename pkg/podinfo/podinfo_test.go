@@ -0,0 +1,284 @@
+package podinfo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestPod returns a minimal pod in the given namespace
+func newTestPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func TestExtractContainerInfo(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate", Image: "migrate:1"}},
+			Containers: []v1.Container{
+				{Name: "app", Image: "app:2"},
+				{Name: "sidecar", Image: "proxy:3"},
+				{Name: "new", Image: "new:4"},
+			},
+		},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", Ready: true, State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "sidecar", RestartCount: 7, State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				{Name: "app", Ready: true, RestartCount: 1, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	want := []ContainerInfo{
+		{Name: "migrate", Image: "migrate:1", Init: true, Ready: true, State: "terminated"},
+		{Name: "app", Image: "app:2", Ready: true, Restarts: 1, State: "running"},
+		{Name: "sidecar", Image: "proxy:3", Restarts: 7, State: "waiting", Reason: "CrashLoopBackOff"},
+		{Name: "new", Image: "new:4", State: "unknown"},
+	}
+	if got := ExtractContainers(pod, time.Now()); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractContainers() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestReadyContainers(t *testing.T) {
+	threeContainers := v1.PodSpec{Containers: []v1.Container{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want string
+	}{
+		{
+			name: "partially ready",
+			pod: &v1.Pod{Spec: threeContainers, Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				{Name: "a", Ready: true}, {Name: "b", Ready: true}, {Name: "c"},
+			}}},
+			want: "2/3",
+		},
+		{
+			name: "no container statuses yet",
+			pod:  &v1.Pod{Spec: threeContainers, Status: v1.PodStatus{Phase: v1.PodPending}},
+			want: "0/3",
+		},
+		{
+			name: "no containers",
+			pod:  &v1.Pod{},
+			want: "0/0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extract(tt.pod, time.Now()).ReadyString(); got != tt.want {
+				t.Errorf("ready = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLastRestartTime(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	finished := func(ago time.Duration) v1.ContainerStatus {
+		return v1.ContainerStatus{
+			RestartCount: 1,
+			LastTerminationState: v1.ContainerState{
+				Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-ago))},
+			},
+		}
+	}
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		statuses []v1.ContainerStatus
+		want     *time.Time
+	}{
+		{name: "no statuses"},
+		{name: "never restarted", statuses: []v1.ContainerStatus{{Name: "app"}}},
+		{
+			name: "terminated without a finish time",
+			statuses: []v1.ContainerStatus{{
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}},
+			}},
+		},
+		{name: "one restart", statuses: []v1.ContainerStatus{finished(time.Hour)}, want: at(time.Hour)},
+		{
+			name:     "most recent of several containers",
+			statuses: []v1.ContainerStatus{finished(3 * time.Hour), {Name: "healthy"}, finished(10 * time.Minute)},
+			want:     at(10 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LastRestartTime(tt.statuses)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("LastRestartTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractBlockedOnInitContainer(t *testing.T) {
+	finished := metav1.NewTime(time.Date(2026, 7, 3, 2, 0, 0, 0, time.UTC))
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "config"}, {Name: "migrate"}, {Name: "warmup"}},
+			Containers:     []v1.Container{{Name: "app"}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "config", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+				{
+					Name: "migrate", RestartCount: 4,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, FinishedAt: finished}},
+				},
+				{Name: "warmup", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+			},
+		},
+	}
+
+	info := Extract(pod, finished.Add(time.Minute))
+	if info.Restarts != 4 || info.InitRestarts != 4 {
+		t.Errorf("Restarts, InitRestarts = %d, %d, want 4, 4", info.Restarts, info.InitRestarts)
+	}
+	if info.Reason != "Init:CrashLoopBackOff" {
+		t.Errorf("Reason = %q, want Init:CrashLoopBackOff", info.Reason)
+	}
+	if info.LastRestartTime == nil || !info.LastRestartTime.Equal(finished.Time) {
+		t.Errorf("LastRestartTime = %v, want %v", info.LastRestartTime, finished.Time)
+	}
+
+	var progress []string
+	for _, c := range ExtractContainers(pod, finished.Time) {
+		progress = append(progress, c.Name+"="+c.InitProgress)
+	}
+	if want := []string{"config=", "migrate=Init:1/3", "warmup=", "app="}; !reflect.DeepEqual(progress, want) {
+		t.Errorf("init progress = %v, want %v", progress, want)
+	}
+
+	// Once the blocking container stops crashing, the status shows progress
+	pod.Status.InitContainerStatuses[1].State = v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	if got := Reason(pod); got != "Init:1/3" {
+		t.Errorf("Reason with a running init container = %q, want Init:1/3", got)
+	}
+}
+
+func TestSpecImages(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "migrate", Image: "app:2"}},
+		Containers: []v1.Container{
+			{Name: "app", Image: "app:2"},
+			{Name: "proxy", Image: "envoy:1.30"},
+		},
+		EphemeralContainers: []v1.EphemeralContainer{
+			{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}},
+		},
+	}}
+	want := []string{"app:2", "envoy:1.30", "busybox"}
+	if got := SpecImages(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("SpecImages() = %v, want %v", got, want)
+	}
+	// Without container statuses nothing resolves to a digest
+	if got := Extract(pod, time.Now()).ContainerImages; !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract().ContainerImages = %v, want %v", got, want)
+	}
+}
+
+func TestResolveImage(t *testing.T) {
+	tests := []struct {
+		image, imageID, want string
+	}{
+		{"nginx:1.25", "", "nginx:1.25"},
+		{"nginx:1.25", "docker-pullable://nginx@sha256:abc", "nginx@sha256:abc"},
+		{"nginx:1.25", "docker.io/library/nginx@sha256:abc", "docker.io/library/nginx@sha256:abc"},
+		{"nginx:1.25", "sha256:abc", "nginx:1.25@sha256:abc"},
+		{"nginx:1.25", "docker://sha256:abc", "nginx:1.25@sha256:abc"},
+	}
+	for _, tt := range tests {
+		if got := ResolveImage(tt.image, tt.imageID); got != tt.want {
+			t.Errorf("ResolveImage(%q, %q) = %q, want %q", tt.image, tt.imageID, got, tt.want)
+		}
+	}
+}
+
+func TestRunningImages(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			ServiceAccountName: "web",
+			InitContainers:     []v1.Container{{Name: "migrate", Image: "app:2"}},
+			Containers: []v1.Container{
+				{Name: "app", Image: "app:2"},
+				{Name: "proxy", Image: "envoy:1.30"},
+			},
+		},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{{Name: "migrate", ImageID: "docker.io/library/app@sha256:a2"}},
+			// The proxy has no status yet
+			ContainerStatuses: []v1.ContainerStatus{{Name: "app", ImageID: "docker.io/library/app@sha256:a2"}},
+		},
+	}
+	want := []string{"docker.io/library/app@sha256:a2", "envoy:1.30"}
+	if got := RunningImages(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("RunningImages() = %v, want %v", got, want)
+	}
+	info := Extract(pod, time.Now())
+	if !reflect.DeepEqual(info.ContainerImages, want) || info.ServiceAccountName != "web" {
+		t.Errorf("Extract() images = %v, service account = %q", info.ContainerImages, info.ServiceAccountName)
+	}
+	containers := ExtractContainers(pod, time.Now())
+	if containers[1].ImageID != "docker.io/library/app@sha256:a2" || containers[2].ImageID != "" {
+		t.Errorf("ExtractContainers() image IDs = %q, %q", containers[1].ImageID, containers[2].ImageID)
+	}
+}
+
+func TestFailingContainers(t *testing.T) {
+	pod := newTestPod("default", "web")
+	pod.Status.InitContainerStatuses = []v1.ContainerStatus{
+		{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+		{Name: "seed", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 3}}},
+	}
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{
+		{Name: "healthy", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+		{
+			Name:                 "crashing",
+			RestartCount:         4,
+			State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1}},
+		},
+		{
+			Name:                 "recovered",
+			RestartCount:         1,
+			State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+		},
+		{
+			Name:                 "restarted-cleanly",
+			RestartCount:         1,
+			State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+			LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}},
+		},
+		{Name: "image", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+	}
+
+	want := []FailingContainer{
+		{Name: "seed"},
+		{Name: "crashing", Previous: true},
+		{Name: "recovered", Previous: true},
+	}
+	if got := FailingContainers(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("FailingContainers() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package podinfo
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// qosResources are the resources the QoS class is computed from
+var qosResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+
+// QOSClass returns the pod's QoS class as reported in its status, or
+// computes it from the container requests and limits the way the kubelet
+// does when the status does not have it yet:
+//   - BestEffort: no container requests or limits CPU or memory
+//   - Guaranteed: every container limits CPU and memory, and the summed
+//     requests equal the summed limits (the API server defaults missing
+//     requests to the limits)
+//   - Burstable: anything in between
+func QOSClass(pod *v1.Pod) v1.PodQOSClass {
+	if pod.Status.QOSClass != "" {
+		return pod.Status.QOSClass
+	}
+	requests := make(v1.ResourceList)
+	limits := make(v1.ResourceList)
+	guaranteed := true
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range containers {
+		for _, name := range qosResources {
+			if request, ok := c.Resources.Requests[name]; ok && request.Sign() > 0 {
+				addQuantity(requests, name, request)
+			}
+			if limit, ok := c.Resources.Limits[name]; ok && limit.Sign() > 0 {
+				addQuantity(limits, name, limit)
+			} else {
+				guaranteed = false
+			}
+		}
+	}
+	if len(requests) == 0 && len(limits) == 0 {
+		return v1.PodQOSBestEffort
+	}
+	if guaranteed && len(requests) == len(limits) {
+		for name, request := range requests {
+			if limit, ok := limits[name]; !ok || limit.Cmp(request) != 0 {
+				guaranteed = false
+			}
+		}
+		if guaranteed {
+			return v1.PodQOSGuaranteed
+		}
+	}
+	return v1.PodQOSBurstable
+}
+
+// addQuantity adds q to list[name]
+func addQuantity(list v1.ResourceList, name v1.ResourceName, q resource.Quantity) {
+	sum := list[name]
+	sum.Add(q)
+	list[name] = sum
+}
+
+// qosClasses are the valid --qos values
+var qosClasses = []v1.PodQOSClass{v1.PodQOSBestEffort, v1.PodQOSBurstable, v1.PodQOSGuaranteed}
+
+// ParseQOSClasses validates --qos values (case-insensitive) and returns
+// them as QoS classes
+func ParseQOSClasses(values []string) ([]v1.PodQOSClass, error) {
+	var classes []v1.PodQOSClass
+	for _, value := range values {
+		found := false
+		for _, class := range qosClasses {
+			if strings.EqualFold(value, string(class)) {
+				classes = append(classes, class)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown --qos class %q (want BestEffort, Burstable or Guaranteed)", value)
+		}
+	}
+	return classes, nil
+}
+
+// filterByQOSClass keeps pods in one of the given QoS classes. No classes
+// keeps every pod.
+func filterByQOSClass(pods []v1.Pod, classes []v1.PodQOSClass) []v1.Pod {
+	if len(classes) == 0 {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		class := QOSClass(&pods[i])
+		for _, want := range classes {
+			if class == want {
+				kept = append(kept, pods[i])
+				break
+			}
+		}
+	}
+	return kept
+}
@@ -0,0 +1,117 @@
+package podinfo
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// resourceList builds a CPU and memory list, leaving out empty values
+func resourceList(cpu, memory string) v1.ResourceList {
+	list := v1.ResourceList{}
+	if cpu != "" {
+		list[v1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		list[v1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return list
+}
+
+// containerWith returns a container with the given requests and limits
+func containerWith(requests, limits v1.ResourceList) v1.Container {
+	return v1.Container{Name: "app", Resources: v1.ResourceRequirements{Requests: requests, Limits: limits}}
+}
+
+func TestQOSClass(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  v1.Pod
+		want v1.PodQOSClass
+	}{
+		{
+			name: "status wins over the spec",
+			pod: v1.Pod{
+				Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+				Status: v1.PodStatus{QOSClass: v1.PodQOSGuaranteed},
+			},
+			want: v1.PodQOSGuaranteed,
+		},
+		{
+			name: "no requests or limits",
+			pod:  v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}},
+			want: v1.PodQOSBestEffort,
+		},
+		{
+			name: "requests equal to limits",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				containerWith(resourceList("500m", "256Mi"), resourceList("500m", "256Mi")),
+			}}},
+			want: v1.PodQOSGuaranteed,
+		},
+		{
+			name: "requests below limits",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				containerWith(resourceList("100m", "256Mi"), resourceList("500m", "256Mi")),
+			}}},
+			want: v1.PodQOSBurstable,
+		},
+		{
+			name: "one container without memory limit",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				containerWith(resourceList("500m", "256Mi"), resourceList("500m", "256Mi")),
+				containerWith(resourceList("100m", ""), resourceList("100m", "")),
+			}}},
+			want: v1.PodQOSBurstable,
+		},
+		{
+			name: "init container requests only",
+			pod: v1.Pod{Spec: v1.PodSpec{
+				InitContainers: []v1.Container{containerWith(resourceList("100m", ""), nil)},
+				Containers:     []v1.Container{{Name: "app"}},
+			}},
+			want: v1.PodQOSBurstable,
+		},
+		{
+			name: "zero quantities are ignored",
+			pod: v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+				containerWith(resourceList("0", "0"), nil),
+			}}},
+			want: v1.PodQOSBestEffort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QOSClass(&tt.pod); got != tt.want {
+				t.Errorf("QOSClass() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQOSClasses(t *testing.T) {
+	classes, err := ParseQOSClasses([]string{"besteffort", "Burstable"})
+	if err != nil {
+		t.Fatalf("ParseQOSClasses() error = %v", err)
+	}
+	if len(classes) != 2 || classes[0] != v1.PodQOSBestEffort || classes[1] != v1.PodQOSBurstable {
+		t.Errorf("ParseQOSClasses() = %v, want [BestEffort Burstable]", classes)
+	}
+	if _, err := ParseQOSClasses([]string{"Premium"}); err == nil {
+		t.Error("ParseQOSClasses(Premium) succeeded, want an error")
+	}
+}
+
+func TestFilterByQOSClass(t *testing.T) {
+	pods := []v1.Pod{*newTestPod("default", "cache"), *newTestPod("default", "db"), *newTestPod("default", "web")}
+	pods[0].Status.QOSClass = v1.PodQOSBestEffort
+	pods[1].Status.QOSClass = v1.PodQOSGuaranteed
+	pods[2].Status.QOSClass = v1.PodQOSBurstable
+
+	kept, _ := Filter{QOSClasses: []v1.PodQOSClass{v1.PodQOSBestEffort, v1.PodQOSBurstable}}.Apply(pods, time.Now())
+	if len(kept) != 2 || kept[0].Name != "cache" || kept[1].Name != "web" {
+		t.Errorf("kept %d pods (%v), want cache and web", len(kept), kept)
+	}
+}
@@ -0,0 +1,104 @@
+package podinfo
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Reason returns the pod status the way kubectl's STATUS column shows
+// it: the phase, refined by init container progress, the reason a container
+// is waiting or terminated (CrashLoopBackOff, ImagePullBackOff, OOMKilled,
+// Completed, ...) and Terminating for pods being deleted.
+func Reason(pod *v1.Pod) string {
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+
+	initializing := false
+	if i := blockingInitContainer(pod); i >= 0 {
+		cs := pod.Status.InitContainerStatuses[i]
+		switch {
+		case cs.State.Terminated != nil:
+			if cs.State.Terminated.Reason != "" {
+				reason = "Init:" + cs.State.Terminated.Reason
+			} else if cs.State.Terminated.Signal != 0 {
+				reason = fmt.Sprintf("Init:Signal:%d", cs.State.Terminated.Signal)
+			} else {
+				reason = fmt.Sprintf("Init:ExitCode:%d", cs.State.Terminated.ExitCode)
+			}
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing":
+			reason = "Init:" + cs.State.Waiting.Reason
+		default:
+			reason = initProgress(pod, i)
+		}
+		initializing = true
+	}
+
+	if !initializing {
+		hasRunning := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			cs := pod.Status.ContainerStatuses[i]
+			switch {
+			case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+				reason = cs.State.Waiting.Reason
+			case cs.State.Terminated != nil && cs.State.Terminated.Reason != "":
+				reason = cs.State.Terminated.Reason
+			case cs.State.Terminated != nil && cs.State.Terminated.Signal != 0:
+				reason = fmt.Sprintf("Signal:%d", cs.State.Terminated.Signal)
+			case cs.State.Terminated != nil:
+				reason = fmt.Sprintf("ExitCode:%d", cs.State.Terminated.ExitCode)
+			case cs.Ready && cs.State.Running != nil:
+				hasRunning = true
+			}
+		}
+		// A completed container next to running ones does not make the pod
+		// completed
+		if reason == "Completed" && hasRunning {
+			reason = string(v1.PodRunning)
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			reason = "Unknown"
+		} else {
+			reason = "Terminating"
+		}
+	}
+	return reason
+}
+
+// blockingInitContainer returns the index in InitContainerStatuses of the
+// init container holding up the start of the pod's app containers, or -1
+// once every init container completed (or, for sidecars, started)
+func blockingInitContainer(pod *v1.Pod) int {
+	for i, cs := range pod.Status.InitContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			continue
+		case isSidecar(pod, cs.Name) && cs.Started != nil && *cs.Started:
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// initProgress renders how many init containers completed before the i-th,
+// the way kubectl does, e.g. "Init:1/3"
+func initProgress(pod *v1.Pod, i int) string {
+	return fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+}
+
+// isSidecar reports whether the named init container is a restartable
+// (sidecar) init container
+func isSidecar(pod *v1.Pod, name string) bool {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package podinfo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// running, waiting and terminated build container states for the tests
+func running() v1.ContainerState { return v1.ContainerState{Running: &v1.ContainerStateRunning{}} }
+
+func waiting(reason string) v1.ContainerState {
+	return v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}
+}
+
+func terminated(reason string, exitCode int32) v1.ContainerState {
+	return v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode}}
+}
+
+func TestPodReason(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name string
+		pod  v1.Pod
+		want string
+	}{
+		{
+			name: "healthy pod shows its phase",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Ready: true, State: running()},
+			}}},
+			want: "Running",
+		},
+		{
+			name: "one crash looping container among healthy ones",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Ready: true, State: running()},
+				{Name: "sidecar", State: waiting("CrashLoopBackOff"), LastTerminationState: terminated("OOMKilled", 137)},
+				{Name: "proxy", Ready: true, State: running()},
+			}}},
+			want: "CrashLoopBackOff",
+		},
+		{
+			name: "image pull failure",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodPending, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: waiting("ImagePullBackOff")},
+			}}},
+			want: "ImagePullBackOff",
+		},
+		{
+			name: "OOM killed container",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: terminated("OOMKilled", 137)},
+			}}},
+			want: "OOMKilled",
+		},
+		{
+			name: "completed pod",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodSucceeded, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "job", State: terminated("Completed", 0)},
+			}}},
+			want: "Completed",
+		},
+		{
+			name: "completed container next to a running one",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Ready: true, State: running()},
+				{Name: "job", State: terminated("Completed", 0)},
+			}}},
+			want: "Running",
+		},
+		{
+			name: "terminated without reason shows the exit code",
+			pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", State: terminated("", 2)},
+			}}},
+			want: "ExitCode:2",
+		},
+		{
+			name: "init container failing",
+			pod: v1.Pod{
+				Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "migrate"}}},
+				Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{
+					{Name: "migrate", State: waiting("CrashLoopBackOff")},
+				}},
+			},
+			want: "Init:CrashLoopBackOff",
+		},
+		{
+			name: "init containers in progress",
+			pod: v1.Pod{
+				Spec: v1.PodSpec{InitContainers: []v1.Container{{Name: "first"}, {Name: "second"}}},
+				Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{
+					{Name: "first", State: terminated("Completed", 0)},
+					{Name: "second", State: running()},
+				}},
+			},
+			want: "Init:1/2",
+		},
+		{
+			name: "being deleted",
+			pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Status:     v1.PodStatus{Phase: v1.PodRunning},
+			},
+			want: "Terminating",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reason(&tt.pod); got != tt.want {
+				t.Errorf("Reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterByReason(t *testing.T) {
+	healthy := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "healthy"},
+		Status:     v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{Name: "app", Ready: true, State: running()}}},
+	}
+	crashing := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "crashing"},
+		Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", Ready: true, State: running()},
+			{Name: "worker", State: waiting("CrashLoopBackOff")},
+		}},
+	}
+
+	kept := filterByReason([]v1.Pod{healthy, crashing}, []string{"crashloopbackoff", "ImagePullBackOff"})
+	if len(kept) != 1 || kept[0].Name != "crashing" {
+		t.Errorf("filterByReason kept %d pods, want only crashing", len(kept))
+	}
+	if all := filterByReason([]v1.Pod{healthy, crashing}, nil); len(all) != 2 {
+		t.Errorf("filterByReason without reasons kept %d pods, want 2", len(all))
+	}
+}
+
+func TestExtractContainerLastTermination(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	lastState := terminated("OOMKilled", 137)
+	lastState.Terminated.FinishedAt = metav1.NewTime(now.Add(-7 * time.Hour))
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", State: waiting("CrashLoopBackOff"), LastTerminationState: lastState},
+		}},
+	}
+	want := &TerminationInfo{ExitCode: 137, Reason: "OOMKilled", FinishedAt: now.Add(-7 * time.Hour), Age: 7 * time.Hour}
+
+	containers := ExtractContainers(pod, now)
+	if len(containers) != 1 || containers[0].LastTerminationReason != "OOMKilled" {
+		t.Fatalf("ExtractContainers() = %+v, want last termination OOMKilled", containers)
+	}
+	if got := containers[0].LastTermination; !reflect.DeepEqual(got, want) || !got.OOMKilled() {
+		t.Errorf("container last termination = %+v, want %+v", got, want)
+	}
+	if got := Extract(pod, now).LastTermination; !reflect.DeepEqual(got, want) {
+		t.Errorf("pod last termination = %+v, want %+v for a single-container pod", got, want)
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: "sidecar"})
+	if got := Extract(pod, now).LastTermination; got != nil {
+		t.Errorf("pod last termination = %+v, want nil with several containers", got)
+	}
+}
+
+func TestExtractTermination(t *testing.T) {
+	now := time.Now()
+	if got := ExtractTermination(nil, now); got != nil {
+		t.Errorf("ExtractTermination(nil) = %+v, want nil", got)
+	}
+	// SIGKILL without OOMKilled, e.g. a failed liveness probe
+	got := ExtractTermination(&v1.ContainerStateTerminated{ExitCode: 137, Signal: 9, Reason: "Error"}, now)
+	want := &TerminationInfo{ExitCode: 137, Signal: 9, Reason: "Error"}
+	if !reflect.DeepEqual(got, want) || got.OOMKilled() {
+		t.Errorf("ExtractTermination() = %+v, want %+v and not OOMKilled", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package podinfo
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodResources holds the effective CPU and memory requests and limits of a
+// pod. A nil quantity means no container sets it.
+type PodResources struct {
+	CPURequest    *resource.Quantity `json:"cpuRequest,omitempty"`
+	CPULimit      *resource.Quantity `json:"cpuLimit,omitempty"`
+	MemoryRequest *resource.Quantity `json:"memoryRequest,omitempty"`
+	MemoryLimit   *resource.Quantity `json:"memoryLimit,omitempty"`
+	// Requests and Limits sum the requests and limits of the app containers
+	// as their specs set them, every resource included, see sumResourceLists
+	Requests v1.ResourceList `json:"requests,omitempty"`
+	Limits   v1.ResourceList `json:"limits,omitempty"`
+}
+
+// ExtractResources computes the effective requests and limits of a pod
+// the way the scheduler does: the larger of the app containers' sum (plus
+// sidecars, i.e. restartable init containers) and the largest regular init
+// container (plus the sidecars started before it), plus the pod overhead.
+func ExtractResources(pod *v1.Pod) PodResources {
+	requests := effectiveResources(pod, func(c v1.Container) v1.ResourceList { return c.Resources.Requests })
+	limits := effectiveResources(pod, func(c v1.Container) v1.ResourceList { return c.Resources.Limits })
+	res := PodResources{
+		CPURequest:    quantityOrNil(requests, v1.ResourceCPU),
+		CPULimit:      quantityOrNil(limits, v1.ResourceCPU),
+		MemoryRequest: quantityOrNil(requests, v1.ResourceMemory),
+		MemoryLimit:   quantityOrNil(limits, v1.ResourceMemory),
+	}
+	res.Requests, res.Limits = sumResourceLists(pod.Spec.Containers)
+	return res
+}
+
+// sumResourceLists adds up the requests and limits of containers. A
+// resource is only limited when every container limits it; one container
+// without a limit leaves the sum unbounded, so it is left out of limits.
+// Either list is nil when it would be empty.
+func sumResourceLists(containers []v1.Container) (requests, limits v1.ResourceList) {
+	requests = v1.ResourceList{}
+	for _, c := range containers {
+		AddResources(requests, c.Resources.Requests)
+	}
+
+	limits = v1.ResourceList{}
+	for i, c := range containers {
+		if i == 0 {
+			AddResources(limits, c.Resources.Limits)
+			continue
+		}
+		for name := range limits {
+			if _, ok := c.Resources.Limits[name]; !ok {
+				delete(limits, name)
+			}
+		}
+		for name, q := range c.Resources.Limits {
+			if sum, ok := limits[name]; ok {
+				sum.Add(q)
+				limits[name] = sum
+			}
+		}
+	}
+
+	if len(requests) == 0 {
+		requests = nil
+	}
+	if len(limits) == 0 {
+		limits = nil
+	}
+	return requests, limits
+}
+
+// effectiveResources applies the effective request rules to the resource
+// list returned by get for each container
+func effectiveResources(pod *v1.Pod, get func(v1.Container) v1.ResourceList) v1.ResourceList {
+	apps := v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		AddResources(apps, get(c))
+	}
+
+	sidecars := v1.ResourceList{}
+	initMax := v1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			AddResources(sidecars, get(c))
+			continue
+		}
+		running := v1.ResourceList{}
+		AddResources(running, sidecars)
+		AddResources(running, get(c))
+		maxResources(initMax, running)
+	}
+
+	effective := v1.ResourceList{}
+	AddResources(effective, apps)
+	AddResources(effective, sidecars)
+	maxResources(effective, initMax)
+	if len(effective) > 0 {
+		AddResources(effective, pod.Spec.Overhead)
+	}
+	return effective
+}
+
+// AddResources adds every quantity in add to list
+func AddResources(list, add v1.ResourceList) {
+	for name, q := range add {
+		sum := list[name]
+		sum.Add(q)
+		list[name] = sum
+	}
+}
+
+// maxResources raises every quantity in list to at least the one in other
+func maxResources(list, other v1.ResourceList) {
+	for name, q := range other {
+		if current, ok := list[name]; !ok || q.Cmp(current) > 0 {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+// quantityOrNil returns the named quantity, or nil if it is unset or zero
+func quantityOrNil(list v1.ResourceList, name v1.ResourceName) *resource.Quantity {
+	q, ok := list[name]
+	if !ok || q.IsZero() {
+		return nil
+	}
+	return &q
+}
@@ -0,0 +1,90 @@
+package podinfo
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSumResourceLists(t *testing.T) {
+	container := func(requests, limits map[v1.ResourceName]string) v1.Container {
+		list := func(values map[v1.ResourceName]string) v1.ResourceList {
+			if values == nil {
+				return nil
+			}
+			l := v1.ResourceList{}
+			for name, value := range values {
+				l[name] = resource.MustParse(value)
+			}
+			return l
+		}
+		return v1.Container{Resources: v1.ResourceRequirements{Requests: list(requests), Limits: list(limits)}}
+	}
+
+	tests := []struct {
+		name         string
+		containers   []v1.Container
+		wantRequests map[v1.ResourceName]string
+		wantLimits   map[v1.ResourceName]string
+	}{
+		{
+			name:       "nothing set",
+			containers: []v1.Container{{}, {}},
+		},
+		{
+			name: "fractional CPU and Mi memory",
+			containers: []v1.Container{
+				container(map[v1.ResourceName]string{"cpu": "0.25", "memory": "128Mi"}, map[v1.ResourceName]string{"cpu": "500m", "memory": "256Mi"}),
+				container(map[v1.ResourceName]string{"cpu": "250m", "memory": "128Mi"}, map[v1.ResourceName]string{"cpu": "1.5", "memory": "256Mi"}),
+			},
+			wantRequests: map[v1.ResourceName]string{"cpu": "500m", "memory": "256Mi"},
+			wantLimits:   map[v1.ResourceName]string{"cpu": "2", "memory": "512Mi"},
+		},
+		{
+			name: "Gi and Mi memory",
+			containers: []v1.Container{
+				container(map[v1.ResourceName]string{"memory": "1Gi"}, map[v1.ResourceName]string{"memory": "2Gi"}),
+				container(map[v1.ResourceName]string{"memory": "512Mi"}, map[v1.ResourceName]string{"memory": "512Mi"}),
+			},
+			wantRequests: map[v1.ResourceName]string{"memory": "1536Mi"},
+			wantLimits:   map[v1.ResourceName]string{"memory": "2560Mi"},
+		},
+		{
+			name: "a container without limits",
+			containers: []v1.Container{
+				container(map[v1.ResourceName]string{"cpu": "100m"}, map[v1.ResourceName]string{"cpu": "200m", "memory": "64Mi"}),
+				container(map[v1.ResourceName]string{"cpu": "100m"}, map[v1.ResourceName]string{"memory": "64Mi"}),
+				container(map[v1.ResourceName]string{"cpu": "100m"}, map[v1.ResourceName]string{"memory": "64Mi", "cpu": "1"}),
+			},
+			wantRequests: map[v1.ResourceName]string{"cpu": "300m"},
+			wantLimits:   map[v1.ResourceName]string{"memory": "192Mi"},
+		},
+	}
+
+	check := func(t *testing.T, kind string, got v1.ResourceList, want map[v1.ResourceName]string) {
+		t.Helper()
+		if len(want) == 0 {
+			if got != nil {
+				t.Errorf("%s = %v, want nil", kind, got)
+			}
+			return
+		}
+		if len(got) != len(want) {
+			t.Errorf("%s = %v, want %v", kind, got, want)
+		}
+		for name, value := range want {
+			q, ok := got[name]
+			if !ok || q.Cmp(resource.MustParse(value)) != 0 {
+				t.Errorf("%s %s = %s, want %s", kind, name, q.String(), value)
+			}
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, limits := sumResourceLists(tt.containers)
+			check(t, "requests", requests, tt.wantRequests)
+			check(t, "limits", limits, tt.wantLimits)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package podinfo
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Scheduling sources: where a SchedulingInfo's reason and message came from
+const (
+	SchedulingFromEvent     = "event"
+	SchedulingFromCondition = "condition"
+)
+
+// SchedulingInfo explains why a pod is not bound to a node yet: the node
+// constraints it asks for and what the scheduler last said about it
+type SchedulingInfo struct {
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeAffinity holds the required node affinity terms; a node has to
+	// match one of them
+	NodeAffinity []string `json:"nodeAffinity,omitempty"`
+	Tolerations  []string `json:"tolerations,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	// Source is SchedulingFromEvent or SchedulingFromCondition, empty when
+	// the scheduler has not reported anything
+	Source string `json:"source,omitempty"`
+}
+
+// ExtractScheduling returns the scheduling constraints of pod, with the
+// reason and message of its PodScheduled condition when that is false.
+// Callers with access to events can replace those with the most recent
+// FailedScheduling event, which is usually more specific; the condition
+// remains once the events have been garbage-collected.
+func ExtractScheduling(pod *v1.Pod) *SchedulingInfo {
+	info := &SchedulingInfo{}
+	if len(pod.Spec.NodeSelector) > 0 {
+		info.NodeSelector = pod.Spec.NodeSelector
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for _, term := range required.NodeSelectorTerms {
+				info.NodeAffinity = append(info.NodeAffinity, FormatNodeSelectorTerm(term))
+			}
+		}
+	}
+	for _, t := range pod.Spec.Tolerations {
+		info.Tolerations = append(info.Tolerations, FormatToleration(t))
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse {
+			info.Reason = cond.Reason
+			info.Message = cond.Message
+			info.Source = SchedulingFromCondition
+		}
+	}
+	return info
+}
+
+// FormatNodeSelectorTerm renders a node selector term as comma-separated
+// requirements, e.g. "topology.kubernetes.io/zone In (a,b), gpu Exists"
+func FormatNodeSelectorTerm(term v1.NodeSelectorTerm) string {
+	var parts []string
+	format := func(reqs []v1.NodeSelectorRequirement, prefix string) {
+		for _, req := range reqs {
+			part := prefix + req.Key + " " + string(req.Operator)
+			if len(req.Values) > 0 {
+				part += " (" + strings.Join(req.Values, ",") + ")"
+			}
+			parts = append(parts, part)
+		}
+	}
+	format(term.MatchExpressions, "")
+	format(term.MatchFields, "field:")
+	if len(parts) == 0 {
+		return "<empty>"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatToleration renders a toleration the way kubectl describe does,
+// e.g. "dedicated=gpu:NoSchedule" or "node.kubernetes.io/not-ready:NoExecute op=Exists for 300s"
+func FormatToleration(t v1.Toleration) string {
+	s := t.Key
+	if t.Value != "" {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + string(t.Effect)
+	}
+	if t.Operator == v1.TolerationOpExists && t.Value == "" {
+		if s != "" {
+			s += " "
+		}
+		s += "op=Exists"
+	}
+	if t.TolerationSeconds != nil {
+		s += fmt.Sprintf(" for %ds", *t.TolerationSeconds)
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package podinfo
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestExtractScheduling(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			NodeSelector: map[string]string{"disktype": "ssd"},
+			Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{MatchExpressions: []v1.NodeSelectorRequirement{
+						{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpIn, Values: []string{"eu-1a", "eu-1b"}},
+						{Key: "gpu", Operator: v1.NodeSelectorOpExists},
+					}},
+					{MatchFields: []v1.NodeSelectorRequirement{
+						{Key: "metadata.name", Operator: v1.NodeSelectorOpNotIn, Values: []string{"node-3"}},
+					}},
+				}},
+			}},
+			Tolerations: []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}},
+		},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{{
+			Type:    v1.PodScheduled,
+			Status:  v1.ConditionFalse,
+			Reason:  "Unschedulable",
+			Message: "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+		}}},
+	}
+
+	info := ExtractScheduling(pod)
+	want := &SchedulingInfo{
+		NodeSelector: map[string]string{"disktype": "ssd"},
+		NodeAffinity: []string{"topology.kubernetes.io/zone In (eu-1a,eu-1b), gpu Exists", "field:metadata.name NotIn (node-3)"},
+		Tolerations:  []string{"dedicated=gpu:NoSchedule"},
+		Reason:       "Unschedulable",
+		Message:      "0/3 nodes are available: 3 node(s) didn't match Pod's node affinity/selector.",
+		Source:       SchedulingFromCondition,
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("ExtractScheduling() = %+v, want %+v", info, want)
+	}
+}
+
+func TestExtractSchedulingNoConstraints(t *testing.T) {
+	info := ExtractScheduling(&v1.Pod{})
+	if !reflect.DeepEqual(info, &SchedulingInfo{}) {
+		t.Errorf("ExtractScheduling() = %+v, want no constraints and no scheduler message", info)
+	}
+}
+
+func TestFormatToleration(t *testing.T) {
+	seconds := int64(300)
+	tests := []struct {
+		toleration v1.Toleration
+		want       string
+	}{
+		{v1.Toleration{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu", Effect: v1.TaintEffectNoSchedule}, "dedicated=gpu:NoSchedule"},
+		{v1.Toleration{Key: "node.kubernetes.io/not-ready", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute, TolerationSeconds: &seconds},
+			"node.kubernetes.io/not-ready:NoExecute op=Exists for 300s"},
+		{v1.Toleration{Operator: v1.TolerationOpExists}, "op=Exists"},
+		{v1.Toleration{Key: "spot", Value: "true"}, "spot=true"},
+	}
+	for _, tt := range tests {
+		if got := FormatToleration(tt.toleration); got != tt.want {
+			t.Errorf("FormatToleration(%+v) = %q, want %q", tt.toleration, got, tt.want)
+		}
+	}
+}
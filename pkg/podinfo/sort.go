@@ -0,0 +1,142 @@
+package podinfo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Sort keys, as accepted by --sort-by
+const (
+	SortByAge       = "age"
+	SortByCPU       = "cpu"
+	SortByMemory    = "memory"
+	SortByName      = "name"
+	SortByNamespace = "namespace"
+	SortByNode      = "node"
+	SortByPhase     = "phase"
+	SortByRestarts  = "restarts"
+)
+
+// sortKeys lists the valid --sort-by keys, for error messages
+var sortKeys = []string{SortByAge, SortByCPU, SortByMemory, SortByName, SortByNamespace, SortByNode, SortByPhase, SortByRestarts}
+
+// Sort directions, as accepted after a --sort-by key, e.g. "restarts:desc"
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// SortKey is one key of a --sort-by list
+type SortKey struct {
+	Key        string
+	Descending bool
+}
+
+// ParseSortKeys parses a comma-separated --sort-by list such as
+// "namespace,restarts:desc,name". A key without a direction sorts the way
+// it did on its own: usage sorts the busiest pods first, age the youngest
+// first and every other key ascending.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, direction, hasDirection := strings.Cut(field, ":")
+		if !slices.Contains(sortKeys, name) {
+			return nil, fmt.Errorf("unknown --sort-by key %q (want %s)", name, strings.Join(sortKeys, ", "))
+		}
+		key := SortKey{Key: name, Descending: name == SortByCPU || name == SortByMemory}
+		if hasDirection {
+			switch direction {
+			case SortAscending:
+				key.Descending = false
+			case SortDescending:
+				key.Descending = true
+			default:
+				return nil, fmt.Errorf("unknown --sort-by direction %q in %q (want %s or %s)", direction, field, SortAscending, SortDescending)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ReverseSortKeys flips the direction of every key, for --reverse
+func ReverseSortKeys(keys []SortKey) []SortKey {
+	reversed := make([]SortKey, len(keys))
+	for i, key := range keys {
+		reversed[i] = SortKey{Key: key.Key, Descending: !key.Descending}
+	}
+	return reversed
+}
+
+// Sort orders pods by keys, the first key deciding and each following key
+// breaking the ties of the previous ones. The sort is stable: pods equal on
+// every key keep their relative order. Pods still pending metrics sort last
+// on a usage key whatever its direction.
+func Sort(infos []PodInfo, keys []SortKey) {
+	slices.SortStableFunc(infos, func(a, b PodInfo) int {
+		for _, key := range keys {
+			if c := key.compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// compare orders two pods by the key, in its direction
+func (k SortKey) compare(a, b PodInfo) int {
+	var c int
+	switch k.Key {
+	case SortByCPU, SortByMemory:
+		qa, qb := usageValue(a, k.Key), usageValue(b, k.Key)
+		if qa == nil || qb == nil {
+			// Missing usage goes last in either direction
+			switch {
+			case qa == nil && qb == nil:
+				return 0
+			case qa == nil:
+				return 1
+			default:
+				return -1
+			}
+		}
+		c = qa.Cmp(*qb)
+	case SortByAge:
+		// The youngest pod, created last, has the smallest age
+		c = b.CreatedAt.Compare(a.CreatedAt)
+	case SortByName:
+		c = strings.Compare(a.Name, b.Name)
+	case SortByNamespace:
+		c = strings.Compare(a.Namespace, b.Namespace)
+	case SortByNode:
+		c = strings.Compare(a.NodeName, b.NodeName)
+	case SortByPhase:
+		c = strings.Compare(a.Phase, b.Phase)
+	case SortByRestarts:
+		c = cmp.Compare(a.Restarts, b.Restarts)
+	}
+	if k.Descending {
+		return -c
+	}
+	return c
+}
+
+// usageValue returns the CPU or memory usage of a pod, nil while its
+// metrics are pending
+func usageValue(info PodInfo, key string) *resource.Quantity {
+	if info.Usage == nil || info.Usage.Pending {
+		return nil
+	}
+	if key == SortByCPU {
+		return info.Usage.CPU
+	}
+	return info.Usage.Memory
+}
@@ -0,0 +1,139 @@
+package podinfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []SortKey
+		wantErr string
+	}{
+		{spec: "", want: nil},
+		{spec: "restarts", want: []SortKey{{Key: SortByRestarts}}},
+		{spec: "cpu", want: []SortKey{{Key: SortByCPU, Descending: true}}},
+		{spec: "memory:asc", want: []SortKey{{Key: SortByMemory}}},
+		{
+			spec: "namespace, restarts:desc,name:asc",
+			want: []SortKey{{Key: SortByNamespace}, {Key: SortByRestarts, Descending: true}, {Key: SortByName}},
+		},
+		{spec: "namespace,size", wantErr: "want age, cpu, memory, name, namespace, node, phase, restarts"},
+		{spec: "restarts:down", wantErr: `unknown --sort-by direction "down"`},
+	}
+	for _, tt := range tests {
+		got, err := ParseSortKeys(tt.spec)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseSortKeys(%q) error = %v, want it to contain %q", tt.spec, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSortKeys(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSortKeys(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+// sortedNames sorts infos by spec and returns their names
+func sortedNames(t *testing.T, infos []PodInfo, spec string) []string {
+	t.Helper()
+	keys, err := ParseSortKeys(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Sort(infos, keys)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+func TestSortByMultipleKeys(t *testing.T) {
+	infos := []PodInfo{
+		{Namespace: "web", Name: "web-b", Restarts: 0},
+		{Namespace: "db", Name: "db-b", Restarts: 3},
+		{Namespace: "web", Name: "web-c", Restarts: 12},
+		{Namespace: "db", Name: "db-a", Restarts: 3},
+		{Namespace: "web", Name: "web-a", Restarts: 0},
+		{Namespace: "db", Name: "db-c", Restarts: 0},
+	}
+	got := sortedNames(t, infos, "namespace,restarts:desc,name")
+	want := []string{"db-a", "db-b", "db-c", "web-c", "web-a", "web-b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+
+	got = sortedNames(t, infos, "namespace:desc,name:desc")
+	want = []string{"web-c", "web-b", "web-a", "db-c", "db-b", "db-a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted descending = %v, want %v", got, want)
+	}
+}
+
+func TestSortIsStable(t *testing.T) {
+	// Pods equal on every key keep the order they were listed in
+	infos := []PodInfo{
+		{Namespace: "web", Name: "third", Restarts: 1},
+		{Namespace: "db", Name: "first", Restarts: 1},
+		{Namespace: "web", Name: "fourth", Restarts: 1},
+		{Namespace: "db", Name: "second", Restarts: 1},
+		{Namespace: "db", Name: "crashing", Restarts: 9},
+	}
+	got := sortedNames(t, infos, "namespace,restarts:desc")
+	want := []string{"crashing", "first", "second", "third", "fourth"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+
+	// Sorting again by a single key keeps the previous order among equals
+	got = sortedNames(t, infos, "restarts")
+	want = []string{"first", "second", "third", "fourth", "crashing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("re-sorted by restarts = %v, want %v", got, want)
+	}
+}
+
+func TestSortPendingUsageLast(t *testing.T) {
+	usage := func(cpu string) *ResourceUsage {
+		q := resource.MustParse(cpu)
+		return &ResourceUsage{CPU: &q}
+	}
+	now := time.Now()
+	infos := []PodInfo{
+		{Name: "pending", Usage: &ResourceUsage{Pending: true}, CreatedAt: now},
+		{Name: "idle", Usage: usage("5m"), CreatedAt: now.Add(-time.Hour)},
+		{Name: "busy", Usage: usage("900m"), CreatedAt: now.Add(-time.Minute)},
+	}
+	if got, want := sortedNames(t, infos, "cpu"), []string{"busy", "idle", "pending"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by cpu = %v, want %v", got, want)
+	}
+	if got, want := sortedNames(t, infos, "cpu:asc"), []string{"idle", "busy", "pending"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by cpu ascending = %v, want %v", got, want)
+	}
+	if got, want := sortedNames(t, infos, "age"), []string{"pending", "busy", "idle"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted by age = %v, want %v", got, want)
+	}
+}
+
+func TestReverseSortKeys(t *testing.T) {
+	keys := []SortKey{{Key: SortByNamespace}, {Key: SortByCPU, Descending: true}}
+	got := ReverseSortKeys(keys)
+	want := []SortKey{{Key: SortByNamespace, Descending: true}, {Key: SortByCPU}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReverseSortKeys = %v, want %v", got, want)
+	}
+	if keys[0].Descending {
+		t.Error("ReverseSortKeys modified its argument")
+	}
+}
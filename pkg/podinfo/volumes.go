@@ -0,0 +1,107 @@
+package podinfo
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// Volume types reported in VolumeInfo.Type; volumes of any other kind are
+// reported as VolumeTypeOther
+const (
+	VolumeTypePVC         = "persistentVolumeClaim"
+	VolumeTypeEphemeral   = "ephemeral"
+	VolumeTypeEmptyDir    = "emptyDir"
+	VolumeTypeConfigMap   = "configMap"
+	VolumeTypeSecret      = "secret"
+	VolumeTypeProjected   = "projected"
+	VolumeTypeDownwardAPI = "downwardAPI"
+	VolumeTypeHostPath    = "hostPath"
+	VolumeTypeCSI         = "csi"
+	VolumeTypeNFS         = "nfs"
+	VolumeTypeOther       = "other"
+)
+
+// VolumeInfo describes a volume of a pod: its type and what it is backed
+// by, e.g. the claim of a PVC volume or the host path of a hostPath volume
+type VolumeInfo struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Source string `json:"source,omitempty"`
+}
+
+// ClaimName returns the PersistentVolumeClaim backing the volume, or "" if
+// it is not backed by one. A generic ephemeral volume is backed by the
+// claim the ephemeral volume controller creates for it.
+func (v VolumeInfo) ClaimName() string {
+	if v.Type == VolumeTypePVC || v.Type == VolumeTypeEphemeral {
+		return v.Source
+	}
+	return ""
+}
+
+// ExtractVolumes returns one entry per volume of the pod, in spec order
+func ExtractVolumes(pod *v1.Pod) []VolumeInfo {
+	volumes := make([]VolumeInfo, 0, len(pod.Spec.Volumes))
+	for _, vol := range pod.Spec.Volumes {
+		info := VolumeInfo{Name: vol.Name}
+		info.Type, info.Source = volumeSource(pod, vol)
+		volumes = append(volumes, info)
+	}
+	return volumes
+}
+
+// volumeSource returns the type of a volume and the name of what backs it
+func volumeSource(pod *v1.Pod, vol v1.Volume) (string, string) {
+	switch src := vol.VolumeSource; {
+	case src.PersistentVolumeClaim != nil:
+		return VolumeTypePVC, src.PersistentVolumeClaim.ClaimName
+	case src.Ephemeral != nil:
+		// The claim is named after the pod and the volume
+		return VolumeTypeEphemeral, pod.Name + "-" + vol.Name
+	case src.EmptyDir != nil:
+		if src.EmptyDir.Medium == v1.StorageMediumMemory {
+			return VolumeTypeEmptyDir, string(v1.StorageMediumMemory)
+		}
+		return VolumeTypeEmptyDir, ""
+	case src.ConfigMap != nil:
+		return VolumeTypeConfigMap, src.ConfigMap.Name
+	case src.Secret != nil:
+		return VolumeTypeSecret, src.Secret.SecretName
+	case src.Projected != nil:
+		return VolumeTypeProjected, ""
+	case src.DownwardAPI != nil:
+		return VolumeTypeDownwardAPI, ""
+	case src.HostPath != nil:
+		return VolumeTypeHostPath, src.HostPath.Path
+	case src.CSI != nil:
+		return VolumeTypeCSI, src.CSI.Driver
+	case src.NFS != nil:
+		return VolumeTypeNFS, src.NFS.Server + ":" + src.NFS.Path
+	}
+	return VolumeTypeOther, ""
+}
+
+// MountsPVC reports whether the pod has a volume backed by a
+// PersistentVolumeClaim, including generic ephemeral volumes
+func MountsPVC(pod *v1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil || vol.Ephemeral != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByPVC keeps the pods mounting at least one PersistentVolumeClaim
+// when pvcOnly is set
+func filterByPVC(pods []v1.Pod, pvcOnly bool) []v1.Pod {
+	if !pvcOnly {
+		return pods
+	}
+	kept := pods[:0]
+	for i := range pods {
+		if MountsPVC(&pods[i]) {
+			kept = append(kept, pods[i])
+		}
+	}
+	return kept
+}
@@ -0,0 +1,67 @@
+package podinfo
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podWithVolumes returns a pod with the given volumes
+func podWithVolumes(name string, volumes ...v1.Volume) v1.Pod {
+	return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: v1.PodSpec{Volumes: volumes}}
+}
+
+func TestExtractVolumes(t *testing.T) {
+	pod := podWithVolumes("db-0",
+		v1.Volume{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}}},
+		v1.Volume{Name: "scratch", VolumeSource: v1.VolumeSource{Ephemeral: &v1.EphemeralVolumeSource{}}},
+		v1.Volume{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+		v1.Volume{Name: "shm", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}}},
+		v1.Volume{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "db-config"}}}},
+		v1.Volume{Name: "tls", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "db-tls"}}},
+		v1.Volume{Name: "logs", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/var/log"}}},
+		v1.Volume{Name: "kube-api-access", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{}}},
+		v1.Volume{Name: "legacy", VolumeSource: v1.VolumeSource{GitRepo: &v1.GitRepoVolumeSource{}}},
+	)
+
+	want := []VolumeInfo{
+		{Name: "data", Type: VolumeTypePVC, Source: "data-db-0"},
+		{Name: "scratch", Type: VolumeTypeEphemeral, Source: "db-0-scratch"},
+		{Name: "cache", Type: VolumeTypeEmptyDir},
+		{Name: "shm", Type: VolumeTypeEmptyDir, Source: "Memory"},
+		{Name: "config", Type: VolumeTypeConfigMap, Source: "db-config"},
+		{Name: "tls", Type: VolumeTypeSecret, Source: "db-tls"},
+		{Name: "logs", Type: VolumeTypeHostPath, Source: "/var/log"},
+		{Name: "kube-api-access", Type: VolumeTypeProjected},
+		{Name: "legacy", Type: VolumeTypeOther},
+	}
+	if got := ExtractVolumes(&pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractVolumes() = %+v, want %+v", got, want)
+	}
+	for _, v := range want {
+		claim := v.ClaimName()
+		if isClaim := v.Type == VolumeTypePVC || v.Type == VolumeTypeEphemeral; isClaim != (claim != "") {
+			t.Errorf("%s: ClaimName() = %q", v.Name, claim)
+		}
+	}
+}
+
+func TestFilterByPVC(t *testing.T) {
+	pods := func() []v1.Pod {
+		return []v1.Pod{
+			podWithVolumes("db", v1.Volume{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}}),
+			podWithVolumes("web", v1.Volume{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}),
+			podWithVolumes("batch", v1.Volume{Name: "scratch", VolumeSource: v1.VolumeSource{Ephemeral: &v1.EphemeralVolumeSource{}}}),
+			podWithVolumes("bare"),
+		}
+	}
+
+	if got := podNames(filterByPVC(pods(), false)); len(got) != 4 {
+		t.Errorf("without --pvc-only kept %v, want every pod", got)
+	}
+	if got, want := podNames(filterByPVC(pods(), true)), []string{"db", "batch"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with --pvc-only kept %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// selectorOptions holds the -l/--selector flag of the commands that list
+// At resources, so listing, watching and deleting filter the same way
+type selectorOptions struct {
+	Selector string
+}
+
+// AddFlags registers --selector and its -l shorthand on fs
+func (o *selectorOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Selector, "selector", "", "label selector to filter At resources, e.g. team=data,env!=prod")
+	fs.StringVar(&o.Selector, "l", "", "shorthand for --selector")
+}
+
+// Validate parses the selector, so a malformed one fails before any API
+// call rather than as a bad request from the server
+func (o *selectorOptions) Validate() error {
+	if _, err := labels.Parse(o.Selector); err != nil {
+		return fmt.Errorf("invalid --selector: %w", err)
+	}
+	return nil
+}
+
+// Labels returns the parsed selector, which matches everything when none is
+// set. Call Validate first.
+func (o *selectorOptions) Labels() labels.Selector {
+	selector, err := labels.Parse(o.Selector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	return selector
+}
+
+// ListOptions returns the list options filtering by the selector
+func (o *selectorOptions) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: o.Selector}
+}
+
+// notFound returns the message for an empty listing of namespace
+func (o *selectorOptions) notFound(namespace string) string {
+	if o.Selector == "" {
+		return fmt.Sprintf("No At resources found in namespace '%s'", namespace)
+	}
+	return fmt.Sprintf("No At resources found in namespace '%s' matching selector '%s'", namespace, o.Selector)
+}
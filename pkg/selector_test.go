@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func TestSelectorOptionsFlags(t *testing.T) {
+	for _, args := range [][]string{{"-l", "team=data"}, {"--selector", "team=data"}} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		var o selectorOptions
+		o.AddFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse(%q) error: %v", args, err)
+		}
+		if o.Selector != "team=data" {
+			t.Errorf("Parse(%q): Selector = %q, want team=data", args, o.Selector)
+		}
+	}
+}
+
+func TestSelectorOptionsValidate(t *testing.T) {
+	for _, selector := range []string{"", "team=data", "team=data,env!=prod", "env in (dev,staging)"} {
+		o := selectorOptions{Selector: selector}
+		if err := o.Validate(); err != nil {
+			t.Errorf("Validate(%q) error: %v", selector, err)
+		}
+	}
+	for _, selector := range []string{"team=a=b", "env in (dev", "!!team"} {
+		o := selectorOptions{Selector: selector}
+		if err := o.Validate(); err == nil {
+			t.Errorf("Validate(%q) error = nil, want an invalid --selector error", selector)
+		}
+	}
+}
+
+func TestSelectorOptionsFiltersList(t *testing.T) {
+	at := func(name string, labels map[string]string) *cnatv1alpha1.At {
+		return &cnatv1alpha1.At{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}}
+	}
+	client := fake.NewSimpleClientset(
+		at("nightly", map[string]string{"team": "data", "env": "prod"}),
+		at("hourly", map[string]string{"team": "data", "env": "dev"}),
+		at("cleanup", map[string]string{"team": "ops"}),
+	)
+
+	o := selectorOptions{Selector: "team=data,env!=prod"}
+	list, err := client.CnatV1alpha1().Ats("default").List(context.Background(), o.ListOptions())
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	var names []string
+	for _, at := range list.Items {
+		names = append(names, at.Name)
+	}
+	if want := []string{"hourly"}; !slices.Equal(names, want) {
+		t.Errorf("List(%s) = %q, want %q", o.Selector, names, want)
+	}
+	if !o.Labels().Matches(labels.Set{"team": "data", "env": "dev"}) {
+		t.Errorf("Labels() = %s does not match team=data,env=dev", o.Labels())
+	}
+}
+
+func TestSelectorOptionsNotFound(t *testing.T) {
+	if got, want := (&selectorOptions{}).notFound("batch"), "No At resources found in namespace 'batch'"; got != want {
+		t.Errorf("notFound() = %q, want %q", got, want)
+	}
+	o := selectorOptions{Selector: "team=data"}
+	if got, want := o.notFound("batch"), "No At resources found in namespace 'batch' matching selector 'team=data'"; got != want {
+		t.Errorf("notFound() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// FormatAge returns the time elapsed since t the way kubectl prints ages,
+// e.g. "45s", "4h30m" or "2d". A zero time is shown as "<unknown>".
+func FormatAge(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// StatusPrinter prints At resources as an aligned table
+type StatusPrinter struct {
+	out io.Writer
+}
+
+// NewStatusPrinter returns a StatusPrinter writing to out
+func NewStatusPrinter(out io.Writer) *StatusPrinter {
+	return &StatusPrinter{out: out}
+}
+
+// Print writes one row per At, sorted by name. restarts holds the restart
+// count of the pod run for each At, keyed by At name; Ats without a pod
+// show "-".
+func (p *StatusPrinter) Print(ats []cnatv1alpha1.At, restarts map[string]int32) error {
+	sorted := make([]cnatv1alpha1.At, len(ats))
+	copy(sorted, ats)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCHEDULE\tPHASE\tRESTARTS\tAGE\tLAST-RUN")
+	for _, at := range sorted {
+		phase := at.Status.Phase
+		if phase == "" {
+			phase = "<none>"
+		}
+		restartCount := "-"
+		if count, ok := restarts[at.Name]; ok {
+			restartCount = fmt.Sprintf("%d", count)
+		}
+		lastRun := "<never>"
+		if at.Status.LastRunTime != nil {
+			lastRun = FormatAge(at.Status.LastRunTime.Time) + " ago"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			at.Name, at.Spec.Schedule, phase, restartCount, FormatAge(at.CreationTimestamp.Time), lastRun)
+	}
+	return w.Flush()
+}
+
+// atRestarts sums container restarts, init containers included, of the pods owned by each At in pods,
+// keyed by At name
+func atRestarts(pods []v1.Pod) map[string]int32 {
+	restarts := make(map[string]int32)
+	for _, pod := range pods {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "At" || owner.APIVersion != cnatv1alpha1.SchemeGroupVersion.String() {
+			continue
+		}
+		restarts[owner.Name] += podinfo.PodRestarts(&pod)
+	}
+	return restarts
+}
+
+// statusCommand holds the clients and options of the status subcommand
+type statusCommand struct {
+	client    clientset.Interface
+	kube      kubernetes.Interface
+	namespace string
+	selector  selectorOptions
+	out       io.Writer
+	// timeout bounds each round of API calls, see config.AddTimeoutFlag
+	timeout time.Duration
+}
+
+// runStatus implements the status subcommand
+func runStatus(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	configOpts.AddTimeoutFlag(fs)
+	logOpts.AddFlags(fs)
+	namespace := fs.String("namespace", "default", "namespace to show At resources from")
+	var selector selectorOptions
+	selector.AddFlags(fs)
+	watchChanges := fs.Bool("watch", false, "reprint the table whenever an At resource changes")
+	outputFile := fs.String("output-file", "", "write the table to this file, replaced atomically once the run succeeds (- for stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := selector.Validate(); err != nil {
+		return err
+	}
+	if *watchChanges && !output.IsStdout(*outputFile) {
+		return fmt.Errorf("--output-file cannot be combined with --watch")
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+	kube, err := kubernetes.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		cmd := &statusCommand{client: client, kube: kube, namespace: *namespace, selector: selector, out: out, timeout: configOpts.Timeout}
+		if *watchChanges {
+			return cmd.watch(ctx)
+		}
+		ats, err := cmd.list(ctx)
+		if err != nil {
+			return err
+		}
+		if len(ats.Items) == 0 {
+			_, err := fmt.Fprintln(out, selector.notFound(*namespace))
+			return err
+		}
+		return cmd.print(ctx, ats.Items)
+	})
+}
+
+// list lists the At resources of the namespace
+func (c *statusCommand) list(ctx context.Context) (*cnatv1alpha1.AtList, error) {
+	ctx, cancel := config.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	ats, err := c.client.CnatV1alpha1().Ats(c.namespace).List(ctx, c.selector.ListOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error listing At resources: %w", config.WrapTimeout(err, c.timeout))
+	}
+	return ats, nil
+}
+
+// print looks up the restart counts and prints the table
+func (c *statusCommand) print(ctx context.Context, ats []cnatv1alpha1.At) error {
+	ctx, cancel := config.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	pods, err := c.kube.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing pods: %w", config.WrapTimeout(err, c.timeout))
+	}
+	return NewStatusPrinter(c.out).Print(ats, atRestarts(pods.Items))
+}
+
+// watch prints the current At resources, then reprints the table on every
+// watch event until ctx is cancelled. The watch is re-established from the
+// last seen resource version if the server closes it.
+func (c *statusCommand) watch(ctx context.Context) error {
+	list, err := c.list(ctx)
+	if err != nil {
+		return err
+	}
+	ats := make(map[string]cnatv1alpha1.At, len(list.Items))
+	for _, at := range list.Items {
+		ats[at.Name] = at
+	}
+	if err := c.print(ctx, list.Items); err != nil {
+		return err
+	}
+
+	resourceVersion := list.ResourceVersion
+	for {
+		opts := c.selector.ListOptions()
+		opts.ResourceVersion = resourceVersion
+		w, err := c.client.CnatV1alpha1().Ats(c.namespace).Watch(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("error watching At resources: %w", err)
+		}
+		for event := range w.ResultChan() {
+			if event.Type == watch.Error {
+				w.Stop()
+				return fmt.Errorf("watch failed: %v", event.Object)
+			}
+			at, ok := event.Object.(*cnatv1alpha1.At)
+			if !ok {
+				continue
+			}
+			resourceVersion = at.ResourceVersion
+			switch event.Type {
+			case watch.Bookmark:
+				continue
+			case watch.Deleted:
+				delete(ats, at.Name)
+			default:
+				ats[at.Name] = *at
+			}
+
+			current := make([]cnatv1alpha1.At, 0, len(ats))
+			for _, at := range ats {
+				current = append(current, at)
+			}
+			fmt.Fprintln(c.out)
+			if err := c.print(ctx, current); err != nil {
+				w.Stop()
+				return err
+			}
+		}
+		w.Stop()
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{ago: 45 * time.Second, want: "45s"},
+		{ago: 4*time.Hour + 30*time.Minute, want: "4h30m"},
+		{ago: 50 * time.Hour, want: "2d2h"},
+		{ago: 3 * 24 * time.Hour, want: "3d"},
+	}
+
+	for _, tt := range tests {
+		// Pad by half a second so the elapsed time rounds to the expected value
+		if got := FormatAge(time.Now().Add(-tt.ago - 500*time.Millisecond)); got != tt.want {
+			t.Errorf("FormatAge(-%s) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+	if got := FormatAge(time.Time{}); got != "<unknown>" {
+		t.Errorf("FormatAge(zero) = %q, want <unknown>", got)
+	}
+}
+
+func TestStatusPrinter(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	lastRun := metav1.NewTime(time.Now().Add(-10 * time.Minute))
+	ats := []cnatv1alpha1.At{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending", CreationTimestamp: created},
+			Spec:       cnatv1alpha1.AtSpec{Schedule: "2099-01-01T00:00:00Z"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "done", CreationTimestamp: created},
+			Spec:       cnatv1alpha1.AtSpec{Schedule: "2020-01-01T00:00:00Z"},
+			Status:     cnatv1alpha1.AtStatus{Phase: cnatv1alpha1.PhaseDone, LastRunTime: &lastRun},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := NewStatusPrinter(&out).Print(ats, map[string]int32{"done": 2}); err != nil {
+		t.Fatalf("Print() error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), out.String())
+	}
+	want := [][]string{
+		{"NAME", "SCHEDULE", "PHASE", "RESTARTS", "AGE", "LAST-RUN"},
+		{"done", "2020-01-01T00:00:00Z", "DONE", "2", "120m", "10m", "ago"},
+		{"pending", "2099-01-01T00:00:00Z", "<none>", "-", "120m", "<never>"},
+	}
+	for i, line := range lines {
+		if got := strings.Fields(line); strings.Join(got, " ") != strings.Join(want[i], " ") {
+			t.Errorf("line %d = %q, want fields %v", i, line, want[i])
+		}
+	}
+	// Columns are aligned: every row starts its SCHEDULE column at the same offset
+	offset := strings.Index(lines[0], "SCHEDULE")
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line[offset:], "20") {
+			t.Errorf("row %q is not aligned with the header", line)
+		}
+	}
+}
+
+func TestAtRestarts(t *testing.T) {
+	isController := true
+	ownedBy := func(name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: cnatv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "At",
+			Name:       name,
+			Controller: &isController,
+		}}
+	}
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-pod", OwnerReferences: ownedBy("example")},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{RestartCount: 1}, {RestartCount: 2}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-init-crash", OwnerReferences: ownedBy("example")},
+			Status:     v1.PodStatus{InitContainerStatuses: []v1.ContainerStatus{{RestartCount: 4}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "unowned"},
+			Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{RestartCount: 5}}},
+		},
+	}
+
+	restarts := atRestarts(pods)
+	if len(restarts) != 1 || restarts["example"] != 7 {
+		t.Errorf("atRestarts() = %v, want map[example:7]", restarts)
+	}
+}
@@ -0,0 +1,121 @@
+// Package textdiff renders line differences between two texts as a unified
+// diff, in the format of diff -u.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// ContextLines is how many unchanged lines surround each hunk
+const ContextLines = 3
+
+// diffLine is one line of a line-based diff
+type diffLine struct {
+	op   diffmatchpatch.Operation
+	text string
+}
+
+// splitLines splits text into lines, keeping their newlines
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Unified returns the line differences between from and to with
+// ContextLines of context, in the format of diff -u, or "" when they
+// are equal
+func Unified(fromName, toName, from, to string) string {
+	// Diff one rune per line. DiffLinesToChars is not used: it encodes the
+	// lines as comma-separated numbers, which DiffMain then diffs digit by
+	// digit.
+	lines := make(map[rune]string)
+	runes := make(map[string]rune)
+	encode := func(text string) []rune {
+		var encoded []rune
+		for _, line := range splitLines(text) {
+			r, ok := runes[line]
+			if !ok {
+				r = rune(len(runes))
+				if r >= 0xD800 {
+					// Skip the surrogates, which are not valid runes
+					r += 0x800
+				}
+				runes[line] = r
+				lines[r] = line
+			}
+			encoded = append(encoded, r)
+		}
+		return encoded
+	}
+	diffs := diffmatchpatch.New().DiffMainRunes(encode(from), encode(to), false)
+
+	var all []diffLine
+	var changed []int
+	for _, d := range diffs {
+		for _, r := range d.Text {
+			if d.Type != diffmatchpatch.DiffEqual {
+				changed = append(changed, len(all))
+			}
+			all = append(all, diffLine{op: d.Type, text: lines[r]})
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	// fromLine and toLine are the line numbers reached before all[i]
+	fromLine, toLine := make([]int, len(all)+1), make([]int, len(all)+1)
+	for i, line := range all {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if line.op != diffmatchpatch.DiffInsert {
+			fromLine[i+1]++
+		}
+		if line.op != diffmatchpatch.DiffDelete {
+			toLine[i+1]++
+		}
+	}
+	for i := 0; i < len(changed); {
+		// Merge changes whose context would overlap into one hunk
+		j := i
+		for j+1 < len(changed) && changed[j+1]-changed[j] <= 2*ContextLines {
+			j++
+		}
+		start := max(changed[i]-ContextLines, 0)
+		end := min(changed[j]+ContextLines+1, len(all))
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[start], fromLine[end]-fromLine[start]),
+			hunkRange(toLine[start], toLine[end]-toLine[start]))
+		for _, line := range all[start:end] {
+			prefix := " "
+			switch line.op {
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+			}
+			b.WriteString(prefix + line.text)
+			if !strings.HasSuffix(line.text, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = j + 1
+	}
+	return b.String()
+}
+
+// hunkRange formats the start,count of a hunk header. before is the number
+// of lines preceding the hunk; an empty range starts at the preceding line.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,23 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{name: "equal", from: "a\nb\n", to: "a\nb\n", want: ""},
+		{name: "empty from", from: "", to: "data:\n  mode: prod\n", want: "--- live\n+++ local\n@@ -0,0 +1,2 @@\n+data:\n+  mode: prod\n"},
+		{name: "empty to", from: "a\n", to: "", want: "--- live\n+++ local\n@@ -1,1 +0,0 @@\n-a\n"},
+		{name: "no final newline", from: "a\nb", to: "a\nc", want: "--- live\n+++ local\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := Unified("live", "local", tt.from, tt.to); diff != tt.want {
+				t.Errorf("Unified() = %q, want %q", diff, tt.want)
+			}
+		})
+	}
+}
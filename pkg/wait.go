@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	utilexec "k8s.io/utils/exec"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/output"
+)
+
+// defaultWaitTimeout bounds wait unless --timeout is set, longer than the
+// API call timeout of the other subcommands since an At may be scheduled
+// minutes ahead
+const defaultWaitTimeout = 10 * time.Minute
+
+// parseWaitCondition parses the --for condition, phase=PHASE, into one of
+// the At phases. The phase is case-insensitive, so phase=Done is DONE.
+func parseWaitCondition(condition string) (string, error) {
+	key, value, ok := strings.Cut(condition, "=")
+	if !ok || key != "phase" {
+		return "", fmt.Errorf("invalid --for %q (want phase=PHASE)", condition)
+	}
+	phase := strings.ToUpper(value)
+	switch phase {
+	case cnatv1alpha1.PhasePending, cnatv1alpha1.PhaseRunning, cnatv1alpha1.PhaseDone:
+		return phase, nil
+	}
+	return "", fmt.Errorf("invalid --for %q (want phase %s, %s or %s)", condition, cnatv1alpha1.PhasePending, cnatv1alpha1.PhaseRunning, cnatv1alpha1.PhaseDone)
+}
+
+// waitForPhase watches the At name of namespace until its phase is phase
+// and returns it. The At is listed first, so one already in that phase
+// returns at once, and listed again whenever the watch can't be resumed.
+// A missing At fails with exit status exitNotFound, one deleted while
+// waiting with an error; ctx bounds the wait.
+func waitForPhase(ctx context.Context, client clientset.Interface, namespace, name, phase string) (*cnatv1alpha1.At, error) {
+	ats := client.CnatV1alpha1().Ats(namespace)
+	byName := fields.OneTermEqualSelector("metadata.name", name).String()
+	// Wrapped like the generated informers, so a client without watch-list
+	// support, such as the fake one, gets a plain list
+	lw := cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = byName
+			return ats.List(ctx, opts)
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = byName
+			return ats.Watch(ctx, opts)
+		},
+	}, client)
+
+	var found *cnatv1alpha1.At
+	precondition := func(store cache.Store) (bool, error) {
+		obj, exists, err := store.GetByKey(namespace + "/" + name)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, utilexec.CodeExitError{Err: fmt.Errorf("At %q not found in namespace '%s'", name, namespace), Code: exitNotFound}
+		}
+		at, ok := obj.(*cnatv1alpha1.At)
+		if !ok || at.Status.Phase != phase {
+			return false, nil
+		}
+		found = at
+		return true, nil
+	}
+	condition := func(event watch.Event) (bool, error) {
+		at, ok := event.Object.(*cnatv1alpha1.At)
+		if !ok || at.Name != name {
+			return false, nil
+		}
+		switch event.Type {
+		case watch.Deleted:
+			return false, fmt.Errorf("At %q was deleted while waiting for phase %s", name, phase)
+		case watch.Added, watch.Modified:
+			found = at
+			return at.Status.Phase == phase, nil
+		}
+		return false, nil
+	}
+
+	if _, err := watchtools.UntilWithSync(ctx, lw, &cnatv1alpha1.At{}, precondition, condition); err != nil {
+		if ctx.Err() != nil {
+			return found, ctx.Err()
+		}
+		return found, err
+	}
+	return found, nil
+}
+
+// runWait implements the wait subcommand
+func runWait(logOpts *logging.Options, args []string) error {
+	fs := flag.NewFlagSet("wait", flag.ExitOnError)
+	var configOpts config.Options
+	configOpts.AddFlags(fs)
+	logOpts.AddFlags(fs)
+	fs.DurationVar(&configOpts.Timeout, "timeout", defaultWaitTimeout, "how long to wait, e.g. 30s or 10m (0 to wait forever)")
+	namespace := fs.String("namespace", "default", "namespace of the At resource")
+	condition := fs.String("for", "phase="+cnatv1alpha1.PhaseDone, "condition to wait for: phase=PENDING, phase=RUNNING or phase=DONE (case-insensitive)")
+	outputFile := addOutputFileFlag(fs)
+	// Flags may come before or after NAME
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: wait [flags] NAME")
+	}
+	name := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: wait [flags] NAME")
+	}
+	phase, err := parseWaitCondition(*condition)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := config.Build(configOpts)
+	if err != nil {
+		return fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	client, err := clientset.NewForConfig(resolved.Config)
+	if err != nil {
+		return fmt.Errorf("error creating clientset: %w", err)
+	}
+
+	ctx, cancel := config.BuildContext(configOpts.Timeout)
+	defer cancel()
+	if _, err := waitForPhase(ctx, client, *namespace, name, phase); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s waiting for At %q to reach phase %s", configOpts.Timeout, name, phase)
+		}
+		return err
+	}
+	return output.Write(*outputFile, output.Options{}, func(out io.Writer) error {
+		_, err := fmt.Fprintf(out, "at/%s condition met (phase %s)\n", name, phase)
+		return err
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+	utilexec "k8s.io/utils/exec"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+		wantErr   bool
+	}{
+		{condition: "phase=Done", want: cnatv1alpha1.PhaseDone},
+		{condition: "phase=running", want: cnatv1alpha1.PhaseRunning},
+		{condition: "phase=PENDING", want: cnatv1alpha1.PhasePending},
+		{condition: "phase=Failed", wantErr: true},
+		{condition: "condition=Ready", wantErr: true},
+		{condition: "Done", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseWaitCondition(tt.condition)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseWaitCondition(%q) = %q, %v, want %q (error %t)", tt.condition, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// newWaitClient returns a client whose At list is list and whose watches
+// are sent to watchers
+func newWaitClient(list *cnatv1alpha1.AtList) (*fake.Clientset, chan *watch.FakeWatcher) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, list, nil
+	})
+	watchers := make(chan *watch.FakeWatcher, 1)
+	client.PrependWatchReactor("ats", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		watchers <- watcher
+		return true, watcher, nil
+	})
+	return client, watchers
+}
+
+// waitResult is what waitForPhase returned in a goroutine
+type waitResult struct {
+	at  *cnatv1alpha1.At
+	err error
+}
+
+func startWait(ctx context.Context, client *fake.Clientset, phase string) chan waitResult {
+	done := make(chan waitResult, 1)
+	go func() {
+		at, err := waitForPhase(ctx, client, "default", "example", phase)
+		done <- waitResult{at, err}
+	}()
+	return done
+}
+
+func receiveResult(t *testing.T, done chan waitResult) waitResult {
+	t.Helper()
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitForPhase() did not return")
+		return waitResult{}
+	}
+}
+
+func TestWaitForPhase(t *testing.T) {
+	client, watchers := newWaitClient(&cnatv1alpha1.AtList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+		Items:    []cnatv1alpha1.At{*watchedAt("example", "10", cnatv1alpha1.PhasePending)},
+	})
+	done := startWait(context.Background(), client, cnatv1alpha1.PhaseDone)
+
+	watcher := <-watchers
+	watcher.Modify(watchedAt("example", "11", cnatv1alpha1.PhaseRunning))
+	watcher.Modify(watchedAt("example", "12", cnatv1alpha1.PhaseDone))
+	result := receiveResult(t, done)
+	if result.err != nil {
+		t.Fatalf("waitForPhase() error: %v", result.err)
+	}
+	if result.at.ResourceVersion != "12" {
+		t.Errorf("waitForPhase() = At at resource version %s, want 12", result.at.ResourceVersion)
+	}
+
+	for _, action := range client.Actions() {
+		var selector string
+		switch action := action.(type) {
+		case k8stesting.ListAction:
+			selector = action.GetListRestrictions().Fields.String()
+		case k8stesting.WatchAction:
+			selector = action.GetWatchRestrictions().Fields.String()
+		}
+		if selector != "metadata.name=example" {
+			t.Errorf("%s field selector = %q, want metadata.name=example", action.GetVerb(), selector)
+		}
+	}
+}
+
+func TestWaitForPhaseAlreadyReached(t *testing.T) {
+	client, _ := newWaitClient(&cnatv1alpha1.AtList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+		Items:    []cnatv1alpha1.At{*watchedAt("example", "10", cnatv1alpha1.PhaseRunning)},
+	})
+	result := receiveResult(t, startWait(context.Background(), client, cnatv1alpha1.PhaseRunning))
+	if result.err != nil || result.at == nil || result.at.Status.Phase != cnatv1alpha1.PhaseRunning {
+		t.Errorf("waitForPhase() = %+v, want the RUNNING At", result)
+	}
+}
+
+func TestWaitForPhaseNotFound(t *testing.T) {
+	client, _ := newWaitClient(&cnatv1alpha1.AtList{ListMeta: metav1.ListMeta{ResourceVersion: "10"}})
+	result := receiveResult(t, startWait(context.Background(), client, cnatv1alpha1.PhaseDone))
+	var exitErr utilexec.ExitError
+	if !errors.As(result.err, &exitErr) || exitErr.ExitStatus() != exitNotFound {
+		t.Errorf("waitForPhase() error = %v, want exit status %d", result.err, exitNotFound)
+	}
+}
+
+func TestWaitForPhaseDeleted(t *testing.T) {
+	client, watchers := newWaitClient(&cnatv1alpha1.AtList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+		Items:    []cnatv1alpha1.At{*watchedAt("example", "10", cnatv1alpha1.PhasePending)},
+	})
+	done := startWait(context.Background(), client, cnatv1alpha1.PhaseDone)
+
+	watcher := <-watchers
+	watcher.Delete(watchedAt("example", "11", cnatv1alpha1.PhasePending))
+	if result := receiveResult(t, done); result.err == nil {
+		t.Error("waitForPhase() after the At was deleted = nil error, want one")
+	}
+}
+
+func TestWaitForPhaseTimeout(t *testing.T) {
+	client, _ := newWaitClient(&cnatv1alpha1.AtList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+		Items:    []cnatv1alpha1.At{*watchedAt("example", "10", cnatv1alpha1.PhasePending)},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result := receiveResult(t, startWait(ctx, client, cnatv1alpha1.PhaseDone))
+	if !errors.Is(result.err, context.DeadlineExceeded) {
+		t.Errorf("waitForPhase() error = %v, want context.DeadlineExceeded", result.err)
+	}
+}
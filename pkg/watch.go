@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/config"
+	clientset "Kubernetes_Programming/pkg/generated/clientset/versioned"
+	"Kubernetes_Programming/pkg/logging"
+)
+
+// atWatcher streams the changes of the At resources of a namespace, one
+// line per event with the phase the At is in afterwards
+type atWatcher struct {
+	client    clientset.Interface
+	namespace string
+	// name restricts the watch to the At of that name when set
+	name string
+	// selector restricts the watch to the Ats with matching labels
+	selector string
+	// timeout bounds each list, see config.AddTimeoutFlag
+	timeout time.Duration
+	out     io.Writer
+	// flush is called after each batch of lines, e.g. to push a buffered
+	// out to the terminal
+	flush func() error
+
+	// known holds the last seen At of each name, to tell on a re-list
+	// which ones changed while the watch was down
+	known map[string]cnatv1alpha1.At
+}
+
+// listOptions restricts opts to w.name and w.selector, if any
+func (w *atWatcher) listOptions(opts metav1.ListOptions) metav1.ListOptions {
+	opts.LabelSelector = w.selector
+	if w.name != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.name).String()
+	}
+	return opts
+}
+
+// run prints the current At resources as ADDED, then every change until ctx
+// is cancelled. A RetryWatcher re-establishes watches the server closes;
+// when the resource version it resumes from has expired, the Ats are listed
+// again and the differences printed before watching from the new version.
+func (w *atWatcher) run(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	if w.known == nil {
+		w.known = map[string]cnatv1alpha1.At{}
+	}
+	for {
+		resourceVersion, err := w.sync(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		expired, err := w.stream(ctx, resourceVersion)
+		if err != nil || !expired {
+			return err
+		}
+		logger.Info("resource version expired, listing the At resources again", "resourceVersion", resourceVersion)
+	}
+}
+
+// sync lists the At resources and prints what changed since they were last
+// seen. It returns the resource version of the list to watch from.
+func (w *atWatcher) sync(ctx context.Context) (string, error) {
+	ctx, cancel := config.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	list, err := w.client.CnatV1alpha1().Ats(w.namespace).List(ctx, w.listOptions(metav1.ListOptions{}))
+	if err != nil {
+		return "", fmt.Errorf("error listing At resources: %w", config.WrapTimeout(err, w.timeout))
+	}
+
+	listed := make(map[string]bool, len(list.Items))
+	for i := range list.Items {
+		at := &list.Items[i]
+		listed[at.Name] = true
+		previous, ok := w.known[at.Name]
+		switch {
+		case !ok:
+			w.print(watch.Added, at)
+		case previous.ResourceVersion != at.ResourceVersion:
+			w.print(watch.Modified, at)
+		}
+	}
+	var gone []string
+	for name := range w.known {
+		if !listed[name] {
+			gone = append(gone, name)
+		}
+	}
+	sort.Strings(gone)
+	for _, name := range gone {
+		at := w.known[name]
+		w.print(watch.Deleted, &at)
+	}
+	return list.ResourceVersion, w.flush()
+}
+
+// stream prints the watch events from resourceVersion on until ctx is
+// cancelled, which is not an error. It reports whether it stopped because
+// resourceVersion, or a later one, expired.
+func (w *atWatcher) stream(ctx context.Context, resourceVersion string) (bool, error) {
+	ats := w.client.CnatV1alpha1().Ats(w.namespace)
+	watcher, err := watchtools.NewRetryWatcherWithContext(ctx, resourceVersion, &cache.ListWatch{
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return ats.Watch(ctx, w.listOptions(opts))
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error watching At resources: %w", err)
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-watcher.Done():
+			if ctx.Err() != nil {
+				return false, nil
+			}
+			return false, fmt.Errorf("watch of At resources in namespace '%s' stopped", w.namespace)
+		case event := <-watcher.ResultChan():
+			switch event.Type {
+			case watch.Error:
+				err := apierrors.FromObject(event.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					return true, nil
+				}
+				return false, fmt.Errorf("watch failed: %w", err)
+			case watch.Bookmark:
+				continue
+			}
+			at, ok := event.Object.(*cnatv1alpha1.At)
+			if !ok {
+				continue
+			}
+			w.print(event.Type, at)
+			if err := w.flush(); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// print writes the line of an event and remembers the At it leaves behind
+func (w *atWatcher) print(eventType watch.EventType, at *cnatv1alpha1.At) {
+	if eventType == watch.Deleted {
+		delete(w.known, at.Name)
+	} else {
+		w.known[at.Name] = *at
+	}
+	phase := at.Status.Phase
+	if phase == "" {
+		phase = "<none>"
+	}
+	fmt.Fprintf(w.out, "%s  %-8s  %s  %s\n", time.Now().Format(time.TimeOnly), eventType, at.Name, phase)
+}
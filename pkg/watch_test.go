@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	cnatv1alpha1 "Kubernetes_Programming/pkg/apis/cnat/v1alpha1"
+	"Kubernetes_Programming/pkg/generated/clientset/versioned/fake"
+)
+
+func watchedAt(name, resourceVersion, phase string) *cnatv1alpha1.At {
+	return &cnatv1alpha1.At{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", ResourceVersion: resourceVersion},
+		Status:     cnatv1alpha1.AtStatus{Phase: phase},
+	}
+}
+
+// waitForLines waits until out holds n lines and returns them
+func waitForLines(t *testing.T, out *syncBuffer, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if out.String() != "" && len(lines) >= n {
+			return lines
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d lines, got:\n%s", n, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// checkLine checks a watch line, ignoring its timestamp
+func checkLine(t *testing.T, line string, want ...string) {
+	t.Helper()
+	if got := strings.Fields(line)[1:]; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("line %q, want fields %q", line, want)
+	}
+}
+
+func TestAtWatcher(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	lists := []*cnatv1alpha1.AtList{
+		{
+			ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+			Items:    []cnatv1alpha1.At{*watchedAt("example", "10", cnatv1alpha1.PhasePending)},
+		},
+		{
+			ListMeta: metav1.ListMeta{ResourceVersion: "20"},
+			Items:    []cnatv1alpha1.At{*watchedAt("example", "18", cnatv1alpha1.PhaseDone), *watchedAt("other", "19", "")},
+		},
+	}
+	var mu sync.Mutex
+	var fieldSelectors []string
+	client.PrependReactor("list", "ats", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fieldSelectors = append(fieldSelectors, action.(k8stesting.ListAction).GetListRestrictions().Fields.String())
+		list := lists[0]
+		if len(lists) > 1 {
+			lists = lists[1:]
+		}
+		return true, list, nil
+	})
+	watchers := make(chan *watch.FakeWatcher, 2)
+	client.PrependWatchReactor("ats", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFake()
+		watchers <- watcher
+		return true, watcher, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &syncBuffer{}
+	w := &atWatcher{client: client, namespace: "default", name: "example", timeout: time.Minute, out: out, flush: func() error { return nil }}
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	lines := waitForLines(t, out, 1)
+	checkLine(t, lines[0], "ADDED", "example", "PENDING")
+
+	first := <-watchers
+	first.Modify(watchedAt("example", "11", cnatv1alpha1.PhaseRunning))
+	lines = waitForLines(t, out, 2)
+	checkLine(t, lines[1], "MODIFIED", "example", "RUNNING")
+
+	// An expired resource version makes the watcher list again and print
+	// what it missed
+	first.Error(&apierrors.NewResourceExpired("too old resource version").ErrStatus)
+	lines = waitForLines(t, out, 4)
+	checkLine(t, lines[2], "MODIFIED", "example", "DONE")
+	checkLine(t, lines[3], "ADDED", "other", "<none>")
+
+	second := <-watchers
+	second.Delete(watchedAt("example", "21", cnatv1alpha1.PhaseDone))
+	lines = waitForLines(t, out, 5)
+	checkLine(t, lines[4], "DELETED", "example", "DONE")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("run() after cancel = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run() did not return after the context was cancelled")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, selector := range fieldSelectors {
+		if selector != "metadata.name=example" {
+			t.Errorf("list field selector = %q, want metadata.name=example", selector)
+		}
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "watch" {
+			continue
+		}
+		if got := action.(k8stesting.WatchAction).GetWatchRestrictions().Fields.String(); got != "metadata.name=example" {
+			t.Errorf("watch field selector = %q, want metadata.name=example", got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// Probe types of --probe-type
+const (
+	probeLiveness  = "liveness"
+	probeReadiness = "readiness"
+	probeStartup   = "startup"
+)
+
+// containerProbe returns the probe of probeType defined on container
+func containerProbe(container *v1.Container, probeType string) (*v1.Probe, error) {
+	switch probeType {
+	case probeLiveness:
+		return container.LivenessProbe, nil
+	case probeReadiness:
+		return container.ReadinessProbe, nil
+	case probeStartup:
+		return container.StartupProbe, nil
+	}
+	return nil, fmt.Errorf("unknown probe type %q (want liveness, readiness or startup)", probeType)
+}
+
+// probeTimeout returns how long the kubelet gives probe to answer,
+// defaulting to one second as the API server does
+func probeTimeout(probe *v1.Probe) time.Duration {
+	if probe.TimeoutSeconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(probe.TimeoutSeconds) * time.Second
+}
+
+// RunProbeExec runs the exec command of the probeType probe (liveness,
+// readiness or startup) of container containerName in pod, the way the
+// kubelet would, bounded by the probe's timeout. The command's exit code is
+// returned, not an error, when it exits non-zero; err reports a probe that
+// isn't an exec probe, a timeout, or a failure to run the command at all.
+func RunProbeExec(ctx context.Context, client kubernetes.Interface, restConfig *rest.Config, pod *v1.Pod, containerName, probeType string) (stdout, stderr string, exitCode int, err error) {
+	var container *v1.Container
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			container = &pod.Spec.Containers[i]
+			break
+		}
+	}
+	if container == nil {
+		return "", "", 0, fmt.Errorf("container %q not found in pod %s", containerName, pod.Name)
+	}
+	probe, err := containerProbe(container, probeType)
+	if err != nil {
+		return "", "", 0, err
+	}
+	switch {
+	case probe == nil:
+		return "", "", 0, fmt.Errorf("container %s has no %s probe", containerName, probeType)
+	case probe.Exec == nil:
+		return "", "", 0, fmt.Errorf("the %s probe of container %s is not an exec probe", probeType, containerName)
+	case len(probe.Exec.Command) == 0:
+		return "", "", 0, fmt.Errorf("the %s probe of container %s has no command", probeType, containerName)
+	}
+
+	timeout := probeTimeout(probe)
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var out, errOut bytes.Buffer
+	err = streamExec(probeCtx, restConfig, client, pod.Namespace, pod.Name, &v1.PodExecOptions{
+		Container: containerName,
+		Command:   probe.Exec.Command,
+		Stdout:    true,
+		Stderr:    true,
+	}, remotecommand.StreamOptions{Stdout: &out, Stderr: &errOut})
+
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		return out.String(), errOut.String(), 0, nil
+	case errors.As(err, &exitErr):
+		return out.String(), errOut.String(), exitErr.ExitStatus(), nil
+	case ctx.Err() == nil && errors.Is(probeCtx.Err(), context.DeadlineExceeded):
+		return out.String(), errOut.String(), 0, fmt.Errorf("probe timed out after %s", timeout)
+	}
+	return out.String(), errOut.String(), 0, err
+}
+
+// runProbe implements the probe subcommand
+func runProbe(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("probe", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", metav1.NamespaceDefault, "namespace of the pod")
+	podName := fs.String("pod", "", "pod whose probe to run (required)")
+	containerName := fs.String("container", "", "container whose probe to run (required when the pod has several)")
+	probeType := fs.String("probe-type", probeReadiness, "probe to run: liveness, readiness or startup")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(outputText); err != nil {
+		return err
+	}
+	if *podName == "" {
+		return errors.New("--pod is required")
+	}
+	if _, err := containerProbe(&v1.Container{}, *probeType); err != nil {
+		return err
+	}
+
+	client, config, err := createKubernetesClient(*clientOpts)
+	if err != nil {
+		return fmt.Errorf("error creating Kubernetes client: %w", err)
+	}
+
+	ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+	defer cancel()
+	pod, err := client.CoreV1().Pods(*namespace).Get(ctx, *podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting pod: %w", timeoutError(handleAPIError(err, "pods", *namespace), clientOpts.Timeout))
+	}
+	container, err := resolveContainer(pod, *containerName)
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr, exitCode, err := RunProbeExec(ctx, client, config, pod, container, *probeType)
+	if err != nil {
+		return fmt.Errorf("error running the %s probe: %w", *probeType, timeoutError(err, clientOpts.Timeout))
+	}
+	fmt.Fprint(os.Stderr, stderr)
+	// A failed probe is a result too, so its output is written before exiting
+	err = replaceOutput(*outputFile, func(out io.Writer) error {
+		fmt.Fprint(out, stdout)
+		if exitCode != 0 {
+			fmt.Fprintf(out, "%s probe of %s/%s failed with exit code %d\n", *probeType, pod.Name, container, exitCode)
+			return nil
+		}
+		fmt.Fprintf(out, "%s probe of %s/%s succeeded\n", *probeType, pod.Name, container)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		// fatal exits with the probe's status without logging an error
+		return utilexec.CodeExitError{Err: errors.New("probe failed"), Code: exitCode}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// newProbedPod returns a running pod "web" whose "app" container has an
+// exec readiness probe and an HTTP liveness probe
+func newProbedPod() *v1.Pod {
+	pod := newContainersPod("web", "app", "sidecar")
+	pod.Status.Phase = v1.PodRunning
+	pod.Spec.Containers[0].ReadinessProbe = &v1.Probe{
+		ProbeHandler:   v1.ProbeHandler{Exec: &v1.ExecAction{Command: []string{"cat", "/tmp/ready"}}},
+		TimeoutSeconds: 5,
+	}
+	pod.Spec.Containers[0].LivenessProbe = &v1.Probe{
+		ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)}},
+	}
+	return pod
+}
+
+func TestRunProbeExec(t *testing.T) {
+	pod := newProbedPod()
+	fake, config, client := startExecServer(t, pod, 0)
+
+	stdout, stderr, exitCode, err := RunProbeExec(context.Background(), client, config, pod, "app", probeReadiness)
+	if err != nil {
+		t.Fatalf("RunProbeExec() error: %v", err)
+	}
+	if stdout != "hello\n" || stderr != "oops\n" || exitCode != 0 {
+		t.Errorf("RunProbeExec() = %q, %q, %d, want hello, oops and exit code 0", stdout, stderr, exitCode)
+	}
+	for _, param := range []string{"container=app", "command=cat", "command=%2Ftmp%2Fready", "stdout=true", "stderr=true"} {
+		if !strings.Contains(fake.query, param) {
+			t.Errorf("exec query %q is missing %s", fake.query, param)
+		}
+	}
+}
+
+func TestRunProbeExecFailing(t *testing.T) {
+	pod := newProbedPod()
+	_, config, client := startExecServer(t, pod, 1)
+
+	_, stderr, exitCode, err := RunProbeExec(context.Background(), client, config, pod, "app", probeReadiness)
+	if err != nil {
+		t.Fatalf("RunProbeExec() error: %v, want the exit code instead", err)
+	}
+	if exitCode != 1 || stderr != "oops\n" {
+		t.Errorf("RunProbeExec() exit code %d, stderr %q, want 1 and oops", exitCode, stderr)
+	}
+}
+
+func TestRunProbeExecUnusable(t *testing.T) {
+	pod := newProbedPod()
+	_, config, client := startExecServer(t, pod, 0)
+
+	tests := []struct {
+		container, probeType, want string
+	}{
+		{container: "app", probeType: probeLiveness, want: "not an exec probe"},
+		{container: "app", probeType: probeStartup, want: "has no startup probe"},
+		{container: "sidecar", probeType: probeReadiness, want: "has no readiness probe"},
+		{container: "missing", probeType: probeReadiness, want: "not found"},
+		{container: "app", probeType: "warmup", want: "unknown probe type"},
+	}
+	for _, tt := range tests {
+		_, _, _, err := RunProbeExec(context.Background(), client, config, pod, tt.container, tt.probeType)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("RunProbeExec(%s, %s) error = %v, want %q", tt.container, tt.probeType, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// podQuery holds the filters applied to listed pods and the details
+// gathered for the pods that remain
+type podQuery struct {
+	Namespaces []string
+	Filter     podinfo.Filter
+	Owners     bool
+	Containers bool
+	Resources  bool
+	Metrics    bool
+	Events     bool
+	// SortBy orders the pods, --reverse already applied to its keys
+	SortBy []podinfo.SortKey
+	// Timeout is the --timeout the requests run under, to report deadline errors
+	Timeout time.Duration
+	// Labels copies each pod's labels into its info
+	Labels bool
+	// Annotations lists the annotation keys copied into each pod's info
+	Annotations []string
+	// WhyPending explains the pods not bound to a node yet
+	WhyPending bool
+	// Volumes extracts each pod's volumes
+	Volumes bool
+	// Scanner flags the pods running vulnerable images, nil without
+	// --scan-images
+	Scanner *imageScanner
+	// FailingContainers records the failing containers of each pod, for
+	// --tail-logs
+	FailingContainers bool
+}
+
+// podResult is the outcome of a podQuery: the matching pods, their infos
+// (in display order) and how many pods --exclude-namespace hid
+type podResult struct {
+	Pods   []v1.Pod
+	Infos  []podinfo.PodInfo
+	Hidden int
+	// Failing holds the failing containers of the pods that have some, by
+	// podRef, when the query asked for them
+	Failing map[string][]podinfo.FailingContainer
+}
+
+// process filters the pods listed from each cluster and extracts their
+// infos. Problems with optional details (metrics, events) are printed as
+// warnings.
+func (q podQuery) process(ctx context.Context, listed []clusterPods, now time.Time) (podResult, error) {
+	var result podResult
+	for _, c := range listed {
+		clusterPods, clusterHidden := q.Filter.Apply(c.Pods, now)
+		result.Hidden += clusterHidden
+
+		var owners *ownerResolver
+		if q.Owners {
+			owners = newOwnerResolver(c.Cluster.Client)
+		}
+		var events *eventFetcher
+		if q.Events || q.WhyPending {
+			events = &eventFetcher{client: c.Cluster.Client}
+		}
+		var clusterInfos []podinfo.PodInfo
+		for i := range clusterPods {
+			podInfo := podinfo.Extract(&clusterPods[i], now)
+			podInfo.Cluster = c.Cluster.Name
+			if owners != nil {
+				podInfo.Owner = owners.resolve(ctx, &clusterPods[i])
+			}
+			if q.Labels {
+				podInfo.Labels = clusterPods[i].Labels
+			}
+			if len(q.Annotations) > 0 {
+				podInfo.Annotations = selectKeys(clusterPods[i].Annotations, q.Annotations)
+			}
+			if q.Containers {
+				podInfo.Containers = podinfo.ExtractContainers(&clusterPods[i], now)
+			}
+			if q.WhyPending && clusterPods[i].Spec.NodeName == "" {
+				scheduling, err := explainPending(ctx, events, &clusterPods[i])
+				if err != nil {
+					logWarnings(ctx, []error{timeoutError(err, q.Timeout)})
+				}
+				podInfo.Scheduling = scheduling
+			}
+			if q.Events && needsEvents(&clusterPods[i]) {
+				podEvents, err := events.fetch(ctx, &clusterPods[i], now)
+				if err != nil {
+					logWarnings(ctx, []error{timeoutError(err, q.Timeout)})
+				} else if podEvents != nil {
+					podInfo.Events = podEvents
+				}
+			}
+			if q.Volumes {
+				podInfo.Volumes = podinfo.ExtractVolumes(&clusterPods[i])
+			}
+			if q.Resources {
+				res := podinfo.ExtractResources(&clusterPods[i])
+				podInfo.Resources = &res
+			}
+			if q.FailingContainers {
+				if failing := podinfo.FailingContainers(&clusterPods[i]); len(failing) > 0 {
+					if result.Failing == nil {
+						result.Failing = map[string][]podinfo.FailingContainer{}
+					}
+					result.Failing[podRef(podInfo)] = failing
+				}
+			}
+			clusterInfos = append(clusterInfos, podInfo)
+		}
+		if q.Metrics && len(clusterInfos) > 0 {
+			// A missing metrics-server only costs the usage columns, not the listing
+			metricsClient, err := metricsclientset.NewForConfig(c.Cluster.Config)
+			if err != nil {
+				return podResult{}, fmt.Errorf("creating metrics client: %w", err)
+			}
+			usage, err := fetchPodUsage(ctx, metricsClient.MetricsV1beta1(), q.Namespaces)
+			if err != nil {
+				logWarnings(ctx, []error{timeoutError(err, q.Timeout)})
+			} else {
+				joinUsage(clusterInfos, clusterPods, usage)
+			}
+		}
+		result.Pods = append(result.Pods, clusterPods...)
+		result.Infos = append(result.Infos, clusterInfos...)
+	}
+	if q.Scanner != nil {
+		logWarnings(ctx, q.Scanner.annotate(ctx, result.Infos))
+	}
+	if len(q.SortBy) > 0 {
+		podinfo.Sort(result.Infos, q.SortBy)
+	}
+	return result, nil
+}
+
+// podView holds how a podResult is displayed
+type podView struct {
+	Output     string
+	GroupBy    string
+	Summary    bool
+	Top        int
+	Resources  bool
+	Namespaces []string
+	// Node is the --node name or pattern, to say which nodes had no pods
+	Node  string
+	Print printOptions
+	// Template prints each pod instead of the text or table output when
+	// --output-template is set
+	Template *template.Template
+}
+
+// structured reports whether the view prints JSON or YAML
+func (v podView) structured() bool {
+	return v.Output == outputJSON || v.Output == outputYAML
+}
+
+// noPodsMessage says where no pods were found: on the --node node(s), in
+// the --namespace namespaces or in the whole cluster
+func (v podView) noPodsMessage() string {
+	var where []string
+	switch {
+	case v.Node == "":
+	case podinfo.IsNodePattern(v.Node):
+		where = append(where, fmt.Sprintf("on nodes matching '%s'", v.Node))
+	default:
+		where = append(where, fmt.Sprintf("on node '%s'", v.Node))
+	}
+	if len(v.Namespaces) > 0 {
+		where = append(where, fmt.Sprintf("in namespace '%s'", strings.Join(v.Namespaces, ",")))
+	}
+	if len(where) == 0 {
+		return "No pods found in the cluster"
+	}
+	return "No pods found " + strings.Join(where, " ")
+}
+
+// printPodNames prints one pod per line as namespace/name, or as just the
+// name when a single namespace was requested, prefixed by the cluster when
+// several are listed
+func printPodNames(out io.Writer, infos []podinfo.PodInfo, singleNamespace bool) {
+	for _, info := range infos {
+		name := podRef(info)
+		if singleNamespace {
+			name = info.Name
+			if info.Cluster != "" {
+				name = info.Cluster + ":" + name
+			}
+		}
+		fmt.Fprintln(out, name)
+	}
+}
+
+// render prints result as a summary, owner groups, JSON/YAML, pod names,
+// node groups, a table or text blocks, followed by the totals unless
+// --no-headers leaves them out
+func (v podView) render(out io.Writer, result podResult) error {
+	infos := result.Infos
+	if v.Summary {
+		podSummary := summarize(infos, v.Top)
+		if v.structured() {
+			return printStructured(out, podSummary, v.Output)
+		}
+		err := podSummary.print(out)
+		printHidden(out, result.Hidden)
+		return err
+	}
+
+	if v.GroupBy == groupByOwnerKey {
+		groups := groupByOwner(infos)
+		if v.structured() {
+			return printStructured(out, groups, v.Output)
+		}
+		err := printOwnerGroups(out, groups)
+		printHidden(out, result.Hidden)
+		return err
+	}
+
+	if v.structured() {
+		var value interface{} = infos
+		if v.GroupBy == groupByNodeKey {
+			value = groupByNode(infos)
+		} else if infos == nil {
+			value = []podinfo.PodInfo{}
+		}
+		return printStructured(out, value, v.Output)
+	}
+	if v.Output == outputName {
+		printPodNames(out, infos, len(v.Namespaces) == 1)
+		return nil
+	}
+	if v.Template != nil {
+		return printTemplate(out, v.Template, infos)
+	}
+
+	if len(result.Pods) == 0 {
+		if v.Print.NoHeaders {
+			return nil
+		}
+		fmt.Fprintln(out, v.noPodsMessage())
+		printHidden(out, result.Hidden)
+		return nil
+	}
+
+	// Display pods
+	if v.GroupBy == groupByNodeKey {
+		if err := printNodeGroups(out, groupByNode(infos), v.Output, v.Print); err != nil {
+			return err
+		}
+	} else if v.Output == outputTable {
+		if err := printPodTable(out, infos, v.Print); err != nil {
+			return err
+		}
+	} else {
+		if !v.Print.NoHeaders {
+			fmt.Fprintf(out, "Found %d pods:\n\n", len(result.Pods))
+		}
+		for _, podInfo := range infos {
+			printPodInfo(out, podInfo, v.Print)
+		}
+	}
+	if v.Print.NoHeaders {
+		return nil
+	}
+	if v.Output == outputTable && v.GroupBy == "" {
+		fmt.Fprintln(out)
+	}
+
+	printTotals(out, result.Pods, v.Namespaces)
+	printHidden(out, result.Hidden)
+	if v.Print.ShowVolumes {
+		printHostPathWarning(out, infos, v.Print.Color)
+	}
+	if v.Resources {
+		fmt.Fprintln(out)
+		if err := printResourceTotals(out, infos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	utilexec "k8s.io/utils/exec"
+)
+
+// Markers of quota rows running out, and the usage percentages they start at
+const (
+	quotaWarnMarker  = "[WARN]"
+	quotaCritMarker  = "[CRIT]"
+	quotaWarnPercent = 80
+	quotaCritPercent = 95
+)
+
+// exitQuotaCritical is the quota --exit-nonzero-on-crit status when a
+// resource is at or above quotaCritPercent of its hard limit
+const exitQuotaCritical = 2
+
+// QuotaEntry is the usage of one resource of a ResourceQuota
+type QuotaEntry struct {
+	Resource v1.ResourceName   `json:"resource"`
+	Used     resource.Quantity `json:"used"`
+	Hard     resource.Quantity `json:"hard"`
+}
+
+// Percent returns Used as a percentage of Hard. A zero hard limit allows
+// nothing, so any use of it counts as 100%.
+func (e QuotaEntry) Percent() float64 {
+	hard := e.Hard.AsApproximateFloat64()
+	used := e.Used.AsApproximateFloat64()
+	if hard <= 0 {
+		if used > 0 {
+			return 100
+		}
+		return 0
+	}
+	return used / hard * 100
+}
+
+// Marker returns quotaCritMarker or quotaWarnMarker when the entry is close
+// to its hard limit, and "" otherwise
+func (e QuotaEntry) Marker() string {
+	switch percent := e.Percent(); {
+	case percent >= quotaCritPercent:
+		return quotaCritMarker
+	case percent >= quotaWarnPercent:
+		return quotaWarnMarker
+	}
+	return ""
+}
+
+// ExtractQuotaUsage returns the usage of each resource rq limits, sorted by
+// resource name. The hard limits come from the status, which the quota
+// controller fills in, or from the spec before it has. A resource missing
+// from the used status has no usage yet.
+func ExtractQuotaUsage(rq *v1.ResourceQuota) []QuotaEntry {
+	hard := rq.Status.Hard
+	if len(hard) == 0 {
+		hard = rq.Spec.Hard
+	}
+	entries := make([]QuotaEntry, 0, len(hard))
+	for name, limit := range hard {
+		entry := QuotaEntry{Resource: name, Hard: limit}
+		if used, ok := rq.Status.Used[name]; ok {
+			entry.Used = used
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Resource < entries[j].Resource })
+	return entries
+}
+
+// QuotaInfo holds the usage of a ResourceQuota
+type QuotaInfo struct {
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace"`
+	Entries   []QuotaEntry `json:"entries"`
+}
+
+// critical reports whether a resource of the quota is at or above
+// quotaCritPercent of its hard limit
+func (q QuotaInfo) critical() bool {
+	for _, entry := range q.Entries {
+		if entry.Marker() == quotaCritMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// listQuotas lists the resource quotas of namespace with their usage
+func listQuotas(ctx context.Context, client kubernetes.Interface, namespace string) ([]QuotaInfo, error) {
+	quotas, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, handleAPIError(err, "resourcequotas", namespace)
+	}
+	infos := []QuotaInfo{}
+	for i := range quotas.Items {
+		rq := &quotas.Items[i]
+		infos = append(infos, QuotaInfo{Name: rq.Name, Namespace: rq.Namespace, Entries: ExtractQuotaUsage(rq)})
+	}
+	return infos, nil
+}
+
+// printQuotaTable prints a usage table for the quota, marking the resources
+// close to their hard limit
+func printQuotaTable(out io.Writer, info QuotaInfo) error {
+	fmt.Fprintf(out, "ResourceQuota: %s/%s\n", info.Namespace, info.Name)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tUSED\tHARD\tPERCENT-USED")
+	for _, entry := range info.Entries {
+		percent := fmt.Sprintf("%.0f%%", entry.Percent())
+		if marker := entry.Marker(); marker != "" {
+			percent += " " + marker
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Resource, entry.Used.String(), entry.Hard.String(), percent)
+	}
+	return w.Flush()
+}
+
+// runQuota implements the quota subcommand
+func runQuota(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("quota", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "default", "namespace to list resource quotas from")
+	exitOnCrit := fs.Bool("exit-nonzero-on-crit", false, fmt.Sprintf("exit with status %d when a resource uses %d%% or more of its quota, e.g. to alert from CI", exitQuotaCritical, quotaCritPercent))
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+
+	var infos []QuotaInfo
+	err := replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err = listQuotas(ctx, client, *namespace)
+		if err != nil {
+			return fmt.Errorf("error listing resource quotas: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintf(out, "No resource quotas found in namespace '%s'\n", *namespace)
+			return nil
+		}
+		for i, info := range infos {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			if err := printQuotaTable(out, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if *exitOnCrit {
+		for _, info := range infos {
+			if info.critical() {
+				// fatal exits with the status without logging an error
+				return utilexec.CodeExitError{Err: errors.New("a resource quota is critically used"), Code: exitQuotaCritical}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExtractQuotaUsage(t *testing.T) {
+	rq := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "compute"},
+		Spec:       v1.ResourceQuotaSpec{Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("99")}},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{
+				v1.ResourceRequestsCPU:    resource.MustParse("4"),
+				v1.ResourceLimitsMemory:   resource.MustParse("8Gi"),
+				v1.ResourcePods:           resource.MustParse("10"),
+				v1.ResourceConfigMaps:     resource.MustParse("20"),
+				v1.ResourceRequestsMemory: resource.MustParse("1G"),
+			},
+			Used: v1.ResourceList{
+				// 3.5 cores in millicores
+				v1.ResourceRequestsCPU: resource.MustParse("3500m"),
+				// 7.75Gi in Mi
+				v1.ResourceLimitsMemory: resource.MustParse("7936Mi"),
+				v1.ResourcePods:         resource.MustParse("8"),
+				// 0.95G in decimal bytes
+				v1.ResourceRequestsMemory: resource.MustParse("950M"),
+			},
+		},
+	}
+
+	entries := ExtractQuotaUsage(rq)
+	want := []struct {
+		resource v1.ResourceName
+		used     string
+		percent  float64
+		marker   string
+	}{
+		{"configmaps", "0", 0, ""},
+		{"limits.memory", "7936Mi", 96.875, quotaCritMarker},
+		{"pods", "8", 80, quotaWarnMarker},
+		{"requests.cpu", "3500m", 87.5, quotaWarnMarker},
+		{"requests.memory", "950M", 95, quotaCritMarker},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Resource != w.resource || e.Used.String() != w.used {
+			t.Errorf("entry %d = %s used %s, want %s used %s", i, e.Resource, e.Used.String(), w.resource, w.used)
+		}
+		if math.Abs(e.Percent()-w.percent) > 0.001 || e.Marker() != w.marker {
+			t.Errorf("%s = %.3f%% %q, want %.3f%% %q", e.Resource, e.Percent(), e.Marker(), w.percent, w.marker)
+		}
+	}
+
+	// Before the quota controller has filled in the status
+	fresh := &v1.ResourceQuota{Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("10")}}}
+	if entries := ExtractQuotaUsage(fresh); len(entries) != 1 || entries[0].Hard.String() != "10" || !entries[0].Used.IsZero() {
+		t.Errorf("ExtractQuotaUsage() without status = %+v, want pods 0/10", entries)
+	}
+}
+
+func TestQuotaEntryZeroHard(t *testing.T) {
+	unused := QuotaEntry{Resource: v1.ResourceServicesLoadBalancers, Hard: resource.MustParse("0")}
+	if unused.Percent() != 0 || unused.Marker() != "" {
+		t.Errorf("unused zero quota = %.0f%% %q, want 0%% and no marker", unused.Percent(), unused.Marker())
+	}
+	used := QuotaEntry{Resource: v1.ResourceServicesLoadBalancers, Used: resource.MustParse("1"), Hard: resource.MustParse("0")}
+	if used.Percent() != 100 || used.Marker() != quotaCritMarker {
+		t.Errorf("used zero quota = %.0f%% %q, want 100%% %s", used.Percent(), used.Marker(), quotaCritMarker)
+	}
+}
+
+func TestListQuotasAndPrint(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "objects"},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("10"), v1.ResourceSecrets: resource.MustParse("100")},
+			Used: v1.ResourceList{v1.ResourcePods: resource.MustParse("10"), v1.ResourceSecrets: resource.MustParse("3")},
+		},
+	})
+
+	infos, err := listQuotas(context.Background(), client, "team-a")
+	if err != nil {
+		t.Fatalf("listQuotas() error = %v", err)
+	}
+	if len(infos) != 1 || !infos[0].critical() {
+		t.Fatalf("listQuotas() = %+v, want one critical quota", infos)
+	}
+
+	var out bytes.Buffer
+	if err := printQuotaTable(&out, infos[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := `ResourceQuota: team-a/objects
+RESOURCE   USED   HARD   PERCENT-USED
+pods       10     10     100% [CRIT]
+secrets    3      100    3%
+`
+	if out.String() != want {
+		t.Errorf("printQuotaTable() =\n%q\nwant\n%q", out.String(), want)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	podcache "Kubernetes_Programming/pkg/cache"
+
+	"golang.org/x/term"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\x1b[H\x1b[2J"
+
+// clusterCaches holds the pod caches watching one cluster, one per
+// --namespace (or a single all-namespaces cache)
+type clusterCaches struct {
+	Cluster cluster
+	Caches  []*podcache.PodCache
+}
+
+// startPodCaches starts a pod cache per cluster and namespace, filtered by
+// the label and field selectors of listOpts, and waits up to timeout (0 for no limit) for their initial
+// list. Clusters that fail to sync are returned as warnings and dropped; it
+// is an error if none sync. The caches keep running until ctx is done: the
+// timeout only applies to the initial list, not to the watch.
+//
+// Each cache lists once and then only watches. When the connection to the
+// API server drops, its reflector re-watches from the last resource version,
+// or re-lists if that version has expired, so a refresh after a disconnect
+// shows the current pods rather than a stale copy.
+func startPodCaches(ctx context.Context, clusters []cluster, namespaces []string, listOpts metav1.ListOptions, timeout time.Duration) ([]clusterCaches, []error, error) {
+	tweak := func(opts *metav1.ListOptions) {
+		opts.LabelSelector = listOpts.LabelSelector
+		opts.FieldSelector = listOpts.FieldSelector
+	}
+	watchNamespaces := namespaces
+	if len(watchNamespaces) == 0 {
+		watchNamespaces = []string{metav1.NamespaceAll}
+	}
+
+	var started []clusterCaches
+	for _, c := range clusters {
+		cc := clusterCaches{Cluster: c}
+		for _, ns := range watchNamespaces {
+			pc := podcache.NewFilteredPodCache(c.Client, ns, 0, tweak)
+			go pc.Run(ctx.Done())
+			cc.Caches = append(cc.Caches, pc)
+		}
+		started = append(started, cc)
+	}
+
+	syncCtx, cancel := requestContext(ctx, timeout)
+	defer cancel()
+
+	var synced []clusterCaches
+	var warnings []error
+	for _, cc := range started {
+		ok := true
+		for _, pc := range cc.Caches {
+			if !pc.WaitForCacheSync(syncCtx.Done()) {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			err := fmt.Errorf("initial pod list: %w", timeoutError(syncCtx.Err(), timeout))
+			if cc.Cluster.Name != "" {
+				err = fmt.Errorf("context %q: %w", cc.Cluster.Name, err)
+			}
+			warnings = append(warnings, err)
+			continue
+		}
+		synced = append(synced, cc)
+	}
+	if len(synced) == 0 {
+		return nil, warnings, fmt.Errorf("no pod cache could be synced")
+	}
+	return synced, warnings, nil
+}
+
+// snapshotPods copies the cached pods of each cluster, sorted by namespace
+// and name like a List response, so every refresh starts from the same order
+func snapshotPods(caches []clusterCaches) []clusterPods {
+	var listed []clusterPods
+	for _, cc := range caches {
+		var pods []v1.Pod
+		for _, pc := range cc.Caches {
+			for _, pod := range pc.ListPods() {
+				pods = append(pods, *pod.DeepCopy())
+			}
+		}
+		sort.Slice(pods, func(i, j int) bool {
+			if pods[i].Namespace != pods[j].Namespace {
+				return pods[i].Namespace < pods[j].Namespace
+			}
+			return pods[i].Name < pods[j].Name
+		})
+		listed = append(listed, clusterPods{Cluster: cc.Cluster, Pods: pods})
+	}
+	return listed
+}
+
+// refreshHeader returns the line printed above each refresh: the interval,
+// the update time and the number of pods per phase
+func refreshHeader(interval time.Duration, now time.Time, pods []v1.Pod) string {
+	counts := make(map[v1.PodPhase]int)
+	for i := range pods {
+		counts[pods[i].Status.Phase]++
+	}
+	return fmt.Sprintf("Every %s, updated %s: %d pods (%d running, %d pending, %d succeeded, %d failed)",
+		interval, now.Format("15:04:05"), len(pods),
+		counts[v1.PodRunning], counts[v1.PodPending], counts[v1.PodSucceeded], counts[v1.PodFailed])
+}
+
+// runRefresh redraws the pod listing every interval from informer caches
+// until interrupted. Ctrl-C stops the informers and returns nil.
+func runRefresh(parent context.Context, interval time.Duration, clusters []cluster, listOpts metav1.ListOptions, query podQuery, view podView) error {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	caches, warnings, err := startPodCaches(ctx, clusters, query.Namespaces, listOpts, query.Timeout)
+	if ctx.Err() != nil {
+		// Interrupted before the caches synced
+		return nil
+	}
+	logWarnings(ctx, warnings)
+	if err != nil {
+		return err
+	}
+
+	terminal := term.IsTerminal(int(os.Stdout.Fd()))
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := redraw(ctx, os.Stdout, terminal, interval, caches, query, view); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// redraw renders one refresh to out, clearing the screen first on a terminal
+func redraw(ctx context.Context, out io.Writer, terminal bool, interval time.Duration, caches []clusterCaches, query podQuery, view podView) error {
+	// Owners, events and metrics are still fetched per refresh; bound them
+	// by --timeout so a slow API server cannot stall the display
+	tickCtx, cancel := requestContext(ctx, query.Timeout)
+	defer cancel()
+
+	now := time.Now()
+	result, err := query.process(tickCtx, snapshotPods(caches), now)
+	if err != nil {
+		return err
+	}
+	if terminal {
+		fmt.Fprint(out, clearScreen)
+	}
+	fmt.Fprintln(out, refreshHeader(interval, now, result.Pods))
+	fmt.Fprintln(out)
+	return view.render(out, result)
+}
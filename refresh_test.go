@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"Kubernetes_Programming/pkg/config"
+)
+
+func TestSnapshotPodsFromCaches(t *testing.T) {
+	labeled := func(namespace, name, app string) *v1.Pod {
+		pod := newTestPod(namespace, name)
+		pod.Labels = map[string]string{"app": app}
+		return pod
+	}
+	client := fake.NewSimpleClientset(
+		labeled("monitoring", "prometheus", "web"),
+		labeled("default", "web-b", "web"),
+		labeled("default", "web-a", "web"),
+		labeled("default", "db", "db"),
+		labeled("other", "web-c", "web"),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	caches, warnings, err := startPodCaches(ctx, []cluster{{Client: client}}, []string{"monitoring", "default"}, metav1.ListOptions{LabelSelector: "app=web"}, config.DefaultTimeout)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("startPodCaches() warnings = %v, err = %v", warnings, err)
+	}
+	listed := snapshotPods(caches)
+	if len(listed) != 1 {
+		t.Fatalf("got %d clusters, want 1", len(listed))
+	}
+	if got, want := podNames(listed[0].Pods), []string{"web-a", "web-b", "prometheus"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot = %v, want %v", got, want)
+	}
+}
+
+func TestRefreshHeader(t *testing.T) {
+	pods := []v1.Pod{
+		{Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{Status: v1.PodStatus{Phase: v1.PodPending}},
+		{Status: v1.PodStatus{Phase: v1.PodFailed}},
+	}
+	now := time.Date(2024, 5, 1, 9, 30, 15, 0, time.UTC)
+	want := "Every 5s, updated 09:30:15: 4 pods (2 running, 1 pending, 0 succeeded, 1 failed)"
+	if got := refreshHeader(5*time.Second, now, pods); got != want {
+		t.Errorf("refreshHeader() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// formatCPU prints a CPU quantity in millicores below one core and in
+// (possibly fractional) cores otherwise, e.g. "500m", "2", "1.5"; nil is "-"
+func formatCPU(q *resource.Quantity) string {
+	if q == nil {
+		return "-"
+	}
+	milli := q.MilliValue()
+	if milli < 1000 {
+		return fmt.Sprintf("%dm", milli)
+	}
+	return strconv.FormatFloat(float64(milli)/1000, 'f', -1, 64)
+}
+
+// formatMemory prints a memory quantity in the largest binary unit it
+// reaches with at most one decimal, e.g. "512Mi", "1.5Gi"; nil is "-"
+func formatMemory(q *resource.Quantity) string {
+	if q == nil {
+		return "-"
+	}
+	value := float64(q.Value())
+	unit := ""
+	for _, u := range []string{"Ki", "Mi", "Gi", "Ti", "Pi"} {
+		if value < 1024 {
+			break
+		}
+		value /= 1024
+		unit = u
+	}
+	return trimDecimal(value) + unit
+}
+
+// formatResourceList prints the quantities of list as the specs set them,
+// e.g. "cpu: 500m, memory: 256Mi": CPU and memory first, "<none>" when
+// missing, then any other resource (e.g. ephemeral-storage) by name
+func formatResourceList(list v1.ResourceList) string {
+	parts := make([]string, 0, len(list)+2)
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		value := "<none>"
+		if q, ok := list[name]; ok {
+			value = q.String()
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, value))
+	}
+	var others []string
+	for name := range list {
+		if name != v1.ResourceCPU && name != v1.ResourceMemory {
+			others = append(others, string(name))
+		}
+	}
+	sort.Strings(others)
+	for _, name := range others {
+		q := list[v1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s: %s", name, q.String()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// trimDecimal formats v with one decimal, dropping a trailing ".0"
+func trimDecimal(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if len(s) > 2 && s[len(s)-2:] == ".0" {
+		return s[:len(s)-2]
+	}
+	return s
+}
+
+// resourceTotals accumulates CPU and memory requests per group
+type resourceTotals struct {
+	cpu    map[string]*resource.Quantity
+	memory map[string]*resource.Quantity
+}
+
+// newResourceTotals returns empty totals
+func newResourceTotals() *resourceTotals {
+	return &resourceTotals{cpu: map[string]*resource.Quantity{}, memory: map[string]*resource.Quantity{}}
+}
+
+// add adds the requests of res to key's totals; missing requests add nothing
+func (t *resourceTotals) add(key string, res podinfo.PodResources) {
+	addQuantity(t.cpu, key, res.CPURequest)
+	addQuantity(t.memory, key, res.MemoryRequest)
+	// Make sure the group is listed even when nothing in it has requests
+	if _, ok := t.cpu[key]; !ok {
+		t.cpu[key] = nil
+	}
+	if _, ok := t.memory[key]; !ok {
+		t.memory[key] = nil
+	}
+}
+
+// addQuantity adds q to totals[key], leaving it untouched when q is nil
+func addQuantity(totals map[string]*resource.Quantity, key string, q *resource.Quantity) {
+	if q == nil {
+		return
+	}
+	if totals[key] == nil {
+		sum := q.DeepCopy()
+		totals[key] = &sum
+		return
+	}
+	totals[key].Add(*q)
+}
+
+// print writes a table of the totals with the given key column header
+func (t *resourceTotals) print(out io.Writer, header string) error {
+	keys := make([]string, 0, len(t.cpu))
+	for key := range t.cpu {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "%s\tCPU-REQ\tMEM-REQ\n", header)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, formatCPU(t.cpu[key]), formatMemory(t.memory[key]))
+	}
+	return w.Flush()
+}
+
+// printResourceTotals prints the total requests per namespace and per node
+func printResourceTotals(out io.Writer, infos []podinfo.PodInfo) error {
+	byNamespace := newResourceTotals()
+	byNode := newResourceTotals()
+	for _, info := range infos {
+		if info.Resources == nil {
+			continue
+		}
+		node := info.NodeName
+		if node == "" {
+			node = "<unscheduled>"
+		}
+		byNamespace.add(info.Namespace, *info.Resources)
+		byNode.add(node, *info.Resources)
+	}
+
+	fmt.Fprintln(out, "Requests by namespace:")
+	if err := byNamespace.print(out, "NAMESPACE"); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nRequests by node:")
+	return byNode.print(out, "NODE")
+}
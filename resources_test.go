@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// withResources returns a container with the given cpu/memory requests and
+// limits; empty strings leave the resource unset
+func withResources(name, cpuReq, memReq, cpuLim, memLim string) v1.Container {
+	list := func(cpu, mem string) v1.ResourceList {
+		l := v1.ResourceList{}
+		if cpu != "" {
+			l[v1.ResourceCPU] = resource.MustParse(cpu)
+		}
+		if mem != "" {
+			l[v1.ResourceMemory] = resource.MustParse(mem)
+		}
+		return l
+	}
+	return v1.Container{Name: name, Resources: v1.ResourceRequirements{Requests: list(cpuReq, memReq), Limits: list(cpuLim, memLim)}}
+}
+
+func TestExtractPodResources(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	sidecar := withResources("sidecar", "100m", "64Mi", "", "")
+	sidecar.RestartPolicy = &always
+
+	tests := []struct {
+		name                               string
+		spec                               v1.PodSpec
+		cpuReq, memReq, cpuLimit, memLimit string
+	}{
+		{
+			name:   "no requests",
+			spec:   v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+			cpuReq: "-", memReq: "-", cpuLimit: "-", memLimit: "-",
+		},
+		{
+			name: "app containers are summed",
+			spec: v1.PodSpec{Containers: []v1.Container{
+				withResources("a", "250m", "512Mi", "500m", "1Gi"),
+				withResources("b", "250m", "1Gi", "1500m", "512Mi"),
+			}},
+			cpuReq: "500m", memReq: "1.5Gi", cpuLimit: "2", memLimit: "1.5Gi",
+		},
+		{
+			name: "larger init container wins",
+			spec: v1.PodSpec{
+				InitContainers: []v1.Container{withResources("init", "2", "128Mi", "", "")},
+				Containers:     []v1.Container{withResources("app", "500m", "256Mi", "", "")},
+			},
+			cpuReq: "2", memReq: "256Mi", cpuLimit: "-", memLimit: "-",
+		},
+		{
+			name: "sidecars add to app containers and later init containers",
+			spec: v1.PodSpec{
+				InitContainers: []v1.Container{sidecar, withResources("init", "1", "", "", "")},
+				Containers:     []v1.Container{withResources("app", "200m", "64Mi", "", "")},
+			},
+			cpuReq: "1.1", memReq: "128Mi", cpuLimit: "-", memLimit: "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := podinfo.ExtractResources(&v1.Pod{Spec: tt.spec})
+			got := []string{formatCPU(res.CPURequest), formatMemory(res.MemoryRequest), formatCPU(res.CPULimit), formatMemory(res.MemoryLimit)}
+			want := []string{tt.cpuReq, tt.memReq, tt.cpuLimit, tt.memLimit}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("got %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFormatQuantities(t *testing.T) {
+	cpu := map[string]string{"100m": "100m", "1": "1", "1500m": "1.5", "0.25": "250m"}
+	for in, want := range cpu {
+		q := resource.MustParse(in)
+		if got := formatCPU(&q); got != want {
+			t.Errorf("formatCPU(%s) = %q, want %q", in, got, want)
+		}
+	}
+	memory := map[string]string{"512": "512", "64Mi": "64Mi", "1536Mi": "1.5Gi", "1G": "953.7Mi", "2Gi": "2Gi"}
+	for in, want := range memory {
+		q := resource.MustParse(in)
+		if got := formatMemory(&q); got != want {
+			t.Errorf("formatMemory(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestShowResources(t *testing.T) {
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		withResources("app", "0.25", "128Mi", "500m", "1Gi"),
+		withResources("proxy", "250m", "128Mi", "", "512Mi"),
+	}}}
+	pod.Spec.Containers[0].Resources.Requests[v1.ResourceEphemeralStorage] = resource.MustParse("1Gi")
+	info := podinfo.Extract(pod, time.Now())
+	res := podinfo.ExtractResources(pod)
+	info.Resources = &res
+
+	var out bytes.Buffer
+	printPodInfo(&out, info, printOptions{ShowResources: true})
+	for _, want := range []string{
+		"  Requests: cpu: 500m, memory: 256Mi, ephemeral-storage: 1Gi\n",
+		// The proxy sets no CPU limit
+		"  Limits: cpu: <none>, memory: 1536Mi\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printPodInfo() =\n%s\nwant a line %q", out.String(), want)
+		}
+	}
+	// The effective values are the --resources view's
+	if strings.Contains(out.String(), "  CPU: ") || strings.Contains(out.String(), "  Memory: ") {
+		t.Errorf("printPodInfo() with only ShowResources =\n%s\nwant no CPU or Memory lines", out.String())
+	}
+	var table bytes.Buffer
+	if err := printPodTable(&table, []podinfo.PodInfo{info}, printOptions{ShowResources: true}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(table.String(), "CPU-REQ") {
+		t.Errorf("printPodTable() with only ShowResources =\n%s\nwant no request and limit columns", table.String())
+	}
+	out.Reset()
+	printPodInfo(&out, info, printOptions{Resources: true})
+	if !strings.Contains(out.String(), "  CPU: requests 500m, limits 500m\n") {
+		t.Errorf("printPodInfo() with Resources =\n%s\nwant the effective CPU line", out.String())
+	}
+
+	out.Reset()
+	printPodInfo(&out, info, printOptions{})
+	if strings.Contains(out.String(), "Requests:") {
+		t.Errorf("printPodInfo() without ShowResources =\n%s", out.String())
+	}
+	if got := formatResourceList(nil); got != "cpu: <none>, memory: <none>" {
+		t.Errorf("formatResourceList(nil) = %q", got)
+	}
+}
+
+func TestResourceTotals(t *testing.T) {
+	cpu := resource.MustParse("250m")
+	mem := resource.MustParse("512Mi")
+	totals := newResourceTotals()
+	totals.add("default", podinfo.PodResources{CPURequest: &cpu, MemoryRequest: &mem})
+	totals.add("default", podinfo.PodResources{CPURequest: &cpu})
+	totals.add("empty", podinfo.PodResources{})
+
+	if got := formatCPU(totals.cpu["default"]); got != "500m" {
+		t.Errorf("default cpu = %q, want 500m", got)
+	}
+	if got := formatMemory(totals.memory["default"]); got != "512Mi" {
+		t.Errorf("default memory = %q, want 512Mi", got)
+	}
+	if got, ok := totals.cpu["empty"]; !ok || got != nil {
+		t.Errorf("empty namespace should be listed without requests, got %v (listed %v)", got, ok)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SecretInfo holds formatted secret information. Only the key names are
+// kept, never the values, unless they are explicitly revealed.
+type SecretInfo struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	Type      string   `json:"type"`
+	Keys      []string `json:"keys"`
+	// Revealed holds the decoded values of the keys named by --reveal-keys
+	Revealed  map[string]string `json:"revealed,omitempty"`
+	Age       time.Duration     `json:"-"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// extractSecretInfo extracts the key names and metadata of a secret,
+// leaving the values out
+func extractSecretInfo(secret *v1.Secret, now time.Time) SecretInfo {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for key := range secret.Data {
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	// StringData is write-only and not returned by the API, but objects
+	// built locally (e.g. from a manifest) may still carry it
+	for key := range secret.StringData {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	secretType := string(secret.Type)
+	if secretType == "" {
+		secretType = string(v1.SecretTypeOpaque)
+	}
+	return SecretInfo{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      secretType,
+		Keys:      keys,
+		Age:       now.Sub(secret.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt: secret.CreationTimestamp.Time,
+	}
+}
+
+// revealSecretKeys returns the values of the named keys the secret has.
+// Data is base64 on the wire and already decoded by the client; values that
+// are not text are summarized instead of printed.
+func revealSecretKeys(secret *v1.Secret, keys []string) map[string]string {
+	revealed := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := secret.Data[key]; ok {
+			if utf8.Valid(value) {
+				revealed[key] = string(value)
+			} else {
+				revealed[key] = fmt.Sprintf("<%d bytes of binary data>", len(value))
+			}
+		} else if value, ok := secret.StringData[key]; ok {
+			revealed[key] = value
+		}
+	}
+	if len(revealed) == 0 {
+		return nil
+	}
+	return revealed
+}
+
+// printSecretTable prints one row per secret with its key names, followed by
+// the revealed values. The NAMESPACE column is shown when listing across
+// namespaces.
+func printSecretTable(out io.Writer, infos []SecretInfo, showNamespace bool, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tTYPE\tDATA-KEYS\tAGE")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			info.Name, info.Type, orNone(strings.Join(info.Keys, ",")), formatAge(ageFormat, info.Age, info.CreatedAt))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		keys := make([]string, 0, len(info.Revealed))
+		for key := range info.Revealed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(out, "\n%s/%s %s:\n%s\n", info.Namespace, info.Name, key, strings.TrimRight(info.Revealed[key], "\n"))
+		}
+	}
+	return nil
+}
+
+// runSecrets implements the secrets subcommand
+func runSecrets(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("secrets", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list secrets from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter secrets, e.g. app=web")
+	revealKeys := fs.String("reveal-keys", "", "comma-separated keys whose decoded values are printed, e.g. tls.crt (values are hidden otherwise)")
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table output shows secret age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+		if err := validateAgeFormat(*ageFormat); err != nil {
+			return err
+		}
+		if _, err := labels.Parse(*labelSelector); err != nil {
+			return fmt.Errorf("invalid --label-selector: %w", err)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		secrets, err := client.CoreV1().Secrets(*namespace).List(ctx, metav1.ListOptions{LabelSelector: *labelSelector})
+		if err != nil {
+			return fmt.Errorf("error listing secrets: %w", timeoutError(handleAPIError(err, "secrets", *namespace), clientOpts.Timeout))
+		}
+
+		now := time.Now()
+		reveal := parseList(*revealKeys)
+		infos := []SecretInfo{}
+		for i := range secrets.Items {
+			info := extractSecretInfo(&secrets.Items[i], now)
+			if len(reveal) > 0 {
+				info.Revealed = revealSecretKeys(&secrets.Items[i], reveal)
+			}
+			infos = append(infos, info)
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No secrets found")
+			return nil
+		}
+		return printSecretTable(out, infos, *namespace == "", *ageFormat)
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testCert     = "-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIUQ\n-----END CERTIFICATE-----\n"
+	testPassword = "hunter2"
+)
+
+// newTestSecret returns a TLS secret that also holds a password
+func newTestSecret() *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-tls"},
+		Type:       v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt":  []byte(testCert),
+			"password": []byte(testPassword),
+			"blob":     {0xff, 0xfe, 0x00},
+		},
+		StringData: map[string]string{"extra": "value", "password": testPassword},
+	}
+}
+
+func TestExtractSecretInfo(t *testing.T) {
+	info := extractSecretInfo(newTestSecret(), time.Now())
+	if want := []string{"blob", "extra", "password", "tls.crt"}; !reflect.DeepEqual(info.Keys, want) {
+		t.Errorf("keys = %v, want %v", info.Keys, want)
+	}
+	if info.Type != "kubernetes.io/tls" || info.Revealed != nil {
+		t.Errorf("info = %+v, want a TLS secret without values", info)
+	}
+
+	if got := extractSecretInfo(&v1.Secret{}, time.Now()).Type; got != "Opaque" {
+		t.Errorf("type = %q, want Opaque by default", got)
+	}
+}
+
+func TestSecretOutputRedaction(t *testing.T) {
+	secret := newTestSecret()
+	secrets := []string{testCert, testPassword, base64.StdEncoding.EncodeToString([]byte(testPassword)), "MIIBszCCAVmgAwIBAgIUQ"}
+
+	var out bytes.Buffer
+	info := extractSecretInfo(secret, time.Now())
+	if err := printSecretTable(&out, []SecretInfo{info}, false, ageCompact); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, value := range secrets {
+		if strings.Contains(out.String(), value) {
+			t.Errorf("default output contains the secret value %q:\n%s", value, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "blob,extra,password,tls.crt") {
+		t.Errorf("default output does not list the keys:\n%s", out.String())
+	}
+
+	out.Reset()
+	info.Revealed = revealSecretKeys(secret, []string{"tls.crt", "blob", "missing"})
+	if err := printSecretTable(&out, []SecretInfo{info}, false, ageCompact); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "default/web-tls tls.crt:\n"+testCert) {
+		t.Errorf("revealed output does not contain the decoded certificate:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), base64.StdEncoding.EncodeToString([]byte(testCert))) {
+		t.Errorf("revealed output contains the base64 value instead of the decoded one:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), testPassword) {
+		t.Errorf("revealed output contains a key that was not revealed:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "<3 bytes of binary data>") {
+		t.Errorf("binary values should be summarized:\n%s", out.String())
+	}
+}
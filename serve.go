@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"Kubernetes_Programming/pkg/logging"
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// defaultServeInterval is how often --serve recomputes the exported metrics
+const defaultServeInterval = 15 * time.Second
+
+// shutdownTimeout bounds how long in-flight scrapes may take after SIGTERM
+const shutdownTimeout = 5 * time.Second
+
+var (
+	podsDesc = prometheus.NewDesc("pods_total",
+		"Number of pods by namespace, phase and node (empty node for unscheduled pods).",
+		[]string{"namespace", "phase", "node"}, nil)
+	restartsDesc = prometheus.NewDesc("pod_restarts_total",
+		"Container restarts of each pod, summed over its containers.",
+		[]string{"namespace", "pod"}, nil)
+	unscheduledDesc = prometheus.NewDesc("pods_unscheduled",
+		"Number of pods not yet bound to a node.",
+		nil, nil)
+)
+
+// podGroupKey is one pods_total series
+type podGroupKey struct {
+	Namespace string
+	Phase     string
+	Node      string
+}
+
+// podRestarts is one pod_restarts_total series
+type podRestarts struct {
+	Namespace string
+	Pod       string
+	Restarts  int32
+}
+
+// podStats is the snapshot of pod metrics served between two refreshes
+type podStats struct {
+	Pods        map[podGroupKey]int
+	Restarts    []podRestarts
+	Unscheduled int
+}
+
+// computePodStats aggregates the filtered pods into the exported series
+func computePodStats(infos []podinfo.PodInfo) podStats {
+	stats := podStats{Pods: make(map[podGroupKey]int)}
+	for _, info := range infos {
+		phase := info.Phase
+		if phase == "" {
+			phase = "Unknown"
+		}
+		stats.Pods[podGroupKey{Namespace: info.Namespace, Phase: phase, Node: info.NodeName}]++
+		stats.Restarts = append(stats.Restarts, podRestarts{Namespace: info.Namespace, Pod: info.Name, Restarts: info.Restarts})
+		if info.NodeName == "" {
+			stats.Unscheduled++
+		}
+	}
+	return stats
+}
+
+// podStatsCollector is a prometheus.Collector exporting the latest podStats.
+// Scrapes read the snapshot set by the last refresh rather than the caches,
+// so every scrape sees one consistent set of series.
+type podStatsCollector struct {
+	mu    sync.RWMutex
+	stats *podStats
+}
+
+// update replaces the exported snapshot
+func (c *podStatsCollector) update(stats podStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = &stats
+}
+
+// ready reports whether a snapshot has been computed yet
+func (c *podStatsCollector) ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stats != nil
+}
+
+func (c *podStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- podsDesc
+	ch <- restartsDesc
+	ch <- unscheduledDesc
+}
+
+func (c *podStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.stats == nil {
+		return
+	}
+	for key, count := range c.stats.Pods {
+		ch <- prometheus.MustNewConstMetric(podsDesc, prometheus.GaugeValue, float64(count), key.Namespace, key.Phase, key.Node)
+	}
+	for _, r := range c.stats.Restarts {
+		ch <- prometheus.MustNewConstMetric(restartsDesc, prometheus.GaugeValue, float64(r.Restarts), r.Namespace, r.Pod)
+	}
+	ch <- prometheus.MustNewConstMetric(unscheduledDesc, prometheus.GaugeValue, float64(c.stats.Unscheduled))
+}
+
+// newServeMux returns the exporter's handlers: /metrics from registry and
+// /healthz, which fails until the first snapshot has been computed
+func newServeMux(registry *prometheus.Registry, collector *podStatsCollector) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !collector.ready() {
+			http.Error(w, "pod caches not synced", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// runServe exports pod metrics on addr until SIGTERM or Ctrl-C. The pods
+// come from informer caches constrained by listOpts and query's filters,
+// and the metrics are recomputed every interval.
+func runServe(parent context.Context, addr string, interval time.Duration, clusters []cluster, listOpts metav1.ListOptions, query podQuery) error {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger := logging.FromContext(ctx)
+
+	collector := &podStatsCollector{}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	server := &http.Server{Addr: addr, Handler: newServeMux(registry, collector), ReadHeaderTimeout: 10 * time.Second}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	logger.Info("serving pod metrics", "addr", addr, "interval", interval)
+
+	caches, warnings, err := startPodCaches(ctx, clusters, query.Namespaces, listOpts, query.Timeout)
+	logWarnings(ctx, warnings)
+	if err != nil && ctx.Err() == nil {
+		server.Close()
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		result, err := query.process(ctx, snapshotPods(caches), time.Now())
+		if err != nil {
+			server.Close()
+			return err
+		}
+		collector.update(computePodStats(result.Infos))
+
+		select {
+		case <-ctx.Done():
+		case err := <-serveErr:
+			return err
+		case <-ticker.C:
+		}
+	}
+
+	logger.Info("shutting down the metrics server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestComputePodStats(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "web", Name: "frontend-1", Phase: "Running", NodeName: "node-a", Restarts: 2},
+		{Namespace: "web", Name: "frontend-2", Phase: "Running", NodeName: "node-a"},
+		{Namespace: "web", Name: "frontend-3", Phase: "Pending"},
+		{Namespace: "batch", Name: "job", NodeName: "node-b", Restarts: 7},
+	}
+
+	stats := computePodStats(infos)
+	wantPods := map[podGroupKey]int{
+		{Namespace: "web", Phase: "Running", Node: "node-a"}:   2,
+		{Namespace: "web", Phase: "Pending"}:                   1,
+		{Namespace: "batch", Phase: "Unknown", Node: "node-b"}: 1,
+	}
+	if len(stats.Pods) != len(wantPods) {
+		t.Errorf("got %d pod series, want %d: %v", len(stats.Pods), len(wantPods), stats.Pods)
+	}
+	for key, want := range wantPods {
+		if got := stats.Pods[key]; got != want {
+			t.Errorf("pods%+v = %d, want %d", key, got, want)
+		}
+	}
+	if stats.Unscheduled != 1 {
+		t.Errorf("unscheduled = %d, want 1", stats.Unscheduled)
+	}
+	if len(stats.Restarts) != 4 || stats.Restarts[3] != (podRestarts{Namespace: "batch", Pod: "job", Restarts: 7}) {
+		t.Errorf("restarts = %+v", stats.Restarts)
+	}
+}
+
+// get fetches path from server and returns the status code and body
+func get(t *testing.T, server *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServeMux(t *testing.T) {
+	collector := &podStatsCollector{}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	server := httptest.NewServer(newServeMux(registry, collector))
+	defer server.Close()
+
+	if code, _ := get(t, server, "/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz before the first refresh = %d, want 503", code)
+	}
+
+	collector.update(computePodStats([]podinfo.PodInfo{
+		{Namespace: "web", Name: "frontend-1", Phase: "Running", NodeName: "node-a", Restarts: 3},
+		{Namespace: "web", Name: "frontend-2", Phase: "Pending"},
+	}))
+
+	if code, _ := get(t, server, "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", code)
+	}
+	code, body := get(t, server, "/metrics")
+	if code != http.StatusOK {
+		t.Fatalf("/metrics = %d, want 200", code)
+	}
+	for _, want := range []string{
+		`pods_total{namespace="web",node="node-a",phase="Running"} 1`,
+		`pods_total{namespace="web",node="",phase="Pending"} 1`,
+		`pod_restarts_total{namespace="web",pod="frontend-1"} 3`,
+		`pod_restarts_total{namespace="web",pod="frontend-2"} 0`,
+		`pods_unscheduled 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics is missing %q:\n%s", want, body)
+		}
+	}
+}
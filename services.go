@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ServiceInfo holds formatted service information
+type ServiceInfo struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Type        string            `json:"type"`
+	ClusterIP   string            `json:"clusterIP"`
+	ExternalIPs []string          `json:"externalIPs"`
+	Pending     bool              `json:"pending"`
+	Ports       []string          `json:"ports"`
+	Selector    map[string]string `json:"selector,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Age         time.Duration     `json:"-"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+// extractServiceInfo extracts relevant information from a service. External
+// addresses are the load balancer ingress points and spec.externalIPs, or
+// the DNS name for ExternalName services; a LoadBalancer without ingress
+// yet is marked pending.
+func extractServiceInfo(service *v1.Service, now time.Time) ServiceInfo {
+	serviceType := service.Spec.Type
+	if serviceType == "" {
+		serviceType = v1.ServiceTypeClusterIP
+	}
+
+	external := []string{}
+	switch serviceType {
+	case v1.ServiceTypeExternalName:
+		external = append(external, service.Spec.ExternalName)
+	case v1.ServiceTypeLoadBalancer:
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				external = append(external, ingress.IP)
+			} else if ingress.Hostname != "" {
+				external = append(external, ingress.Hostname)
+			}
+		}
+	}
+	external = append(external, service.Spec.ExternalIPs...)
+
+	ports := []string{}
+	for _, port := range service.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = v1.ProtocolTCP
+		}
+		if port.NodePort != 0 {
+			ports = append(ports, fmt.Sprintf("%d:%d/%s", port.Port, port.NodePort, protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, protocol))
+		}
+	}
+
+	return ServiceInfo{
+		Name:        service.Name,
+		Namespace:   service.Namespace,
+		Type:        string(serviceType),
+		ClusterIP:   service.Spec.ClusterIP,
+		ExternalIPs: external,
+		Pending:     serviceType == v1.ServiceTypeLoadBalancer && len(external) == 0,
+		Ports:       ports,
+		Selector:    service.Spec.Selector,
+		Labels:      service.Labels,
+		Age:         now.Sub(service.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt:   service.CreationTimestamp.Time,
+	}
+}
+
+// orNone returns s, or "<none>" for an empty table cell
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// clusterIPString returns the cluster IP column ("None" for headless services)
+func (info ServiceInfo) clusterIPString() string {
+	return orNone(info.ClusterIP)
+}
+
+// externalIPString returns the external IP column
+func (info ServiceInfo) externalIPString() string {
+	if info.Pending {
+		return "<pending>"
+	}
+	return orNone(strings.Join(info.ExternalIPs, ","))
+}
+
+// portsString returns the ports column, e.g. "80:30080/TCP,443/TCP"
+func (info ServiceInfo) portsString() string {
+	return orNone(strings.Join(info.Ports, ","))
+}
+
+// selectorString returns the selector column, e.g. "app=web"
+func (info ServiceInfo) selectorString() string {
+	return orNone(labels.Set(info.Selector).String())
+}
+
+// labelsString returns the labels column
+func (info ServiceInfo) labelsString() string {
+	return orNone(labels.Set(info.Labels).String())
+}
+
+// listServices lists services in namespace (all namespaces when empty)
+// matching selector and extracts their infos
+func listServices(ctx context.Context, client kubernetes.Interface, namespace, selector string, now time.Time) ([]ServiceInfo, error) {
+	services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, handleAPIError(err, "services", namespace)
+	}
+	infos := []ServiceInfo{}
+	for i := range services.Items {
+		infos = append(infos, extractServiceInfo(&services.Items[i], now))
+	}
+	return infos, nil
+}
+
+// printServiceTable prints one row per service. The NAMESPACE column is
+// shown when listing across namespaces, and LABELS with showLabels.
+func printServiceTable(out io.Writer, infos []ServiceInfo, showNamespace, showLabels bool, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprint(w, "NAME\tTYPE\tCLUSTER-IP\tEXTERNAL-IP\tPORTS\tSELECTOR\tAGE")
+	if showLabels {
+		fmt.Fprint(w, "\tLABELS")
+	}
+	fmt.Fprintln(w)
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s",
+			info.Name, info.Type, info.clusterIPString(), info.externalIPString(), info.portsString(),
+			info.selectorString(), formatAge(ageFormat, info.Age, info.CreatedAt))
+		if showLabels {
+			fmt.Fprintf(w, "\t%s", info.labelsString())
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
+
+// printServiceInfo prints formatted service information
+func printServiceInfo(out io.Writer, info ServiceInfo, showLabels bool, ageFormat string) {
+	fmt.Fprintf(out, "Service: %s\n", info.Name)
+	fmt.Fprintf(out, "  Namespace: %s\n", info.Namespace)
+	fmt.Fprintf(out, "  Type: %s\n", info.Type)
+	fmt.Fprintf(out, "  Cluster IP: %s\n", info.clusterIPString())
+	fmt.Fprintf(out, "  External IP: %s\n", info.externalIPString())
+	fmt.Fprintf(out, "  Ports: %s\n", info.portsString())
+	fmt.Fprintf(out, "  Selector: %s\n", info.selectorString())
+	if showLabels {
+		fmt.Fprintf(out, "  Labels: %s\n", info.labelsString())
+	}
+	fmt.Fprintf(out, "  Age: %s\n", formatAge(ageFormat, info.Age, info.CreatedAt))
+	fmt.Fprintln(out)
+}
+
+// runServices implements the services subcommand
+func runServices(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("services", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list services from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter services, e.g. app=web,tier!=cache")
+	showLabels := fs.Bool("show-labels", false, "show each service's labels in a LABELS column")
+	output := fs.String("output", outputTable, "output format: table, text, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table and text output show service age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputText, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, text, json or yaml)", *output)
+		}
+		if err := validateAgeFormat(*ageFormat); err != nil {
+			return err
+		}
+		if _, err := labels.Parse(*labelSelector); err != nil {
+			return fmt.Errorf("invalid --label-selector: %w", err)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err := listServices(ctx, client, *namespace, *labelSelector, time.Now())
+		if err != nil {
+			return fmt.Errorf("error listing services: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		switch *output {
+		case outputJSON, outputYAML:
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No services found")
+			return nil
+		}
+		if *output == outputText {
+			for _, info := range infos {
+				printServiceInfo(out, info, *showLabels, *ageFormat)
+			}
+			return nil
+		}
+		return printServiceTable(out, infos, *namespace == "", *showLabels, *ageFormat)
+	})
+}
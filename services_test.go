@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestService returns a service of the given type in the default namespace
+func newTestService(name string, serviceType v1.ServiceType, ports ...v1.ServicePort) *v1.Service {
+	return &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": name}},
+		Spec: v1.ServiceSpec{
+			Type:     serviceType,
+			Ports:    ports,
+			Selector: map[string]string{"app": name},
+		},
+	}
+}
+
+func TestListServices(t *testing.T) {
+	clusterIP := newTestService("api", v1.ServiceTypeClusterIP, v1.ServicePort{Port: 8080})
+	clusterIP.Spec.ClusterIP = "10.0.0.10"
+
+	headless := newTestService("db", v1.ServiceTypeClusterIP, v1.ServicePort{Port: 5432})
+	headless.Spec.ClusterIP = v1.ClusterIPNone
+
+	nodePort := newTestService("web", v1.ServiceTypeNodePort,
+		v1.ServicePort{Port: 80, NodePort: 30080},
+		v1.ServicePort{Port: 53, NodePort: 30053, Protocol: v1.ProtocolUDP})
+	nodePort.Spec.ClusterIP = "10.0.0.20"
+
+	ready := newTestService("ingress", v1.ServiceTypeLoadBalancer, v1.ServicePort{Port: 443, NodePort: 31443})
+	ready.Spec.ClusterIP = "10.0.0.30"
+	ready.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "203.0.113.7"}, {Hostname: "lb.example.com"}}
+
+	pending := newTestService("gateway", v1.ServiceTypeLoadBalancer, v1.ServicePort{Port: 443, NodePort: 32443})
+	pending.Spec.ClusterIP = "10.0.0.40"
+
+	externalName := newTestService("search", v1.ServiceTypeExternalName)
+	externalName.Spec.ExternalName = "search.example.com"
+	externalName.Spec.Selector = nil
+
+	client := fake.NewSimpleClientset(clusterIP, headless, nodePort, ready, pending, externalName)
+	infos, err := listServices(context.Background(), client, "default", "", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type row struct{ Type, ClusterIP, ExternalIP, Ports, Selector string }
+	want := map[string]row{
+		"api":     {"ClusterIP", "10.0.0.10", "<none>", "8080/TCP", "app=api"},
+		"db":      {"ClusterIP", "None", "<none>", "5432/TCP", "app=db"},
+		"web":     {"NodePort", "10.0.0.20", "<none>", "80:30080/TCP,53:30053/UDP", "app=web"},
+		"ingress": {"LoadBalancer", "10.0.0.30", "203.0.113.7,lb.example.com", "443:31443/TCP", "app=ingress"},
+		"gateway": {"LoadBalancer", "10.0.0.40", "<pending>", "443:32443/TCP", "app=gateway"},
+		"search":  {"ExternalName", "<none>", "search.example.com", "<none>", "<none>"},
+	}
+	got := map[string]row{}
+	for _, info := range infos {
+		got[info.Name] = row{info.Type, info.clusterIPString(), info.externalIPString(), info.portsString(), info.selectorString()}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("services =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestListServicesSelector(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newTestService("api", v1.ServiceTypeClusterIP),
+		newTestService("web", v1.ServiceTypeClusterIP),
+	)
+	infos, err := listServices(context.Background(), client, "", "app=web", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Name != "web" {
+		t.Fatalf("got %+v, want only web", infos)
+	}
+	if got := infos[0].labelsString(); got != "app=web" {
+		t.Errorf("labels = %q, want app=web", got)
+	}
+}
+
+func TestExtractServiceInfoExternalIPs(t *testing.T) {
+	now := time.Now()
+	service := newTestService("legacy", "", v1.ServicePort{Port: 80})
+	service.Spec.ExternalIPs = []string{"198.51.100.1"}
+	service.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Hour))
+
+	info := extractServiceInfo(service, now)
+	if info.Type != "ClusterIP" {
+		t.Errorf("type = %q, want ClusterIP by default", info.Type)
+	}
+	if got := info.externalIPString(); got != "198.51.100.1" {
+		t.Errorf("external IP = %q, want 198.51.100.1", got)
+	}
+	if got := formatAge(ageCompact, info.Age, info.CreatedAt); got != "5h" {
+		t.Errorf("age = %q, want 5h", got)
+	}
+}
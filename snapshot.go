@@ -0,0 +1,182 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// writeSnapshot saves infos to path for a later --diff, in the format of
+// --output json, so the output of an earlier run can be diffed too
+func writeSnapshot(path string, infos []podinfo.PodInfo) error {
+	if infos == nil {
+		infos = []podinfo.PodInfo{}
+	}
+	return replaceOutput(path, func(out io.Writer) error {
+		return printStructured(out, infos, outputJSON)
+	})
+}
+
+// readSnapshot loads the pods saved by --snapshot-out or --output json
+func readSnapshot(path string) ([]podinfo.PodInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading --diff snapshot: %w", err)
+	}
+	var infos []podinfo.PodInfo
+	if err := json.Unmarshal(data, &infos); err != nil {
+		return nil, fmt.Errorf("reading --diff snapshot %s: not a JSON list of pods: %w", path, err)
+	}
+	return infos, nil
+}
+
+// podDiffEntry is a pod of a snapshot diff, on its current node (its last
+// known one for removed pods)
+type podDiffEntry struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+	NodeName  string `json:"nodeName,omitempty"`
+}
+
+// podMove is a pod whose node changed
+type podMove struct {
+	podDiffEntry
+	OldNodeName string `json:"oldNodeName"`
+}
+
+// podRestart is a pod whose restart count increased by RestartDelta
+type podRestart struct {
+	podDiffEntry
+	OldRestarts  int32 `json:"oldRestarts"`
+	Restarts     int32 `json:"restarts"`
+	RestartDelta int32 `json:"restartDelta"`
+}
+
+// podDiff is what changed between a snapshot and the live pods
+type podDiff struct {
+	Added     []podDiffEntry `json:"added"`
+	Removed   []podDiffEntry `json:"removed"`
+	Moved     []podMove      `json:"moved"`
+	Restarted []podRestart   `json:"restarted"`
+}
+
+// empty reports whether nothing changed
+func (d podDiff) empty() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Moved)+len(d.Restarted) == 0
+}
+
+// snapshotKey identifies a pod across runs. The UID is part of it, so a pod
+// recreated under the same name is removed and added rather than
+// unchanged.
+func snapshotKey(info podinfo.PodInfo) string {
+	return podRef(info) + "/" + info.UID
+}
+
+// diffEntry returns the identity of info as a diff entry
+func diffEntry(info podinfo.PodInfo) podDiffEntry {
+	return podDiffEntry{Cluster: info.Cluster, Namespace: info.Namespace, Name: info.Name, UID: info.UID, NodeName: info.NodeName}
+}
+
+// diffSnapshots compares the pods of a snapshot with the current ones:
+// pods only in current are added, pods only in old removed, and pods in
+// both moved when their node changed and restarted when their restart count
+// went up. A pod bound to a node since the snapshot, having had none, has
+// not moved. Each list is sorted by cluster, namespace and name.
+func diffSnapshots(old, current []podinfo.PodInfo) podDiff {
+	diff := podDiff{Added: []podDiffEntry{}, Removed: []podDiffEntry{}, Moved: []podMove{}, Restarted: []podRestart{}}
+	previous := make(map[string]podinfo.PodInfo, len(old))
+	for _, info := range old {
+		previous[snapshotKey(info)] = info
+	}
+	seen := make(map[string]bool, len(current))
+	for _, info := range current {
+		key := snapshotKey(info)
+		seen[key] = true
+		before, ok := previous[key]
+		if !ok {
+			diff.Added = append(diff.Added, diffEntry(info))
+			continue
+		}
+		if before.NodeName != "" && before.NodeName != info.NodeName {
+			diff.Moved = append(diff.Moved, podMove{podDiffEntry: diffEntry(info), OldNodeName: before.NodeName})
+		}
+		if info.Restarts > before.Restarts {
+			diff.Restarted = append(diff.Restarted, podRestart{
+				podDiffEntry: diffEntry(info),
+				OldRestarts:  before.Restarts,
+				Restarts:     info.Restarts,
+				RestartDelta: info.Restarts - before.Restarts,
+			})
+		}
+	}
+	for _, info := range old {
+		if !seen[snapshotKey(info)] {
+			diff.Removed = append(diff.Removed, diffEntry(info))
+		}
+	}
+	sortDiffEntries(diff.Added, func(e podDiffEntry) podDiffEntry { return e })
+	sortDiffEntries(diff.Removed, func(e podDiffEntry) podDiffEntry { return e })
+	sortDiffEntries(diff.Moved, func(m podMove) podDiffEntry { return m.podDiffEntry })
+	sortDiffEntries(diff.Restarted, func(r podRestart) podDiffEntry { return r.podDiffEntry })
+	return diff
+}
+
+// sortDiffEntries sorts a list of the diff by cluster, namespace and name
+func sortDiffEntries[T any](entries []T, entry func(T) podDiffEntry) {
+	slices.SortFunc(entries, func(x, y T) int {
+		a, b := entry(x), entry(y)
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+}
+
+// diffRef names a diff entry as podRef does
+func diffRef(entry podDiffEntry) string {
+	return podRef(podinfo.PodInfo{Cluster: entry.Cluster, Namespace: entry.Namespace, Name: entry.Name})
+}
+
+// orUnscheduled returns node, or "<none>" for a pod not bound to a node
+func orUnscheduled(node string) string {
+	if node == "" {
+		return "<none>"
+	}
+	return node
+}
+
+// printPodDiff prints the diff grouped by kind of change
+func printPodDiff(out io.Writer, diff podDiff) {
+	if diff.empty() {
+		fmt.Fprintln(out, "No pods changed since the snapshot")
+		return
+	}
+	if len(diff.Added) > 0 {
+		fmt.Fprintf(out, "Added (%d):\n", len(diff.Added))
+		for _, e := range diff.Added {
+			fmt.Fprintf(out, "  %s on %s\n", diffRef(e), orUnscheduled(e.NodeName))
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Fprintf(out, "Removed (%d):\n", len(diff.Removed))
+		for _, e := range diff.Removed {
+			fmt.Fprintf(out, "  %s from %s\n", diffRef(e), orUnscheduled(e.NodeName))
+		}
+	}
+	if len(diff.Moved) > 0 {
+		fmt.Fprintf(out, "Moved (%d):\n", len(diff.Moved))
+		for _, m := range diff.Moved {
+			fmt.Fprintf(out, "  %s: %s -> %s\n", diffRef(m.podDiffEntry), m.OldNodeName, orUnscheduled(m.NodeName))
+		}
+	}
+	if len(diff.Restarted) > 0 {
+		fmt.Fprintf(out, "Restarted (%d):\n", len(diff.Restarted))
+		for _, r := range diff.Restarted {
+			fmt.Fprintf(out, "  %s: %d -> %d restarts (+%d)\n", diffRef(r.podDiffEntry), r.OldRestarts, r.Restarts, r.RestartDelta)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	pod := func(name, uid, node string, restarts int32) podinfo.PodInfo {
+		return podinfo.PodInfo{Namespace: "default", Name: name, UID: uid, NodeName: node, Restarts: restarts}
+	}
+	old := []podinfo.PodInfo{
+		pod("web-1", "u1", "node-a", 0),
+		pod("web-2", "u2", "node-a", 3),
+		pod("db-0", "u3", "node-b", 1),
+		pod("gone", "u4", "node-b", 0),
+		pod("pending", "u5", "", 0),
+	}
+	current := []podinfo.PodInfo{
+		pod("web-2", "u2", "node-c", 5),
+		pod("web-1", "u1", "node-a", 0),
+		// Recreated under the same name
+		pod("db-0", "u6", "node-b", 0),
+		pod("new", "u7", "", 0),
+		// Scheduled since the snapshot, which is not a move
+		pod("pending", "u5", "node-c", 0),
+	}
+
+	got := diffSnapshots(old, current)
+	want := podDiff{
+		Added: []podDiffEntry{
+			{Namespace: "default", Name: "db-0", UID: "u6", NodeName: "node-b"},
+			{Namespace: "default", Name: "new", UID: "u7"},
+		},
+		Removed: []podDiffEntry{
+			{Namespace: "default", Name: "db-0", UID: "u3", NodeName: "node-b"},
+			{Namespace: "default", Name: "gone", UID: "u4", NodeName: "node-b"},
+		},
+		Moved: []podMove{
+			{podDiffEntry: podDiffEntry{Namespace: "default", Name: "web-2", UID: "u2", NodeName: "node-c"}, OldNodeName: "node-a"},
+		},
+		Restarted: []podRestart{
+			{podDiffEntry: podDiffEntry{Namespace: "default", Name: "web-2", UID: "u2", NodeName: "node-c"}, OldRestarts: 3, Restarts: 5, RestartDelta: 2},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffSnapshots() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	var out bytes.Buffer
+	printPodDiff(&out, got)
+	wantText := `Added (2):
+  default/db-0 on node-b
+  default/new on <none>
+Removed (2):
+  default/db-0 from node-b
+  default/gone from node-b
+Moved (1):
+  default/web-2: node-a -> node-c
+Restarted (1):
+  default/web-2: 3 -> 5 restarts (+2)
+`
+	if out.String() != wantText {
+		t.Errorf("printPodDiff() =\n%s\nwant\n%s", out.String(), wantText)
+	}
+
+	out.Reset()
+	printPodDiff(&out, diffSnapshots(old, old))
+	if out.String() != "No pods changed since the snapshot\n" {
+		t.Errorf("printPodDiff() without changes = %q", out.String())
+	}
+}
+
+func TestPodDiffJSON(t *testing.T) {
+	diff := diffSnapshots(nil, nil)
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"added":[],"removed":[],"moved":[],"restarted":[]}`; string(data) != want {
+		t.Errorf("empty diff = %s, want %s", data, want)
+	}
+
+	restart := podRestart{podDiffEntry: podDiffEntry{Namespace: "default", Name: "web"}, OldRestarts: 0, Restarts: 2, RestartDelta: 2}
+	data, err = json.Marshal(restart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"namespace":"default","name":"web","oldRestarts":0,"restarts":2,"restartDelta":2}`; string(data) != want {
+		t.Errorf("restart = %s, want %s", data, want)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pods.json")
+	infos := []podinfo.PodInfo{{Cluster: "prod", Namespace: "default", Name: "web-1", UID: "u1", NodeName: "node-a", Restarts: 2}}
+	if err := writeSnapshot(path, infos); err != nil {
+		t.Fatalf("writeSnapshot() error = %v", err)
+	}
+	got, err := readSnapshot(path)
+	if err != nil {
+		t.Fatalf("readSnapshot() error = %v", err)
+	}
+	if diff := diffSnapshots(got, infos); !diff.empty() {
+		t.Errorf("diff against the round-tripped snapshot = %+v, want none", diff)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"pods": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSnapshot(path); err == nil {
+		t.Error("readSnapshot() of a JSON object succeeded, want an error")
+	}
+	if _, err := readSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("readSnapshot() of a missing file succeeded, want an error")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StatefulSetPod is one pod of a StatefulSet
+type StatefulSetPod struct {
+	Name string `json:"name"`
+	// Ordinal is the index from the pod name suffix, or -1 for a pod the
+	// selector matches whose name has none
+	Ordinal int    `json:"ordinal"`
+	Phase   string `json:"phase"`
+}
+
+// StatefulSetInfo holds formatted StatefulSet information
+type StatefulSetInfo struct {
+	Name            string           `json:"name"`
+	Namespace       string           `json:"namespace"`
+	DesiredReplicas int32            `json:"desiredReplicas"`
+	ReadyReplicas   int32            `json:"readyReplicas"`
+	CurrentReplicas int32            `json:"currentReplicas"`
+	UpdatedReplicas int32            `json:"updatedReplicas"`
+	ServiceName     string           `json:"serviceName"`
+	Pods            []StatefulSetPod `json:"pods,omitempty"`
+	Age             time.Duration    `json:"-"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
+
+// podOrdinal extracts the ordinal of a pod of the StatefulSet setName from
+// its name, setName-N. ok is false for a name without that form.
+func podOrdinal(setName, podName string) (ordinal int, ok bool) {
+	suffix, found := strings.CutPrefix(podName, setName+"-")
+	if !found {
+		return -1, false
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil || n < 0 || strconv.Itoa(n) != suffix {
+		return -1, false
+	}
+	return n, true
+}
+
+// extractStatefulSetInfo extracts relevant information from a StatefulSet
+// and its pods, sorted by ordinal. Pods without an ordinal come last,
+// sorted by name.
+func extractStatefulSetInfo(sts *appsv1.StatefulSet, pods []v1.Pod, now time.Time) StatefulSetInfo {
+	// An unset replica count defaults to 1, as in the API server
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	setPods := []StatefulSetPod{}
+	for _, pod := range pods {
+		ordinal, _ := podOrdinal(sts.Name, pod.Name)
+		phase := string(pod.Status.Phase)
+		if phase == "" {
+			phase = "<none>"
+		}
+		setPods = append(setPods, StatefulSetPod{Name: pod.Name, Ordinal: ordinal, Phase: phase})
+	}
+	sort.SliceStable(setPods, func(i, j int) bool {
+		a, b := setPods[i], setPods[j]
+		if (a.Ordinal < 0) != (b.Ordinal < 0) {
+			return b.Ordinal < 0
+		}
+		if a.Ordinal != b.Ordinal {
+			return a.Ordinal < b.Ordinal
+		}
+		return a.Name < b.Name
+	})
+	return StatefulSetInfo{
+		Name:            sts.Name,
+		Namespace:       sts.Namespace,
+		DesiredReplicas: desired,
+		ReadyReplicas:   sts.Status.ReadyReplicas,
+		CurrentReplicas: sts.Status.CurrentReplicas,
+		UpdatedReplicas: sts.Status.UpdatedReplicas,
+		ServiceName:     sts.Spec.ServiceName,
+		Pods:            setPods,
+		Age:             now.Sub(sts.CreationTimestamp.Time).Truncate(time.Second),
+		CreatedAt:       sts.CreationTimestamp.Time,
+	}
+}
+
+// listStatefulSets lists the StatefulSets in namespace matching selector
+// and, with withPods, fetches the pods each one's spec.selector matches
+func listStatefulSets(ctx context.Context, client kubernetes.Interface, namespace, selector string, withPods bool, now time.Time) ([]StatefulSetInfo, error) {
+	sets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, handleAPIError(err, "statefulsets", namespace)
+	}
+	infos := []StatefulSetInfo{}
+	for i := range sets.Items {
+		sts := &sets.Items[i]
+		var pods []v1.Pod
+		if withPods && sts.Spec.Selector != nil {
+			podSelector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("statefulset %s/%s: invalid selector: %w", sts.Namespace, sts.Name, err)
+			}
+			list, err := client.CoreV1().Pods(sts.Namespace).List(ctx, metav1.ListOptions{LabelSelector: podSelector.String()})
+			if err != nil {
+				return nil, handleAPIError(err, "pods", sts.Namespace)
+			}
+			pods = list.Items
+		}
+		info := extractStatefulSetInfo(sts, pods, now)
+		if !withPods {
+			info.Pods = nil
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// printStatefulSetTable prints one row per StatefulSet, followed with
+// showPods by an indented row per pod with its phase. The NAMESPACE column
+// is shown when listing across namespaces.
+func printStatefulSetTable(out io.Writer, infos []StatefulSetInfo, showNamespace, showPods bool, ageFormat string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if showNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tREADY\tCURRENT\tUPDATED\tDESIRED\tSERVICE-NAME\tAGE")
+	for _, info := range infos {
+		if showNamespace {
+			fmt.Fprintf(w, "%s\t", info.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%d\t%d\t%s\t%s\n",
+			info.Name, info.ReadyReplicas, info.DesiredReplicas, info.CurrentReplicas, info.UpdatedReplicas,
+			info.DesiredReplicas, info.ServiceName, formatAge(ageFormat, info.Age, info.CreatedAt))
+		if !showPods {
+			continue
+		}
+		for _, pod := range info.Pods {
+			if showNamespace {
+				fmt.Fprint(w, "\t")
+			}
+			fmt.Fprintf(w, "  %s\t%s\t\t\t\t\t\n", pod.Name, pod.Phase)
+		}
+	}
+	return w.Flush()
+}
+
+// runStatefulSets implements the statefulsets subcommand
+func runStatefulSets(clientOpts *clientOptions, args []string) error {
+	fs := flag.NewFlagSet("statefulsets", flag.ExitOnError)
+	clientOpts.AddFlags(fs)
+	fs.BoolVar(&clientOpts.Verbose, "verbose", false, "print the resolved context and API server at startup")
+	clientOpts.AddTimeoutFlag(fs)
+	namespace := fs.String("namespace", "", "namespace to list statefulsets from (empty for all namespaces)")
+	labelSelector := fs.String("label-selector", "", "label selector to filter statefulsets, e.g. app=db,tier!=cache")
+	showPods := fs.Bool("show-pods", false, "also list the pods of each statefulset in ordinal order with their phases")
+	output := fs.String("output", outputTable, "output format: table, json or yaml")
+	outputFile := addOutputFileFlag(fs)
+	ageFormat := fs.String("age-format", ageCompact, "how table output shows statefulset age: compact (like kubectl), full or absolute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := clientOpts.setupLogger(*output); err != nil {
+		return err
+	}
+	return replaceOutput(*outputFile, func(out io.Writer) error {
+		switch *output {
+		case outputTable, outputJSON, outputYAML:
+		default:
+			return fmt.Errorf("unknown --output format %q (want table, json or yaml)", *output)
+		}
+		if err := validateAgeFormat(*ageFormat); err != nil {
+			return err
+		}
+		if _, err := labels.Parse(*labelSelector); err != nil {
+			return fmt.Errorf("invalid --label-selector: %w", err)
+		}
+
+		client, _, err := createKubernetesClient(*clientOpts)
+		if err != nil {
+			return fmt.Errorf("error creating Kubernetes client: %w", err)
+		}
+
+		ctx, cancel := requestContext(context.Background(), clientOpts.Timeout)
+		defer cancel()
+
+		infos, err := listStatefulSets(ctx, client, *namespace, *labelSelector, *showPods, time.Now())
+		if err != nil {
+			return fmt.Errorf("error listing statefulsets: %w", timeoutError(err, clientOpts.Timeout))
+		}
+		if *output != outputTable {
+			return printStructured(out, infos, *output)
+		}
+		if len(infos) == 0 {
+			fmt.Fprintln(out, "No statefulsets found")
+			return nil
+		}
+		return printStatefulSetTable(out, infos, *namespace == "", *showPods, *ageFormat)
+	})
+}
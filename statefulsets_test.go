@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestStatefulSet returns a StatefulSet in the default namespace
+// selecting app=name
+func newTestStatefulSet(name string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: name + "-headless",
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: replicas - 1, CurrentReplicas: replicas, UpdatedReplicas: replicas - 1},
+	}
+}
+
+// newStatefulSetPod returns a pod labelled app=app in phase
+func newStatefulSetPod(app, name string, phase v1.PodPhase) *v1.Pod {
+	pod := newTestPod("default", name)
+	pod.Labels = map[string]string{"app": app}
+	pod.Status.Phase = phase
+	return pod
+}
+
+func TestPodOrdinal(t *testing.T) {
+	tests := []struct {
+		podName string
+		want    int
+		wantOK  bool
+	}{
+		{podName: "web-0", want: 0, wantOK: true},
+		{podName: "web-12", want: 12, wantOK: true},
+		{podName: "web-01", want: -1},
+		{podName: "web-abc", want: -1},
+		{podName: "web-", want: -1},
+		{podName: "webapp-1", want: -1},
+		{podName: "web-db-1", want: -1},
+	}
+	for _, tt := range tests {
+		got, ok := podOrdinal("web", tt.podName)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("podOrdinal(web, %q) = %d, %t, want %d, %t", tt.podName, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestListStatefulSetsSortsPodsByOrdinal(t *testing.T) {
+	// The fake client returns pods sorted by name, which puts web-10 before
+	// web-2
+	client := fake.NewSimpleClientset(
+		newTestStatefulSet("web", 4),
+		newStatefulSetPod("web", "web-10", v1.PodPending),
+		newStatefulSetPod("web", "web-2", v1.PodRunning),
+		newStatefulSetPod("web", "web-0", v1.PodRunning),
+		newStatefulSetPod("web", "web-1", v1.PodFailed),
+		newStatefulSetPod("web", "web-canary", v1.PodRunning),
+		newStatefulSetPod("db", "db-0", v1.PodRunning),
+	)
+
+	infos, err := listStatefulSets(context.Background(), client, "default", "", true, time.Now())
+	if err != nil {
+		t.Fatalf("listStatefulSets() error: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("listStatefulSets() = %d statefulsets, want 1", len(infos))
+	}
+	want := []StatefulSetPod{
+		{Name: "web-0", Ordinal: 0, Phase: "Running"},
+		{Name: "web-1", Ordinal: 1, Phase: "Failed"},
+		{Name: "web-2", Ordinal: 2, Phase: "Running"},
+		{Name: "web-10", Ordinal: 10, Phase: "Pending"},
+		{Name: "web-canary", Ordinal: -1, Phase: "Running"},
+	}
+	if !reflect.DeepEqual(infos[0].Pods, want) {
+		t.Errorf("pods = %+v, want %+v", infos[0].Pods, want)
+	}
+
+	// Without --show-pods the pods are neither fetched nor reported
+	client.ClearActions()
+	infos, err = listStatefulSets(context.Background(), client, "default", "", false, time.Now())
+	if err != nil {
+		t.Fatalf("listStatefulSets() error: %v", err)
+	}
+	if infos[0].Pods != nil {
+		t.Errorf("pods without withPods = %+v, want none", infos[0].Pods)
+	}
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource == "pods" {
+			t.Errorf("listStatefulSets() without withPods listed pods")
+		}
+	}
+}
+
+func TestPrintStatefulSetTable(t *testing.T) {
+	now := time.Now()
+	sts := newTestStatefulSet("web", 3)
+	sts.CreationTimestamp = metav1.NewTime(now.Add(-2 * time.Hour))
+	pods := []v1.Pod{
+		*newStatefulSetPod("web", "web-1", v1.PodPending),
+		*newStatefulSetPod("web", "web-0", v1.PodRunning),
+	}
+	info := extractStatefulSetInfo(sts, pods, now)
+
+	var out bytes.Buffer
+	if err := printStatefulSetTable(&out, []StatefulSetInfo{info}, false, true, ageCompact); err != nil {
+		t.Fatalf("printStatefulSetTable() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header, 1 statefulset and 2 pods:\n%s", len(lines), out.String())
+	}
+	if got := strings.Fields(lines[1]); !reflect.DeepEqual(got, []string{"web", "2/3", "3", "2", "3", "web-headless", "120m"}) {
+		t.Errorf("row = %q", lines[1])
+	}
+	if got := strings.Fields(lines[2]); !reflect.DeepEqual(got, []string{"web-0", "Running"}) {
+		t.Errorf("first pod row = %q, want web-0 Running", lines[2])
+	}
+	if got := strings.Fields(lines[3]); !reflect.DeepEqual(got, []string{"web-1", "Pending"}) {
+		t.Errorf("second pod row = %q, want web-1 Pending", lines[3])
+	}
+}
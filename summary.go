@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// countEntry is the number of pods in one phase, namespace or node
+type countEntry struct {
+	Name string `json:"name"`
+	Pods int    `json:"pods"`
+}
+
+// podSummary describes the shape of the listed pods without listing them
+type podSummary struct {
+	Total       int          `json:"total"`
+	Restarts    int32        `json:"restarts"`
+	Unscheduled int          `json:"unscheduled"`
+	ByPhase     []countEntry `json:"byPhase"`
+	ByQOSClass  []countEntry `json:"byQOSClass"`
+	ByNamespace []countEntry `json:"byNamespace"`
+	ByNode      []countEntry `json:"byNode"`
+}
+
+// summarize counts pods by phase, QoS class, namespace and node. Entries are sorted by
+// count, most pods first; top limits the namespaces to the N largest when
+// positive.
+func summarize(infos []podinfo.PodInfo, top int) podSummary {
+	phases := make(map[string]int)
+	qosClasses := make(map[string]int)
+	namespaces := make(map[string]int)
+	nodes := make(map[string]int)
+	summary := podSummary{Total: len(infos)}
+	for _, info := range infos {
+		phase := info.Phase
+		if phase == "" {
+			phase = "Unknown"
+		}
+		phases[phase]++
+		qosClasses[info.QOSClass]++
+		namespaces[info.Namespace]++
+		if info.NodeName == "" {
+			summary.Unscheduled++
+		} else {
+			nodes[info.NodeName]++
+		}
+		summary.Restarts += info.Restarts
+	}
+
+	summary.ByPhase = sortedCounts(phases)
+	summary.ByQOSClass = sortedCounts(qosClasses)
+	summary.ByNamespace = sortedCounts(namespaces)
+	if top > 0 && len(summary.ByNamespace) > top {
+		summary.ByNamespace = summary.ByNamespace[:top]
+	}
+	summary.ByNode = sortedCounts(nodes)
+	return summary
+}
+
+// sortedCounts returns counts sorted by descending count, then name
+func sortedCounts(counts map[string]int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for name, pods := range counts {
+		entries = append(entries, countEntry{Name: name, Pods: pods})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Pods != entries[j].Pods {
+			return entries[i].Pods > entries[j].Pods
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// print writes the summary as a set of tables
+func (s podSummary) print(out io.Writer) error {
+	fmt.Fprintf(out, "Total: %d pods, %d restarts, %d unscheduled\n", s.Total, s.Restarts, s.Unscheduled)
+	for _, table := range []struct {
+		header  string
+		entries []countEntry
+	}{
+		{"PHASE", s.ByPhase},
+		{"QOS", s.ByQOSClass},
+		{"NAMESPACE", s.ByNamespace},
+		{"NODE", s.ByNode},
+	} {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		fmt.Fprintf(w, "%s\tPODS\n", table.header)
+		for _, e := range table.entries {
+			fmt.Fprintf(w, "%s\t%d\n", e.Name, e.Pods)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
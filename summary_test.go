@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSummarize(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "web", NodeName: "node-1", Phase: "Running", QOSClass: "Burstable", Restarts: 2},
+		{Namespace: "web", NodeName: "node-1", Phase: "Running", QOSClass: "BestEffort"},
+		{Namespace: "web", NodeName: "node-2", Phase: "Failed", QOSClass: "BestEffort", Restarts: 5},
+		{Namespace: "batch", NodeName: "node-2", Phase: "Succeeded", QOSClass: "Burstable"},
+		{Namespace: "batch", Phase: "Pending", QOSClass: "BestEffort"},
+		{Namespace: "monitoring", NodeName: "node-1", Phase: "Running", QOSClass: "Guaranteed"},
+	}
+
+	got := summarize(infos, 2)
+	want := podSummary{
+		Total:       6,
+		Restarts:    7,
+		Unscheduled: 1,
+		ByPhase: []countEntry{
+			{Name: "Running", Pods: 3},
+			{Name: "Failed", Pods: 1},
+			{Name: "Pending", Pods: 1},
+			{Name: "Succeeded", Pods: 1},
+		},
+		ByQOSClass: []countEntry{
+			{Name: "BestEffort", Pods: 3},
+			{Name: "Burstable", Pods: 2},
+			{Name: "Guaranteed", Pods: 1},
+		},
+		ByNamespace: []countEntry{{Name: "web", Pods: 3}, {Name: "batch", Pods: 2}},
+		ByNode:      []countEntry{{Name: "node-1", Pods: 3}, {Name: "node-2", Pods: 2}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("summarize() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if all := summarize(infos, 0); len(all.ByNamespace) != 3 {
+		t.Errorf("--top 0 kept %d namespaces, want all 3", len(all.ByNamespace))
+	}
+}
+
+func TestListPodsSelector(t *testing.T) {
+	web := newTestPod("default", "web")
+	web.Labels = map[string]string{"app": "web"}
+	db := newTestPod("default", "db")
+	db.Labels = map[string]string{"app": "db"}
+	client := fake.NewSimpleClientset(web, db)
+
+	pods, _, err := listPods(context.Background(), client, []string{"default"}, metav1.ListOptions{LabelSelector: "app=web"}, podinfo.DefaultConcurrency)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 1 || pods[0].Name != "web" {
+		t.Errorf("selector app=web listed %d pods", len(pods))
+	}
+	if summary := summarize([]podinfo.PodInfo{podinfo.Extract(&pods[0], web.CreationTimestamp.Time)}, 0); summary.Total != 1 {
+		t.Errorf("summary of the filtered pods counted %d pods, want 1", summary.Total)
+	}
+}
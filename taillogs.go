@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// defaultLogBytes is the default --log-bytes cap on the log printed per
+// container
+const defaultLogBytes = 4096
+
+// logTailOptions holds the flags of --tail-logs
+type logTailOptions struct {
+	// Lines is how many lines to print from the end of each log
+	Lines int64
+	// MaxBytes caps the log printed per container, so one noisy pod cannot
+	// flood the terminal
+	MaxBytes int64
+}
+
+// validate checks the flag values
+func (o logTailOptions) validate() error {
+	if o.Lines < 0 {
+		return fmt.Errorf("--tail-logs must not be negative, got %d", o.Lines)
+	}
+	if o.MaxBytes <= 0 {
+		return fmt.Errorf("--log-bytes must be positive, got %d", o.MaxBytes)
+	}
+	return nil
+}
+
+// podLogOptions returns the API options for the tail of container's log
+func (o logTailOptions) podLogOptions(container podinfo.FailingContainer) *v1.PodLogOptions {
+	lines := o.Lines
+	return &v1.PodLogOptions{
+		Container: container.Name,
+		Previous:  container.Previous,
+		TailLines: &lines,
+	}
+}
+
+// fetchLogTail returns the tail of a container's log, cut to its last
+// MaxBytes, and how many bytes were cut. The cut drops the partial line it
+// lands in; the end of the log, where a crash shows, is always kept.
+func fetchLogTail(ctx context.Context, client kubernetes.Interface, namespace, pod string, container podinfo.FailingContainer, opts logTailOptions) ([]byte, int, error) {
+	stream, err := client.CoreV1().Pods(namespace).GetLogs(pod, opts.podLogOptions(container)).Stream(ctx)
+	if err != nil {
+		return nil, 0, handleAPIError(err, "pods/log", namespace)
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(len(data)) <= opts.MaxBytes {
+		return data, 0, nil
+	}
+	cut := len(data) - int(opts.MaxBytes)
+	if i := bytes.IndexByte(data[cut:len(data)-1], '\n'); i >= 0 {
+		cut += i + 1
+	}
+	return data[cut:], cut, nil
+}
+
+// logHeader delimits the log of one container, e.g.
+// "==> default/web-1 container app (previous run) <=="
+func logHeader(ref string, container podinfo.FailingContainer) string {
+	header := fmt.Sprintf("==> %s container %s", ref, container.Name)
+	if container.Previous {
+		header += " (previous run)"
+	}
+	return header + " <=="
+}
+
+// tailFailingLogs prints the tail of the log of each failing container of
+// the pods of infos, in display order. A log that cannot be read (e.g.
+// pods/log is forbidden, or the container was never started) or is empty
+// gets a one-line note instead of failing the run.
+func tailFailingLogs(ctx context.Context, out io.Writer, clusters []cluster, infos []podinfo.PodInfo, failing map[string][]podinfo.FailingContainer, opts logTailOptions) {
+	clients := make(map[string]kubernetes.Interface, len(clusters))
+	for _, c := range clusters {
+		clients[c.Name] = c.Client
+	}
+	for _, info := range infos {
+		ref := podRef(info)
+		for _, container := range failing[ref] {
+			fmt.Fprintln(out, logHeader(ref, container))
+			data, cut, err := fetchLogTail(ctx, clients[info.Cluster], info.Namespace, info.Name, container, opts)
+			switch {
+			case err != nil:
+				fmt.Fprintf(out, "(logs unavailable: %s)\n", strings.TrimSpace(err.Error()))
+			case len(data) == 0:
+				fmt.Fprintln(out, "(no logs)")
+			default:
+				if cut > 0 {
+					fmt.Fprintf(out, "(%d earlier bytes cut by --log-bytes %d)\n", cut, opts.MaxBytes)
+				}
+				out.Write(data)
+				if !bytes.HasSuffix(data, []byte("\n")) {
+					fmt.Fprintln(out)
+				}
+			}
+			fmt.Fprintln(out)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// startLogServer serves the logs of pods by "namespace/pod/container", with
+// a 403 for the pods of namespace locked. Each request's query is recorded.
+func startLogServer(t *testing.T, logs map[string]string) (kubernetes.Interface, *[]string) {
+	t.Helper()
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /api/v1/namespaces/{namespace}/pods/{pod}/log
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 7 || parts[6] != "log" {
+			http.NotFound(w, r)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		if parts[3] == "locked" {
+			status := apierrors.NewForbidden(schema.GroupResource{Resource: "pods/log"}, parts[5],
+				errors.New(`User "alice" cannot get resource "pods/log" in API group "" in the namespace "locked"`)).ErrStatus
+			status.APIVersion, status.Kind = "v1", "Status"
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(status)
+			return
+		}
+		w.Write([]byte(logs[parts[3]+"/"+parts[5]+"/"+r.URL.Query().Get("container")]))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	return client, &queries
+}
+
+func TestLogTailOptions(t *testing.T) {
+	for _, opts := range []logTailOptions{{Lines: -1, MaxBytes: 10}, {Lines: 10, MaxBytes: 0}} {
+		if err := opts.validate(); err == nil {
+			t.Errorf("validate(%+v) succeeded, want an error", opts)
+		}
+	}
+	got := logTailOptions{Lines: 20, MaxBytes: 100}.podLogOptions(podinfo.FailingContainer{Name: "app", Previous: true})
+	if got.Container != "app" || !got.Previous || got.TailLines == nil || *got.TailLines != 20 {
+		t.Errorf("podLogOptions() = %+v, want the last 20 lines of the previous run of app", got)
+	}
+}
+
+func TestTailFailingLogs(t *testing.T) {
+	client, queries := startLogServer(t, map[string]string{
+		"default/web-1/app":   "starting\npanic: nil map\n",
+		"default/web-1/proxy": "",
+		"default/noisy/app":   "line 1\nline 2\nline 3\nthe end",
+	})
+	infos := podInfos("default/web-1", "default/healthy", "locked/db-1", "default/noisy")
+	failing := map[string][]podinfo.FailingContainer{
+		"default/web-1": {{Name: "app", Previous: true}, {Name: "proxy"}},
+		"locked/db-1":   {{Name: "db"}},
+		"default/noisy": {{Name: "app"}},
+	}
+
+	var out bytes.Buffer
+	tailFailingLogs(context.Background(), &out, []cluster{{Client: client}}, infos, failing, logTailOptions{Lines: 20, MaxBytes: 24})
+	want := `==> default/web-1 container app (previous run) <==
+starting
+panic: nil map
+
+==> default/web-1 container proxy <==
+(no logs)
+
+==> locked/db-1 container db <==
+(logs unavailable: forbidden: requires 'get pods/log' in namespace 'locked', which user "alice" does not have)
+
+==> default/noisy container app <==
+(7 earlier bytes cut by --log-bytes 24)
+line 2
+line 3
+the end
+
+`
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+	if len(*queries) != 4 || (*queries)[0] != "container=app&previous=true&tailLines=20" {
+		t.Errorf("queries = %q, want 4 requests, the first for the previous run of app", *queries)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// namedTemplates are the --output-template values that stand for a
+// predefined template over a PodInfo
+var namedTemplates = map[string]string{
+	"minimal": `{{.Namespace}}/{{.Name}}\t{{.Phase}}`,
+	"wide":    `{{.Namespace}}\t{{.Name}}\t{{.ReadyString}}\t{{.Reason}}\t{{.Restarts}}\t{{or .NodeName "<none>"}}\t{{or .PodIP "<none>"}}\t{{.QOSClass}}\t{{.Owner}}`,
+}
+
+// templateColors are the colors the colorize template function accepts
+var templateColors = map[string]string{
+	"red":    ansiRed,
+	"green":  ansiGreen,
+	"yellow": ansiYellow,
+	"dim":    ansiDim,
+}
+
+// templateEscapes turns the \t and \n a template typed in a shell holds
+// into the tab and newline they stand for
+var templateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+// templateFuncs are the functions available to output templates besides
+// the text/template builtins. The last argument of each is the value, so
+// they work at the end of a pipeline: {{.Name | truncate 20}}.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// truncate shortens s to n characters, ending it with "..." when cut
+	"truncate": func(n int, s string) (string, error) {
+		if n < 0 {
+			return "", fmt.Errorf("truncate: negative length %d", n)
+		}
+		runes := []rune(s)
+		if len(runes) <= n {
+			return s, nil
+		}
+		if n <= 3 {
+			return string(runes[:n]), nil
+		}
+		return string(runes[:n-3]) + "...", nil
+	},
+	// colorize wraps s in the ANSI escape sequences of a color: red,
+	// green, yellow or dim
+	"colorize": func(color, s string) (string, error) {
+		code, ok := templateColors[color]
+		if !ok {
+			return "", fmt.Errorf("colorize: unknown color %q (want red, green, yellow or dim)", color)
+		}
+		return colorize(s, code), nil
+	},
+}
+
+// parseOutputTemplate parses an --output-template value: the name of a
+// predefined template or a text/template string, in which \t and \n stand
+// for a tab and a newline
+func parseOutputTemplate(text string) (*template.Template, error) {
+	if named, ok := namedTemplates[text]; ok {
+		text = named
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(templateEscapes.Replace(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderTemplate executes the template tmpl, which may name a predefined
+// template such as minimal or wide, against data and returns the output
+func RenderTemplate(tmpl string, data interface{}) (string, error) {
+	t, err := parseOutputTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// printTemplate executes tmpl once per item, one line each, aligning the
+// tab-separated cells of the lines into columns
+func printTemplate[T any](out io.Writer, tmpl *template.Template, items []T) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	var line bytes.Buffer
+	for _, item := range items {
+		line.Reset()
+		if err := tmpl.Execute(&line, item); err != nil {
+			return fmt.Errorf("error executing --output-template: %w", err)
+		}
+		if !bytes.HasSuffix(line.Bytes(), []byte("\n")) {
+			line.WriteByte('\n')
+		}
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	info := podinfo.PodInfo{Namespace: "web", Name: "frontend-7d9f", Phase: "Running", ReadyContainers: 1, TotalContainers: 2}
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{tmpl: `{{.Name}}\t{{.Phase}}`, want: "frontend-7d9f\tRunning"},
+		{tmpl: `{{.Name | upper}} {{lower .Phase}}`, want: "FRONTEND-7D9F running"},
+		{tmpl: `{{.Name | truncate 8}}`, want: "front..."},
+		{tmpl: `{{truncate 20 .Name}}`, want: "frontend-7d9f"},
+		{tmpl: `{{truncate 2 .Name}}`, want: "fr"},
+		{tmpl: `{{.Phase | colorize "green"}}`, want: ansiGreen + "Running" + ansiReset},
+		{tmpl: `{{.ReadyString}}`, want: "1/2"},
+		{tmpl: "minimal", want: "web/frontend-7d9f\tRunning"},
+	}
+	for _, tt := range tests {
+		got, err := RenderTemplate(tt.tmpl, info)
+		if err != nil {
+			t.Errorf("RenderTemplate(%q) error: %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("RenderTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	info := podinfo.PodInfo{Name: "frontend"}
+	tests := []struct {
+		name    string
+		tmpl    string
+		wantErr string
+	}{
+		{name: "unclosed action", tmpl: `{{.Name`, wantErr: "invalid --output-template"},
+		{name: "unknown function", tmpl: `{{.Name | shout}}`, wantErr: `function "shout" not defined`},
+		{name: "unknown field", tmpl: `{{.Nmae}}`, wantErr: "can't evaluate field Nmae"},
+		{name: "unknown color", tmpl: `{{colorize "purple" .Name}}`, wantErr: `unknown color "purple"`},
+		{name: "negative length", tmpl: `{{truncate -1 .Name}}`, wantErr: "negative length"},
+		{name: "wrong argument type", tmpl: `{{truncate "five" .Name}}`, wantErr: "expected integer"},
+	}
+	for _, tt := range tests {
+		_, err := RenderTemplate(tt.tmpl, info)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: RenderTemplate(%q) error = %v, want one containing %q", tt.name, tt.tmpl, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPrintTemplate(t *testing.T) {
+	tmpl, err := parseOutputTemplate(`{{.Name}}\t{{.Phase}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos := []podinfo.PodInfo{
+		{Name: "a", Phase: "Running"},
+		{Name: "frontend-7d9f", Phase: "Pending"},
+	}
+	var out bytes.Buffer
+	if err := printTemplate(&out, tmpl, infos); err != nil {
+		t.Fatalf("printTemplate() error: %v", err)
+	}
+	want := "a               Running\nfrontend-7d9f   Pending\n"
+	if out.String() != want {
+		t.Errorf("printTemplate() =\n%q\nwant\n%q", out.String(), want)
+	}
+
+	// Templates work on the other *Info structs too
+	deployments := []DeploymentInfo{{Name: "web", DesiredReplicas: 3}}
+	tmpl, err = parseOutputTemplate(`{{.Name}}={{.DesiredReplicas}}\n`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := printTemplate(&out, tmpl, deployments); err != nil {
+		t.Fatalf("printTemplate() error: %v", err)
+	}
+	if out.String() != "web=3\n" {
+		t.Errorf("printTemplate(deployments) = %q, want %q", out.String(), "web=3\n")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// fetchPodUsage queries metrics.k8s.io for the usage of pods in namespaces
+// (all namespaces when empty), summed over containers and keyed by
+// namespace/name
+func fetchPodUsage(ctx context.Context, client metricsclient.PodMetricsesGetter, namespaces []string) (map[string]podinfo.ResourceUsage, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	usage := make(map[string]podinfo.ResourceUsage)
+	for _, ns := range namespaces {
+		list, err := client.PodMetricses(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("metrics-server is not available, usage is not shown: %w", err)
+		}
+		for i := range list.Items {
+			usage[list.Items[i].Namespace+"/"+list.Items[i].Name] = sumContainerUsage(&list.Items[i])
+		}
+	}
+	return usage, nil
+}
+
+// sumContainerUsage adds up the usage of all containers of a pod
+func sumContainerUsage(metrics *metricsv1beta1.PodMetrics) podinfo.ResourceUsage {
+	total := v1.ResourceList{}
+	for _, c := range metrics.Containers {
+		podinfo.AddResources(total, c.Usage)
+	}
+	cpu := total[v1.ResourceCPU]
+	memory := total[v1.ResourceMemory]
+	return podinfo.ResourceUsage{CPU: &cpu, Memory: &memory}
+}
+
+// joinUsage attaches usage to each pod, marking pods without metrics as
+// pending. infos and pods are parallel slices; the pods' effective requests
+// are used to compute the usage percentages.
+func joinUsage(infos []podinfo.PodInfo, pods []v1.Pod, usage map[string]podinfo.ResourceUsage) {
+	for i := range infos {
+		u, ok := usage[infos[i].Namespace+"/"+infos[i].Name]
+		if !ok {
+			u = podinfo.ResourceUsage{Pending: true}
+		} else {
+			requests := podinfo.ExtractResources(&pods[i])
+			u.CPUPercent = percentOf(u.CPU, requests.CPURequest, true)
+			u.MemoryPercent = percentOf(u.Memory, requests.MemoryRequest, false)
+		}
+		infos[i].Usage = &u
+	}
+}
+
+// percentOf returns used as a whole percentage of request, or nil when
+// there is no request. CPU is compared in millicores, memory in bytes.
+func percentOf(used, request *resource.Quantity, milli bool) *int64 {
+	if used == nil || request == nil || request.IsZero() {
+		return nil
+	}
+	var percent int64
+	if milli {
+		percent = used.MilliValue() * 100 / request.MilliValue()
+	} else {
+		percent = used.Value() * 100 / request.Value()
+	}
+	return &percent
+}
+
+// usageStrings returns the CPU and memory columns for a pod's usage, with
+// the percentage of requests when known, e.g. "120m (24%)"
+func usageStrings(u *podinfo.ResourceUsage) (cpu, memory string) {
+	if u == nil || u.Pending {
+		return "<pending>", "<pending>"
+	}
+	withPercent := func(value string, percent *int64) string {
+		if percent == nil {
+			return value
+		}
+		return fmt.Sprintf("%s (%d%%)", value, *percent)
+	}
+	return withPercent(formatCPU(u.CPU), u.CPUPercent), withPercent(formatMemory(u.Memory), u.MemoryPercent)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	restfake "k8s.io/client-go/rest/fake"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsscheme "k8s.io/metrics/pkg/client/clientset/versioned/scheme"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+)
+
+// newPodMetrics returns metrics for a pod with one entry per cpu/memory pair
+func newPodMetrics(namespace, name string, usage ...[2]string) metricsv1beta1.PodMetrics {
+	metrics := metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	for _, u := range usage {
+		metrics.Containers = append(metrics.Containers, metricsv1beta1.ContainerMetrics{Usage: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(u[0]),
+			v1.ResourceMemory: resource.MustParse(u[1]),
+		}})
+	}
+	return metrics
+}
+
+// newMetricsClient returns a metrics client backed by a fake RESTClient that
+// answers each request with the PodMetricsList for the requested namespace,
+// or with err when set
+func newMetricsClient(t *testing.T, podMetrics map[string]metricsv1beta1.PodMetricsList, err error) (*metricsclient.MetricsV1beta1Client, *[]string) {
+	t.Helper()
+	var paths []string
+	restClient := &restfake.RESTClient{
+		NegotiatedSerializer: metricsscheme.Codecs.WithoutConversion(),
+		GroupVersion:         metricsv1beta1.SchemeGroupVersion,
+		VersionedAPIPath:     "/apis/metrics.k8s.io/v1beta1",
+		Err:                  err,
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path)
+			namespace := ""
+			if parts := strings.Split(req.URL.Path, "/"); len(parts) > 5 && parts[4] == "namespaces" {
+				namespace = parts[5]
+			}
+			list := podMetrics[namespace]
+			body, err := json.Marshal(&list)
+			if err != nil {
+				t.Fatalf("encoding pod metrics: %v", err)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{runtime.ContentTypeJSON}},
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}),
+	}
+	return metricsclient.New(restClient), &paths
+}
+
+func TestFetchPodUsage(t *testing.T) {
+	client, paths := newMetricsClient(t, map[string]metricsv1beta1.PodMetricsList{
+		"": {Items: []metricsv1beta1.PodMetrics{
+			newPodMetrics("default", "web", [2]string{"100m", "64Mi"}, [2]string{"50m", "64Mi"}),
+		}},
+	}, nil)
+
+	usage, err := fetchPodUsage(context.Background(), client, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"/apis/metrics.k8s.io/v1beta1/pods"}; !reflect.DeepEqual(*paths, want) {
+		t.Errorf("requested %v, want %v", *paths, want)
+	}
+	cpu, memory := usageStrings(&podinfo.ResourceUsage{CPU: usage["default/web"].CPU, Memory: usage["default/web"].Memory})
+	if cpu != "150m" || memory != "128Mi" {
+		t.Errorf("usage = %s/%s, want 150m/128Mi", cpu, memory)
+	}
+}
+
+func TestFetchPodUsageNamespaces(t *testing.T) {
+	client, paths := newMetricsClient(t, map[string]metricsv1beta1.PodMetricsList{
+		"a": {Items: []metricsv1beta1.PodMetrics{newPodMetrics("a", "one", [2]string{"1", "1Gi"})}},
+		"b": {Items: []metricsv1beta1.PodMetrics{newPodMetrics("b", "two", [2]string{"2", "2Gi"})}},
+	}, nil)
+
+	usage, err := fetchPodUsage(context.Background(), client, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*paths) != 2 {
+		t.Errorf("made %d requests, want one per namespace: %v", len(*paths), *paths)
+	}
+	if _, ok := usage["a/one"]; !ok {
+		t.Errorf("missing usage for a/one: %v", usage)
+	}
+	if _, ok := usage["b/two"]; !ok {
+		t.Errorf("missing usage for b/two: %v", usage)
+	}
+}
+
+func TestFetchPodUsageUnavailable(t *testing.T) {
+	client, _ := newMetricsClient(t, nil, apierrors.NewServiceUnavailable("the server is currently unable to handle the request"))
+
+	if _, err := fetchPodUsage(context.Background(), client, []string{"a", "b"}); err == nil {
+		t.Fatal("expected an error when metrics-server is unavailable")
+	}
+}
+
+func TestUsagePercentOfRequests(t *testing.T) {
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+	pods := []v1.Pod{
+		{Spec: v1.PodSpec{Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("500m"),
+			v1.ResourceMemory: resource.MustParse("256Mi"),
+		}}}}}},
+		{Spec: v1.PodSpec{Containers: []v1.Container{{}}}},
+	}
+	infos := []podinfo.PodInfo{{Namespace: "default", Name: "requests"}, {Namespace: "default", Name: "best-effort"}}
+	joinUsage(infos, pods, map[string]podinfo.ResourceUsage{
+		"default/requests":    {CPU: quantity("120m"), Memory: quantity("384Mi")},
+		"default/best-effort": {CPU: quantity("120m"), Memory: quantity("384Mi")},
+	})
+
+	if cpu, memory := usageStrings(infos[0].Usage); cpu != "120m (24%)" || memory != "384Mi (150%)" {
+		t.Errorf("usage with requests = %s/%s, want 120m (24%%)/384Mi (150%%)", cpu, memory)
+	}
+	if cpu, memory := usageStrings(infos[1].Usage); cpu != "120m" || memory != "384Mi" {
+		t.Errorf("usage without requests = %s/%s, want 120m/384Mi", cpu, memory)
+	}
+	if infos[1].Usage.CPUPercent != nil || infos[1].Usage.MemoryPercent != nil {
+		t.Errorf("percentages set without requests: %+v", infos[1].Usage)
+	}
+}
+
+func TestJoinAndSortUsage(t *testing.T) {
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+	infos := []podinfo.PodInfo{
+		{Namespace: "default", Name: "small"},
+		{Namespace: "default", Name: "new"},
+		{Namespace: "default", Name: "big"},
+	}
+	joinUsage(infos, make([]v1.Pod, len(infos)), map[string]podinfo.ResourceUsage{
+		"default/small": {CPU: quantity("10m"), Memory: quantity("1Gi")},
+		"default/big":   {CPU: quantity("2"), Memory: quantity("10Mi")},
+	})
+
+	if cpu, _ := usageStrings(infos[1].Usage); cpu != "<pending>" {
+		t.Errorf("pod without metrics shows %q, want <pending>", cpu)
+	}
+
+	names := func() []string {
+		var names []string
+		for _, info := range infos {
+			names = append(names, info.Name)
+		}
+		return names
+	}
+	podinfo.Sort(infos, []podinfo.SortKey{{Key: podinfo.SortByCPU, Descending: true}})
+	if got := names(); !reflect.DeepEqual(got, []string{"big", "small", "new"}) {
+		t.Errorf("sorted by cpu = %v", got)
+	}
+	podinfo.Sort(infos, []podinfo.SortKey{{Key: podinfo.SortByMemory, Descending: true}})
+	if got := names(); !reflect.DeepEqual(got, []string{"small", "big", "new"}) {
+		t.Errorf("sorted by memory = %v", got)
+	}
+}
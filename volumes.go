@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+// hostPathFlag marks hostPath volumes in text and table output, since they
+// tie pods to a node's filesystem
+const hostPathFlag = "HOSTPATH"
+
+// volumeString renders a volume for text output, e.g.
+// "data: persistentVolumeClaim data-0"
+func volumeString(v podinfo.VolumeInfo) string {
+	s := v.Name + ": " + v.Type
+	if v.Source != "" {
+		s += " " + v.Source
+	}
+	if v.Type == podinfo.VolumeTypeHostPath {
+		s += "  <- " + hostPathFlag
+	}
+	return s
+}
+
+// printVolumes prints the volumes of a pod below its text block, with
+// hostPath volumes in red when color is enabled
+func printVolumes(out io.Writer, volumes []podinfo.VolumeInfo, color bool) {
+	if len(volumes) == 0 {
+		fmt.Fprintf(out, "  Volumes: <none>\n")
+		return
+	}
+	fmt.Fprintf(out, "  Volumes:\n")
+	for _, v := range volumes {
+		line := "    - " + volumeString(v)
+		if color && v.Type == podinfo.VolumeTypeHostPath {
+			line = colorize(line, ansiRed)
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+// volumesCell is the VOLUMES table cell: the claim names of PVC volumes and
+// the types of the others, with hostPath volumes upper-cased and followed by
+// their path, e.g. "pvc:data-0,configMap,HOSTPATH:/var/log"
+func volumesCell(volumes []podinfo.VolumeInfo) string {
+	if len(volumes) == 0 {
+		return "<none>"
+	}
+	cells := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		switch {
+		case v.Type == podinfo.VolumeTypeHostPath:
+			cells = append(cells, hostPathFlag+":"+v.Source)
+		case v.ClaimName() != "":
+			cells = append(cells, "pvc:"+v.ClaimName())
+		default:
+			cells = append(cells, v.Type)
+		}
+	}
+	return strings.Join(cells, ",")
+}
+
+// hostPathPods returns "namespace/name" of the pods with a hostPath volume
+func hostPathPods(infos []podinfo.PodInfo) []string {
+	var pods []string
+	for _, info := range infos {
+		for _, v := range info.Volumes {
+			if v.Type == podinfo.VolumeTypeHostPath {
+				pods = append(pods, info.Namespace+"/"+info.Name)
+				break
+			}
+		}
+	}
+	return pods
+}
+
+// printHostPathWarning lists the pods mounting hostPath volumes after the
+// totals of text and table output
+func printHostPathWarning(out io.Writer, infos []podinfo.PodInfo, color bool) {
+	pods := hostPathPods(infos)
+	if len(pods) == 0 {
+		return
+	}
+	warning := fmt.Sprintf("WARNING: %d pods mount hostPath volumes: %s", len(pods), strings.Join(pods, ", "))
+	if color {
+		warning = colorize(warning, ansiRed)
+	}
+	fmt.Fprintln(out, warning)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"Kubernetes_Programming/pkg/podinfo"
+)
+
+func TestVolumesCell(t *testing.T) {
+	volumes := []podinfo.VolumeInfo{
+		{Name: "data", Type: podinfo.VolumeTypePVC, Source: "data-0"},
+		{Name: "config", Type: podinfo.VolumeTypeConfigMap, Source: "app-config"},
+		{Name: "logs", Type: podinfo.VolumeTypeHostPath, Source: "/var/log"},
+	}
+	if got, want := volumesCell(volumes), "pvc:data-0,configMap,HOSTPATH:/var/log"; got != want {
+		t.Errorf("volumesCell() = %q, want %q", got, want)
+	}
+	if got := volumesCell(nil); got != "<none>" {
+		t.Errorf("volumesCell(nil) = %q, want <none>", got)
+	}
+}
+
+func TestPrintVolumes(t *testing.T) {
+	var out bytes.Buffer
+	printVolumes(&out, []podinfo.VolumeInfo{
+		{Name: "data", Type: podinfo.VolumeTypePVC, Source: "data-0"},
+		{Name: "logs", Type: podinfo.VolumeTypeHostPath, Source: "/var/log"},
+	}, false)
+
+	want := "  Volumes:\n" +
+		"    - data: persistentVolumeClaim data-0\n" +
+		"    - logs: hostPath /var/log  <- HOSTPATH\n"
+	if out.String() != want {
+		t.Errorf("printVolumes() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrintHostPathWarning(t *testing.T) {
+	infos := []podinfo.PodInfo{
+		{Namespace: "default", Name: "web", Volumes: []podinfo.VolumeInfo{{Name: "cache", Type: podinfo.VolumeTypeEmptyDir}}},
+		{Namespace: "kube-system", Name: "fluentd", Volumes: []podinfo.VolumeInfo{
+			{Name: "varlog", Type: podinfo.VolumeTypeHostPath, Source: "/var/log"},
+			{Name: "containers", Type: podinfo.VolumeTypeHostPath, Source: "/var/lib/docker/containers"},
+		}},
+	}
+
+	var out bytes.Buffer
+	printHostPathWarning(&out, infos, false)
+	if got, want := out.String(), "WARNING: 1 pods mount hostPath volumes: kube-system/fluentd\n"; got != want {
+		t.Errorf("warning = %q, want %q", got, want)
+	}
+
+	out.Reset()
+	printHostPathWarning(&out, infos[:1], false)
+	if out.Len() != 0 {
+		t.Errorf("warning without hostPath volumes = %q, want none", out.String())
+	}
+
+	out.Reset()
+	printHostPathWarning(&out, infos, true)
+	if !strings.HasPrefix(out.String(), ansiRed) {
+		t.Errorf("colored warning = %q, want it in red", out.String())
+	}
+}